@@ -1,14 +1,12 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/net/context"
 )
 
 // SimpleAlbum contains basic data about an album.
@@ -33,9 +31,21 @@ type SimpleAlbum struct {
 	Endpoint string `json:"href"`
 	// The cover art for the album in various sizes,
 	// widest first.
-	Images []Image `json:"images"`
+	Images Images `json:"images"`
 	// Known external URLs for this album.
-	ExternalURLs map[string]string `json:"external_urls"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	// The date the album was first released.  For example, "1981-12-15".
+	// Depending on the ReleaseDatePrecision, it might be shown as
+	// "1981" or "1981-12". You can use ReleaseDateTime to convert this
+	// to a time.Time value.
+	ReleaseDate string `json:"release_date"`
+	// The precision with which ReleaseDate value is known.
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	// Why the album can't be played, if a market was given and the album
+	// isn't playable there.
+	Restrictions *Restrictions `json:"restrictions,omitempty"`
+	// The object type: "album".
+	Type string `json:"type"`
 }
 
 // Copyright contains the copyright statement associated with an album.
@@ -43,9 +53,20 @@ type Copyright struct {
 	// The copyright text for the album.
 	Text string `json:"text"`
 	// The type of copyright.
-	Type string `json:"type"`
+	Type CopyrightType `json:"type"`
 }
 
+// CopyrightType identifies what a copyright statement covers.
+type CopyrightType string
+
+// CopyrightType values.
+const (
+	// CopyrightC is the copyright of the composition (©).
+	CopyrightC CopyrightType = "C"
+	// CopyrightP is the copyright of the sound recording (℗).
+	CopyrightP CopyrightType = "P"
+)
+
 // FullAlbum provides extra album data in addition to the data provided by SimpleAlbum.
 type FullAlbum struct {
 	SimpleAlbum
@@ -55,61 +76,153 @@ type FullAlbum struct {
 	// The popularity of the album, represented as an integer between 0 and 100,
 	// with 100 being the most popular.  Popularity of an album is calculated
 	// from the popularify of the album's individual tracks.
-	Popularity int `json:"popularity"`
-	// The date the album was first released.  For example, "1981-12-15".
-	// Depending on the ReleaseDatePrecision, it might be shown as
-	// "1981" or "1981-12". You can use ReleaseDateTime to convert this
-	// to a time.Time value.
-	ReleaseDate string `json:"release_date"`
-	// The precision with which ReleaseDate value is known: "year", "month", or "day"
-	ReleaseDatePrecision string            `json:"release_date_precision"`
-	Tracks               SimpleTrackPage   `json:"tracks"`
-	ExternalIDs          map[string]string `json:"external_ids"`
+	Popularity  Popularity      `json:"popularity"`
+	Tracks      SimpleTrackPage `json:"tracks"`
+	ExternalIDs ExternalIDs     `json:"external_ids"`
 }
 
 // SavedAlbum provides info about an album saved to an user's account.
 type SavedAlbum struct {
-	// The date and time the track was saved, represented as an ISO
-	// 8601 UTC timestamp with a zero offset (YYYY-MM-DDTHH:MM:SSZ).
-	// You can use the TimestampLayout constant to convert this to
-	// a time.Time value.
-	AddedAt   string `json:"added_at"`
+	// The date and time the album was saved.
+	AddedAt   time.Time `json:"added_at"`
 	FullAlbum `json:"album"`
 }
 
+// Copyright returns the text of the album's copyright statement of the
+// given type, or the empty string if it has none.
+func (f *FullAlbum) Copyright(typ CopyrightType) string {
+	for _, c := range f.Copyrights {
+		if c.Type == typ {
+			return c.Text
+		}
+	}
+	return ""
+}
+
+// DatePrecision is the precision with which a release date is known.
+type DatePrecision string
+
+// DatePrecision values, from least to most precise.
+const (
+	PrecisionYear  DatePrecision = "year"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionDay   DatePrecision = "day"
+)
+
+// rank orders precisions from least (0) to most precise.  Unknown
+// precisions are treated as PrecisionYear, as ReleaseDateTime does.
+func (p DatePrecision) rank() int {
+	switch p {
+	case PrecisionDay:
+		return 2
+	case PrecisionMonth:
+		return 1
+	}
+	return 0
+}
+
+// Coarser returns the less precise of p and q.
+func (p DatePrecision) Coarser(q DatePrecision) DatePrecision {
+	if q.rank() < p.rank() {
+		return q
+	}
+	return p
+}
+
+// Truncate returns t with the parts that aren't known at precision p
+// cleared.  For example, truncating to PrecisionMonth gives the first day
+// of the month.
+func (p DatePrecision) Truncate(t time.Time) time.Time {
+	switch p.rank() {
+	case 2:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case 1:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+}
+
+// CompareReleaseDates returns -1, 0 or +1 depending on whether a was
+// released before, at the same time as, or after b.  The dates are compared
+// at the coarser of their two precisions, so an album released in "2019" is
+// not considered to be earlier than one released on "2019-05-03" just because
+// its date parses as January 1st.  When the dates are the same at that
+// precision, the less precise one comes first, so the result is consistent
+// and can be used for sorting.
+func CompareReleaseDates(a, b *SimpleAlbum) int {
+	p := a.ReleaseDatePrecision.Coarser(b.ReleaseDatePrecision)
+	if c := compareTimes(p.Truncate(a.ReleaseDateTime()), p.Truncate(b.ReleaseDateTime())); c != 0 {
+		return c
+	}
+	if ra, rb := a.ReleaseDatePrecision.rank(), b.ReleaseDatePrecision.rank(); ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	return compareTimes(a.ReleaseDateTime(), b.ReleaseDateTime())
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
 // ReleaseDateTime converts the album's ReleaseDate to a time.TimeValue.
 // All of the fields in the result may not be valid.  For example, if
-// f.ReleaseDatePrecision is "month", then only the month and year
+// f.ReleaseDatePrecision is PrecisionMonth, then only the month and year
 // (but not the day) of the result are valid.
-func (f *FullAlbum) ReleaseDateTime() time.Time {
-	if f.ReleaseDatePrecision == "day" {
-		result, _ := time.Parse(DateLayout, f.ReleaseDate)
-		return result
+func (f *SimpleAlbum) ReleaseDateTime() time.Time {
+	return parseReleaseDate(f.ReleaseDate, f.ReleaseDatePrecision)
+}
+
+// ReleaseDateTime converts the album's ReleaseDate to a time.Time, as
+// SimpleAlbum's ReleaseDateTime does.
+func (a *AlbumInfo) ReleaseDateTime() time.Time {
+	return parseReleaseDate(a.ReleaseDate, a.ReleaseDatePrecision)
+}
+
+// parseReleaseDate parses a release date known with precision p, in UTC,
+// with the parts that aren't known set to the first month or day.  Spotify
+// sometimes gives dates that are more or less precise than their precision
+// says, or that have a month or day of 00, so the date is parsed as far as
+// it is valid, up to p.  The zero time is returned if the year isn't.
+func parseReleaseDate(date string, p DatePrecision) time.Time {
+	parts := strings.SplitN(date, "-", 3)
+	if len(parts) > p.rank()+1 {
+		parts = parts[:p.rank()+1]
+	}
+	ymd := [3]int{0, 1, 1}
+	max := [3]int{9999, 12, 31}
+	for i, s := range parts {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > max[i] {
+			break
+		}
+		ymd[i] = n
 	}
-	if f.ReleaseDatePrecision == "month" {
-		ym := strings.Split(f.ReleaseDate, "-")
-		year, _ := strconv.Atoi(ym[0])
-		month, _ := strconv.Atoi(ym[1])
-		return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	if ymd[0] == 0 {
+		return time.Time{}
 	}
-	year, _ := strconv.Atoi(f.ReleaseDate)
-	return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return time.Date(ymd[0], time.Month(ymd[1]), ymd[2], 0, 0, 0, 0, time.UTC)
 }
 
 // GetAlbum gets Spotify catalog information for a single album, given its Spotify ID.
 func (c *Client) GetAlbum(id ID) (*FullAlbum, error) {
-	spotifyURL := fmt.Sprintf("%salbums/%s", baseAddress, id)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetAlbumWithContext(context.Background(), id)
+}
+
+// GetAlbumWithContext is like GetAlbum, with a context.
+func (c *Client) GetAlbumWithContext(ctx context.Context, id ID) (*FullAlbum, error) {
+	e := c.endpoint("albums/%s", id)
+	e.setDefaultMarket()
 	var a FullAlbum
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &a); err != nil {
 		return nil, err
 	}
 	return &a, nil
@@ -133,23 +246,24 @@ func GetAlbums(ids ...ID) ([]*FullAlbum, error) {
 // in the order requested.  If an album is not found, that position in the
 // result slice will be nil.
 func (c *Client) GetAlbums(ids ...ID) ([]*FullAlbum, error) {
+	return c.GetAlbumsWithContext(context.Background(), ids...)
+}
+
+// GetAlbumsWithContext is like GetAlbums, with a context.
+func (c *Client) GetAlbumsWithContext(ctx context.Context, ids ...ID) ([]*FullAlbum, error) {
 	if len(ids) > 20 {
 		return nil, errors.New("spotify: exceeded maximum number of albums")
 	}
-	spotifyURL := fmt.Sprintf("%salbums?ids=%s", baseAddress, strings.Join(toStringSlice(ids), ","))
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
+	if err := ValidateIDs(ids...); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	e := c.endpoint("albums")
+	e.setIDs(ids)
+	e.setDefaultMarket()
 	var a struct {
 		Albums []*FullAlbum `json:"albums"`
 	}
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &a); err != nil {
 		return nil, err
 	}
 	return a.Albums, nil
@@ -195,7 +309,7 @@ func GetAlbumTracks(id ID) (*SimpleTrackPage, error) {
 // If you only care about the tracks, this call is more efficient
 // than GetAlbum.
 func (c *Client) GetAlbumTracks(id ID) (*SimpleTrackPage, error) {
-	return c.GetAlbumTracksOpt(id, -1, -1)
+	return c.GetAlbumTracksWithContext(context.Background(), id, -1, -1)
 }
 
 // GetAlbumTracksOpt is a wrapper around DefaultClient.GetAlbumTracksOpt.
@@ -209,27 +323,21 @@ func GetAlbumTracksOpt(id ID, limit, offset int) (*SimpleTrackPage, error) {
 // The offset argument can be used to specify the index of the first track to return.
 // It can be used along with limit to reqeust the next set of results.
 func (c *Client) GetAlbumTracksOpt(id ID, limit, offset int) (*SimpleTrackPage, error) {
-	spotifyURL := fmt.Sprintf("%salbums/%s/tracks", baseAddress, id)
-	v := url.Values{}
+	return c.GetAlbumTracksWithContext(context.Background(), id, limit, offset)
+}
+
+// GetAlbumTracksWithContext is like GetAlbumTracksOpt, with a context.
+func (c *Client) GetAlbumTracksWithContext(ctx context.Context, id ID, limit, offset int) (*SimpleTrackPage, error) {
+	e := c.endpoint("albums/%s/tracks", id)
+	e.setDefaultMarket()
 	if limit != -1 {
-		v.Set("limit", strconv.Itoa(limit))
+		e.setInt("limit", limit)
 	}
 	if offset != -1 {
-		v.Set("offset", strconv.Itoa(offset))
-	}
-	optional := v.Encode()
-	if optional != "" {
-		spotifyURL = spotifyURL + "?" + optional
+		e.setInt("offset", offset)
 	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var result SimpleTrackPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -3,6 +3,7 @@ package spotify
 import (
 	"net/http"
 	"testing"
+	"time"
 )
 
 // The example from https://developer.spotify.com/web-api/get-album/
@@ -34,7 +35,7 @@ func TestFindAlbumBadID(t *testing.T) {
 		t.Error("Expected nil album, got", album.Name)
 		return
 	}
-	se, ok := err.(Error)
+	se, ok := err.(*Error)
 	if !ok {
 		t.Error("Expected spotify error, got", err)
 		return
@@ -105,3 +106,68 @@ func TestFindAlbumTracks(t *testing.T) {
 		t.Error("Expected 1 track, got", len(res.Tracks))
 	}
 }
+
+func TestCompareReleaseDates(t *testing.T) {
+	album := func(date string, p DatePrecision) *SimpleAlbum {
+		return &SimpleAlbum{ReleaseDate: date, ReleaseDatePrecision: p}
+	}
+	tests := []struct {
+		a, b *SimpleAlbum
+		want int
+	}{
+		{album("2019-05-03", PrecisionDay), album("2019-05-04", PrecisionDay), -1},
+		{album("2019-05-03", PrecisionDay), album("2019-05-03", PrecisionDay), 0},
+		{album("2019", PrecisionYear), album("2018-12-31", PrecisionDay), 1},
+		{album("2019-06", PrecisionMonth), album("2019-05-30", PrecisionDay), 1},
+		{album("2019-05", PrecisionMonth), album("2019-05-01", PrecisionDay), -1},
+		{album("2019", PrecisionYear), album("2019-05-03", PrecisionDay), -1},
+		{album("2019-05-03", PrecisionDay), album("2019", PrecisionYear), 1},
+		{album("2019", PrecisionYear), album("2019", PrecisionYear), 0},
+	}
+	for _, test := range tests {
+		if got := CompareReleaseDates(test.a, test.b); got != test.want {
+			t.Errorf("CompareReleaseDates(%s, %s) = %d, want %d\n",
+				test.a.ReleaseDate, test.b.ReleaseDate, got, test.want)
+		}
+	}
+}
+
+func TestReleaseDateTime(t *testing.T) {
+	tests := []struct {
+		date string
+		p    DatePrecision
+		want time.Time
+	}{
+		{"1981-12-15", PrecisionDay, time.Date(1981, 12, 15, 0, 0, 0, 0, time.UTC)},
+		{"1981-12", PrecisionMonth, time.Date(1981, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"1981", PrecisionYear, time.Date(1981, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// less precise than the precision says
+		{"1981", PrecisionMonth, time.Date(1981, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"1981-12", PrecisionDay, time.Date(1981, 12, 1, 0, 0, 0, 0, time.UTC)},
+		// more precise than the precision says
+		{"1981-12-15", PrecisionYear, time.Date(1981, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"1981-00-00", PrecisionDay, time.Date(1981, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0000", PrecisionYear, time.Time{}},
+		{"", "", time.Time{}},
+	}
+	for _, test := range tests {
+		a := &SimpleAlbum{ReleaseDate: test.date, ReleaseDatePrecision: test.p}
+		if got := a.ReleaseDateTime(); !got.Equal(test.want) {
+			t.Errorf("ReleaseDateTime of %q at %q precision = %v, want %v\n", test.date, test.p, got, test.want)
+		}
+	}
+}
+
+func TestAlbumCopyright(t *testing.T) {
+	client := testClientFile(http.StatusOK, "test_data/golden/album_full.json")
+	album, err := client.GetAlbum(ID("0sNOF9WDwhWunNAHPD3Baj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := album.Copyright(CopyrightP); got != "(P) 2000 Sony Music Entertainment Inc." {
+		t.Errorf("Got P copyright %q\n", got)
+	}
+	if got := album.Copyright(CopyrightC); got != "" {
+		t.Errorf("Expected no C copyright, got %q\n", got)
+	}
+}
@@ -0,0 +1,53 @@
+package spotify
+
+import (
+	"errors"
+	"net/http"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// ErrNoToken is returned by a TokenStore when no token has been saved
+// for the requested user.
+var ErrNoToken = errors.New("spotify: no token stored for user")
+
+// TokenStore persists users' OAuth2 tokens between requests.  On App Engine,
+// where instances come and go, tokens are typically kept in Datastore or
+// memcache keyed by the application's own user ID.
+type TokenStore interface {
+	// Load returns the token saved for the user, or ErrNoToken.
+	Load(ctx context.Context, userID string) (*oauth2.Token, error)
+	// Save stores the user's token, replacing any previous one.
+	Save(ctx context.Context, userID string, token *oauth2.Token) error
+}
+
+// NewClientFromContext creates a Client for a single App Engine request.
+// It loads the user's token from store and sends all API (and token refresh)
+// requests through urlfetch using the request context ctx.  If the token is
+// refreshed, the new token is saved back to store, and passed to the
+// function set with OnTokenRefreshed.
+//
+// The returned client should not be used after the request completes.
+func (a Authenticator) NewClientFromContext(ctx context.Context, store TokenStore,
+	userID string, opts ...ClientOption) (*Client, error) {
+
+	return a.newClientWithTransport(ctx, &urlfetch.Transport{Context: ctx}, store, userID, opts...)
+}
+
+func (a Authenticator) newClientWithTransport(ctx context.Context, base http.RoundTripper,
+	store TokenStore, userID string, opts ...ClientOption) (*Client, error) {
+
+	token, err := store.Load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	refreshCtx := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base})
+	var c Client
+	hc := a.newHTTPClient(refreshCtx, base, token, func(t *oauth2.Token) error {
+		return store.Save(ctx, userID, t)
+	}, clientClock{&c})
+	c = NewClient(hc, opts...)
+	return &c, nil
+}
@@ -0,0 +1,78 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+type memTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*oauth2.Token
+}
+
+func newMemTokenStore(tokens map[string]*oauth2.Token) *memTokenStore {
+	return &memTokenStore{tokens: tokens}
+}
+
+func (m *memTokenStore) Load(ctx context.Context, userID string) (*oauth2.Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tokens[userID]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return t, nil
+}
+
+func (m *memTokenStore) Save(ctx context.Context, userID string, token *oauth2.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[userID] = token
+	return nil
+}
+
+func TestNewClientFromStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/token") {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "fresh", "token_type": "Bearer", "expires_in": 3600}`)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer fresh" {
+			t.Errorf("Got Authorization %q\n", auth)
+		}
+		fmt.Fprint(w, `{"id": "wizzler"}`)
+	}))
+	defer server.Close()
+
+	a := NewAuthenticator("http://localhost/callback")
+	a.config.Endpoint.TokenURL = server.URL + "/api/token"
+	store := newMemTokenStore(map[string]*oauth2.Token{
+		"u1": {AccessToken: "stale", RefreshToken: "r", Expiry: time.Now().Add(-time.Hour)},
+	})
+
+	c, err := a.newClientWithTransport(context.Background(), http.DefaultTransport, store, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.http.Get(server.URL + "/v1/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if store.tokens["u1"].AccessToken != "fresh" {
+		t.Error("Refreshed token wasn't saved")
+	}
+
+	if _, err := a.newClientWithTransport(context.Background(), http.DefaultTransport, store, "u2"); err != ErrNoToken {
+		t.Error("Expected ErrNoToken, got", err)
+	}
+}
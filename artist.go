@@ -1,13 +1,6 @@
 package spotify
 
-import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
-)
+import "golang.org/x/net/context"
 
 // SimpleArtist contains basic info about an artist.
 type SimpleArtist struct {
@@ -16,8 +9,10 @@ type SimpleArtist struct {
 	// The Spotify URI for the artist.
 	URI URI `json:"uri"`
 	// A link to the Web API enpoint providing full details of the artist.
-	Endpoint     string            `json:"href"`
-	ExternalURLs map[string]string `json:"external_urls"`
+	Endpoint     string       `json:"href"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	// The object type: "artist".
+	Type string `json:"type"`
 }
 
 // FullArtist provides extra artist data in addition to what is provided by SimpleArtist.
@@ -25,13 +20,13 @@ type FullArtist struct {
 	SimpleArtist
 	// The popularity of the artist, expressed as an integer between 0 and 100.
 	// The artist's popularity is calculated from the popularity of the artist's tracks.
-	Popularity int `json:"popularity"`
+	Popularity Popularity `json:"popularity"`
 	// A list of genres the artist is associated with.  For example, "Prog Rock"
 	// or "Post-Grunge".  If not yet classified, the slice is empty.
-	Genres    []string `json:"genres"`
-	Followers Followers
+	Genres    []string  `json:"genres"`
+	Followers Followers `json:"followers"`
 	// Images of the artist in various sizes, widest first.
-	Images []Image `json:"images"`
+	Images Images `json:"images"`
 }
 
 // GetArtist is a wrapper around DefaultClient.GetArtist.
@@ -41,18 +36,13 @@ func GetArtist(id ID) (*FullArtist, error) {
 
 // GetArtist gets Spotify catalog information for a single artist, given its Spotify ID.
 func (c *Client) GetArtist(id ID) (*FullArtist, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s", baseAddress, id)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetArtistWithContext(context.Background(), id)
+}
+
+// GetArtistWithContext is like GetArtist, with a context.
+func (c *Client) GetArtistWithContext(ctx context.Context, id ID) (*FullArtist, error) {
 	var a FullArtist
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.get(ctx, c.endpoint("artists/%s", id).String(), &a); err != nil {
 		return nil, err
 	}
 	return &a, nil
@@ -69,20 +59,20 @@ func GetArtists(ids ...ID) ([]*FullArtist, error) {
 // in the result will be nil.  Duplicate IDs will result in duplicate artists
 // in the result.
 func (c *Client) GetArtists(ids ...ID) ([]*FullArtist, error) {
-	spotifyURL := fmt.Sprintf("%sartists?ids=%s", baseAddress, strings.Join(toStringSlice(ids), ","))
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
+	return c.GetArtistsWithContext(context.Background(), ids...)
+}
+
+// GetArtistsWithContext is like GetArtists, with a context.
+func (c *Client) GetArtistsWithContext(ctx context.Context, ids ...ID) ([]*FullArtist, error) {
+	if err := ValidateIDs(ids...); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	e := c.endpoint("artists")
+	e.setIDs(ids)
 	var a struct {
 		Artists []*FullArtist
 	}
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &a); err != nil {
 		return nil, err
 	}
 	return a.Artists, nil
@@ -97,21 +87,21 @@ func GetArtistsTopTracks(artistID ID, country string) ([]FullTrack, error) {
 // tracks in a particular country.  It returns a maximum of 10 tracks.  The
 // country is specified as an ISO 3166-1 alpha-2 country code.
 func (c *Client) GetArtistsTopTracks(artistID ID, country string) ([]FullTrack, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s/top-tracks?country=%s", baseAddress, artistID, country)
-	resp, err := c.http.Get(spotifyURL)
+	return c.GetArtistsTopTracksWithContext(context.Background(), artistID, country)
+}
+
+// GetArtistsTopTracksWithContext is like GetArtistsTopTracks, with a context.
+func (c *Client) GetArtistsTopTracksWithContext(ctx context.Context, artistID ID, country string) ([]FullTrack, error) {
+	m, err := ParseMarket(country)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	e := c.endpoint("artists/%s/top-tracks", artistID)
+	e.set("country", string(m))
 	var t struct {
 		Tracks []FullTrack `json:"tracks"`
 	}
-
-	err = json.NewDecoder(resp.Body).Decode(&t)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &t); err != nil {
 		return nil, err
 	}
 	return t.Tracks, nil
@@ -127,20 +117,15 @@ func GetRelatedArtists(id ID) ([]FullArtist, error) {
 // listening history.  This function returns up to 20 artists that are considered
 // related to the specified artist.
 func (c *Client) GetRelatedArtists(id ID) ([]FullArtist, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s/related-artists", baseAddress, id)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetRelatedArtistsWithContext(context.Background(), id)
+}
+
+// GetRelatedArtistsWithContext is like GetRelatedArtists, with a context.
+func (c *Client) GetRelatedArtistsWithContext(ctx context.Context, id ID) ([]FullArtist, error) {
 	var a struct {
 		Artists []FullArtist `json:"artists"`
 	}
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.get(ctx, c.endpoint("artists/%s/related-artists", id).String(), &a); err != nil {
 		return nil, err
 	}
 	return a.Artists, nil
@@ -154,7 +139,7 @@ func GetArtistAlbums(artistID ID) (*SimpleAlbumPage, error) {
 // GetArtistAlbums gets Spotify catalog information about an artist's albums.
 // It is equivalent to GetArtistAlbumsOpt(artistID, nil).
 func (c *Client) GetArtistAlbums(artistID ID) (*SimpleAlbumPage, error) {
-	return c.GetArtistAlbumsOpt(artistID, nil, nil)
+	return c.GetArtistAlbumsWithContext(context.Background(), artistID, nil, nil)
 }
 
 // GetArtistAlbumsOpt is a wrapper around DefaultClient.GetArtistAlbumsOpt
@@ -168,44 +153,34 @@ func GetArtistAlbumsOpt(artistID ID, options *Options, t *AlbumType) (*SimpleAlb
 // The AlbumType argument can be used to find a particular type of album.  Search
 // for multiple types by OR-ing the types together.
 func (c *Client) GetArtistAlbumsOpt(artistID ID, options *Options, t *AlbumType) (*SimpleAlbumPage, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s/albums", baseAddress, artistID)
+	return c.GetArtistAlbumsWithContext(context.Background(), artistID, options, t)
+}
+
+// GetArtistAlbumsWithContext is like GetArtistAlbumsOpt, with a context.
+func (c *Client) GetArtistAlbumsWithContext(ctx context.Context, artistID ID, options *Options, t *AlbumType) (*SimpleAlbumPage, error) {
+	e := c.endpoint("artists/%s/albums", artistID)
 	// add optional query string if options were specified
-	values := url.Values{}
 	if t != nil {
-		values.Set("album_type", t.encode())
+		e.set("album_type", t.encode())
 	}
 	if options != nil {
 		if options.Country != nil {
-			values.Set("market", *options.Country)
+			if err := e.setCountry("market", options.Country); err != nil {
+				return nil, err
+			}
+		} else if c.market != "" {
+			e.set("market", c.market)
 		} else {
 			// if the market is not specified, Spotify will likely return a lot
 			// of duplicates (one for each market in which the album is available)
 			// - prevent this behavior by falling back to the US by default
 			// TODO: would this ever be the desired behavior?
-			values.Set("market", CountryUSA)
-		}
-		if options.Limit != nil {
-			values.Set("limit", strconv.Itoa(*options.Limit))
+			e.set("market", CountryUSA)
 		}
-		if options.Offset != nil {
-			values.Set("offset", strconv.Itoa(*options.Offset))
-		}
-	}
-	if query := values.Encode(); query != "" {
-		spotifyURL += "?" + query
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+		e.setPaging(options)
 	}
 	var p SimpleAlbumPage
-	err = json.NewDecoder(resp.Body).Decode(&p)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &p); err != nil {
 		return nil, err
 	}
 	return &p, nil
@@ -132,6 +132,12 @@ func TestRelatedArtists(t *testing.T) {
 	if a2.Popularity != 54 {
 		t.Errorf("Expected popularity 54, got %d\n", a2.Popularity)
 	}
+	if a2.Followers.Endpoint != nil {
+		t.Error("Expected nil followers endpoint")
+	}
+	if a2.Followers.Total() != 11703 {
+		t.Errorf("Expected 11703 followers, got %d\n", a2.Followers.Total())
+	}
 }
 
 func TestArtistAlbumsFiltered(t *testing.T) {
@@ -1,8 +1,7 @@
 package spotify
 
 import (
-	"encoding/json"
-	"net/http"
+	"golang.org/x/net/context"
 )
 
 // AudioAnalysis contains audio information and metadata for the specified track
@@ -108,20 +107,19 @@ type TrackInfo struct {
 // the associated track including loudness, tempo, key, pitch, and timbre for denoted
 // sections of the track. For a full outline of the output, see: https://developer.spotify.com/web-api/get-audio-analysis/
 func (c *Client) GetAudioAnalysis(id ID) (*AudioAnalysis, error) {
-	spotifyURL := baseAddress + "audio-analysis/" + id.String()
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetAudioAnalysisWithContext(context.Background(), id)
+}
 
+// GetAudioAnalysisWithContext is like GetAudioAnalysis, with a context.
+func (c *Client) GetAudioAnalysisWithContext(ctx context.Context, id ID) (*AudioAnalysis, error) {
 	var a AudioAnalysis
-	err = json.NewDecoder(resp.Body).Decode(&a)
-	if err != nil {
+	if err := c.getAudioAnalysis(ctx, id, &a); err != nil {
 		return nil, err
 	}
 	return &a, nil
 }
+
+// getAudioAnalysis gets the audio analysis of a track, decoding it into v.
+func (c *Client) getAudioAnalysis(ctx context.Context, id ID, v interface{}) error {
+	return c.get(ctx, c.endpoint("audio-analysis/%s", id).String(), v)
+}
@@ -1,10 +1,7 @@
 package spotify
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
+	"golang.org/x/net/context"
 )
 
 // AudioFeatures contains various high-level acoustic attributes
@@ -72,6 +69,8 @@ type AudioFeatures struct {
 	// cheerful, euphoric), while tracks with low valence sound more negative
 	// (e.g. sad, depressed, angry).
 	Valence float32 `json:"valence"`
+	// The object type: "audio_features".
+	Type string `json:"type"`
 }
 
 // Key represents a pitch using Pitch Class notation.
@@ -105,26 +104,54 @@ const (
 	Major
 )
 
+// GetTrackAudioFeatures queries the Spotify Web API for the high-level
+// acoustic attributes of a single track.  This call requires
+// authorization.
+func (c *Client) GetTrackAudioFeatures(id ID) (*AudioFeatures, error) {
+	return c.GetTrackAudioFeaturesWithContext(context.Background(), id)
+}
+
+// GetTrackAudioFeaturesWithContext is like GetTrackAudioFeatures, with a
+// context.
+func (c *Client) GetTrackAudioFeaturesWithContext(ctx context.Context, id ID) (*AudioFeatures, error) {
+	if err := ValidateIDs(id); err != nil {
+		return nil, err
+	}
+	var f AudioFeatures
+	if err := c.get(ctx, c.endpoint("audio-features/%s", id).String(), &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// maxAudioFeatures is the most tracks the audio-features endpoint accepts
+// in one request.
+const maxAudioFeatures = 100
+
 // GetAudioFeatures queries the Spotify Web API for various
 // high-level acoustic attributes of audio tracks.
 // Objects are returned in the order requested.  If an object
 // is not found, a nil value is returned in the appropriate position.
-// This call requires authorization.
+// More than 100 tracks are requested in batches of 100, as by
+// GetAudioFeaturesBatch.  This call requires authorization.
 func (c *Client) GetAudioFeatures(ids ...ID) ([]*AudioFeatures, error) {
-	url := fmt.Sprintf("%saudio-features?ids=%s", baseAddress, strings.Join(toStringSlice(ids), ","))
-	resp, err := c.http.Get(url)
-	if err != nil {
-		return nil, err
+	return c.GetAudioFeaturesWithContext(context.Background(), ids...)
+}
+
+// GetAudioFeaturesWithContext is like GetAudioFeatures, with a context.
+func (c *Client) GetAudioFeaturesWithContext(ctx context.Context, ids ...ID) ([]*AudioFeatures, error) {
+	if len(ids) > maxAudioFeatures {
+		return c.GetAudioFeaturesBatchWithContext(ctx, ids...)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+	if err := ValidateIDs(ids...); err != nil {
+		return nil, err
 	}
+	e := c.endpoint("audio-features")
+	e.setIDs(ids)
 	temp := struct {
 		F []*AudioFeatures `json:"audio_features"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&temp)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &temp); err != nil {
 		return nil, err
 	}
 	return temp.F, nil
@@ -1,7 +1,11 @@
 package spotify
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -92,3 +96,54 @@ func TestAudioFeatures(t *testing.T) {
 		t.Errorf("Want key G, got %v\n", features[0].Key)
 	}
 }
+
+func TestGetTrackAudioFeatures(t *testing.T) {
+	c := testClientString(http.StatusOK, `{"id": "24JygzOLM0EmRQeGtFcIcG", "danceability": 0.5, "key": 7}`)
+	f, err := c.GetTrackAudioFeatures("24JygzOLM0EmRQeGtFcIcG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ID != "24JygzOLM0EmRQeGtFcIcG" || f.Danceability != 0.5 || Key(f.Key) != G {
+		t.Errorf("Got %+v\n", f)
+	}
+	if req := getLastRequest(c); req.URL.Path != "/v1/audio-features/24JygzOLM0EmRQeGtFcIcG" {
+		t.Errorf("Got path %s\n", req.URL.Path)
+	}
+}
+
+func TestAudioFeaturesChunked(t *testing.T) {
+	var requests int32
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		if len(ids) > 100 {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"status": 400, "message": "too many ids requested"}}`)),
+			}, nil
+		}
+		features := make([]string, len(ids))
+		for i, id := range ids {
+			features[i] = fmt.Sprintf(`{"id":"%s"}`, id)
+		}
+		body := `{"audio_features":[` + strings.Join(features, ",") + `]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+
+	ids := make([]ID, 250)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("%022d", i))
+	}
+	features, err := c.GetAudioFeatures(ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d\n", requests)
+	}
+	for i, f := range features {
+		if f == nil || f.ID != ids[i] {
+			t.Fatalf("Features %d: got %v, want %s\n", i, f, ids[i])
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package spotify
 
 import (
-	"crypto/tls"
 	"errors"
 	"net/http"
 	"os"
+	"strings"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
@@ -57,6 +57,15 @@ const (
 	ScopeUserReadRecentlyPlayed = "user-read-recently-played"
 	// ScopeUserTopRead seeks read access to a user's top tracks and artists.
 	ScopeUserTopRead = "user-top-read"
+	// ScopeUserReadCurrentlyPlaying seeks read access to the track a user
+	// is currently listening to.
+	ScopeUserReadCurrentlyPlaying = "user-read-currently-playing"
+	// ScopeUserReadPlaybackState seeks read access to a user's player
+	// state, including the track they are listening to.
+	ScopeUserReadPlaybackState = "user-read-playback-state"
+	// ScopeUserModifyPlaybackState seeks write access to a user's player,
+	// to start, pause and skip playback.
+	ScopeUserModifyPlaybackState = "user-modify-playback-state"
 )
 
 // Authenticator provides convenience functions for implementing the OAuth2 flow.
@@ -75,6 +84,8 @@ const (
 type Authenticator struct {
 	config  *oauth2.Config
 	context context.Context
+	// onRefresh is called with refreshed tokens (see OnTokenRefreshed).
+	onRefresh func(*oauth2.Token)
 }
 
 // NewAuthenticator creates an authenticator which is used to implement the
@@ -97,15 +108,16 @@ func NewAuthenticator(redirectURL string, scopes ...string) Authenticator {
 		},
 	}
 
-	// disable HTTP/2 for DefaultClient, see: https://github.com/zmb3/spotify/issues/20
-	tr := &http.Transport{
-		TLSNextProto: map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
-	}
-	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: tr})
-	return Authenticator{
-		config:  cfg,
-		context: ctx,
-	}
+	a := Authenticator{config: cfg}
+	a.SetTransport(NewTransport(DefaultTransportOptions))
+	return a
+}
+
+// SetTransport sets the transport used to exchange codes for tokens, and
+// by the clients returned by NewClient.  Use it with NewTransport to tune
+// the connection pool for bulk jobs.
+func (a *Authenticator) SetTransport(tr http.RoundTripper) {
+	a.context = context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: tr})
 }
 
 // SetAuthInfo overwrites the client ID and secret key used by the authenticator.
@@ -124,6 +136,51 @@ func (a Authenticator) AuthURL(state string) string {
 	return a.config.AuthCodeURL(state)
 }
 
+// AuthURLWithDialog is like AuthURL, but it asks the Spotify Accounts
+// Service to show the authorization dialog even if the user has already
+// approved the application, so that they can switch accounts or grant
+// scopes that were added since.
+func (a Authenticator) AuthURLWithDialog(state string) string {
+	return a.config.AuthCodeURL(state, oauth2.SetAuthURLParam("show_dialog", "true"))
+}
+
+// Scopes returns the scopes the authenticator asks for.
+func (a Authenticator) Scopes() []string {
+	return append([]string(nil), a.config.Scopes...)
+}
+
+// GrantedScopes returns the scopes that token was granted, which may be
+// fewer than were asked for.  It returns nil if the token's response
+// didn't say.
+func GrantedScopes(token *oauth2.Token) []string {
+	s, _ := token.Extra("scope").(string)
+	if s == "" {
+		return nil
+	}
+	return strings.Fields(s)
+}
+
+// MissingScopes returns the scopes the authenticator asks for that token
+// wasn't granted.  If the token's response didn't list the scopes granted,
+// they are assumed to be those asked for.
+func (a Authenticator) MissingScopes(token *oauth2.Token) []string {
+	granted := GrantedScopes(token)
+	if granted == nil {
+		return nil
+	}
+	has := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		has[s] = true
+	}
+	var missing []string
+	for _, s := range a.config.Scopes {
+		if !has[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
 // Token pulls an authorization code from an HTTP request and attempts to exchange
 // it for an access token.  The standard use case is to call Token from the handler
 // that handles requests to your application's redirect URL.
@@ -150,9 +207,14 @@ func (a Authenticator) Exchange(code string) (*oauth2.Token, error) {
 }
 
 // NewClient creates a Client that will use the specified access token for its API requests.
-func (a Authenticator) NewClient(token *oauth2.Token) Client {
-	client := a.config.Client(a.context, token)
-	return Client{
-		http: client,
+// The token is refreshed when it expires, if it has a refresh token; use
+// OnTokenRefreshed to save the new tokens.
+func (a Authenticator) NewClient(token *oauth2.Token, opts ...ClientOption) Client {
+	var base http.RoundTripper
+	if hc, ok := a.context.Value(oauth2.HTTPClient).(*http.Client); ok {
+		base = hc.Transport
 	}
+	var c Client
+	c = NewClient(a.newHTTPClient(a.context, base, token, nil, clientClock{&c}), opts...)
+	return c
 }
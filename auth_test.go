@@ -0,0 +1,41 @@
+package spotify
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestAuthURL(t *testing.T) {
+	a := NewAuthenticator("http://localhost/callback", ScopeUserTopRead, ScopeUserReadRecentlyPlayed)
+	a.SetAuthInfo("client-id", "secret")
+	u, err := url.Parse(a.AuthURLWithDialog("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "client-id" || q.Get("state") != "xyz" || q.Get("show_dialog") != "true" {
+		t.Errorf("Got query %v\n", q)
+	}
+	if scope := q.Get("scope"); scope != "user-top-read user-read-recently-played" {
+		t.Errorf("Got scope %q\n", scope)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	a := NewAuthenticator("http://localhost/callback", ScopeUserTopRead, ScopeUserReadRecentlyPlayed)
+	token := (&oauth2.Token{AccessToken: "abc"}).WithExtra(map[string]interface{}{
+		"scope": "user-top-read user-read-email",
+	})
+	if got, want := GrantedScopes(token), []string{ScopeUserTopRead, ScopeUserReadEmail}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got granted scopes %v, want %v\n", got, want)
+	}
+	if got := a.MissingScopes(token); !reflect.DeepEqual(got, []string{ScopeUserReadRecentlyPlayed}) {
+		t.Errorf("Got missing scopes %v\n", got)
+	}
+	if got := a.MissingScopes(&oauth2.Token{AccessToken: "abc"}); got != nil {
+		t.Errorf("Got missing scopes %v for a token that doesn't list its scopes\n", got)
+	}
+}
@@ -0,0 +1,123 @@
+package spotify
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+)
+
+// Availability describes where a track can be played.
+type Availability struct {
+	ID ID
+	// Playable maps each market checked to whether the track can be
+	// played there, either as is or through relinking.
+	Playable map[string]bool
+	// Relinked maps the markets in which the track is only playable as a
+	// different track (for example, the same recording released by a
+	// different label) to the ID of that track.
+	Relinked map[string]ID
+}
+
+// Markets returns the markets checked in which the track is playable.
+func (a *Availability) Markets() []string {
+	var markets []string
+	for m, ok := range a.Playable {
+		if ok {
+			markets = append(markets, m)
+		}
+	}
+	sort.Strings(markets)
+	return markets
+}
+
+// TrackAvailability reports in which of the given markets each track can
+// be played.  Tracks are first checked against their available markets;
+// tracks that aren't directly available in a market are then requested
+// for that market, so that track relinking is taken into account.  A track
+// that isn't found is reported as unplayable everywhere.  The result is in
+// the same order as ids.
+func (c *Client) TrackAvailability(ids []ID, markets ...string) ([]*Availability, error) {
+	return c.TrackAvailabilityWithContext(context.Background(), ids, markets...)
+}
+
+// TrackAvailabilityWithContext is like TrackAvailability, with a context.
+// If a request fails, the requests still running are canceled.
+func (c *Client) TrackAvailabilityWithContext(ctx context.Context, ids []ID, markets ...string) ([]*Availability, error) {
+	for _, m := range markets {
+		if _, err := ParseMarket(m); err != nil {
+			return nil, err
+		}
+	}
+	// The lists of available markets are left out when a market is given,
+	// so ignore the client's default market (see WithMarket).
+	all := *c
+	all.market = ""
+	tracks, err := all.GetTracksBatchWithContext(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Availability, len(ids))
+	// missing maps each market to the tracks not directly available there
+	missing := make(map[string][]int)
+	for i, t := range tracks {
+		a := &Availability{ID: ids[i], Playable: make(map[string]bool), Relinked: make(map[string]ID)}
+		result[i] = a
+		available := make(map[string]bool)
+		if t != nil {
+			for _, m := range t.AvailableMarkets {
+				available[m] = true
+			}
+		}
+		for _, m := range markets {
+			a.Playable[m] = available[m]
+			if !available[m] && t != nil {
+				missing[m] = append(missing[m], i)
+			}
+		}
+	}
+
+	// request the missing tracks for each market, 50 at a time
+	type job struct {
+		market string
+		idx    []int
+		tracks []*FullTrack
+	}
+	var jobs []*job
+	for _, m := range markets {
+		idx := missing[m]
+		for len(idx) > 0 {
+			n := len(idx)
+			if n > 50 {
+				n = 50
+			}
+			jobs = append(jobs, &job{market: m, idx: idx[:n]})
+			idx = idx[n:]
+		}
+	}
+	err = FanOut(ctx, len(jobs), c.concurrency, func(ctx context.Context, i int) error {
+		j := jobs[i]
+		chunk := make([]ID, len(j.idx))
+		for k, n := range j.idx {
+			chunk[k] = ids[n]
+		}
+		var err error
+		j.tracks, err = c.GetTracksWithContext(ctx, &Options{Country: &j.market}, chunk...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		for i, t := range j.tracks {
+			if t == nil || !t.IsPlayable {
+				continue
+			}
+			a := result[j.idx[i]]
+			a.Playable[j.market] = true
+			if t.ID != a.ID {
+				a.Relinked[j.market] = t.ID
+			}
+		}
+	}
+	return result, nil
+}
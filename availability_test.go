@@ -0,0 +1,74 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTrackAvailability(t *testing.T) {
+	const (
+		trackA   = "4iV5W9uYEdYUVa79Axb7Rh"
+		trackB   = "1301WleyT98MSxVHPZCA6M"
+		relinkB  = "6rqhFgbbKwnb9MLmUQDhG6"
+		notFound = "0eGsygTp906u18L0Oimnem"
+	)
+	var (
+		mu      sync.Mutex
+		markets []string
+	)
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		market := req.URL.Query().Get("market")
+		mu.Lock()
+		markets = append(markets, market)
+		mu.Unlock()
+		var body string
+		switch market {
+		case "":
+			body = `{"tracks":[
+				{"id":"` + trackA + `","available_markets":["US","GB"]},
+				{"id":"` + trackB + `","available_markets":["US"]},
+				null]}`
+		case "GB":
+			body = `{"tracks":[{"id":"` + trackB + `","is_playable":false}]}`
+		case "DE":
+			body = `{"tracks":[
+				{"id":"` + trackA + `","is_playable":false},
+				{"id":"` + relinkB + `","is_playable":true,"linked_from":{"id":"` + trackB + `"}}]}`
+		default:
+			t.Fatalf("Unexpected request %s\n", req.URL)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+
+	result, err := c.TrackAvailability([]ID{trackA, trackB, notFound}, "US", "GB", "DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the markets are requested concurrently, in any order
+	sort.Strings(markets)
+	if !reflect.DeepEqual(markets, []string{"", "DE", "GB"}) {
+		t.Error("Unexpected requests for markets", markets)
+	}
+	if got := result[0].Markets(); !reflect.DeepEqual(got, []string{"GB", "US"}) {
+		t.Error("Unexpected markets for track A:", got)
+	}
+	b := result[1]
+	if !b.Playable["DE"] || b.Playable["GB"] || b.Relinked["DE"] != relinkB || len(b.Relinked) != 1 {
+		t.Errorf("Unexpected availability for track B %+v\n", b)
+	}
+	if result[2].ID != notFound || len(result[2].Markets()) != 0 {
+		t.Errorf("Unexpected availability for missing track %+v\n", result[2])
+	}
+}
+
+func TestTrackAvailabilityBadMarket(t *testing.T) {
+	c := testClientString(http.StatusOK, `{"tracks":[]}`)
+	if _, err := c.TrackAvailability([]ID{"4iV5W9uYEdYUVa79Axb7Rh"}, "XX"); err == nil {
+		t.Error("Expected an error for an invalid market")
+	}
+}
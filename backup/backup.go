@@ -0,0 +1,257 @@
+// Package backup keeps versioned backups of playlists, so that earlier
+// versions can be compared with the current one and restored.
+package backup
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"golang.org/x/net/context"
+)
+
+// Version is a playlist as it was at a point in time.
+type Version struct {
+	PlaylistID  spotify.ID   `json:"playlist_id"`
+	OwnerID     string       `json:"owner_id"`
+	SnapshotID  string       `json:"snapshot_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Public      bool         `json:"public"`
+	Tracks      []spotify.ID `json:"tracks"`
+	TakenAt     time.Time    `json:"taken_at"`
+}
+
+// Store keeps the versions of each playlist.
+type Store interface {
+	// Save adds a new version.
+	Save(ctx context.Context, v *Version) error
+	// Versions returns the saved versions of a playlist, oldest first.
+	Versions(ctx context.Context, playlistID spotify.ID) ([]*Version, error)
+}
+
+// MemoryStore is a Store that keeps versions in memory.
+type MemoryStore struct {
+	mu       sync.Mutex
+	versions map[spotify.ID][]*Version
+}
+
+// Save implements Store.
+func (m *MemoryStore) Save(ctx context.Context, v *Version) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.versions == nil {
+		m.versions = make(map[spotify.ID][]*Version)
+	}
+	m.versions[v.PlaylistID] = append(m.versions[v.PlaylistID], v)
+	return nil
+}
+
+// Versions implements Store.
+func (m *MemoryStore) Versions(ctx context.Context, playlistID spotify.ID) ([]*Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Version(nil), m.versions[playlistID]...), nil
+}
+
+// ErrNoVersions is returned by Latest when a playlist has no backups.
+var ErrNoVersions = errors.New("backup: no versions of playlist")
+
+// Latest returns the most recent version of a playlist in store.
+func Latest(ctx context.Context, store Store, playlistID spotify.ID) (*Version, error) {
+	versions, err := store.Versions(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrNoVersions
+	}
+	return versions[len(versions)-1], nil
+}
+
+// PlaylistBackup takes backups of playlists.
+type PlaylistBackup struct {
+	Client spotify.SpotifyClient
+	Store  Store
+	// Clock timestamps versions and drives Run.  If nil,
+	// spotify.SystemClock is used.
+	Clock spotify.Clock
+}
+
+func (b *PlaylistBackup) clock() spotify.Clock {
+	if b.Clock == nil {
+		return spotify.SystemClock
+	}
+	return b.Clock
+}
+
+// Snapshot saves a new version of each playlist whose snapshot ID has
+// changed since its last backup, and returns the versions saved.
+func (b *PlaylistBackup) Snapshot(ctx context.Context, ownerID string, playlists ...spotify.ID) ([]*Version, error) {
+	var saved []*Version
+	for _, id := range playlists {
+		p, err := b.Client.GetPlaylistWithContext(ctx, ownerID, id, "")
+		if err != nil {
+			return saved, err
+		}
+		last, err := Latest(ctx, b.Store, id)
+		if err != nil && err != ErrNoVersions {
+			return saved, err
+		}
+		if last != nil && last.SnapshotID == p.SnapshotID {
+			continue
+		}
+		v := &Version{
+			PlaylistID:  id,
+			OwnerID:     ownerID,
+			SnapshotID:  p.SnapshotID,
+			Name:        p.Name,
+			Description: p.Description,
+			Public:      p.IsPublic,
+			TakenAt:     b.clock().Now(),
+		}
+		if v.Tracks, err = b.tracks(ctx, ownerID, id, p.Tracks); err != nil {
+			return saved, err
+		}
+		if err := b.Store.Save(ctx, v); err != nil {
+			return saved, err
+		}
+		saved = append(saved, v)
+	}
+	return saved, nil
+}
+
+// tracks returns the IDs of all the tracks in a playlist, starting from
+// the first page included with the playlist.
+func (b *PlaylistBackup) tracks(ctx context.Context, ownerID string, id spotify.ID, page spotify.PlaylistTrackPage) ([]spotify.ID, error) {
+	var ids []spotify.ID
+	limit := 100
+	for {
+		for _, t := range page.Tracks {
+			ids = append(ids, t.Track.ID)
+		}
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return ids, nil
+		}
+		offset := len(ids)
+		next, err := b.Client.GetPlaylistTracksWithContext(ctx, ownerID, id, &spotify.Options{Limit: &limit, Offset: &offset}, "")
+		if err != nil {
+			return nil, err
+		}
+		page = *next
+	}
+}
+
+// Run takes a snapshot of the playlists every interval, until stop is
+// closed.  Errors are passed to onError, if it isn't nil.  On App Engine,
+// call Snapshot from a cron handler instead.
+func (b *PlaylistBackup) Run(ctx context.Context, interval time.Duration, stop <-chan struct{},
+	onError func(error), ownerID string, playlists ...spotify.ID) {
+
+	for {
+		if _, err := b.Snapshot(ctx, ownerID, playlists...); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-b.clock().After(interval):
+		}
+	}
+}
+
+// Restore makes the playlist match v: its name, visibility, description
+// and tracks are replaced.
+func (b *PlaylistBackup) Restore(v *Version) error {
+	details := &spotify.PlaylistDetails{Name: &v.Name, Public: &v.Public, Description: &v.Description}
+	if err := b.Client.ChangePlaylistDetails(v.OwnerID, v.PlaylistID, details); err != nil {
+		return err
+	}
+	chunks := spotify.ChunkIDs(v.Tracks, 100)
+	if len(chunks) == 0 {
+		return b.Client.ReplacePlaylistTracks(v.OwnerID, v.PlaylistID)
+	}
+	if err := b.Client.ReplacePlaylistTracks(v.OwnerID, v.PlaylistID, chunks[0]...); err != nil {
+		return err
+	}
+	for _, chunk := range chunks[1:] {
+		if _, err := b.Client.AddTracksToPlaylist(v.OwnerID, v.PlaylistID, chunk...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Changes describes the differences between two versions of a playlist.
+type Changes struct {
+	Renamed        bool
+	AccessChanged  bool
+	Added, Removed []spotify.ID
+	// Reordered is true if the tracks the versions have in common are in
+	// a different order.
+	Reordered bool
+}
+
+// Empty reports whether there are no differences.
+func (c Changes) Empty() bool {
+	return !c.Renamed && !c.AccessChanged && len(c.Added) == 0 && len(c.Removed) == 0 && !c.Reordered
+}
+
+// Diff compares two versions of a playlist.  A track that appears more
+// often in to than in from is reported as added, and vice versa.
+func Diff(from, to *Version) Changes {
+	c := Changes{
+		Renamed:       from.Name != to.Name,
+		AccessChanged: from.Public != to.Public,
+	}
+	count := make(map[spotify.ID]int)
+	for _, id := range from.Tracks {
+		count[id]++
+	}
+	for _, id := range to.Tracks {
+		count[id]--
+	}
+	for _, id := range to.Tracks {
+		if count[id] < 0 {
+			c.Added = append(c.Added, id)
+			count[id]++
+		}
+	}
+	for _, id := range from.Tracks {
+		if count[id] > 0 {
+			c.Removed = append(c.Removed, id)
+			count[id]--
+		}
+	}
+	c.Reordered = !sameOrder(common(from.Tracks, to.Tracks), common(to.Tracks, from.Tracks))
+	return c
+}
+
+// common returns the tracks of a that also appear in b, in order.
+func common(a, b []spotify.ID) []spotify.ID {
+	in := make(map[spotify.ID]int)
+	for _, id := range b {
+		in[id]++
+	}
+	var ids []spotify.ID
+	for _, id := range a {
+		if in[id] > 0 {
+			ids = append(ids, id)
+			in[id]--
+		}
+	}
+	return ids
+}
+
+func sameOrder(a, b []spotify.ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
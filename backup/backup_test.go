@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+	"golang.org/x/net/context"
+)
+
+var (
+	trackA = spotify.ID("4iV5W9uYEdYUVa79Axb7Rh")
+	trackB = spotify.ID("1301WleyT98MSxVHPZCA6M")
+	trackC = spotify.ID("6rqhFgbbKwnb9MLmUQDhG6")
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	ctx := context.Background()
+
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Mix", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AddTracksToPlaylist(spotifytest.UserID, pl.ID, trackA, trackB)
+
+	b := &PlaylistBackup{Client: &c, Store: &MemoryStore{}}
+	if saved, err := b.Snapshot(ctx, spotifytest.UserID, pl.ID); err != nil || len(saved) != 1 {
+		t.Fatal("First snapshot failed", err)
+	}
+	if saved, _ := b.Snapshot(ctx, spotifytest.UserID, pl.ID); len(saved) != 0 {
+		t.Error("Unchanged playlist shouldn't be saved again")
+	}
+
+	c.RemoveTracksFromPlaylist(spotifytest.UserID, pl.ID, trackA)
+	c.AddTracksToPlaylist(spotifytest.UserID, pl.ID, trackC)
+	edited, description := "Mix (edited)", "Edited"
+	c.ChangePlaylistDetails(spotifytest.UserID, pl.ID, &spotify.PlaylistDetails{Name: &edited, Description: &description})
+	if _, err := b.Snapshot(ctx, spotifytest.UserID, pl.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, _ := b.Store.Versions(ctx, pl.ID)
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d\n", len(versions))
+	}
+	d := Diff(versions[0], versions[1])
+	if len(d.Added) != 1 || d.Added[0] != trackC || len(d.Removed) != 1 || d.Removed[0] != trackA || d.Reordered {
+		t.Errorf("Unexpected diff %+v\n", d)
+	}
+
+	if err := b.Restore(versions[0]); err != nil {
+		t.Fatal(err)
+	}
+	if tracks := f.PlaylistTracks(pl.ID); len(tracks) != 2 || tracks[0] != trackA || tracks[1] != trackB {
+		t.Error("Playlist wasn't restored:", tracks)
+	}
+	restored, _ := c.GetPlaylist(spotifytest.UserID, pl.ID)
+	if restored.Name != "Mix" {
+		t.Error("Name wasn't restored:", restored.Name)
+	}
+	if restored.Description != "" {
+		t.Error("Description wasn't restored:", restored.Description)
+	}
+}
+
+func TestDiffReordered(t *testing.T) {
+	from := &Version{Tracks: []spotify.ID{trackA, trackB, trackC}}
+	to := &Version{Tracks: []spotify.ID{trackB, trackA, trackC, trackC}}
+	d := Diff(from, to)
+	if !d.Reordered || len(d.Added) != 1 || d.Added[0] != trackC || len(d.Removed) != 0 {
+		t.Errorf("Unexpected diff %+v\n", d)
+	}
+	if !Diff(from, from).Empty() {
+		t.Error("Diff of a version with itself should be empty")
+	}
+}
@@ -0,0 +1,153 @@
+package spotify
+
+import (
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the most requests a client's batch methods make at
+// once, unless it's changed with WithConcurrency.
+const DefaultConcurrency = 4
+
+// WithConcurrency sets the most requests the client's batch methods (such
+// as GetTracksBatch) make at once.  The requests still go through the
+// client's transport, so a limit set with WithRateLimit applies to all of
+// them together.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// FanOut calls fn(ctx, i) for each i from 0 to n-1, with at most limit
+// calls running at once; if limit isn't positive, DefaultConcurrency is
+// used.  If a call returns an error, the context passed to the other calls
+// is canceled, calls that haven't started are skipped, and FanOut returns
+// the first error once the running calls have returned.  If ctx is canceled
+// first, FanOut returns ctx.Err().
+func FanOut(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, limit)
+start:
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-gctx.Done():
+			break start
+		}
+		i := i
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return fn(gctx, i)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// batch splits ids into chunks of at most size and calls fn for each,
+// concurrently.  start is the index in ids of the first ID in the chunk.
+// fn should make its request with the ctx it's given, which is canceled if
+// ctx is or if another chunk fails.
+func (c *Client) batch(ctx context.Context, ids []ID, size int, fn func(ctx context.Context, chunk []ID, start int) error) error {
+	if err := ValidateIDs(ids...); err != nil {
+		return err
+	}
+	chunks := ChunkIDs(ids, size)
+	return FanOut(ctx, len(chunks), c.concurrency, func(ctx context.Context, i int) error {
+		return fn(ctx, chunks[i], i*size)
+	})
+}
+
+// GetTracksBatch is like GetTracks, but accepts any number of IDs.  It
+// makes one request for every 50 tracks, several at a time (see
+// WithConcurrency).
+func (c *Client) GetTracksBatch(ids ...ID) ([]*FullTrack, error) {
+	return c.GetTracksBatchWithContext(context.Background(), ids...)
+}
+
+// GetTracksBatchWithContext is like GetTracksBatch, with a context.  If a
+// request fails, the requests still running are canceled.
+func (c *Client) GetTracksBatchWithContext(ctx context.Context, ids ...ID) ([]*FullTrack, error) {
+	result := make([]*FullTrack, len(ids))
+	err := c.batch(ctx, ids, 50, func(ctx context.Context, chunk []ID, start int) error {
+		tracks, err := c.GetTracksWithContext(ctx, nil, chunk...)
+		copy(result[start:], tracks)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetArtistsBatch is like GetArtists, but accepts any number of IDs.  It
+// makes one request for every 50 artists, several at a time (see
+// WithConcurrency).
+func (c *Client) GetArtistsBatch(ids ...ID) ([]*FullArtist, error) {
+	return c.GetArtistsBatchWithContext(context.Background(), ids...)
+}
+
+// GetArtistsBatchWithContext is like GetArtistsBatch, with a context.  If
+// a request fails, the requests still running are canceled.
+func (c *Client) GetArtistsBatchWithContext(ctx context.Context, ids ...ID) ([]*FullArtist, error) {
+	result := make([]*FullArtist, len(ids))
+	err := c.batch(ctx, ids, 50, func(ctx context.Context, chunk []ID, start int) error {
+		artists, err := c.GetArtistsWithContext(ctx, chunk...)
+		copy(result[start:], artists)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAlbumsBatch is like GetAlbums, but accepts any number of IDs.  It
+// makes one request for every 20 albums, several at a time (see
+// WithConcurrency).
+func (c *Client) GetAlbumsBatch(ids ...ID) ([]*FullAlbum, error) {
+	return c.GetAlbumsBatchWithContext(context.Background(), ids...)
+}
+
+// GetAlbumsBatchWithContext is like GetAlbumsBatch, with a context.  If a
+// request fails, the requests still running are canceled.
+func (c *Client) GetAlbumsBatchWithContext(ctx context.Context, ids ...ID) ([]*FullAlbum, error) {
+	result := make([]*FullAlbum, len(ids))
+	err := c.batch(ctx, ids, 20, func(ctx context.Context, chunk []ID, start int) error {
+		albums, err := c.GetAlbumsWithContext(ctx, chunk...)
+		copy(result[start:], albums)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAudioFeaturesBatch is like GetAudioFeatures, but accepts any number
+// of IDs.  It makes one request for every 100 tracks, several at a time
+// (see WithConcurrency).
+func (c *Client) GetAudioFeaturesBatch(ids ...ID) ([]*AudioFeatures, error) {
+	return c.GetAudioFeaturesBatchWithContext(context.Background(), ids...)
+}
+
+// GetAudioFeaturesBatchWithContext is like GetAudioFeaturesBatch, with a
+// context.  If a request fails, the requests still running are canceled.
+func (c *Client) GetAudioFeaturesBatchWithContext(ctx context.Context, ids ...ID) ([]*AudioFeatures, error) {
+	result := make([]*AudioFeatures, len(ids))
+	err := c.batch(ctx, ids, maxAudioFeatures, func(ctx context.Context, chunk []ID, start int) error {
+		features, err := c.GetAudioFeaturesWithContext(ctx, chunk...)
+		copy(result[start:], features)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
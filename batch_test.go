@@ -0,0 +1,134 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestFanOutLimit(t *testing.T) {
+	var running, max int32
+	err := FanOut(context.Background(), 20, 3, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max > 3 {
+		t.Errorf("Expected at most 3 calls at once, got %d\n", max)
+	}
+}
+
+func TestFanOutError(t *testing.T) {
+	fail := errors.New("fail")
+	var calls int32
+	err := FanOut(context.Background(), 100, 1, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return fail
+		}
+		return nil
+	})
+	if err != fail {
+		t.Errorf("Expected the call's error, got %v\n", err)
+	}
+	if calls == 100 {
+		t.Error("Expected calls after the error to be skipped")
+	}
+}
+
+func TestFanOutCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := FanOut(ctx, 10, 1, func(ctx context.Context, i int) error { return nil })
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+}
+
+func TestGetTracksBatch(t *testing.T) {
+	var requests int32
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		tracks := make([]string, len(ids))
+		for i, id := range ids {
+			tracks[i] = fmt.Sprintf(`{"id":"%s"}`, id)
+		}
+		body := `{"tracks":[` + strings.Join(tracks, ",") + `]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})}, WithConcurrency(2))
+
+	ids := make([]ID, 120)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("%022d", i))
+	}
+	tracks, err := c.GetTracksBatch(ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d\n", requests)
+	}
+	for i, track := range tracks {
+		if track == nil || track.ID != ids[i] {
+			t.Fatalf("Track %d: got %v, want %s\n", i, track, ids[i])
+		}
+	}
+}
+
+func TestGetTracksBatchInvalidID(t *testing.T) {
+	client := testClientString(http.StatusOK, "")
+	if _, err := client.GetTracksBatch(ID("0eGsygTp906u18L0Oimnem"), ID("nope")); err == nil {
+		t.Error("Expected an error for an invalid ID")
+	}
+	if getLastRequest(client) != nil {
+		t.Error("Request shouldn't be sent for invalid IDs")
+	}
+}
+
+func TestGetTracksBatchCancelsOnError(t *testing.T) {
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Query().Get("ids"), "0000") {
+			body := `{"error":{"status":400,"message":"invalid id"}}`
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		}
+		// the other chunk waits until it's canceled
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})}, WithConcurrency(2))
+
+	ids := make([]ID, 100)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("%04d%018d", i/50, i))
+	}
+	done := make(chan error)
+	go func() {
+		_, err := c.GetTracksBatchWithContext(context.Background(), ids...)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if e, ok := err.(*Error); !ok || e.Status != http.StatusBadRequest {
+			t.Errorf("Expected the failed chunk's error, got %v\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The running request wasn't canceled")
+	}
+}
@@ -0,0 +1,185 @@
+package spotify
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultBatchWait is how long a Batcher created with a zero wait collects
+// calls before sending them.
+const DefaultBatchWait = 5 * time.Millisecond
+
+// ErrNotFound is returned by a Batcher when the Web API has nothing for the
+// ID that was asked for.
+var ErrNotFound = errors.New("spotify: not found")
+
+// Batcher turns lookups of single items, made from many goroutines, into
+// requests to the batch endpoints.  Each call waits up to the Batcher's
+// wait for other calls to join it, and is then sent along with them in one
+// request, so code that looks up one track per row (for instance, once per
+// item in a template) uses far less of the rate limit.  A batch is sent
+// early once it's as large as the endpoint allows.  A Batcher is safe for
+// concurrent use.
+type Batcher struct {
+	tracks   *batchQueue
+	artists  *batchQueue
+	features *batchQueue
+}
+
+// NewBatcher returns a Batcher that sends its batches with c, after
+// collecting calls for wait (or DefaultBatchWait if wait is zero).  wait is
+// measured with the client's Clock.
+func NewBatcher(c *Client, wait time.Duration) *Batcher {
+	if wait == 0 {
+		wait = DefaultBatchWait
+	}
+	newQueue := func(max int, fetch func(ids []ID) ([]interface{}, error)) *batchQueue {
+		return &batchQueue{clock: c.getClock(), wait: wait, max: max, fetch: fetch}
+	}
+	return &Batcher{
+		tracks: newQueue(50, func(ids []ID) ([]interface{}, error) {
+			tracks, err := c.GetTracks(ids...)
+			result := make([]interface{}, len(tracks))
+			for i, t := range tracks {
+				if t != nil {
+					result[i] = t
+				}
+			}
+			return result, err
+		}),
+		artists: newQueue(50, func(ids []ID) ([]interface{}, error) {
+			artists, err := c.GetArtists(ids...)
+			result := make([]interface{}, len(artists))
+			for i, a := range artists {
+				if a != nil {
+					result[i] = a
+				}
+			}
+			return result, err
+		}),
+		features: newQueue(100, func(ids []ID) ([]interface{}, error) {
+			features, err := c.GetAudioFeatures(ids...)
+			result := make([]interface{}, len(features))
+			for i, f := range features {
+				if f != nil {
+					result[i] = f
+				}
+			}
+			return result, err
+		}),
+	}
+}
+
+// GetTrack is like Client.GetTrack, but the track is requested along with
+// others in a batch.
+func (b *Batcher) GetTrack(id ID) (*FullTrack, error) {
+	v, err := b.tracks.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FullTrack), nil
+}
+
+// GetArtist is like Client.GetArtist, but the artist is requested along
+// with others in a batch.
+func (b *Batcher) GetArtist(id ID) (*FullArtist, error) {
+	v, err := b.artists.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FullArtist), nil
+}
+
+// GetAudioFeatures gets the audio features of one track, requesting them
+// along with others in a batch.
+func (b *Batcher) GetAudioFeatures(id ID) (*AudioFeatures, error) {
+	v, err := b.features.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*AudioFeatures), nil
+}
+
+// batchQueue collects the calls for one batch endpoint.
+type batchQueue struct {
+	clock Clock
+	wait  time.Duration
+	max   int
+	// fetch requests the items with the IDs, returning them in the same
+	// order, with nil for items that weren't found.
+	fetch func(ids []ID) ([]interface{}, error)
+
+	mu      sync.Mutex
+	pending []batchCall
+	// gen counts the batches sent, so that the timer of a batch that was
+	// sent early because it was full doesn't send the next one.
+	gen int
+}
+
+type batchCall struct {
+	id   ID
+	done chan batchResult
+}
+
+type batchResult struct {
+	v   interface{}
+	err error
+}
+
+// get adds a call for id to the pending batch and waits for its result.
+func (q *batchQueue) get(id ID) (interface{}, error) {
+	if err := ValidateIDs(id); err != nil {
+		return nil, err
+	}
+	call := batchCall{id: id, done: make(chan batchResult, 1)}
+	q.mu.Lock()
+	q.pending = append(q.pending, call)
+	switch len(q.pending) {
+	case q.max:
+		go q.send(q.take())
+	case 1:
+		gen := q.gen
+		go func() {
+			<-q.clock.After(q.wait)
+			q.mu.Lock()
+			if q.gen != gen {
+				q.mu.Unlock()
+				return
+			}
+			calls := q.take()
+			q.mu.Unlock()
+			q.send(calls)
+		}()
+	}
+	q.mu.Unlock()
+	r := <-call.done
+	return r.v, r.err
+}
+
+// take removes and returns the pending calls.  q.mu must be held.
+func (q *batchQueue) take() []batchCall {
+	calls := q.pending
+	q.pending = nil
+	q.gen++
+	return calls
+}
+
+// send requests the items for calls and gives each call its result.
+func (q *batchQueue) send(calls []batchCall) {
+	ids := make([]ID, len(calls))
+	for i, call := range calls {
+		ids[i] = call.id
+	}
+	items, err := q.fetch(ids)
+	for i, call := range calls {
+		switch {
+		case err != nil:
+			call.done <- batchResult{err: err}
+		case i >= len(items) || items[i] == nil:
+			call.done <- batchResult{err: ErrNotFound}
+		default:
+			call.done <- batchResult{v: items[i]}
+		}
+	}
+}
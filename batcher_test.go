@@ -0,0 +1,121 @@
+package spotify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock whose After channels fire when the test sends on
+// fire.
+type manualClock struct {
+	systemClock
+	fire chan time.Time
+}
+
+func (c manualClock) After(d time.Duration) <-chan time.Time { return c.fire }
+
+func TestBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		mu.Lock()
+		requests = append(requests, req.URL.Query().Get("ids"))
+		mu.Unlock()
+		var tracks []string
+		for _, id := range ids {
+			if id == "0000000000000000000003" {
+				tracks = append(tracks, "null")
+				continue
+			}
+			tracks = append(tracks, fmt.Sprintf(`{"id": %q}`, id))
+		}
+		body := `{"tracks": [` + strings.Join(tracks, ",") + `]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})
+	clock := manualClock{fire: make(chan time.Time)}
+	c := NewClient(&http.Client{Transport: tr}, WithClock(clock))
+	b := NewBatcher(&c, time.Millisecond)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := ID(fmt.Sprintf("%022d", i))
+			track, err := b.GetTrack(id)
+			if err == nil && track.ID != id {
+				err = fmt.Errorf("got track %s", track.ID)
+			}
+			errs[i] = err
+		}(i)
+	}
+	// Let the calls join the batch before its timer fires.
+	for {
+		b.tracks.mu.Lock()
+		n := len(b.tracks.pending)
+		b.tracks.mu.Unlock()
+		if n == len(errs) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	clock.fire <- time.Now()
+	wg.Wait()
+
+	for i, err := range errs {
+		if i == 3 {
+			if err != ErrNotFound {
+				t.Errorf("got error %v for a missing track, want ErrNotFound", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("track %d: %v", i, err)
+		}
+	}
+	if len(requests) != 1 {
+		t.Errorf("got %d requests, want 1: %v", len(requests), requests)
+	}
+}
+
+func TestBatcherFull(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		mu.Unlock()
+		features := make([]string, len(ids))
+		for i, id := range ids {
+			features[i] = fmt.Sprintf(`{"id": %q}`, id)
+		}
+		body := `{"audio_features": [` + strings.Join(features, ",") + `]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})
+	// The timer never fires, so only a full batch is sent.
+	c := NewClient(&http.Client{Transport: tr}, WithClock(manualClock{}))
+	b := NewBatcher(&c, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := b.GetAudioFeatures(ID(fmt.Sprintf("%022d", i))); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(sizes) != 1 || sizes[0] != 100 {
+		t.Errorf("got batches of %v, want one of 100", sizes)
+	}
+}
@@ -0,0 +1,121 @@
+package spotify
+
+import "testing"
+
+// The benchmarks in this file and the decode benchmarks elsewhere measure
+// the JSON layer.  To check a change for regressions, run them before and
+// after it and compare the results with benchstat:
+//     go test -run NONE -bench . -benchmem -count 10 > old.txt
+//     (make the change)
+//     go test -run NONE -bench . -benchmem -count 10 > new.txt
+//     benchstat old.txt new.txt
+// TestAllocationBudgets fails outright if decoding a payload allocates much
+// more than it used to.
+
+func BenchmarkDecodeTopTracks(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "top_tracks.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CurrentUserTopTracks(nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTopArtists(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "top_artists.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CurrentUserTopArtists(nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodePlaylistTracks(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "playlist_track_page.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetPlaylistTracks("user", "playlist"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSearch(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "search_result.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Search("holiday", SearchTypeTrack|SearchTypeArtist); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRequestTopTracks measures building and sending a request, with a
+// response that takes no time to decode.
+func BenchmarkRequestTopTracks(b *testing.B) {
+	c := benchmarkClient([]byte("{}"))
+	limit, offset, timeRange := 50, 50, "short"
+	opt := &Options{Limit: &limit, Offset: &offset, Timerange: &timeRange}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CurrentUserTopTracks(opt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRequestSearch(b *testing.B) {
+	c := benchmarkClient([]byte("{}"))
+	country, limit := CountryUSA, 20
+	opt := &Options{Country: &country, Limit: &limit}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.SearchOpt("album:gold artist:abba", SearchTypeAlbum, opt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// allocationBudgets are the most allocations that decoding each payload may
+// take, with about a fifth of headroom over what it takes now.  If a change makes a test
+// fail, check whether the extra allocations are worth it before raising the
+// budget.
+var allocationBudgets = []struct {
+	golden string
+	call   func(c Client) error
+	allocs float64
+}{
+	{"audio_analysis.json", func(c Client) error { _, err := c.GetAudioAnalysis("foo"); return err }, 45},
+	{"play_history.json", func(c Client) error { _, err := c.CurrentUserRecentTracks(50); return err }, 65},
+	{"playlist_track_page.json", func(c Client) error { _, err := c.GetPlaylistTracks("user", "playlist"); return err }, 310},
+	{"top_artists.json", func(c Client) error { _, err := c.CurrentUserTopArtists(nil); return err }, 45},
+	{"top_tracks.json", func(c Client) error { _, err := c.CurrentUserTopTracks(nil); return err }, 95},
+	{"track_full.json", func(c Client) error { _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); return err }, 160},
+}
+
+func TestAllocationBudgets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budgets in short mode")
+	}
+	if raceEnabled {
+		t.Skip("skipping allocation budgets under the race detector")
+	}
+	for _, b := range allocationBudgets {
+		c := benchmarkClient(readGolden(t, b.golden))
+		var err error
+		allocs := testing.AllocsPerRun(20, func() {
+			if e := b.call(c); e != nil {
+				err = e
+			}
+		})
+		if err != nil {
+			t.Errorf("%s: %v", b.golden, err)
+			continue
+		}
+		if allocs > b.allocs {
+			t.Errorf("%s: decoding took %.0f allocations, over the budget of %.0f", b.golden, allocs, b.allocs)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package blend compares the musical taste of two users and builds a
+// playlist that mixes their favourite tracks, favouring what they have in
+// common.
+package blend
+
+import (
+	"math"
+	"sort"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Weights of each component of the similarity score.  They sum to 1.
+const (
+	artistWeight = 0.4
+	genreWeight  = 0.4
+	trackWeight  = 0.2
+)
+
+// Profile is a user's top tracks and artists, most listened to first.
+type Profile struct {
+	Tracks  []spotify.TrackItem
+	Artists []spotify.ArtistItem
+}
+
+// Fetch gets the top 50 tracks and artists of the user the client is
+// authorized for.  The time range is one of "short_term", "medium_term"
+// or "long_term", or empty for Spotify's default.  It requires the
+// ScopeUserTopRead scope.
+func Fetch(c spotify.SpotifyClient, timeRange string) (*Profile, error) {
+	limit := 50
+	opt := &spotify.Options{Limit: &limit}
+	if timeRange != "" {
+		opt.Timerange = &timeRange
+	}
+	tracks, err := c.CurrentUserTopTracks(opt)
+	if err != nil {
+		return nil, err
+	}
+	artists, err := c.CurrentUserTopArtists(opt)
+	if err != nil {
+		return nil, err
+	}
+	return &Profile{Tracks: tracks.Items, Artists: artists.Items}, nil
+}
+
+// rankWeight gives each item of a ranked list a weight between 0 and 1,
+// decreasing linearly from 1 for the first item.
+func rankWeight(i, n int) float64 {
+	return 1 - float64(i)/float64(n)
+}
+
+func (p *Profile) trackWeights() map[string]float64 {
+	w := make(map[string]float64)
+	for i, t := range p.Tracks {
+		w[string(t.ID)] = rankWeight(i, len(p.Tracks))
+	}
+	return w
+}
+
+func (p *Profile) artistWeights() map[string]float64 {
+	w := make(map[string]float64)
+	for i, a := range p.Artists {
+		w[string(a.ID)] = rankWeight(i, len(p.Artists))
+	}
+	return w
+}
+
+// genreWeights scores each genre by the weights of the user's top artists
+// in it, scaled so the top genre has a weight of 1.
+func (p *Profile) genreWeights() map[string]float64 {
+	w := make(map[string]float64)
+	var max float64
+	for i, a := range p.Artists {
+		for _, g := range a.Genres {
+			w[g] += rankWeight(i, len(p.Artists))
+			max = math.Max(max, w[g])
+		}
+	}
+	for g := range w {
+		w[g] /= max
+	}
+	return w
+}
+
+// Shared is something both users like.  Affinity, between 0 and 1, is
+// how much they both like it: the geometric mean of their weights.
+type Shared struct {
+	ID       spotify.ID `json:"id,omitempty"`
+	Name     string     `json:"name"`
+	Affinity float64    `json:"affinity"`
+}
+
+// Comparison describes how similar two users' tastes are.
+type Comparison struct {
+	Artists []Shared `json:"artists"`
+	Tracks  []Shared `json:"tracks"`
+	Genres  []Shared `json:"genres"`
+	// The scores are between 0 (nothing in common) and 1 (identical).
+	ArtistScore float64 `json:"artist_score"`
+	TrackScore  float64 `json:"track_score"`
+	GenreScore  float64 `json:"genre_score"`
+	Score       float64 `json:"score"`
+}
+
+// Compare compares two profiles.
+func Compare(a, b *Profile) *Comparison {
+	c := &Comparison{}
+	names := make(map[string]string)
+	for _, t := range append(append([]spotify.TrackItem(nil), a.Tracks...), b.Tracks...) {
+		names[string(t.ID)] = t.Name
+	}
+	c.Tracks, c.TrackScore = overlap(a.trackWeights(), b.trackWeights(), names, true)
+	for _, ar := range append(append([]spotify.ArtistItem(nil), a.Artists...), b.Artists...) {
+		names[string(ar.ID)] = ar.Name
+	}
+	c.Artists, c.ArtistScore = overlap(a.artistWeights(), b.artistWeights(), names, true)
+	c.Genres, c.GenreScore = overlap(a.genreWeights(), b.genreWeights(), nil, false)
+	c.Score = artistWeight*c.ArtistScore + genreWeight*c.GenreScore + trackWeight*c.TrackScore
+	return c
+}
+
+// overlap returns the keys in both a and b, with the most shared first,
+// and their weighted Jaccard similarity.
+func overlap(a, b map[string]float64, names map[string]string, ids bool) ([]Shared, float64) {
+	var shared []Shared
+	var inter, union float64
+	for k, wa := range a {
+		wb := b[k]
+		inter += math.Min(wa, wb)
+		union += math.Max(wa, wb)
+		if wb > 0 {
+			s := Shared{Name: k, Affinity: math.Sqrt(wa * wb)}
+			if ids {
+				s.ID = spotify.ID(k)
+				s.Name = names[k]
+			}
+			shared = append(shared, s)
+		}
+	}
+	for k, wb := range b {
+		if _, ok := a[k]; !ok {
+			union += wb
+		}
+	}
+	sort.Sort(byAffinity(shared))
+	if union == 0 {
+		return shared, 0
+	}
+	return shared, inter / union
+}
+
+type byAffinity []Shared
+
+func (s byAffinity) Len() int      { return len(s) }
+func (s byAffinity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byAffinity) Less(i, j int) bool {
+	if s[i].Affinity != s[j].Affinity {
+		return s[i].Affinity > s[j].Affinity
+	}
+	return s[i].Name < s[j].Name
+}
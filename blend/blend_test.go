@@ -0,0 +1,83 @@
+package blend
+
+import (
+	"math"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func artist(id string, genres ...string) spotify.ArtistItem {
+	return spotify.ArtistItem{ID: spotify.ID(id), Name: "Artist " + id, Genres: genres}
+}
+
+func track(id, artist string) spotify.TrackItem {
+	return spotify.TrackItem{ID: spotify.ID(id), Name: "Track " + id, Artists: []spotify.ArtistInfo{{ID: spotify.ID(artist)}}}
+}
+
+var (
+	alice = &Profile{
+		Artists: []spotify.ArtistItem{artist("x", "rock", "indie"), artist("y", "jazz")},
+		Tracks:  []spotify.TrackItem{track("1", "y"), track("2", "x"), track("3", "x")},
+	}
+	bob = &Profile{
+		Artists: []spotify.ArtistItem{artist("z", "rock"), artist("x", "rock", "indie")},
+		Tracks:  []spotify.TrackItem{track("4", "z"), track("3", "x"), track("5", "z")},
+	}
+)
+
+func TestCompare(t *testing.T) {
+	c := Compare(alice, bob)
+	if len(c.Artists) != 1 || c.Artists[0].ID != "x" || c.Artists[0].Name != "Artist x" {
+		t.Errorf("Unexpected shared artists %+v\n", c.Artists)
+	}
+	if len(c.Tracks) != 1 || c.Tracks[0].ID != "3" {
+		t.Errorf("Unexpected shared tracks %+v\n", c.Tracks)
+	}
+	if len(c.Genres) != 2 || c.Genres[0].Name != "rock" {
+		t.Errorf("Unexpected shared genres %+v\n", c.Genres)
+	}
+	if c.Score <= 0 || c.Score >= 1 {
+		t.Errorf("Unexpected score %g\n", c.Score)
+	}
+	if same := Compare(alice, alice); math.Abs(same.Score-1) > 1e-9 {
+		t.Errorf("Identical profiles should score 1, got %g\n", same.Score)
+	}
+	if none := Compare(alice, &Profile{}); none.Score != 0 {
+		t.Errorf("Expected a score of 0, got %g\n", none.Score)
+	}
+}
+
+func TestTracks(t *testing.T) {
+	c := Compare(alice, bob)
+	ids := Tracks(alice, bob, c, 4)
+	if len(ids) != 4 {
+		t.Fatalf("Expected 4 tracks, got %v\n", ids)
+	}
+	// alice's track by a shared artist in a shared genre beats her own
+	// favourite, and the shared track is bob's best
+	want := []spotify.ID{"2", "3", "1", "4"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("Got %v, want %v\n", ids, want)
+		}
+	}
+	if all := Tracks(alice, bob, c, 100); len(all) != 5 {
+		t.Errorf("Expected all 5 tracks, got %v\n", all)
+	}
+}
+
+func TestSave(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	ids := []spotify.ID{"4iV5W9uYEdYUVa79Axb7Rh", "1301WleyT98MSxVHPZCA6M"}
+	pl, err := Save(&c, spotifytest.UserID, "Blend", false, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.PlaylistTracks(pl.ID); len(got) != 2 {
+		t.Error("Unexpected tracks:", got)
+	}
+}
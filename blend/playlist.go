@@ -0,0 +1,120 @@
+package blend
+
+import (
+	"sort"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// candidate is a track and how strongly it belongs in the blend.
+type candidate struct {
+	id    spotify.ID
+	score float64
+}
+
+// candidates scores the top tracks of p.  A track's score is its weight in
+// p, boosted if the other user also likes the track, its artists or their
+// genres.
+func candidates(p *Profile, cmp *Comparison) []candidate {
+	tracks := make(map[spotify.ID]float64)
+	for _, s := range cmp.Tracks {
+		tracks[s.ID] = s.Affinity
+	}
+	artists := make(map[spotify.ID]float64)
+	for _, s := range cmp.Artists {
+		artists[s.ID] = s.Affinity
+	}
+	genres := make(map[string]float64)
+	for _, s := range cmp.Genres {
+		genres[s.Name] = s.Affinity
+	}
+	artistGenres := make(map[spotify.ID][]string)
+	for _, a := range p.Artists {
+		artistGenres[a.ID] = a.Genres
+	}
+
+	var cs []candidate
+	for i, t := range p.Tracks {
+		if t.ID == "" {
+			continue
+		}
+		var artist, genre float64
+		for _, a := range t.Artists {
+			if artists[a.ID] > artist {
+				artist = artists[a.ID]
+			}
+			for _, g := range artistGenres[a.ID] {
+				if genres[g] > genre {
+					genre = genres[g]
+				}
+			}
+		}
+		boost := 1 + 2*tracks[t.ID] + artist + genre
+		cs = append(cs, candidate{id: t.ID, score: rankWeight(i, len(p.Tracks)) * boost})
+	}
+	sort.Stable(byScore(cs))
+	return cs
+}
+
+type byScore []candidate
+
+func (c byScore) Len() int           { return len(c) }
+func (c byScore) Less(i, j int) bool { return c[i].score > c[j].score }
+func (c byScore) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// Tracks chooses up to size tracks for a blended playlist.  Tracks are
+// taken alternately from each user, best first, so both users are equally
+// represented; tracks, artists and genres they share are ranked higher.
+func Tracks(a, b *Profile, cmp *Comparison, size int) []spotify.ID {
+	lists := [2][]candidate{candidates(a, cmp), candidates(b, cmp)}
+	seen := make(map[spotify.ID]bool)
+	var ids []spotify.ID
+	for turn := 0; len(ids) < size && (len(lists[0]) > 0 || len(lists[1]) > 0); turn = 1 - turn {
+		for len(lists[turn]) > 0 {
+			c := lists[turn][0]
+			lists[turn] = lists[turn][1:]
+			if !seen[c.id] {
+				seen[c.id] = true
+				ids = append(ids, c.id)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// Save creates a playlist for userID containing the tracks.  It requires
+// the ScopePlaylistModifyPublic or ScopePlaylistModifyPrivate scope.
+func Save(c spotify.SpotifyClient, userID, name string, public bool, tracks []spotify.ID) (*spotify.FullPlaylist, error) {
+	pl, err := c.CreatePlaylistForUser(userID, name, public)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range spotify.ChunkIDs(tracks, 100) {
+		if _, err := c.AddTracksToPlaylist(userID, pl.ID, chunk...); err != nil {
+			return nil, err
+		}
+	}
+	return pl, nil
+}
+
+// Result is the outcome of blending two users' tastes.
+type Result struct {
+	*Comparison
+	Tracks []spotify.ID `json:"tracks"`
+}
+
+// Mix fetches the top items of the users the two clients are authorized
+// for, compares them and chooses up to size tracks for a blended playlist.
+func Mix(a, b spotify.SpotifyClient, timeRange string, size int) (*Result, error) {
+	pa, err := Fetch(a, timeRange)
+	if err != nil {
+		return nil, err
+	}
+	pb, err := Fetch(b, timeRange)
+	if err != nil {
+		return nil, err
+	}
+	cmp := Compare(pa, pb)
+	return &Result{Comparison: cmp, Tracks: Tracks(pa, pb, cmp, size)}, nil
+}
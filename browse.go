@@ -0,0 +1,172 @@
+package spotify
+
+import (
+	"golang.org/x/net/context"
+)
+
+// This file contains the endpoints of Spotify's "Browse" tab: featured
+// playlists and new releases.  The categories are in category.go.  The
+// results of each are wrapped in an object in the response, so their pages
+// are followed with the Next*Page methods here rather than with getPage.
+
+// LocalTimestampLayout is the format of PlaylistOptions.Timestamp: an ISO
+// 8601 timestamp without a time zone, in the user's local time.  For
+// example, to get the playlists featured for a user in Berlin now:
+//
+//    loc, _ := time.LoadLocation("Europe/Berlin")
+//    ts := time.Now().In(loc).Format(spotify.LocalTimestampLayout)
+//    opt := &spotify.PlaylistOptions{Timestamp: &ts}
+const LocalTimestampLayout = "2006-01-02T15:04:05"
+
+// PlaylistOptions contains optional parameters that can be used when querying
+// for featured playlists.  Only the non-nil fields are used in the request.
+type PlaylistOptions struct {
+	Options
+	// The desired language, consisting of a lowercase IO 639
+	// language code and an uppercase ISO 3166-1 alpha-2
+	// country code, joined by an underscore.  Provide this
+	// parameter if you want the results returned in a particular
+	// language.  If not specified, the result will be returned
+	// in the Spotify default language (American English).
+	Locale *string
+	// A timestamp in ISO 8601 format (yyyy-MM-ddTHH:mm:ss).
+	// use this paramter to specify the user's local time to
+	// get results tailored for that specific date and time
+	// in the day.  If not provided, the response defaults to
+	// the current UTC time.  See LocalTimestampLayout.
+	Timestamp *string
+}
+
+// FeaturedPlaylistsOpt gets a list of playlists featured by Spotify.
+// It accepts a number of optional parameters via the opt argument.
+// This call requires authorization.
+func (c *Client) FeaturedPlaylistsOpt(opt *PlaylistOptions) (message string, playlists *SimplePlaylistPage, e error) {
+	return c.FeaturedPlaylistsWithContext(context.Background(), opt)
+}
+
+// FeaturedPlaylistsWithContext is like FeaturedPlaylistsOpt, with a
+// context.
+func (c *Client) FeaturedPlaylistsWithContext(ctx context.Context, opt *PlaylistOptions) (message string, playlists *SimplePlaylistPage, e error) {
+	u := c.endpoint("browse/featured-playlists")
+	if opt != nil {
+		if opt.Locale != nil {
+			u.set("locale", *opt.Locale)
+		}
+		if err := u.setCountry("country", opt.Country); err != nil {
+			return "", nil, err
+		}
+		if opt.Timestamp != nil {
+			u.set("timestamp", *opt.Timestamp)
+		}
+		u.setPaging(&opt.Options)
+	}
+	var result struct {
+		Playlists SimplePlaylistPage `json:"playlists"`
+		Message   string             `json:"message"`
+	}
+	if err := c.get(ctx, u.String(), &result); err != nil {
+		return "", nil, err
+	}
+	return result.Message, &result.Playlists, nil
+}
+
+// FeaturedPlaylists gets a list of playlists featured by Spotify.
+// It is equivalent to c.FeaturedPlaylistsOpt(nil).
+func (c *Client) FeaturedPlaylists() (message string, playlists *SimplePlaylistPage, e error) {
+	return c.FeaturedPlaylistsWithContext(context.Background(), nil)
+}
+
+// NewReleasesOpt is like NewReleases, but it accepts optional parameters
+// for filtering the results.
+func (c *Client) NewReleasesOpt(opt *Options) (albums *SimpleAlbumPage, err error) {
+	return c.NewReleasesWithContext(context.Background(), opt)
+}
+
+// NewReleasesWithContext is like NewReleasesOpt, with a context.
+func (c *Client) NewReleasesWithContext(ctx context.Context, opt *Options) (albums *SimpleAlbumPage, err error) {
+	e := c.endpoint("browse/new-releases")
+	if opt != nil {
+		if err := e.setCountry("country", opt.Country); err != nil {
+			return nil, err
+		}
+		e.setPaging(opt)
+	}
+	var result struct {
+		Albums SimpleAlbumPage `json:"albums"`
+	}
+	if err := c.getPage(ctx, e.String(), &result); err != nil {
+		return nil, err
+	}
+	return &result.Albums, nil
+}
+
+// NewReleases gets a list of new album releases featured in Spotify.
+// This call requires bearer authorization.
+func (c *Client) NewReleases() (albums *SimpleAlbumPage, err error) {
+	return c.NewReleasesWithContext(context.Background(), nil)
+}
+
+// NextPlaylistsPage replaces p, a page of featured playlists or of a
+// category's playlists, with the next page.  It returns ErrNoMorePages
+// after the last page.
+func (c *Client) NextPlaylistsPage(p *SimplePlaylistPage) error {
+	return c.NextPlaylistsPageWithContext(context.Background(), p)
+}
+
+// NextPlaylistsPageWithContext is like NextPlaylistsPage, with a context.
+func (c *Client) NextPlaylistsPageWithContext(ctx context.Context, p *SimplePlaylistPage) error {
+	if p.Next == "" {
+		return ErrNoMorePages
+	}
+	var result struct {
+		Playlists SimplePlaylistPage `json:"playlists"`
+	}
+	if err := c.getPage(ctx, p.Next, &result); err != nil {
+		return err
+	}
+	*p = result.Playlists
+	return nil
+}
+
+// NextNewReleasesPage replaces p, a page of new releases, with the next
+// page.  It returns ErrNoMorePages after the last page.
+func (c *Client) NextNewReleasesPage(p *SimpleAlbumPage) error {
+	return c.NextNewReleasesPageWithContext(context.Background(), p)
+}
+
+// NextNewReleasesPageWithContext is like NextNewReleasesPage, with a
+// context.
+func (c *Client) NextNewReleasesPageWithContext(ctx context.Context, p *SimpleAlbumPage) error {
+	if p.Next == "" {
+		return ErrNoMorePages
+	}
+	var result struct {
+		Albums SimpleAlbumPage `json:"albums"`
+	}
+	if err := c.getPage(ctx, p.Next, &result); err != nil {
+		return err
+	}
+	*p = result.Albums
+	return nil
+}
+
+// NextCategoriesPage replaces p, a page of categories, with the next page.
+// It returns ErrNoMorePages after the last page.
+func (c *Client) NextCategoriesPage(p *CategoryPage) error {
+	return c.NextCategoriesPageWithContext(context.Background(), p)
+}
+
+// NextCategoriesPageWithContext is like NextCategoriesPage, with a context.
+func (c *Client) NextCategoriesPageWithContext(ctx context.Context, p *CategoryPage) error {
+	if p.Next == "" {
+		return ErrNoMorePages
+	}
+	var result struct {
+		Categories CategoryPage `json:"categories"`
+	}
+	if err := c.getPage(ctx, p.Next, &result); err != nil {
+		return err
+	}
+	*p = result.Categories
+	return nil
+}
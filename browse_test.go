@@ -0,0 +1,48 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewReleasesOpt(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/new_releases.txt")
+	country, limit := "SE", 20
+	albums, err := c.NewReleasesOpt(&Options{Country: &country, Limit: &limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(albums.Albums) == 0 || albums.Albums[0].Name == "" {
+		t.Errorf("Got %+v, want the albums of the response\n", albums)
+	}
+	if q := getLastRequest(c).URL.Query(); q.Get("country") != "SE" || q.Get("limit") != "20" {
+		t.Errorf("Got query %v\n", q)
+	}
+}
+
+func TestNextPlaylistsPage(t *testing.T) {
+	pages := map[string]string{
+		"": `{"message": "Monday morning music", "playlists": {"items": [{"name": "Wake Up"}],
+			"next": "https://api.spotify.com/v1/browse/featured-playlists?offset=1&limit=1"}}`,
+		"1": `{"message": "Monday morning music", "playlists": {"items": [{"name": "Coffee"}], "offset": 1, "next": null}}`,
+	}
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := pages[req.URL.Query().Get("offset")]
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+	_, p, err := c.FeaturedPlaylists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NextPlaylistsPage(p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Playlists) != 1 || p.Playlists[0].Name != "Coffee" {
+		t.Errorf("Got %+v, want the second page\n", p)
+	}
+	if err := c.NextPlaylistsPage(p); err != ErrNoMorePages {
+		t.Errorf("Got %v after the last page, want ErrNoMorePages\n", err)
+	}
+}
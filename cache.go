@@ -0,0 +1,254 @@
+package spotify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores API responses so that repeated lookups don't need to go back
+// to the Web API.  Implementations must be safe for concurrent use.  Errors
+// are not reported: a cache that fails should behave as if it were empty.
+type Cache interface {
+	// Get returns the value stored for key, and whether it was found and
+	// hasn't expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for the duration ttl.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// WithCache makes the client cache successful GET responses in cache,
+// according to DefaultCacheTTL.  Responses larger than the client's limit
+// (see WithMaxResponseBytes) aren't cached.
+func WithCache(cache Cache) ClientOption {
+	return WithCacheTTL(cache, DefaultCacheTTL)
+}
+
+// WithCacheTTL is like WithCache, but ttl decides which responses are
+// cached and for how long (see CacheTransport.TTL).
+func WithCacheTTL(cache Cache, ttl func(req *http.Request) time.Duration) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &CacheTransport{Base: hc.Transport, Cache: cache, TTL: ttl, client: c}
+		c.http = &hc
+	}
+}
+
+// DefaultCacheTTL returns how long the response to req may be cached.
+// Audio features and analyses never change, so they are kept for a long
+// time.  Catalog lookups of tracks, albums and artists are kept for a day,
+// since popularity and availability change slowly.  Nothing else is cached,
+// because it depends on the user.
+func DefaultCacheTTL(req *http.Request) time.Duration {
+	if req.URL.Host != "api.spotify.com" || strings.Contains(req.URL.RawQuery, MarketFromToken) {
+		return 0
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v1/")
+	switch {
+	case strings.HasPrefix(path, "audio-analysis/"), strings.HasPrefix(path, "audio-features"):
+		return 30 * 24 * time.Hour
+	case strings.HasPrefix(path, "tracks"), strings.HasPrefix(path, "albums"),
+		strings.HasPrefix(path, "artists"):
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// UserCacheTTL is like DefaultCacheTTL, but also caches the current user's
+// profile and top artists and tracks for an hour.  It must only be used with
+// a cache that is private to one user, such as a MemcacheCache created for
+// that user.
+func UserCacheTTL(req *http.Request) time.Duration {
+	if d := DefaultCacheTTL(req); d > 0 {
+		return d
+	}
+	if req.URL.Host != "api.spotify.com" {
+		return 0
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v1/")
+	if path == "me" || strings.HasPrefix(path, "me/top/") || strings.HasPrefix(path, "users/") && strings.Count(path, "/") == 1 {
+		return time.Hour
+	}
+	return 0
+}
+
+// CacheTransport is an http.RoundTripper that serves GET requests from a
+// Cache when possible, and stores successful responses in it.  Cached
+// responses have the header X-Cache set to "HIT".
+//
+// Cache-Control headers are honored as far as they make sense for the Web
+// API.  A request with "no-cache" skips the cache, but its response is
+// stored; a request with "no-store" neither reads nor fills the cache.  A
+// response with "no-store" isn't stored, and one with a positive max-age
+// is kept for no longer than that.  The Web API marks most responses
+// "private, max-age=0", so those directives are left to TTL (use a cache
+// private to the user for personalized responses).
+type CacheTransport struct {
+	// Base is used to send requests that can't be served from the cache.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Cache stores the responses.
+	Cache Cache
+	// TTL returns how long the response to a request may be cached, or
+	// zero if it mustn't be.  If nil, DefaultCacheTTL is used.
+	TTL func(req *http.Request) time.Duration
+	// MaxResponseBytes is the largest response body that is read to be
+	// cached.  A larger response fails with a ResponseTooLargeError, and
+	// isn't cached.  If zero, DefaultMaxResponseBytes is used; if
+	// negative, the size isn't limited.
+	MaxResponseBytes int64
+
+	// client is the client the transport was added to by WithCacheTTL,
+	// whose limit is used instead of MaxResponseBytes.
+	client *Client
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ttl := t.TTL
+	if ttl == nil {
+		ttl = DefaultCacheTTL
+	}
+	if req.Method != "GET" {
+		return base.RoundTrip(req)
+	}
+	d := ttl(req)
+	if d <= 0 {
+		return base.RoundTrip(req)
+	}
+	key := req.URL.String()
+	if l := req.Header.Get("Accept-Language"); l != "" {
+		// Localized responses differ by language; see WithLocale.
+		key += "\n" + l
+	}
+	reqCC := cacheControl(req.Header)
+	if reqCC["no-store"] {
+		return base.RoundTrip(req)
+	}
+	if !reqCC["no-cache"] {
+		if body, ok := t.Cache.Get(key); ok {
+			return cachedResponse(req, body), nil
+		}
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	if cacheControl(resp.Header)["no-store"] {
+		return resp, nil
+	}
+	if maxAge := cacheMaxAge(resp.Header); maxAge > 0 && maxAge < d {
+		d = maxAge
+	}
+	body, err := readBody(resp.Body, transportLimit(t.client, t.MaxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	t.Cache.Set(key, body, d)
+	return resp, nil
+}
+
+// cacheControl returns the directives without values of the Cache-Control
+// header in h.
+func cacheControl(h http.Header) map[string]bool {
+	cc := make(map[string]bool)
+	for _, v := range h["Cache-Control"] {
+		for _, d := range strings.Split(v, ",") {
+			cc[strings.ToLower(strings.TrimSpace(d))] = true
+		}
+	}
+	return cc
+}
+
+// cacheMaxAge returns the max-age directive of the Cache-Control header in
+// h, or zero if it has none.
+func cacheMaxAge(h http.Header) time.Duration {
+	for _, v := range h["Cache-Control"] {
+		for _, d := range strings.Split(v, ",") {
+			d = strings.ToLower(strings.TrimSpace(d))
+			if strings.HasPrefix(d, "max-age=") {
+				n, _ := strconv.Atoi(strings.TrimPrefix(d, "max-age="))
+				return time.Duration(n) * time.Second
+			}
+		}
+	}
+	return 0
+}
+
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":   {"application/json; charset=utf-8"},
+			"Content-Length": {strconv.Itoa(len(body))},
+			"X-Cache":        {"HIT"},
+		},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// MemoryCache is a Cache that keeps responses in memory.  It is only
+// shared by clients in the same process (or App Engine instance).
+type MemoryCache struct {
+	clock Clock
+
+	mu    sync.Mutex
+	items map[string]memoryCacheItem
+}
+
+type memoryCacheItem struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.  Entries expire according to
+// clock, which may be nil to use SystemClock.
+func NewMemoryCache(clock Clock) *MemoryCache {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &MemoryCache{
+		clock: clock,
+		items: make(map[string]memoryCacheItem),
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if !m.clock.Now().Before(item.expires) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return item.value, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[key] = memoryCacheItem{
+		value:   value,
+		expires: m.clock.Now().Add(ttl),
+	}
+}
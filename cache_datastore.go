@@ -0,0 +1,157 @@
+package spotify
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+)
+
+// maxChunkSize is the largest amount of data stored in a single Datastore
+// entity.  Entities are limited to 1MB, including their keys and other
+// properties, so this leaves some room to spare.
+const maxChunkSize = 1000 * 1000
+
+// DatastoreCache is a Cache backed by App Engine Datastore.  Unlike an
+// in-memory cache, its contents survive instance restarts, which makes it a
+// good fit for audio features and analyses that never change.
+//
+// Values are compressed with gzip.  Values that are still too large for a
+// single entity (some audio analyses are several megabytes) are split
+// across child entities.
+type DatastoreCache struct {
+	// Clock is used to expire entries.  If nil, SystemClock is used.
+	Clock Clock
+
+	ctx  context.Context
+	kind string
+}
+
+// NewDatastoreCache creates a DatastoreCache for the App Engine request
+// context ctx.  Entries are stored as entities of the given kind (and chunks
+// of large entries as kind + "Chunk").
+func NewDatastoreCache(ctx context.Context, kind string) *DatastoreCache {
+	return &DatastoreCache{ctx: ctx, kind: kind}
+}
+
+type cacheEntity struct {
+	Data    []byte `datastore:",noindex"`
+	Chunks  int    `datastore:",noindex"`
+	Expires time.Time
+}
+
+type cacheChunk struct {
+	Data []byte `datastore:",noindex"`
+}
+
+func (d *DatastoreCache) clock() Clock {
+	if d.Clock == nil {
+		return SystemClock
+	}
+	return d.Clock
+}
+
+func (d *DatastoreCache) key(key string) *datastore.Key {
+	// keys can be long URLs, so use a fixed size hash as the key name
+	sum := sha1.Sum([]byte(key))
+	return datastore.NewKey(d.ctx, d.kind, hex.EncodeToString(sum[:]), 0, nil)
+}
+
+func (d *DatastoreCache) chunkKeys(parent *datastore.Key, n int) []*datastore.Key {
+	keys := make([]*datastore.Key, n)
+	for i := range keys {
+		keys[i] = datastore.NewKey(d.ctx, d.kind+"Chunk", "", int64(i+1), parent)
+	}
+	return keys
+}
+
+// Get implements Cache.
+func (d *DatastoreCache) Get(key string) ([]byte, bool) {
+	k := d.key(key)
+	var e cacheEntity
+	if err := datastore.Get(d.ctx, k, &e); err != nil {
+		return nil, false
+	}
+	if !d.clock().Now().Before(e.Expires) {
+		return nil, false
+	}
+	data := e.Data
+	if e.Chunks > 0 {
+		chunks := make([]cacheChunk, e.Chunks)
+		if err := datastore.GetMulti(d.ctx, d.chunkKeys(k, e.Chunks), chunks); err != nil {
+			return nil, false
+		}
+		parts := make([][]byte, len(chunks))
+		for i, c := range chunks {
+			parts[i] = c.Data
+		}
+		data = bytes.Join(parts, nil)
+	}
+	value, err := gunzip(data)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Cache.
+func (d *DatastoreCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := gzipBytes(value)
+	if err != nil {
+		return
+	}
+	k := d.key(key)
+	e := cacheEntity{Expires: d.clock().Now().Add(ttl)}
+	if len(data) <= maxChunkSize {
+		e.Data = data
+	} else {
+		// write the chunks first, so that a reader never sees an entry
+		// that refers to chunks that don't exist yet
+		parts := splitChunks(data, maxChunkSize)
+		chunks := make([]cacheChunk, len(parts))
+		for i, p := range parts {
+			chunks[i].Data = p
+		}
+		if _, err := datastore.PutMulti(d.ctx, d.chunkKeys(k, len(chunks)), chunks); err != nil {
+			return
+		}
+		e.Chunks = len(chunks)
+	}
+	datastore.Put(d.ctx, k, &e)
+}
+
+// splitChunks splits data into pieces of at most size bytes.
+func splitChunks(data []byte, size int) [][]byte {
+	var chunks [][]byte
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
+
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
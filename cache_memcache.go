@@ -0,0 +1,74 @@
+package spotify
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/memcache"
+)
+
+// maxMemcacheKey is the longest key accepted by memcache.
+const maxMemcacheKey = 250
+
+// MemcacheCache is a Cache backed by App Engine memcache.  It is cheap and
+// shared by all instances, but entries may be evicted at any time.
+//
+// Each cache is confined to a memcache namespace.  A cache created for a
+// particular user can't see the entries of any other user, so it's safe to
+// use it for personalized responses (see UserCacheTTL).
+type MemcacheCache struct {
+	ctx context.Context
+	// MaxTTL, if non-zero, limits how long entries are kept, regardless of
+	// the TTL requested by the caller.
+	MaxTTL time.Duration
+}
+
+// NewMemcacheCache creates a MemcacheCache for the App Engine request
+// context ctx.  If userID is empty, the cache is shared by all users and
+// should only be used for non-personalized data.
+func NewMemcacheCache(ctx context.Context, userID string) (*MemcacheCache, error) {
+	ns := "spotify"
+	if userID != "" {
+		// namespaces may only contain a limited set of characters, so
+		// use a hash of the user ID
+		sum := sha1.Sum([]byte(userID))
+		ns += "." + hex.EncodeToString(sum[:])
+	}
+	nctx, err := appengine.Namespace(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	return &MemcacheCache{ctx: nctx}, nil
+}
+
+func memcacheKey(key string) string {
+	if len(key) <= maxMemcacheKey {
+		return key
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get implements Cache.
+func (m *MemcacheCache) Get(key string) ([]byte, bool) {
+	item, err := memcache.Get(m.ctx, memcacheKey(key))
+	if err != nil {
+		return nil, false
+	}
+	return item.Value, true
+}
+
+// Set implements Cache.
+func (m *MemcacheCache) Set(key string, value []byte, ttl time.Duration) {
+	if m.MaxTTL > 0 && ttl > m.MaxTTL {
+		ttl = m.MaxTTL
+	}
+	memcache.Set(m.ctx, &memcache.Item{
+		Key:        memcacheKey(key),
+		Value:      value,
+		Expiration: ttl,
+	})
+}
@@ -0,0 +1,187 @@
+package spotify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestCacheTransport(t *testing.T) {
+	var hits int
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		hits++
+		body := `{"audio_features": [{"id": "6rqhFgbbKwnb9MLmUQDhG6", "tempo": 118.211}]}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+
+	clock := &stoppedClock{t: time.Now()}
+	cache := NewMemoryCache(clock)
+	c := NewClient(&http.Client{Transport: tr}, WithCache(cache))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetAudioFeatures("6rqhFgbbKwnb9MLmUQDhG6"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d\n", hits)
+	}
+
+	clock.t = clock.t.Add(31 * 24 * time.Hour)
+	if _, err := c.GetAudioFeatures("6rqhFgbbKwnb9MLmUQDhG6"); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 2 {
+		t.Error("Expired entry was served from the cache")
+	}
+}
+
+func TestCacheMaxResponseBytes(t *testing.T) {
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"audio_features": [{"id": "6rqhFgbbKwnb9MLmUQDhG6", "tempo": 118.211}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})
+	cache := &setCounter{Cache: NewMemoryCache(nil)}
+	c := NewClient(&http.Client{Transport: tr}, WithCache(cache), WithMaxResponseBytes(20))
+	_, err := c.GetAudioFeatures("6rqhFgbbKwnb9MLmUQDhG6")
+	if e, ok := err.(*url.Error); !ok || e.Err != (ResponseTooLargeError{Limit: 20}) {
+		t.Errorf("Expected a ResponseTooLargeError, got %v\n", err)
+	}
+	if cache.sets != 0 {
+		t.Error("A response over the limit was cached")
+	}
+}
+
+// setCounter is a Cache that counts the values stored in it.
+type setCounter struct {
+	Cache
+	sets int
+}
+
+func (c *setCounter) Set(key string, value []byte, ttl time.Duration) {
+	c.sets++
+	c.Cache.Set(key, value, ttl)
+}
+
+func TestCacheControl(t *testing.T) {
+	var hits int
+	respCC := "private, max-age=60"
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		hits++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Cache-Control": {respCC}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "6rqhFgbbKwnb9MLmUQDhG6"}`)),
+		}, nil
+	})
+	clock := &stoppedClock{t: time.Now()}
+	cache := NewMemoryCache(clock)
+	ct := &CacheTransport{Base: tr, Cache: cache}
+	get := func(reqCC string) {
+		req, _ := http.NewRequest("GET", "https://api.spotify.com/v1/tracks/6rqhFgbbKwnb9MLmUQDhG6", nil)
+		if reqCC != "" {
+			req.Header.Set("Cache-Control", reqCC)
+		}
+		resp, err := ct.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	get("")
+	get("")
+	if hits != 1 {
+		t.Fatalf("Expected 1 request to reach the server, got %d\n", hits)
+	}
+	get("no-cache")
+	if hits != 2 {
+		t.Error("A no-cache request was served from the cache")
+	}
+	// The response's max-age caps the day that tracks are kept for.
+	clock.t = clock.t.Add(2 * time.Minute)
+	get("")
+	if hits != 3 {
+		t.Error("An entry was kept past the response's max-age")
+	}
+
+	respCC = "no-store"
+	clock.t = clock.t.Add(2 * time.Minute)
+	get("")
+	get("")
+	if hits != 5 {
+		t.Errorf("A no-store response was cached (%d requests reached the server)\n", hits)
+	}
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	tests := []struct {
+		url    string
+		cached bool
+	}{
+		{"https://api.spotify.com/v1/audio-analysis/6rqhFgbbKwnb9MLmUQDhG6", true},
+		{"https://api.spotify.com/v1/tracks?ids=6rqhFgbbKwnb9MLmUQDhG6", true},
+		{"https://api.spotify.com/v1/tracks/6rqhFgbbKwnb9MLmUQDhG6?market=from_token", false},
+		{"https://api.spotify.com/v1/me/top/tracks", false},
+		{"https://example.com/v1/tracks/6rqhFgbbKwnb9MLmUQDhG6", false},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest("GET", test.url, nil)
+		if cached := DefaultCacheTTL(req) > 0; cached != test.cached {
+			t.Errorf("%s: cached = %v, want %v\n", test.url, cached, test.cached)
+		}
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefghij"), 25)
+	chunks := splitChunks(data, 100)
+	if len(chunks) != 3 || len(chunks[2]) != 50 {
+		t.Errorf("Unexpected chunks: %d\n", len(chunks))
+	}
+	if !bytes.Equal(bytes.Join(chunks, nil), data) {
+		t.Error("Chunks don't reassemble the data")
+	}
+	z, err := gzipBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := gunzip(z)
+	if err != nil || !bytes.Equal(back, data) {
+		t.Error("gzip round trip failed", err)
+	}
+}
+
+func TestUserCacheTTL(t *testing.T) {
+	tests := []struct {
+		url    string
+		cached bool
+	}{
+		{"https://api.spotify.com/v1/me", true},
+		{"https://api.spotify.com/v1/me/top/artists?limit=10", true},
+		{"https://api.spotify.com/v1/users/wizzler", true},
+		{"https://api.spotify.com/v1/users/wizzler/playlists", false},
+		{"https://api.spotify.com/v1/me/player/recently-played", false},
+		{"https://api.spotify.com/v1/audio-features?ids=6rqhFgbbKwnb9MLmUQDhG6", true},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest("GET", test.url, nil)
+		if cached := UserCacheTTL(req) > 0; cached != test.cached {
+			t.Errorf("%s: cached = %v, want %v\n", test.url, cached, test.cached)
+		}
+	}
+	if k := memcacheKey(strings.Repeat("x", 300)); len(k) != 40 {
+		t.Errorf("Long key wasn't hashed: %q\n", k)
+	}
+}
@@ -1,11 +1,7 @@
 package spotify
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
+	"golang.org/x/net/context"
 )
 
 // Category is used by Spotify to tag items in.  For example, on the Spotify
@@ -14,7 +10,7 @@ type Category struct {
 	// A link to the Web API endpoint returning full details of the category
 	Endpoint string `json:"href"`
 	// The category icon, in various sizes
-	Icons []Image `json:"icons"`
+	Icons Images `json:"icons"`
 	// The Spotify category ID.  This isn't a base-62 Spotify ID, its just
 	// a short string that describes and identifies the category (ie "party").
 	ID string `json:"id"`
@@ -32,27 +28,22 @@ type Category struct {
 //
 // This call requries authorization.
 func (c *Client) GetCategoryOpt(id, country, locale string) (Category, error) {
+	return c.GetCategoryWithContext(context.Background(), id, country, locale)
+}
+
+// GetCategoryWithContext is like GetCategoryOpt, with a context.
+func (c *Client) GetCategoryWithContext(ctx context.Context, id, country, locale string) (Category, error) {
 	cat := Category{}
-	spotifyURL := fmt.Sprintf("%sbrowse/categories/%s", baseAddress, id)
-	values := url.Values{}
+	e := c.endpoint("browse/categories/%s", id)
 	if country != "" {
-		values.Set("country", country)
+		if err := e.setCountry("country", &country); err != nil {
+			return cat, err
+		}
 	}
 	if locale != "" {
-		values.Set("locale", locale)
-	}
-	if query := values.Encode(); query != "" {
-		spotifyURL += "?" + query
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return cat, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return cat, decodeError(resp.Body)
+		e.set("locale", locale)
 	}
-	err = json.NewDecoder(resp.Body).Decode(&cat)
+	err := c.get(ctx, e.String(), &cat)
 	return cat, err
 }
 
@@ -60,47 +51,35 @@ func (c *Client) GetCategoryOpt(id, country, locale string) (Category, error) {
 // (on, for example, the Spotify player's Browse tab).
 // This call requires authorization.
 func (c *Client) GetCategory(id string) (Category, error) {
-	return c.GetCategoryOpt(id, "", "")
+	return c.GetCategoryWithContext(context.Background(), id, "", "")
 }
 
 // GetCategoryPlaylists gets a list of Spotify playlists tagged with a paricular category.
 // This call requires authorization.
 func (c *Client) GetCategoryPlaylists(catID string) (*SimplePlaylistPage, error) {
-	return c.GetCategoryPlaylistsOpt(catID, nil)
+	return c.GetCategoryPlaylistsWithContext(context.Background(), catID, nil)
 }
 
 // GetCategoryPlaylistsOpt is like GetCategoryPlaylists, but it accepts optional
 // arguments.  This call requires authorization.
 func (c *Client) GetCategoryPlaylistsOpt(catID string, opt *Options) (*SimplePlaylistPage, error) {
-	spotifyURL := fmt.Sprintf("%sbrowse/categories/%s/playlists", baseAddress, catID)
+	return c.GetCategoryPlaylistsWithContext(context.Background(), catID, opt)
+}
+
+// GetCategoryPlaylistsWithContext is like GetCategoryPlaylistsOpt, with a
+// context.
+func (c *Client) GetCategoryPlaylistsWithContext(ctx context.Context, catID string, opt *Options) (*SimplePlaylistPage, error) {
+	e := c.endpoint("browse/categories/%s/playlists", catID)
 	if opt != nil {
-		values := url.Values{}
-		if opt.Country != nil {
-			values.Set("country", *opt.Country)
-		}
-		if opt.Limit != nil {
-			values.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			values.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if query := values.Encode(); query != "" {
-			spotifyURL += "?" + query
+		if err := e.setCountry("country", opt.Country); err != nil {
+			return nil, err
 		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+		e.setPaging(opt)
 	}
 	wrapper := struct {
 		Playlists SimplePlaylistPage `json:"playlists"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&wrapper)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &wrapper); err != nil {
 		return nil, err
 	}
 	return &wrapper.Playlists, nil
@@ -110,7 +89,7 @@ func (c *Client) GetCategoryPlaylistsOpt(catID string, opt *Options) (*SimplePla
 // (on, for example, the Spotify player's "Browse" tab).
 // This call requires authorization.
 func (c *Client) GetCategories() (*CategoryPage, error) {
-	return c.GetCategoriesOpt(nil, "")
+	return c.GetCategoriesWithContext(context.Background(), nil, "")
 }
 
 // GetCategoriesOpt is like GetCategories, but it accepts optional parameters.
@@ -121,38 +100,25 @@ func (c *Client) GetCategories() (*CategoryPage, error) {
 // code, separated by an underscore.  Specify the empty string to have results
 // returned in the Spotify default language (American English).
 func (c *Client) GetCategoriesOpt(opt *Options, locale string) (*CategoryPage, error) {
-	spotifyURL := baseAddress + "browse/categories"
-	values := url.Values{}
+	return c.GetCategoriesWithContext(context.Background(), opt, locale)
+}
+
+// GetCategoriesWithContext is like GetCategoriesOpt, with a context.
+func (c *Client) GetCategoriesWithContext(ctx context.Context, opt *Options, locale string) (*CategoryPage, error) {
+	e := c.endpoint("browse/categories")
 	if locale != "" {
-		values.Set("locale", locale)
+		e.set("locale", locale)
 	}
 	if opt != nil {
-		if opt.Country != nil {
-			values.Set("country", *opt.Country)
-		}
-		if opt.Limit != nil {
-			values.Set("limit", strconv.Itoa(*opt.Limit))
+		if err := e.setCountry("country", opt.Country); err != nil {
+			return nil, err
 		}
-		if opt.Offset != nil {
-			values.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-	}
-	if query := values.Encode(); query != "" {
-		spotifyURL += "?" + query
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+		e.setPaging(opt)
 	}
 	wrapper := struct {
 		Categories CategoryPage `json:"categories"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&wrapper)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &wrapper); err != nil {
 		return nil, err
 	}
 	return &wrapper.Categories, nil
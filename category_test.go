@@ -92,7 +92,7 @@ func TestGetCategoriesInvalidToken(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error but didn't get one")
 	}
-	serr, ok := err.(Error)
+	serr, ok := err.(*Error)
 	if !ok {
 		t.Fatal("Expected a 'spotify.Error'")
 	}
@@ -0,0 +1,109 @@
+package spotify
+
+import "math"
+
+var keyNames = [...]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// String returns the name of the pitch class, using sharps for
+// accidentals.  For example, "C#".
+func (k Key) String() string {
+	if k < C || k > B {
+		return "?"
+	}
+	return keyNames[k]
+}
+
+// Chord is a triad identified by its root note and mode.
+type Chord struct {
+	Root Key
+	Mode Mode
+}
+
+// String returns the chord name in lead sheet notation.  Minor chords
+// are suffixed with "m".  For example, "A" or "F#m".
+func (c Chord) String() string {
+	if c.Mode == Minor {
+		return c.Root.String() + "m"
+	}
+	return c.Root.String()
+}
+
+// SectionChord is the chord estimated for a single section of a track.
+type SectionChord struct {
+	// The starting point (in seconds) of the section.
+	Start float64
+	// The duration (in seconds) of the section.
+	Duration float64
+	Chord    Chord
+	// How well the section's pitch content matches the chord, from 0.0
+	// to 1.0.  Sections with no clear harmony have low confidence.
+	Confidence float64
+}
+
+// EstimateChords estimates the predominant chord of every section of the
+// analyzed track.  The chroma vectors (Segment.Pitches) of the segments in
+// each section are averaged and matched against major and minor triad
+// templates.  The estimate is rough - it is intended for simple chord sheet
+// features rather than accurate transcription.
+func (a *AudioAnalysis) EstimateChords() []SectionChord {
+	result := make([]SectionChord, 0, len(a.Sections))
+	for _, s := range a.Sections {
+		chroma := sectionChroma(a.Segments, s.Start, s.Start+s.Duration)
+		chord, score := matchChord(chroma)
+		result = append(result, SectionChord{
+			Start:      s.Start,
+			Duration:   s.Duration,
+			Chord:      chord,
+			Confidence: score,
+		})
+	}
+	return result
+}
+
+// sectionChroma averages the pitch vectors of the segments that overlap
+// the range [start, end), weighted by the overlap and segment confidence.
+func sectionChroma(segments []Segment, start, end float64) [12]float64 {
+	var chroma [12]float64
+	for _, seg := range segments {
+		overlap := math.Min(end, seg.Start+seg.Duration) - math.Max(start, seg.Start)
+		if overlap <= 0 || len(seg.Pitches) != 12 {
+			continue
+		}
+		weight := overlap * math.Max(seg.Confidence, 0.1)
+		for i, p := range seg.Pitches {
+			chroma[i] += weight * p
+		}
+	}
+	return chroma
+}
+
+// matchChord finds the triad whose template best correlates with the
+// chroma vector, returning the chord and the cosine similarity.
+func matchChord(chroma [12]float64) (Chord, float64) {
+	var norm float64
+	for _, v := range chroma {
+		norm += v * v
+	}
+	if norm == 0 {
+		return Chord{}, 0
+	}
+	norm = math.Sqrt(norm)
+
+	var best Chord
+	bestScore := -1.0
+	for root := C; root <= B; root++ {
+		for _, mode := range []Mode{Major, Minor} {
+			third := 4
+			if mode == Minor {
+				third = 3
+			}
+			sum := chroma[root] + chroma[(int(root)+third)%12] + chroma[(int(root)+7)%12]
+			// a triad template has three unit components
+			score := sum / (norm * math.Sqrt(3))
+			if score > bestScore {
+				best, bestScore = Chord{Root: root, Mode: mode}, score
+			}
+		}
+	}
+	return best, bestScore
+}
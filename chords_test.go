@@ -0,0 +1,46 @@
+package spotify
+
+import "testing"
+
+func chroma(notes ...Key) []float64 {
+	p := make([]float64, 12)
+	for _, n := range notes {
+		p[n] = 1
+	}
+	return p
+}
+
+func TestEstimateChords(t *testing.T) {
+	a := AudioAnalysis{
+		Sections: []Section{
+			{Start: 0, Duration: 2},
+			{Start: 2, Duration: 2},
+		},
+		Segments: []Segment{
+			{Start: 0, Duration: 1, Confidence: 1, Pitches: chroma(G, B, D)},
+			{Start: 1, Duration: 1, Confidence: 1, Pitches: chroma(G, B, D)},
+			{Start: 2, Duration: 2, Confidence: 1, Pitches: chroma(A, C, E)},
+		},
+	}
+	chords := a.EstimateChords()
+	if l := len(chords); l != 2 {
+		t.Fatalf("Wanted 2 chords, got %d\n", l)
+	}
+	if name := chords[0].Chord.String(); name != "G" {
+		t.Errorf("Wanted G, got %s\n", name)
+	}
+	if name := chords[1].Chord.String(); name != "Am" {
+		t.Errorf("Wanted Am, got %s\n", name)
+	}
+	if c := chords[0].Confidence; c < 0.99 {
+		t.Errorf("Wanted confidence near 1.0, got %v\n", c)
+	}
+}
+
+func TestEstimateChordsSilence(t *testing.T) {
+	a := AudioAnalysis{Sections: []Section{{Start: 0, Duration: 2}}}
+	chords := a.EstimateChords()
+	if chords[0].Confidence != 0 {
+		t.Error("Expected zero confidence for a section with no pitch data")
+	}
+}
@@ -0,0 +1,56 @@
+package spotify
+
+import "time"
+
+// Clock provides the current time and the ability to wait.  The client uses
+// it for anything time dependent (token expiry, backoff between retries and
+// polling), so that tests can substitute a fake clock and run instantly and
+// deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep pauses the current goroutine for at least the duration d.
+	Sleep(d time.Duration)
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package.  It is used when
+// no other Clock is configured.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// ClientOption configures optional behavior of a Client.
+type ClientOption func(*Client)
+
+// WithClock sets the Clock used by the client.  It is mainly useful in
+// tests; see spotifytest.FakeClock.  It applies to every option that uses
+// the clock, such as WithLogging and WithRateLimit, wherever it comes.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+func (c *Client) getClock() Clock {
+	if c.clock == nil {
+		return SystemClock
+	}
+	return c.clock
+}
+
+// clientClock is the Clock of a client, looked up each time it's used, so
+// that options applied before WithClock still use the clock it sets.
+type clientClock struct {
+	c *Client
+}
+
+func (c clientClock) Now() time.Time                         { return c.c.getClock().Now() }
+func (c clientClock) Sleep(d time.Duration)                  { c.c.getClock().Sleep(d) }
+func (c clientClock) After(d time.Duration) <-chan time.Time { return c.c.getClock().After(d) }
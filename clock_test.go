@@ -0,0 +1,26 @@
+package spotify
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stoppedClock struct {
+	systemClock
+	t time.Time
+}
+
+func (c stoppedClock) Now() time.Time { return c.t }
+
+func TestWithClock(t *testing.T) {
+	want := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(http.DefaultClient, WithClock(stoppedClock{t: want}))
+	if got := c.getClock().Now(); !got.Equal(want) {
+		t.Errorf("Got %v, want %v\n", got, want)
+	}
+	var zero Client
+	if zero.getClock() != SystemClock {
+		t.Error("Zero Client should use SystemClock")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// withClient returns a command function that runs fn with a client that uses
+// the saved token.
+func withClient(fn func(c *spotify.Client, args []string, w io.Writer) error) func(args []string, w io.Writer) error {
+	return func(args []string, w io.Writer) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		return fn(c, args, w)
+	}
+}
+
+// topFlags parses the flags of the top-tracks and top-artists commands.
+func topFlags(name string, args []string) (*spotify.Options, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "the number of items to show, up to 50")
+	timeRange := flags.String("range", "medium_term", "the time range: short_term, medium_term or long_term")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	return &spotify.Options{Limit: limit, Timerange: timeRange}, nil
+}
+
+func topTracks(c *spotify.Client, args []string, w io.Writer) error {
+	opt, err := topFlags("top-tracks", args)
+	if err != nil {
+		return err
+	}
+	top, err := c.CurrentUserTopTracksWithContext(context.Background(), opt)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"#", "TRACK", "ARTISTS", "ALBUM", "POPULARITY"}}
+	for i, t := range top.Items {
+		names := make([]string, len(t.Artists))
+		for j, a := range t.Artists {
+			names[j] = a.Name
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), t.Name, strings.Join(names, ", "), t.Album.Name, strconv.Itoa(int(t.Popularity)),
+		})
+	}
+	return write(w, top, rows)
+}
+
+func topArtists(c *spotify.Client, args []string, w io.Writer) error {
+	opt, err := topFlags("top-artists", args)
+	if err != nil {
+		return err
+	}
+	top, err := c.CurrentUserTopArtistsWithContext(context.Background(), opt)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"#", "ARTIST", "GENRES", "FOLLOWERS", "POPULARITY"}}
+	for i, a := range top.Items {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), a.Name, strings.Join(a.Genres, ", "),
+			fmt.Sprint(a.Followers.Count), strconv.Itoa(int(a.Popularity)),
+		})
+	}
+	return write(w, top, rows)
+}
+
+func recent(c *spotify.Client, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("recent", flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "the number of tracks to show, up to 50")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	history, err := c.CurrentUserRecentTracksWithContext(context.Background(), &spotify.Options{Limit: limit})
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"PLAYED AT", "TRACK", "ARTISTS"}}
+	for _, item := range history.Items {
+		names := make([]string, len(item.Track.Artists))
+		for j, a := range item.Track.Artists {
+			names[j] = a.Name
+		}
+		rows = append(rows, []string{item.PlayedAt.Format(time.RFC3339Nano), item.Track.Name, strings.Join(names, ", ")})
+	}
+	return write(w, history, rows)
+}
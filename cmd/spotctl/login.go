@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// redirectURI is the redirect URI that spotctl listens on while logging in.
+// It must be registered with the application.
+const redirectURI = "http://localhost:8080/callback"
+
+// scopes are the scopes that spotctl asks for.
+var scopes = []string{
+	spotify.ScopeUserReadPrivate,
+	spotify.ScopeUserTopRead,
+	spotify.ScopeUserReadRecentlyPlayed,
+	spotify.ScopeUserReadPlaybackState,
+	spotify.ScopePlaylistReadPrivate,
+	spotify.ScopePlaylistReadCollaborative,
+	spotify.ScopePlaylistModifyPublic,
+	spotify.ScopePlaylistModifyPrivate,
+}
+
+// authenticator returns the authenticator of the application.  There's no
+// client secret: with PKCE, the client ID is sent with each request for a
+// token instead.
+func authenticator() spotify.Authenticator {
+	a := spotify.NewAuthenticator(redirectURI, scopes...)
+	a.SetAuthInfo(*clientID, "")
+	return a
+}
+
+func runLogin(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *clientID == "" {
+		return errors.New("no client ID: set SPOTIFY_ID or use -client-id")
+	}
+	verifier, err := spotify.NewCodeVerifier()
+	if err != nil {
+		return err
+	}
+	// A code verifier is as good a random state as any.
+	state, err := spotify.NewCodeVerifier()
+	if err != nil {
+		return err
+	}
+	a := authenticator()
+	url := a.AuthURLWithPKCE(state, spotify.CodeChallenge(verifier))
+
+	l, err := net.Listen("tcp", "localhost:8080")
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	go http.Serve(l, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(rw, r)
+			return
+		}
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(rw, "The state doesn't match.", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			fmt.Fprintln(rw, "Login failed.  You can close this window.")
+			// Only the first callback is waited for.
+			select {
+			case errs <- errors.New("login failed: " + q.Get("error")):
+			default:
+			}
+			return
+		}
+		fmt.Fprintln(rw, "Logged in.  You can close this window.")
+		select {
+		case codes <- q.Get("code"):
+		default:
+		}
+	}))
+
+	fmt.Fprintln(os.Stderr, "Log in to Spotify by visiting this page in your browser:")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, url)
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return err
+	}
+
+	token, err := a.ExchangeWithPKCE(code, verifier)
+	if err != nil {
+		return err
+	}
+	if err := saveToken(token); err != nil {
+		return err
+	}
+	c := a.NewClient(token)
+	user, err := c.CurrentUser()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Logged in as %s.\n", user.ID)
+	return nil
+}
+
+// loadToken reads the saved token.
+func loadToken() (*oauth2.Token, error) {
+	f, err := os.Open(*tokenFile)
+	if os.IsNotExist(err) {
+		return nil, errors.New("not logged in: run spotctl login first")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var token oauth2.Token
+	if err := json.NewDecoder(f).Decode(&token); err != nil {
+		return nil, fmt.Errorf("can't read the token from %s: %v", *tokenFile, err)
+	}
+	return &token, nil
+}
+
+// saveToken saves the token, readable only by the user.
+func saveToken(token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(*tokenFile), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return writeFile(*tokenFile, data, 0600)
+}
+
+// writeFile writes data to a temporary file and renames it, so that an
+// interrupted write doesn't lose the saved token.
+func writeFile(name string, data []byte, perm os.FileMode) error {
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// newClient returns a client that uses the saved token.
+func newClient() (*spotify.Client, error) {
+	token, err := loadToken()
+	if err != nil {
+		return nil, err
+	}
+	a := authenticator()
+	// Save refreshed tokens, so that they're used next time.
+	a.OnTokenRefreshed(func(token *oauth2.Token) {
+		if err := saveToken(token); err != nil {
+			fmt.Fprintf(os.Stderr, "spotctl: can't save the refreshed token: %v\n", err)
+		}
+	})
+	c := a.NewClient(token)
+	return &c, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/nowplaying"
+	"github.com/ljmeyers80529/spot-go-gae/watch"
+)
+
+func nowPlaying(c *spotify.Client, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("nowplaying", flag.ContinueOnError)
+	interval := flags.Duration("interval", 5*time.Second, "how often to poll the player")
+	once := flags.Bool("once", false, "show what is playing once, instead of refreshing it until interrupted")
+	width := flags.Int("width", 80, "the width of the terminal")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	state := new(nowplaying.State)
+	watcher := &watch.Watcher{
+		Client:   c,
+		Interval: *interval,
+		Sink: watch.SinkFunc(func(e watch.Event) error {
+			state.Send(e)
+			// The events don't say which device is playing, so look it up
+			// when playback starts.
+			if e.Type == watch.TrackChanged || e.Type == watch.Resumed {
+				if ps, err := c.PlayerStateWithContext(context.Background(), nil); err == nil {
+					state.SetDevice(ps.Device.Name)
+				}
+			}
+			return nil
+		}),
+	}
+	if err := watcher.Poll(); err != nil {
+		return err
+	}
+	if *once || *format == "json" {
+		sc := state.At(time.Now())
+		if *format == "json" {
+			return writeJSON(w, sc)
+		}
+		_, err := io.WriteString(w, strings.Join(sc.Lines(*width), "\n")+"\n")
+		return err
+	}
+
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	errs := make(chan error, 1)
+	go watcher.Run(stop, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	defer close(stop)
+
+	r := &nowplaying.Renderer{W: w, Width: *width}
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		if err := r.Draw(state.At(time.Now())); err != nil {
+			return err
+		}
+		select {
+		case <-tick.C:
+		case err := <-errs:
+			return err
+		case <-interrupt:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// write writes the result of a command in the format chosen with -format:
+// either v as JSON, or rows as a table, the first row being its header.
+func write(w io.Writer, v interface{}, rows [][]string) error {
+	if *format == "json" {
+		return writeJSON(w, v)
+	}
+	return writeTable(w, rows)
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeTable writes rows as aligned columns.  Tabs and newlines in the cells
+// are replaced with spaces, so they don't break the alignment.
+func writeTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = clean.Replace(cell)
+		}
+		if _, err := io.WriteString(tw, strings.Join(row, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/dedupe"
+)
+
+func playlist(c *spotify.Client, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: spotctl playlist dedupe [-apply] [-tolerance d] playlist")
+	}
+	switch args[0] {
+	case "dedupe":
+		return playlistDedupe(c, args[1:], w)
+	}
+	return fmt.Errorf("unknown playlist command %q", args[0])
+}
+
+// playlistID returns the ID of the playlist given by an ID, a URI or a link.
+func playlistID(arg string) (spotify.ID, error) {
+	t, id, err := spotify.ParseURL(arg)
+	if err == spotify.ErrInvalidURI {
+		return spotify.ID(arg), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if t != spotify.ItemTypePlaylist {
+		return "", fmt.Errorf("%s is a %s, not a playlist", arg, t)
+	}
+	return id, nil
+}
+
+func playlistDedupe(c *spotify.Client, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("playlist dedupe", flag.ContinueOnError)
+	apply := flags.Bool("apply", false, "remove the duplicates, instead of only listing them")
+	tolerance := flags.Duration("tolerance", dedupe.DefaultTolerance,
+		"the largest difference in duration between tracks matched by name; 0 to only match identical tracks")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: spotctl playlist dedupe [-apply] [-tolerance d] playlist")
+	}
+	id, err := playlistID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	user, err := c.CurrentUser()
+	if err != nil {
+		return err
+	}
+	d := &dedupe.Deduper{Client: c, Tolerance: *tolerance}
+	if *tolerance == 0 {
+		// For the Deduper, zero means the default, and negative disables
+		// fuzzy matching.
+		d.Tolerance = -time.Nanosecond
+	}
+	report, err := d.FindInPlaylist(user.ID, id)
+	if err != nil {
+		return err
+	}
+	removable := report.Removable()
+
+	if *format == "json" {
+		if err := writeJSON(w, report); err != nil {
+			return err
+		}
+	} else {
+		if err := report.WriteText(w); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d duplicate groups, %d tracks to remove.\n", len(report.Groups), len(removable))
+	}
+	if len(removable) == 0 {
+		return nil
+	}
+	if !*apply {
+		if *format != "json" {
+			fmt.Fprintln(w, "This was a dry run; use -apply to remove them.")
+		}
+		return nil
+	}
+	if p := removable[0].Playlist; p.Owner.ID != user.ID && !p.Collaborative {
+		return fmt.Errorf("can't remove tracks from %q, which belongs to %s and isn't collaborative", p.Name, p.Owner.ID)
+	}
+	if err := d.Remove(report, user.ID); err != nil {
+		return err
+	}
+	if *format != "json" {
+		fmt.Fprintf(w, "Removed %d tracks.\n", len(removable))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// goldenClient returns a client that answers every request with the golden
+// file, and records the URLs it requests.
+func goldenClient(t *testing.T, golden string, urls *[]string) *spotify.Client {
+	body, err := ioutil.ReadFile("../../test_data/golden/" + golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bodyClient(body, urls)
+}
+
+// bodyClient returns a client that answers every request with body, and
+// records the URLs it requests.
+func bodyClient(body []byte, urls *[]string) *spotify.Client {
+	c := spotify.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*urls = append(*urls, req.URL.String())
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+	})})
+	return &c
+}
+
+// withFormat runs fn with the output format set to f.
+func withFormat(f string, fn func()) {
+	old := *format
+	*format = f
+	defer func() { *format = old }()
+	fn()
+}
+
+func TestTopTracksTable(t *testing.T) {
+	var urls []string
+	c := goldenClient(t, "top_tracks.json", &urls)
+	var out bytes.Buffer
+	withFormat("table", func() {
+		if err := topTracks(c, []string{"-limit", "5", "-range", "short_term"}, &out); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if len(urls) != 1 || !strings.Contains(urls[0], "limit=5") || !strings.Contains(urls[0], "time_range=short_term") {
+		t.Errorf("requested %v", urls)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "#  TRACK") {
+		t.Fatalf("got table:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[1], "1  The Funeral") || !strings.Contains(lines[1], "Band of Horses") {
+		t.Errorf("got first row %q", lines[1])
+	}
+}
+
+func TestTopTracksNullAlbum(t *testing.T) {
+	// Local files have no album.
+	var urls []string
+	c := bodyClient([]byte(`{"items": [{"name": "Demo", "album": null, "artists": [{"name": "Me"}], "is_local": true}]}`), &urls)
+	var out bytes.Buffer
+	withFormat("table", func() {
+		if err := topTracks(c, nil, &out); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "1  Demo") {
+		t.Errorf("got table:\n%s", out.String())
+	}
+}
+
+func TestRecentJSON(t *testing.T) {
+	var urls []string
+	c := goldenClient(t, "play_history.json", &urls)
+	var out bytes.Buffer
+	withFormat("json", func() {
+		if err := recent(c, nil, &out); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var history spotify.PlayHistory
+	if err := json.Unmarshal(out.Bytes(), &history); err != nil {
+		t.Fatal(err)
+	}
+	playedAt := time.Date(2017, 5, 19, 9, 13, 23, 631000000, time.UTC)
+	if len(history.Items) == 0 || !history.Items[0].PlayedAt.Equal(playedAt) {
+		t.Errorf("got %+v", history.Items)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeTable(&out, [][]string{{"A", "B"}, {"one\ttwo", "three"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "A        B\none two  three\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestNowPlayingOnce(t *testing.T) {
+	var urls []string
+	c := bodyClient([]byte(`{
+		"device": {"name": "Kitchen"},
+		"progress_ms": 61000,
+		"is_playing": false,
+		"item": {"name": "Speak", "uri": "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", "duration_ms": 180000, "artists": [{"name": "Band of Horses"}]}
+	}`), &urls)
+	var out bytes.Buffer
+	if err := nowPlaying(c, []string{"-once", "-width", "40"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 5 || lines[0] != "⏸ Speak" || lines[1] != "  Band of Horses" || lines[3] != "  on Kitchen" {
+		t.Errorf("got\n%s", out.String())
+	}
+	if !strings.HasSuffix(lines[2], " 1:01 / 3:00") {
+		t.Errorf("got progress line %q", lines[2])
+	}
+	// The device is looked up when a track starts.
+	if len(urls) != 2 || !strings.HasSuffix(urls[0], "/me/player/currently-playing") || !strings.HasSuffix(urls[1], "/me/player") {
+		t.Errorf("requested %v", urls)
+	}
+}
+
+func TestPlaylistDedupe(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Dupes", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddTracksToPlaylist(spotifytest.UserID, pl.ID, "a", "b", "a", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	withFormat("table", func() {
+		err = playlist(&c, []string{"dedupe", string(pl.URI)}, &out)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "1 duplicate groups, 2 tracks to remove.") || !strings.Contains(out.String(), "dry run") {
+		t.Errorf("got\n%s", out.String())
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 4 {
+		t.Errorf("a dry run changed the playlist: %v", ids)
+	}
+
+	out.Reset()
+	withFormat("table", func() {
+		err = playlist(&c, []string{"dedupe", "-apply", string(pl.ID)}, &out)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("got playlist %v after removing duplicates", ids)
+	}
+
+	if err := playlist(&c, []string{"dedupe", "spotify:track:a"}, &out); err == nil {
+		t.Error("got no error for a track URI")
+	}
+}
@@ -0,0 +1,99 @@
+package spotify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// WithCoalescing makes the client send a GET request only once when the
+// same URL is requested again before the first response has arrived, for
+// instance when several widgets on a page ask for the same track's
+// analysis at once.  Every caller gets the response; each decodes its own
+// copy, so the results can be modified independently.  Responses are read
+// up to the client's limit (see WithMaxResponseBytes).
+//
+// The CoalesceTransport it adds wraps the client's authorization, so only
+// requests made through the client itself are coalesced.
+func WithCoalescing() ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &CoalesceTransport{Base: hc.Transport, client: c}
+		c.http = &hc
+	}
+}
+
+// CoalesceTransport is an http.RoundTripper that sends concurrent, identical
+// GET requests upstream only once and gives each caller its own copy of the
+// response.  Requests are identical if they have the same URL and
+// Authorization and Accept-Language headers, so one transport can be
+// shared by clients for different users if it comes after their
+// authorization, as the base transport of an Authenticator (see
+// SetTransport).  If the request that was sent is canceled, the requests
+// waiting for it fail too.
+type CoalesceTransport struct {
+	// Base is used to send the requests.  If nil, http.DefaultTransport is
+	// used.
+	Base http.RoundTripper
+	// MaxResponseBytes is the largest response body that is read.  A
+	// larger response fails with a ResponseTooLargeError.  If zero,
+	// DefaultMaxResponseBytes is used; if negative, the size isn't
+	// limited.
+	MaxResponseBytes int64
+
+	// client is the client the transport was added to by WithCoalescing,
+	// whose limit is used instead of MaxResponseBytes.
+	client *Client
+	group  singleflight.Group
+}
+
+// coalescedResponse is the part of a response that is shared by the
+// requests waiting for it.
+type coalescedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CoalesceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != "GET" {
+		return base.RoundTrip(req)
+	}
+	key := req.URL.String() + "\n" + req.Header.Get("Authorization") + "\n" + req.Header.Get("Accept-Language")
+	v, err, _ := t.group.Do(key, func() (interface{}, error) {
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := readBody(resp.Body, transportLimit(t.client, t.MaxResponseBytes))
+		if err != nil {
+			return nil, err
+		}
+		return coalescedResponse{resp: resp, body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	shared := v.(coalescedResponse)
+	resp := *shared.resp
+	resp.Header = cloneHeader(shared.resp.Header)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(shared.body))
+	resp.Request = req
+	return &resp, nil
+}
+
+// cloneHeader returns a copy of h, so that callers sharing a response can
+// modify its header.
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, vs := range h {
+		c[k] = append([]string(nil), vs...)
+	}
+	return c
+}
@@ -0,0 +1,102 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescing(t *testing.T) {
+	const callers = 5
+	var hits int32
+	started := make(chan struct{}, callers)
+	release := make(chan struct{})
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&hits, 1)
+		started <- struct{}{}
+		<-release
+		body := `{"audio_features": [{"id": "6rqhFgbbKwnb9MLmUQDhG6", "tempo": 118.211}]}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+	c := NewClient(&http.Client{Transport: tr}, WithCoalescing())
+
+	var wg sync.WaitGroup
+	results := make([][]*AudioFeatures, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = c.GetAudioFeatures("6rqhFgbbKwnb9MLmUQDhG6")
+		}(i)
+	}
+	// Give the other callers time to start waiting for the first request.
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if results[i][0].Tempo != 118.211 {
+			t.Errorf("caller %d got tempo %v", i, results[i][0].Tempo)
+		}
+	}
+	results[0][0].Tempo = 0
+	if results[1][0].Tempo == 0 {
+		t.Error("callers share a decoded result")
+	}
+}
+
+func TestCoalesceTransportKey(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	tr := &CoalesceTransport{Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen[req.Header.Get("Authorization")] = true
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("{}"))}, nil
+	})}
+	for _, token := range []string{"Bearer a", "Bearer b"} {
+		req, _ := http.NewRequest("GET", "https://api.spotify.com/v1/me", nil)
+		req.Header.Set("Authorization", token)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if len(seen) != 2 {
+		t.Errorf("got requests for %d users, want 2", len(seen))
+	}
+}
+
+func TestCoalesceMaxResponseBytes(t *testing.T) {
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`))}, nil
+	})
+	// The limit is the client's, even if it's set after WithCoalescing.
+	c := NewClient(&http.Client{Transport: tr}, WithCoalescing(), WithMaxResponseBytes(10))
+	_, err := c.CurrentUser()
+	if e, ok := err.(*url.Error); !ok || e.Err != (ResponseTooLargeError{Limit: 10}) {
+		t.Errorf("got error %v, want a ResponseTooLargeError", err)
+	}
+
+	c = NewClient(&http.Client{Transport: tr}, WithCoalescing(), WithMaxResponseBytes(0))
+	if _, err := c.CurrentUser(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,51 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+type testKey struct{}
+
+func TestWithContext(t *testing.T) {
+	var got []interface{}
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		got = append(got, req.Context().Value(testKey{}))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`)),
+		}, nil
+	})
+	c := NewClient(&http.Client{Transport: tr})
+
+	ctx := context.WithValue(context.Background(), testKey{}, "request")
+	user, err := c.CurrentUserWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "wizzler" || len(got) != 1 || got[0] != "request" {
+		t.Errorf("Got user %q, contexts %v\n", user.ID, got)
+	}
+	if _, err := c.CurrentUser(); err != nil || len(got) != 2 || got[1] != nil {
+		t.Errorf("Expected the client to be unchanged, got contexts %v (%v)\n", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.FollowArtistWithContext(ctx, "0TnOYISbd1XYRBk9myaseg"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+	if _, err := c.AddTracksToPlaylistWithContext(ctx, "wizzler", "2Mbo7U8JPtEVXRo1lTN8Gd", "0TnOYISbd1XYRBk9myaseg"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+	if _, err := c.SearchWithContext(ctx, "abba", SearchTypeArtist, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+}
@@ -1,14 +1,29 @@
 package spotify
 
+import (
+	"strconv"
+	"time"
+)
+
 // This file contains the types that implement Spotify's cursor-based
 // paging object.  Like the standard paging object, this object is a
 // container for a set of items. Unlike the standard paging object, a
 // cursor-based paging object does not provide random access to the results.
 
 // Cursor contains a key that can be used to find the next set
-// of items.
+// of items.  Before is only returned by endpoints that can page
+// backwards, such as the recently played tracks.
 type Cursor struct {
-	After string `json:"after"`
+	After  string `json:"after"`
+	Before string `json:"before,omitempty"`
+}
+
+// TimeCursor returns the cursor for time t, for endpoints whose cursors are
+// Unix times in milliseconds, such as the recently played tracks.  Use it
+// to ask for the tracks played before or after a time, without a cursor
+// from an earlier result.
+func TimeCursor(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
 }
 
 // cursorPage contains all of the fields in a Spotify cursor-based
@@ -0,0 +1,231 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// WithStrictDecoding makes the client reject responses that contain fields
+// its models don't have, returning a DecodeError instead of silently
+// dropping them.  Spotify adds fields to its responses from time to time,
+// so this shouldn't be used in production, but it's useful in staging or in
+// integration tests to notice when the API has drifted from this package.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithRawResponses makes the client call fn with each response it decodes,
+// along with the raw JSON it was decoded from.  v is the value the response
+// was decoded into, such as a *FullTrack, so fn can type switch on it.  This
+// lets callers read fields that this package doesn't have models for yet.
+// fn must not modify raw, and must copy it if it keeps it after returning.
+func WithRawResponses(fn func(v interface{}, raw json.RawMessage)) ClientOption {
+	return func(c *Client) {
+		c.raw = fn
+	}
+}
+
+// Codec decodes JSON.  Unmarshal must behave like json.Unmarshal, including
+// calling the UnmarshalJSON methods of the models that have them.  The
+// standard-library-compatible configurations of faster JSON packages (such
+// as json-iterator's ConfigCompatibleWithStandardLibrary) satisfy it.
+type Codec interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdCodec is the Codec that uses encoding/json.  It is used when no other
+// Codec is configured.
+var StdCodec Codec = stdCodec{}
+
+type stdCodec struct{}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// WithCodec makes the client decode responses with codec instead of
+// encoding/json, which can save a lot of CPU time when decoding large
+// responses such as audio analyses.  It has no effect if the client also
+// uses WithStrictDecoding, since strict decoding relies on encoding/json.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// DecodeError is returned by a client created with WithStrictDecoding when
+// a response doesn't match the model it's decoded into.
+type DecodeError struct {
+	// Type is the name of the Go type the response was decoded into.
+	Type string
+	// Field is the name of the field in the response that the type has no
+	// field for, or the empty string if the mismatch was something else
+	// (such as a value of the wrong JSON type).
+	Field string
+	// Err is the error returned by the JSON decoder.
+	Err error
+}
+
+func (e DecodeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("spotify: %s has no field for %q", e.Type, e.Field)
+	}
+	return fmt.Sprintf("spotify: couldn't decode %s: %v", e.Type, e.Err)
+}
+
+// DefaultMaxResponseBytes is the largest response body a client decodes,
+// unless it's changed with WithMaxResponseBytes.  The largest responses the
+// Web API returns, audio analyses of long tracks, are a few megabytes.
+const DefaultMaxResponseBytes = 16 << 20
+
+// maxErrorBody is the most of an error response that is read.  Error
+// responses are small, so anything larger isn't one from the Web API.
+const maxErrorBody = 64 << 10
+
+// WithMaxResponseBytes sets the largest response body, in bytes, that the
+// client decodes.  A larger response is abandoned, and the call returns a
+// ResponseTooLargeError, so a malformed or unexpectedly large response
+// can't use up the memory of a small instance.  If n isn't positive, the
+// size isn't limited.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		if n < 0 {
+			n = 0
+		}
+		c.maxResponse = n
+	}
+}
+
+// ResponseTooLargeError is returned when a response body is larger than the
+// client's limit (see WithMaxResponseBytes).
+type ResponseTooLargeError struct {
+	// Limit is the limit that the response exceeded.
+	Limit int64
+}
+
+func (e ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("spotify: response is larger than %d bytes", e.Limit)
+}
+
+// transportLimit returns the largest response body that a transport reads,
+// or 0 for no limit: that of c, the client the transport was added to by
+// an option, if there is one, and otherwise n, the transport's own
+// MaxResponseBytes.
+func transportLimit(c *Client, n int64) int64 {
+	switch {
+	case c != nil:
+		return c.maxResponse
+	case n == 0:
+		return DefaultMaxResponseBytes
+	case n < 0:
+		return 0
+	}
+	return n
+}
+
+// readBody reads and closes a response body of at most max bytes, or of
+// any size if max is 0.  A larger body is abandoned, and a
+// ResponseTooLargeError returned.
+func readBody(body io.ReadCloser, max int64) ([]byte, error) {
+	defer body.Close()
+	var r io.Reader = body
+	if max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(data)) > max {
+		return nil, ResponseTooLargeError{Limit: max}
+	}
+	return data, nil
+}
+
+// unknownFieldPrefix starts the message of the error the JSON decoder
+// returns for an unknown field, when DisallowUnknownFields is set.
+const unknownFieldPrefix = "json: unknown field "
+
+// maxPooledBuffer is the capacity above which a response buffer isn't
+// returned to bufferPool, so one huge response (such as an audio analysis)
+// doesn't keep a lot of memory in use.
+const maxPooledBuffer = 256 << 10
+
+// bufferPool holds the buffers that response bodies are read into, so that
+// frequent calls (polling recently played tracks, or fetching features in
+// batches) don't allocate a new buffer each time.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// decode decodes the JSON response body r into v.
+func (c *Client) decode(r io.Reader, v interface{}) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+	if c.maxResponse > 0 {
+		r = io.LimitReader(r, c.maxResponse+1)
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+	if c.maxResponse > 0 && int64(buf.Len()) > c.maxResponse {
+		return ResponseTooLargeError{Limit: c.maxResponse}
+	}
+	data := firstValue(buf.Bytes())
+	if err := c.unmarshal(data, v); err != nil {
+		return err
+	}
+	if c.raw != nil {
+		c.raw(v, json.RawMessage(data))
+	}
+	return nil
+}
+
+// firstValue returns the first JSON value in data.  Like json.Decoder, which
+// responses used to be decoded with, decode ignores anything after it.  If
+// data doesn't start with a valid value, it is returned as it is, for the
+// codec to report the error.
+func firstValue(data []byte) []byte {
+	r := jsonReader{data: data}
+	r.skip()
+	if r.err != nil {
+		return data
+	}
+	return data[:r.pos]
+}
+
+// unmarshal decodes data into v.  Like json.Decoder, it returns io.EOF if
+// data is empty.
+func (c *Client) unmarshal(data []byte, v interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return io.EOF
+	}
+	if !c.strict {
+		if c.codec == nil {
+			return StdCodec.Unmarshal(data, v)
+		}
+		return c.codec.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return nil
+	}
+	e := DecodeError{Type: reflect.TypeOf(v).String(), Err: err}
+	if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+		e.Field = strings.Trim(msg[len(unknownFieldPrefix):], `"`)
+	}
+	return e
+}
@@ -0,0 +1,580 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//go:generate go run internal/gendecode/main.go -o decode_gen.go
+
+// FastCodec is a Codec that decodes the audio analysis, the pages of
+// tracks, albums, artists and playlists, and the personalization results
+// with decoders generated for them (in decode_gen.go), which don't use
+// reflection.  Other types are decoded with encoding/json.  Use it with
+// WithCodec for pipelines that decode a lot of analyses or pages.
+//
+// The gain grows with the size of the response.  In the benchmarks, the
+// analysis of a four minute track (BenchmarkFastCodecFullAudioAnalysis)
+// takes about half as long to get as with encoding/json, with as many
+// allocations, and a page of playlist tracks about 30% less time, with 177
+// allocations instead of 242.  For a small response, such as the golden
+// analysis in BenchmarkFastCodecAudioAnalysis, the time is mostly spent on
+// the request, and the difference is small.
+//
+// Unlike encoding/json, the generated decoders only match keys exactly, not
+// regardless of case.  The Web API always uses the case in the models' tags.
+var FastCodec Codec = fastCodec{}
+
+type fastCodec struct{}
+
+func (fastCodec) Unmarshal(data []byte, v interface{}) error {
+	if fastDecoders == nil {
+		return json.Unmarshal(data, v)
+	}
+	r := jsonReader{data: data}
+	if !fastDecoders(&r, v) {
+		return json.Unmarshal(data, v)
+	}
+	r.space()
+	if r.err == nil && r.pos < len(r.data) {
+		r.fail("after top-level value")
+	}
+	return r.err
+}
+
+// fastDecoders decodes data into v with its generated decoder and reports
+// whether v has one.  It is set by decode_gen.go.
+var fastDecoders func(r *jsonReader, v interface{}) bool
+
+// jsonReader reads JSON for the generated decoders.  Errors are sticky:
+// after the first one, every method returns a zero value, so the generated
+// code only checks for an error at the end.
+type jsonReader struct {
+	data []byte
+	pos  int
+	err  error
+	// key is the object key read by the last call to nextKey.  It may
+	// refer to data or to scratch, so it is only valid until the next call.
+	key     []byte
+	scratch []byte
+	// first is set when an object or array has just been opened, so that
+	// the first key or element isn't preceded by a comma.  Nested values
+	// always leave it cleared, so one flag serves every level.
+	first bool
+}
+
+// fail records a syntax error at the current position.
+func (r *jsonReader) fail(context string) {
+	if r.err != nil {
+		return
+	}
+	if r.pos >= len(r.data) {
+		r.err = fmt.Errorf("spotify: unexpected end of JSON input %s", context)
+		return
+	}
+	r.err = fmt.Errorf("spotify: invalid character %q %s at offset %d", r.data[r.pos], context, r.pos)
+}
+
+// space skips whitespace.
+func (r *jsonReader) space() {
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case ' ', '\t', '\n', '\r':
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end of the input.
+func (r *jsonReader) peek() byte {
+	r.space()
+	if r.err != nil || r.pos >= len(r.data) {
+		return 0
+	}
+	return r.data[r.pos]
+}
+
+// literal consumes lit if it comes next.
+func (r *jsonReader) literal(lit string) bool {
+	if len(r.data)-r.pos < len(lit) || string(r.data[r.pos:r.pos+len(lit)]) != lit {
+		return false
+	}
+	r.pos += len(lit)
+	return true
+}
+
+// null consumes a null if it comes next, and reports whether it did.
+func (r *jsonReader) null() bool {
+	return r.peek() == 'n' && r.literal("null")
+}
+
+// beginObject consumes the start of an object.  It returns false, leaving
+// the value to decode into unchanged, if the object is null instead.
+func (r *jsonReader) beginObject() bool {
+	switch r.peek() {
+	case '{':
+		r.pos++
+		r.first = true
+		return true
+	case 'n':
+		if r.literal("null") {
+			return false
+		}
+	}
+	r.fail("looking for beginning of object")
+	return false
+}
+
+// nextKey reads the next key of the current object into r.key, and the
+// colon after it.  It returns false at the end of the object.
+func (r *jsonReader) nextKey() bool {
+	if !r.next('}') {
+		return false
+	}
+	if r.peek() != '"' {
+		r.fail("looking for beginning of object key string")
+		return false
+	}
+	r.key = r.stringBytes()
+	if r.peek() != ':' {
+		r.fail("after object key")
+		return false
+	}
+	r.pos++
+	return r.err == nil
+}
+
+// beginArray consumes the start of an array.  Unlike beginObject, it
+// doesn't accept null, which the generated code checks for first.
+func (r *jsonReader) beginArray() bool {
+	if r.peek() == '[' {
+		r.pos++
+		r.first = true
+		return true
+	}
+	r.fail("looking for beginning of array")
+	return false
+}
+
+// nextElem reports whether the current array has another element.
+func (r *jsonReader) nextElem() bool {
+	return r.next(']')
+}
+
+// next consumes the comma before the next key or element, or the closing
+// byte, and reports whether there is another key or element.
+func (r *jsonReader) next(closing byte) bool {
+	c := r.peek()
+	if r.err != nil {
+		return false
+	}
+	if c == closing {
+		r.pos++
+		r.first = false
+		return false
+	}
+	if r.first {
+		r.first = false
+		return true
+	}
+	if c != ',' {
+		r.fail("after value")
+		return false
+	}
+	r.pos++
+	return true
+}
+
+// stringBytes reads a string, returning its unescaped contents.  The
+// result may refer to r.data or r.scratch.
+func (r *jsonReader) stringBytes() []byte {
+	if r.peek() != '"' {
+		r.fail("looking for beginning of string")
+		return nil
+	}
+	r.pos++
+	start := r.pos
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		switch {
+		case c == '"':
+			s := r.data[start:r.pos]
+			r.pos++
+			if !utf8.Valid(s) {
+				return r.unescape(s)
+			}
+			return s
+		case c == '\\':
+			return r.slowString(start)
+		case c < ' ':
+			r.fail("in string literal")
+			return nil
+		}
+		r.pos++
+	}
+	r.fail("in string literal")
+	return nil
+}
+
+// slowString finishes reading a string that contains escapes, which starts
+// at start.
+func (r *jsonReader) slowString(start int) []byte {
+	for r.pos < len(r.data) {
+		switch c := r.data[r.pos]; {
+		case c == '"':
+			s := r.data[start:r.pos]
+			r.pos++
+			return r.unescape(s)
+		case c == '\\':
+			r.pos += 2
+		case c < ' ':
+			r.fail("in string literal")
+			return nil
+		default:
+			r.pos++
+		}
+	}
+	r.fail("in string literal")
+	return nil
+}
+
+// unescape decodes the escapes in s into r.scratch, replacing invalid UTF-8
+// with the replacement character as encoding/json does.
+func (r *jsonReader) unescape(s []byte) []byte {
+	b := r.scratch[:0]
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			i += 2
+			switch s[i-1] {
+			case '"', '\\', '/':
+				b = append(b, s[i-1])
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				rr := hex4(s[i:])
+				if rr < 0 {
+					r.err = fmt.Errorf("spotify: invalid escape in JSON string %q", s)
+					return nil
+				}
+				i += 4
+				if utf16.IsSurrogate(rr) {
+					if len(s) >= i+6 && s[i] == '\\' && s[i+1] == 'u' {
+						if dec := utf16.DecodeRune(rr, hex4(s[i+2:])); dec != utf8.RuneError {
+							rr = dec
+							i += 6
+						} else {
+							rr = utf8.RuneError
+						}
+					} else {
+						rr = utf8.RuneError
+					}
+				}
+				b = appendRune(b, rr)
+			default:
+				r.err = fmt.Errorf("spotify: invalid escape in JSON string %q", s)
+				return nil
+			}
+			continue
+		}
+		if c < utf8.RuneSelf {
+			b = append(b, c)
+			i++
+			continue
+		}
+		rr, size := utf8.DecodeRune(s[i:])
+		b = appendRune(b, rr)
+		i += size
+	}
+	r.scratch = b
+	return b
+}
+
+// hex4 decodes four hex digits, returning -1 if they aren't valid.
+func hex4(s []byte) rune {
+	if len(s) < 4 {
+		return -1
+	}
+	var rr rune
+	for _, c := range s[:4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		rr = rr*16 + rune(c)
+	}
+	return rr
+}
+
+func appendRune(b []byte, rr rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], rr)
+	return append(b, buf[:n]...)
+}
+
+// string reads a string, or null as the empty string.
+func (r *jsonReader) string() string {
+	if r.null() {
+		return ""
+	}
+	b := r.stringBytes()
+	// Market codes make up most of the strings in many responses, in the
+	// available markets of tracks and albums, so they aren't allocated.
+	if len(b) == 2 && 'A' <= b[0] && b[0] <= 'Z' && 'A' <= b[1] && b[1] <= 'Z' {
+		return codeStrings[b[0]-'A'][b[1]-'A']
+	}
+	return string(b)
+}
+
+// codeStrings holds every string of two upper case letters.
+var codeStrings [26][26]string
+
+func init() {
+	for i := range codeStrings {
+		for j := range codeStrings[i] {
+			codeStrings[i][j] = string([]byte{byte('A' + i), byte('A' + j)})
+		}
+	}
+}
+
+// bool reads a boolean, or null as false.
+func (r *jsonReader) bool() bool {
+	switch r.peek() {
+	case 't':
+		if r.literal("true") {
+			return true
+		}
+	case 'f':
+		if r.literal("false") {
+			return false
+		}
+	case 'n':
+		if r.literal("null") {
+			return false
+		}
+	}
+	r.fail("looking for a boolean")
+	return false
+}
+
+// number reads a number, returning its text, whether it is an integer, and
+// (if it has few enough digits) its mantissa and decimal exponent.
+func (r *jsonReader) number() (text []byte, integer bool, mant uint64, exp int, exact bool) {
+	r.peek()
+	start := r.pos
+	data := r.data
+	i := r.pos
+	// The sign is applied by the callers, from the text.
+	if i < len(data) && data[i] == '-' {
+		i++
+	}
+	digits := 0
+	integer, exact = true, true
+	digit := func() bool { return i < len(data) && '0' <= data[i] && data[i] <= '9' }
+	if !digit() {
+		r.fail("looking for beginning of value")
+		return nil, false, 0, 0, false
+	}
+	if data[i] == '0' {
+		i++
+	} else {
+		for digit() {
+			if digits < 19 {
+				mant = mant*10 + uint64(data[i]-'0')
+				digits++
+			} else {
+				exact = false
+			}
+			i++
+		}
+	}
+	if i < len(data) && data[i] == '.' {
+		integer = false
+		i++
+		if !digit() {
+			r.pos = i
+			r.fail("after decimal point in numeric literal")
+			return nil, false, 0, 0, false
+		}
+		for digit() {
+			if digits < 19 {
+				mant = mant*10 + uint64(data[i]-'0')
+				digits++
+				exp--
+			} else {
+				exact = false
+			}
+			i++
+		}
+	}
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		integer = false
+		i++
+		esign := 1
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			if data[i] == '-' {
+				esign = -1
+			}
+			i++
+		}
+		if !digit() {
+			r.pos = i
+			r.fail("in exponent of numeric literal")
+			return nil, false, 0, 0, false
+		}
+		e := 0
+		for digit() {
+			if e < 10000 {
+				e = e*10 + int(data[i]-'0')
+			}
+			i++
+		}
+		exp += esign * e
+	}
+	r.pos = i
+	return data[start:i], integer, mant, exp, exact
+}
+
+// float10 holds the powers of ten that are exactly representable as a
+// float64.
+var float10 = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+// float64 reads a number, or null as zero.
+func (r *jsonReader) float64() float64 {
+	if r.null() {
+		return 0
+	}
+	text, _, mant, exp, exact := r.number()
+	if r.err != nil {
+		return 0
+	}
+	// When the mantissa and the power of ten are both exactly
+	// representable, one multiplication or division is correctly rounded.
+	if exact && mant < 1<<53 && exp >= -22 && exp <= 22 {
+		f := float64(mant)
+		if exp < 0 {
+			f /= float10[-exp]
+		} else {
+			f *= float10[exp]
+		}
+		if text[0] == '-' {
+			f = -f
+		}
+		return f
+	}
+	f, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		r.err = fmt.Errorf("spotify: can't decode number %s: %v", text, err)
+	}
+	return f
+}
+
+// int64 reads an integer, or null as zero.
+func (r *jsonReader) int64() int64 {
+	if r.null() {
+		return 0
+	}
+	text, integer, mant, _, exact := r.number()
+	if r.err != nil {
+		return 0
+	}
+	if !integer {
+		r.err = fmt.Errorf("spotify: can't decode number %s into an integer", text)
+		return 0
+	}
+	if exact && mant < 1<<63 {
+		if text[0] == '-' {
+			return -int64(mant)
+		}
+		return int64(mant)
+	}
+	n, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		r.err = fmt.Errorf("spotify: can't decode number %s: %v", text, err)
+	}
+	return n
+}
+
+// uint64 reads a non-negative integer, or null as zero.
+func (r *jsonReader) uint64() uint64 {
+	if r.null() {
+		return 0
+	}
+	text, _, _, _, _ := r.number()
+	if r.err != nil {
+		return 0
+	}
+	n, err := strconv.ParseUint(string(text), 10, 64)
+	if err != nil {
+		r.err = fmt.Errorf("spotify: can't decode number %s into an unsigned integer", text)
+	}
+	return n
+}
+
+// skip skips a value of any type.
+func (r *jsonReader) skip() {
+	switch r.peek() {
+	case '{':
+		r.pos++
+		r.first = true
+		for r.nextKey() {
+			r.skip()
+		}
+	case '[':
+		r.pos++
+		r.first = true
+		for r.nextElem() {
+			r.skip()
+		}
+	case '"':
+		r.stringBytes()
+	case 't':
+		if !r.literal("true") {
+			r.fail("in literal true")
+		}
+	case 'f':
+		if !r.literal("false") {
+			r.fail("in literal false")
+		}
+	case 'n':
+		if !r.literal("null") {
+			r.fail("in literal null")
+		}
+	default:
+		r.number()
+	}
+}
+
+// unmarshal decodes the next value into v with encoding/json, for the
+// fields whose types have no generated decoder.
+func (r *jsonReader) unmarshal(v interface{}) {
+	r.space()
+	start := r.pos
+	r.skip()
+	if r.err != nil {
+		return
+	}
+	if err := json.Unmarshal(r.data[start:r.pos], v); err != nil {
+		r.err = err
+	}
+}
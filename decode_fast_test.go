@@ -0,0 +1,166 @@
+package spotify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFastCodecGolden checks that FastCodec decodes each golden payload
+// exactly as encoding/json does.  It fails if decode_gen.go is out of date
+// with the models.
+func TestFastCodecGolden(t *testing.T) {
+	for name, model := range goldenFiles {
+		data, err := ioutil.ReadFile(filepath.Join("test_data", "golden", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, got := model(), model()
+		if err := json.Unmarshal(data, want); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := FastCodec.Unmarshal(data, got); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: FastCodec and encoding/json decode differently", name)
+		}
+	}
+}
+
+func TestFastCodecValues(t *testing.T) {
+	tests := []string{
+		`{"loudness": -5.5e-1, "tempo": 1E2, "tempo_confidence": 0.12345678901234567890123, "key": -3, "mode": null}`,
+		`{"loudness": 123456789012345678901234567890, "tempo": 0, "time_signature": 4}`,
+		`{"codestring": "a\"b\\c\/d\n\u00e9\ud83c\udfb5\ud800x", "sample_md5": "\u0041", "synchstring": ""}`,
+		`{"unknown": {"nested": [1, "two", {"three": null}, true, false]}, "key": 7}`,
+		` { "rhythmstring" : "caf` + "\xff" + `" , "key" : 1 } `,
+		`null`,
+	}
+	for _, test := range tests {
+		var want, got TrackInfo
+		if err := json.Unmarshal([]byte(test), &want); err != nil {
+			t.Fatalf("%s: %v", test, err)
+		}
+		r := jsonReader{data: []byte(test)}
+		got.decodeFast(&r)
+		if r.err != nil {
+			t.Errorf("%s: %v", test, r.err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", test, got, want)
+		}
+	}
+}
+
+func TestFastCodecFloats(t *testing.T) {
+	for _, s := range []string{"0", "-0.0", "1.5", "0.1", "3.141592653589793", "1e22", "1e23", "2.2250738585072014e-308", "-118.211", "123456789012345678"} {
+		r := jsonReader{data: []byte(s)}
+		got := r.float64()
+		var want float64
+		json.Unmarshal([]byte(s), &want)
+		if r.err != nil || got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("%s: got %v (error %v), want %v", s, got, r.err, want)
+		}
+	}
+}
+
+func TestFastCodecErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`{`,
+		`{"key": 1,}`,
+		`{"key" 1}`,
+		`{"key": 1 "mode": 2}`,
+		`{"key": 1.5}`,
+		`{"key": 01}`,
+		`{"codestring": "a` + "\n" + `b"}`,
+		`{"codestring": "\x"}`,
+		`{"key": 1} x`,
+		`[1]`,
+		`{"key": tru}`,
+	}
+	for _, test := range tests {
+		var info TrackInfo
+		if err := FastCodec.Unmarshal([]byte(`{"track": `+test+`}`), new(AudioAnalysis)); err == nil {
+			t.Errorf("%s: no error", test)
+		}
+		r := jsonReader{data: []byte(test)}
+		info.decodeFast(&r)
+		if test != "" && strings.HasPrefix(test, "{") && r.err == nil && !strings.HasSuffix(test, "x") {
+			t.Errorf("%s: no error from decodeFast", test)
+		}
+	}
+}
+
+func BenchmarkFastCodecAudioAnalysis(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "audio_analysis.json"))
+	WithCodec(FastCodec)(&c)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioAnalysis("foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFastCodecPlaylistTracks(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "playlist_track_page.json"))
+	WithCodec(FastCodec)(&c)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetPlaylistTracks("user", "playlist"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// fullAnalysis returns the golden audio analysis with its intervals
+// repeated to the numbers of a typical four minute track, for which the
+// decoding time outweighs that of making the request.
+func fullAnalysis(b *testing.B) []byte {
+	var a map[string]interface{}
+	if err := json.Unmarshal(readGolden(b, "audio_analysis.json"), &a); err != nil {
+		b.Fatal(err)
+	}
+	for key, n := range map[string]int{"bars": 120, "beats": 480, "sections": 10, "segments": 900, "tatums": 960} {
+		items := a[key].([]interface{})
+		var all []interface{}
+		for len(all) < n {
+			all = append(all, items...)
+		}
+		a[key] = all
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkStdCodecFullAudioAnalysis(b *testing.B) {
+	c := benchmarkClient(fullAnalysis(b))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioAnalysis("foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFastCodecFullAudioAnalysis(b *testing.B) {
+	c := benchmarkClient(fullAnalysis(b))
+	WithCodec(FastCodec)(&c)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioAnalysis("foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
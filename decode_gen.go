@@ -0,0 +1,2076 @@
+// Code generated by gendecode. DO NOT EDIT.
+
+package spotify
+
+func init() {
+	fastDecoders = decodeGenerated
+}
+
+// decodeGenerated decodes the value with its generated decoder, if it has one.
+func decodeGenerated(r *jsonReader, v interface{}) bool {
+	switch v := v.(type) {
+	case *AudioAnalysis:
+		v.decodeFast(r)
+	case *AudioFeatures:
+		v.decodeFast(r)
+	case *PlayHistory:
+		v.decodeFast(r)
+	case *TopArtists:
+		v.decodeFast(r)
+	case *TopTracks:
+		v.decodeFast(r)
+	case *FullTrack:
+		v.decodeFast(r)
+	case *CategoryPage:
+		v.decodeFast(r)
+	case *FullArtistCursorPage:
+		v.decodeFast(r)
+	case *FullArtistPage:
+		v.decodeFast(r)
+	case *FullTrackPage:
+		v.decodeFast(r)
+	case *PlaylistTrackPage:
+		v.decodeFast(r)
+	case *SavedAlbumPage:
+		v.decodeFast(r)
+	case *SavedTrackPage:
+		v.decodeFast(r)
+	case *SimpleAlbumPage:
+		v.decodeFast(r)
+	case *SimplePlaylistPage:
+		v.decodeFast(r)
+	case *SimpleTrackPage:
+		v.decodeFast(r)
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *AlbumInfo) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "album_type":
+			v.AlbumType = r.string()
+		case "artists":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []ArtistInfo{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 ArtistInfo
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		case "available_markets":
+			if r.null() {
+				v.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.AvailableMarkets == nil {
+					v.AvailableMarkets = []string{}
+				}
+				v.AvailableMarkets = v.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.AvailableMarkets = append(v.AvailableMarkets, e0)
+				}
+			}
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		case "name":
+			v.Name = r.string()
+		case "type":
+			v.ItemType = r.string()
+		case "release_date":
+			v.ReleaseDate = r.string()
+		case "release_date_precision":
+			v.ReleaseDatePrecision = DatePrecision(r.string())
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *ArtistInfo) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "name":
+			v.Name = r.string()
+		case "type":
+			v.Type = r.string()
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *ArtistItem) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "followers":
+			v.Followers.decodeFast(r)
+		case "genres":
+			if r.null() {
+				v.Genres = nil
+			} else if r.beginArray() {
+				if v.Genres == nil {
+					v.Genres = []string{}
+				}
+				v.Genres = v.Genres[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.Genres = append(v.Genres, e0)
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		case "name":
+			v.Name = r.string()
+		case "popularity":
+			v.Popularity = Popularity(r.int64())
+		case "type":
+			v.Type = r.string()
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *AudioAnalysis) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "bars":
+			if r.null() {
+				v.Bars = nil
+			} else if r.beginArray() {
+				if v.Bars == nil {
+					v.Bars = []BeatBar{}
+				}
+				v.Bars = v.Bars[:0]
+				for r.nextElem() {
+					var e0 BeatBar
+					e0.decodeFast(r)
+					v.Bars = append(v.Bars, e0)
+				}
+			}
+		case "beats":
+			if r.null() {
+				v.Beats = nil
+			} else if r.beginArray() {
+				if v.Beats == nil {
+					v.Beats = []BeatBar{}
+				}
+				v.Beats = v.Beats[:0]
+				for r.nextElem() {
+					var e0 BeatBar
+					e0.decodeFast(r)
+					v.Beats = append(v.Beats, e0)
+				}
+			}
+		case "meta":
+			v.Meta.decodeFast(r)
+		case "sections":
+			if r.null() {
+				v.Sections = nil
+			} else if r.beginArray() {
+				if v.Sections == nil {
+					v.Sections = []Section{}
+				}
+				v.Sections = v.Sections[:0]
+				for r.nextElem() {
+					var e0 Section
+					e0.decodeFast(r)
+					v.Sections = append(v.Sections, e0)
+				}
+			}
+		case "segments":
+			if r.null() {
+				v.Segments = nil
+			} else if r.beginArray() {
+				if v.Segments == nil {
+					v.Segments = []Segment{}
+				}
+				v.Segments = v.Segments[:0]
+				for r.nextElem() {
+					var e0 Segment
+					e0.decodeFast(r)
+					v.Segments = append(v.Segments, e0)
+				}
+			}
+		case "tatums":
+			if r.null() {
+				v.Tatums = nil
+			} else if r.beginArray() {
+				if v.Tatums == nil {
+					v.Tatums = []Tatum{}
+				}
+				v.Tatums = v.Tatums[:0]
+				for r.nextElem() {
+					var e0 Tatum
+					e0.decodeFast(r)
+					v.Tatums = append(v.Tatums, e0)
+				}
+			}
+		case "track":
+			v.TrackInfo.decodeFast(r)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *AudioFeatures) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "acousticness":
+			v.Acousticness = float32(r.float64())
+		case "analysis_url":
+			v.AnalysisURL = r.string()
+		case "danceability":
+			v.Danceability = float32(r.float64())
+		case "duration_ms":
+			v.Duration = int(r.int64())
+		case "energy":
+			v.Energy = float32(r.float64())
+		case "id":
+			v.ID = ID(r.string())
+		case "instrumentalness":
+			v.Instrumentalness = float32(r.float64())
+		case "key":
+			v.Key = int(r.int64())
+		case "liveness":
+			v.Liveness = float32(r.float64())
+		case "loudness":
+			v.Loudness = float32(r.float64())
+		case "mode":
+			v.Mode = int(r.int64())
+		case "speechiness":
+			v.Speechiness = float32(r.float64())
+		case "tempo":
+			v.Tempo = float32(r.float64())
+		case "time_signature":
+			v.TimeSignature = int(r.int64())
+		case "track_href":
+			v.TrackURL = r.string()
+		case "uri":
+			v.URI = URI(r.string())
+		case "valence":
+			v.Valence = float32(r.float64())
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *BeatBar) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "start":
+			v.Start = r.float64()
+		case "duration":
+			v.Duration = r.float64()
+		case "confidence":
+			v.Confidence = r.float64()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Category) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.Endpoint = r.string()
+		case "icons":
+			if r.null() {
+				v.Icons = nil
+			} else if r.beginArray() {
+				if v.Icons == nil {
+					v.Icons = Images{}
+				}
+				v.Icons = v.Icons[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Icons = append(v.Icons, e0)
+				}
+			}
+		case "id":
+			v.ID = r.string()
+		case "name":
+			v.Name = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *CategoryPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Categories = nil
+			} else if r.beginArray() {
+				if v.Categories == nil {
+					v.Categories = []Category{}
+				}
+				v.Categories = v.Categories[:0]
+				for r.nextElem() {
+					var e0 Category
+					e0.decodeFast(r)
+					v.Categories = append(v.Categories, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Copyright) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "text":
+			v.Text = r.string()
+		case "type":
+			v.Type = CopyrightType(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Cursor) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "after":
+			v.After = r.string()
+		case "before":
+			v.Before = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Followers) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "total":
+			v.Count = uint(r.uint64())
+		case "href":
+			if r.null() {
+				v.Endpoint = nil
+			} else {
+				if v.Endpoint == nil {
+					v.Endpoint = new(string)
+				}
+				(*v.Endpoint) = r.string()
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullAlbum) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "name":
+			v.SimpleAlbum.Name = r.string()
+		case "album_type":
+			v.SimpleAlbum.AlbumType = r.string()
+		case "id":
+			v.SimpleAlbum.ID = ID(r.string())
+		case "uri":
+			v.SimpleAlbum.URI = URI(r.string())
+		case "available_markets":
+			if r.null() {
+				v.SimpleAlbum.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.SimpleAlbum.AvailableMarkets == nil {
+					v.SimpleAlbum.AvailableMarkets = []string{}
+				}
+				v.SimpleAlbum.AvailableMarkets = v.SimpleAlbum.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.SimpleAlbum.AvailableMarkets = append(v.SimpleAlbum.AvailableMarkets, e0)
+				}
+			}
+		case "href":
+			v.SimpleAlbum.Endpoint = r.string()
+		case "images":
+			if r.null() {
+				v.SimpleAlbum.Images = nil
+			} else if r.beginArray() {
+				if v.SimpleAlbum.Images == nil {
+					v.SimpleAlbum.Images = Images{}
+				}
+				v.SimpleAlbum.Images = v.SimpleAlbum.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.SimpleAlbum.Images = append(v.SimpleAlbum.Images, e0)
+				}
+			}
+		case "external_urls":
+			if r.null() {
+				v.SimpleAlbum.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.SimpleAlbum.ExternalURLs == nil {
+					v.SimpleAlbum.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.SimpleAlbum.ExternalURLs[k0] = e0
+				}
+			}
+		case "release_date":
+			v.SimpleAlbum.ReleaseDate = r.string()
+		case "release_date_precision":
+			v.SimpleAlbum.ReleaseDatePrecision = DatePrecision(r.string())
+		case "restrictions":
+			if r.null() {
+				v.SimpleAlbum.Restrictions = nil
+			} else {
+				if v.SimpleAlbum.Restrictions == nil {
+					v.SimpleAlbum.Restrictions = new(Restrictions)
+				}
+				(*v.SimpleAlbum.Restrictions).decodeFast(r)
+			}
+		case "type":
+			v.SimpleAlbum.Type = r.string()
+		case "artists":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []SimpleArtist{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 SimpleArtist
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		case "copyrights":
+			if r.null() {
+				v.Copyrights = nil
+			} else if r.beginArray() {
+				if v.Copyrights == nil {
+					v.Copyrights = []Copyright{}
+				}
+				v.Copyrights = v.Copyrights[:0]
+				for r.nextElem() {
+					var e0 Copyright
+					e0.decodeFast(r)
+					v.Copyrights = append(v.Copyrights, e0)
+				}
+			}
+		case "genres":
+			if r.null() {
+				v.Genres = nil
+			} else if r.beginArray() {
+				if v.Genres == nil {
+					v.Genres = []string{}
+				}
+				v.Genres = v.Genres[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.Genres = append(v.Genres, e0)
+				}
+			}
+		case "popularity":
+			v.Popularity = Popularity(r.int64())
+		case "tracks":
+			v.Tracks.decodeFast(r)
+		case "external_ids":
+			r.unmarshal(&v.ExternalIDs)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullArtist) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "name":
+			v.SimpleArtist.Name = r.string()
+		case "id":
+			v.SimpleArtist.ID = ID(r.string())
+		case "uri":
+			v.SimpleArtist.URI = URI(r.string())
+		case "href":
+			v.SimpleArtist.Endpoint = r.string()
+		case "external_urls":
+			if r.null() {
+				v.SimpleArtist.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.SimpleArtist.ExternalURLs == nil {
+					v.SimpleArtist.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.SimpleArtist.ExternalURLs[k0] = e0
+				}
+			}
+		case "type":
+			v.SimpleArtist.Type = r.string()
+		case "popularity":
+			v.Popularity = Popularity(r.int64())
+		case "genres":
+			if r.null() {
+				v.Genres = nil
+			} else if r.beginArray() {
+				if v.Genres == nil {
+					v.Genres = []string{}
+				}
+				v.Genres = v.Genres[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.Genres = append(v.Genres, e0)
+				}
+			}
+		case "followers":
+			v.Followers.decodeFast(r)
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullArtistCursorPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.cursorPage.Endpoint = r.string()
+		case "limit":
+			v.cursorPage.Limit = int(r.int64())
+		case "next":
+			v.cursorPage.Next = r.string()
+		case "total":
+			v.cursorPage.Total = int(r.int64())
+		case "cursors":
+			v.cursorPage.Cursor.decodeFast(r)
+		case "items":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []FullArtist{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 FullArtist
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullArtistPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []FullArtist{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 FullArtist
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullTrack) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "artists":
+			if r.null() {
+				v.SimpleTrack.Artists = nil
+			} else if r.beginArray() {
+				if v.SimpleTrack.Artists == nil {
+					v.SimpleTrack.Artists = []SimpleArtist{}
+				}
+				v.SimpleTrack.Artists = v.SimpleTrack.Artists[:0]
+				for r.nextElem() {
+					var e0 SimpleArtist
+					e0.decodeFast(r)
+					v.SimpleTrack.Artists = append(v.SimpleTrack.Artists, e0)
+				}
+			}
+		case "available_markets":
+			if r.null() {
+				v.SimpleTrack.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.SimpleTrack.AvailableMarkets == nil {
+					v.SimpleTrack.AvailableMarkets = []string{}
+				}
+				v.SimpleTrack.AvailableMarkets = v.SimpleTrack.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.SimpleTrack.AvailableMarkets = append(v.SimpleTrack.AvailableMarkets, e0)
+				}
+			}
+		case "disc_number":
+			v.SimpleTrack.DiscNumber = int(r.int64())
+		case "duration_ms":
+			v.SimpleTrack.Duration = int(r.int64())
+		case "explicit":
+			v.SimpleTrack.Explicit = r.bool()
+		case "external_urls":
+			if r.null() {
+				v.SimpleTrack.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.SimpleTrack.ExternalURLs == nil {
+					v.SimpleTrack.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.SimpleTrack.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.SimpleTrack.Endpoint = r.string()
+		case "id":
+			v.SimpleTrack.ID = ID(r.string())
+		case "name":
+			v.SimpleTrack.Name = r.string()
+		case "preview_url":
+			if r.null() {
+				v.SimpleTrack.PreviewURL = nil
+			} else {
+				if v.SimpleTrack.PreviewURL == nil {
+					v.SimpleTrack.PreviewURL = new(string)
+				}
+				(*v.SimpleTrack.PreviewURL) = r.string()
+			}
+		case "track_number":
+			v.SimpleTrack.TrackNumber = int(r.int64())
+		case "uri":
+			v.SimpleTrack.URI = URI(r.string())
+		case "is_playable":
+			v.SimpleTrack.IsPlayable = r.bool()
+		case "linked_from":
+			if r.null() {
+				v.SimpleTrack.LinkedFrom = nil
+			} else {
+				if v.SimpleTrack.LinkedFrom == nil {
+					v.SimpleTrack.LinkedFrom = new(LinkedTrack)
+				}
+				(*v.SimpleTrack.LinkedFrom).decodeFast(r)
+			}
+		case "restrictions":
+			if r.null() {
+				v.SimpleTrack.Restrictions = nil
+			} else {
+				if v.SimpleTrack.Restrictions == nil {
+					v.SimpleTrack.Restrictions = new(Restrictions)
+				}
+				(*v.SimpleTrack.Restrictions).decodeFast(r)
+			}
+		case "type":
+			v.SimpleTrack.Type = r.string()
+		case "album":
+			if r.null() {
+				v.Album = nil
+			} else {
+				if v.Album == nil {
+					v.Album = new(SimpleAlbum)
+				}
+				(*v.Album).decodeFast(r)
+			}
+		case "external_ids":
+			r.unmarshal(&v.ExternalIDs)
+		case "popularity":
+			v.Popularity = Popularity(r.int64())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *FullTrackPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Tracks = nil
+			} else if r.beginArray() {
+				if v.Tracks == nil {
+					v.Tracks = []FullTrack{}
+				}
+				v.Tracks = v.Tracks[:0]
+				for r.nextElem() {
+					var e0 FullTrack
+					e0.decodeFast(r)
+					v.Tracks = append(v.Tracks, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *HistoryItem) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "track":
+			v.Track.decodeFast(r)
+		case "played_at":
+			r.unmarshal(&v.PlayedAt)
+		case "context":
+			if r.null() {
+				v.Context = nil
+			} else {
+				if v.Context == nil {
+					v.Context = new(TrackContext)
+				}
+				(*v.Context).decodeFast(r)
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Image) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "height":
+			v.Height = int(r.int64())
+		case "width":
+			v.Width = int(r.int64())
+		case "url":
+			v.URL = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *LinkedTrack) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "type":
+			v.Type = r.string()
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Meta) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "analyzer_version":
+			v.Analyzer = r.string()
+		case "platform":
+			v.Platform = r.string()
+		case "detailed_status":
+			v.Status = r.string()
+		case "status_code":
+			v.StatusCode = int(r.int64())
+		case "timestamp":
+			v.Timestamp = int(r.int64())
+		case "analysis_time":
+			v.AnalysisTime = r.float64()
+		case "input_process":
+			v.InputProcess = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *PlayHistory) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "items":
+			if r.null() {
+				v.Items = nil
+			} else if r.beginArray() {
+				if v.Items == nil {
+					v.Items = []HistoryItem{}
+				}
+				v.Items = v.Items[:0]
+				for r.nextElem() {
+					var e0 HistoryItem
+					e0.decodeFast(r)
+					v.Items = append(v.Items, e0)
+				}
+			}
+		case "next":
+			v.Next = r.string()
+		case "limit":
+			v.Limit = int(r.int64())
+		case "href":
+			v.Endpoint = r.string()
+		case "cursors":
+			v.Cursor.decodeFast(r)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *PlaylistTrack) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "added_at":
+			r.unmarshal(&v.AddedAt)
+		case "added_by":
+			v.AddedBy.decodeFast(r)
+		case "track":
+			v.Track.decodeFast(r)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *PlaylistTrackPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Tracks = nil
+			} else if r.beginArray() {
+				if v.Tracks == nil {
+					v.Tracks = []PlaylistTrack{}
+				}
+				v.Tracks = v.Tracks[:0]
+				for r.nextElem() {
+					var e0 PlaylistTrack
+					e0.decodeFast(r)
+					v.Tracks = append(v.Tracks, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *PlaylistTracks) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.Endpoint = r.string()
+		case "total":
+			v.Total = uint(r.uint64())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Restrictions) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "reason":
+			v.Reason = RestrictionReason(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SavedAlbum) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "added_at":
+			r.unmarshal(&v.AddedAt)
+		case "album":
+			v.FullAlbum.decodeFast(r)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SavedAlbumPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Albums = nil
+			} else if r.beginArray() {
+				if v.Albums == nil {
+					v.Albums = []SavedAlbum{}
+				}
+				v.Albums = v.Albums[:0]
+				for r.nextElem() {
+					var e0 SavedAlbum
+					e0.decodeFast(r)
+					v.Albums = append(v.Albums, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SavedTrack) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "added_at":
+			r.unmarshal(&v.AddedAt)
+		case "track":
+			v.FullTrack.decodeFast(r)
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SavedTrackPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Tracks = nil
+			} else if r.beginArray() {
+				if v.Tracks == nil {
+					v.Tracks = []SavedTrack{}
+				}
+				v.Tracks = v.Tracks[:0]
+				for r.nextElem() {
+					var e0 SavedTrack
+					e0.decodeFast(r)
+					v.Tracks = append(v.Tracks, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Section) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "start":
+			v.Start = r.float64()
+		case "duration":
+			v.Duration = r.float64()
+		case "confidence":
+			v.Confidence = r.float64()
+		case "loudness":
+			v.Loudness = r.float64()
+		case "tempo":
+			v.Tempo = r.float64()
+		case "tempo_confidence":
+			v.TempoConfidence = r.float64()
+		case "key":
+			v.Key = int(r.int64())
+		case "key_confidence":
+			v.KeyConfidence = r.float64()
+		case "mode":
+			v.Mode = int(r.int64())
+		case "mode_confidence":
+			v.ModeConfidence = r.float64()
+		case "time_signature":
+			v.TimeSignature = int(r.int64())
+		case "time_signature_confidence":
+			v.TimeSigConfidence = r.float64()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Segment) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "start":
+			v.Start = r.float64()
+		case "duration":
+			v.Duration = r.float64()
+		case "confidence":
+			v.Confidence = r.float64()
+		case "loudness_start":
+			v.LoudnessStart = r.float64()
+		case "loudness_max_time":
+			v.LoudnessMaxTime = r.float64()
+		case "loudness_max":
+			v.LoudnessMax = r.float64()
+		case "loudness_end":
+			v.LoudnessEnd = r.float64()
+		case "pitches":
+			if r.null() {
+				v.Pitches = nil
+			} else if r.beginArray() {
+				if v.Pitches == nil {
+					v.Pitches = []float64{}
+				}
+				v.Pitches = v.Pitches[:0]
+				for r.nextElem() {
+					var e0 float64
+					e0 = r.float64()
+					v.Pitches = append(v.Pitches, e0)
+				}
+			}
+		case "timbre":
+			if r.null() {
+				v.Timbre = nil
+			} else if r.beginArray() {
+				if v.Timbre == nil {
+					v.Timbre = []float64{}
+				}
+				v.Timbre = v.Timbre[:0]
+				for r.nextElem() {
+					var e0 float64
+					e0 = r.float64()
+					v.Timbre = append(v.Timbre, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimpleAlbum) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "name":
+			v.Name = r.string()
+		case "album_type":
+			v.AlbumType = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "uri":
+			v.URI = URI(r.string())
+		case "available_markets":
+			if r.null() {
+				v.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.AvailableMarkets == nil {
+					v.AvailableMarkets = []string{}
+				}
+				v.AvailableMarkets = v.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.AvailableMarkets = append(v.AvailableMarkets, e0)
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "release_date":
+			v.ReleaseDate = r.string()
+		case "release_date_precision":
+			v.ReleaseDatePrecision = DatePrecision(r.string())
+		case "restrictions":
+			if r.null() {
+				v.Restrictions = nil
+			} else {
+				if v.Restrictions == nil {
+					v.Restrictions = new(Restrictions)
+				}
+				(*v.Restrictions).decodeFast(r)
+			}
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimpleAlbumPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Albums = nil
+			} else if r.beginArray() {
+				if v.Albums == nil {
+					v.Albums = []SimpleAlbum{}
+				}
+				v.Albums = v.Albums[:0]
+				for r.nextElem() {
+					var e0 SimpleAlbum
+					e0.decodeFast(r)
+					v.Albums = append(v.Albums, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimpleArtist) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "name":
+			v.Name = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "uri":
+			v.URI = URI(r.string())
+		case "href":
+			v.Endpoint = r.string()
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimplePlaylist) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "collaborative":
+			v.Collaborative = r.bool()
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		case "name":
+			v.Name = r.string()
+		case "owner":
+			v.Owner.decodeFast(r)
+		case "public":
+			v.IsPublic = r.bool()
+		case "snapshot_id":
+			v.SnapshotID = r.string()
+		case "tracks":
+			v.Tracks.decodeFast(r)
+		case "uri":
+			v.URI = URI(r.string())
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimplePlaylistPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Playlists = nil
+			} else if r.beginArray() {
+				if v.Playlists == nil {
+					v.Playlists = []SimplePlaylist{}
+				}
+				v.Playlists = v.Playlists[:0]
+				for r.nextElem() {
+					var e0 SimplePlaylist
+					e0.decodeFast(r)
+					v.Playlists = append(v.Playlists, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimpleTrack) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "artists":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []SimpleArtist{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 SimpleArtist
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		case "available_markets":
+			if r.null() {
+				v.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.AvailableMarkets == nil {
+					v.AvailableMarkets = []string{}
+				}
+				v.AvailableMarkets = v.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.AvailableMarkets = append(v.AvailableMarkets, e0)
+				}
+			}
+		case "disc_number":
+			v.DiscNumber = int(r.int64())
+		case "duration_ms":
+			v.Duration = int(r.int64())
+		case "explicit":
+			v.Explicit = r.bool()
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "name":
+			v.Name = r.string()
+		case "preview_url":
+			if r.null() {
+				v.PreviewURL = nil
+			} else {
+				if v.PreviewURL == nil {
+					v.PreviewURL = new(string)
+				}
+				(*v.PreviewURL) = r.string()
+			}
+		case "track_number":
+			v.TrackNumber = int(r.int64())
+		case "uri":
+			v.URI = URI(r.string())
+		case "is_playable":
+			v.IsPlayable = r.bool()
+		case "linked_from":
+			if r.null() {
+				v.LinkedFrom = nil
+			} else {
+				if v.LinkedFrom == nil {
+					v.LinkedFrom = new(LinkedTrack)
+				}
+				(*v.LinkedFrom).decodeFast(r)
+			}
+		case "restrictions":
+			if r.null() {
+				v.Restrictions = nil
+			} else {
+				if v.Restrictions == nil {
+					v.Restrictions = new(Restrictions)
+				}
+				(*v.Restrictions).decodeFast(r)
+			}
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *SimpleTrackPage) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "href":
+			v.basePage.Endpoint = r.string()
+		case "limit":
+			v.basePage.Limit = int(r.int64())
+		case "offset":
+			v.basePage.Offset = int(r.int64())
+		case "total":
+			v.basePage.Total = int(r.int64())
+		case "next":
+			v.basePage.Next = r.string()
+		case "previous":
+			v.basePage.Previous = r.string()
+		case "items":
+			if r.null() {
+				v.Tracks = nil
+			} else if r.beginArray() {
+				if v.Tracks == nil {
+					v.Tracks = []SimpleTrack{}
+				}
+				v.Tracks = v.Tracks[:0]
+				for r.nextElem() {
+					var e0 SimpleTrack
+					e0.decodeFast(r)
+					v.Tracks = append(v.Tracks, e0)
+				}
+			}
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *Tatum) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "start":
+			v.Start = r.float64()
+		case "duration":
+			v.Duration = r.float64()
+		case "confidence":
+			v.Confidence = r.float64()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *TopArtists) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "items":
+			if r.null() {
+				v.Items = nil
+			} else if r.beginArray() {
+				if v.Items == nil {
+					v.Items = []ArtistItem{}
+				}
+				v.Items = v.Items[:0]
+				for r.nextElem() {
+					var e0 ArtistItem
+					e0.decodeFast(r)
+					v.Items = append(v.Items, e0)
+				}
+			}
+		case "total":
+			v.Total = int(r.int64())
+		case "limit":
+			v.Limit = int(r.int64())
+		case "offset":
+			v.Offset = int(r.int64())
+		case "href":
+			v.Endpoint = r.string()
+		case "previous":
+			v.Previous = r.string()
+		case "next":
+			v.Next = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *TopTracks) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "items":
+			if r.null() {
+				v.Items = nil
+			} else if r.beginArray() {
+				if v.Items == nil {
+					v.Items = []TrackItem{}
+				}
+				v.Items = v.Items[:0]
+				for r.nextElem() {
+					var e0 TrackItem
+					e0.decodeFast(r)
+					v.Items = append(v.Items, e0)
+				}
+			}
+		case "total":
+			v.Total = int(r.int64())
+		case "limit":
+			v.Limit = int(r.int64())
+		case "offset":
+			v.Offset = int(r.int64())
+		case "href":
+			v.Endpoint = r.string()
+		case "previous":
+			v.Previous = r.string()
+		case "next":
+			v.Next = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *TrackContext) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "type":
+			v.Type = r.string()
+		case "href":
+			v.Endpoint = r.string()
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *TrackInfo) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "num_samples":
+			v.NumSamples = int(r.int64())
+		case "duration":
+			v.Duration = r.float64()
+		case "sample_md5":
+			v.SampleMD5 = r.string()
+		case "offset_seconds":
+			v.OffsetSeconds = r.float64()
+		case "window_seconds":
+			v.WindowSeconds = r.float64()
+		case "analysis_sample_rate":
+			v.AnalysisSampleRate = int(r.int64())
+		case "analysis_channels":
+			v.AnalysisChannels = int(r.int64())
+		case "end_of_fade_in":
+			v.EndFadeIn = r.float64()
+		case "start_of_fade_out":
+			v.StartFadeOut = r.float64()
+		case "loudness":
+			v.Loudness = r.float64()
+		case "tempo":
+			v.Tempo = r.float64()
+		case "tempo_confidence":
+			v.TempoConfidence = r.float64()
+		case "time_signature":
+			v.TimeSignature = int(r.int64())
+		case "time_signature_confidence":
+			v.TimeSigConfidence = r.float64()
+		case "key":
+			v.Key = int(r.int64())
+		case "key_confidence":
+			v.KeyConfidence = r.float64()
+		case "mode":
+			v.Mode = int(r.int64())
+		case "mode_confidence":
+			v.ModeConfidence = r.float64()
+		case "codestring":
+			v.Codestring = r.string()
+		case "code_version":
+			v.CodeVersion = r.float64()
+		case "echoprintstring":
+			v.EchoPrintString = r.string()
+		case "echoprint_version":
+			v.EchoPrintVersion = r.float64()
+		case "synchstring":
+			v.SynchString = r.string()
+		case "synch_version":
+			v.SynchVersion = r.float64()
+		case "rhythmstring":
+			v.RhythmString = r.string()
+		case "rhythm_version":
+			v.RhythmVersion = r.float64()
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *TrackItem) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "album":
+			v.Album.decodeFast(r)
+		case "artists":
+			if r.null() {
+				v.Artists = nil
+			} else if r.beginArray() {
+				if v.Artists == nil {
+					v.Artists = []ArtistInfo{}
+				}
+				v.Artists = v.Artists[:0]
+				for r.nextElem() {
+					var e0 ArtistInfo
+					e0.decodeFast(r)
+					v.Artists = append(v.Artists, e0)
+				}
+			}
+		case "available_markets":
+			if r.null() {
+				v.AvailableMarkets = nil
+			} else if r.beginArray() {
+				if v.AvailableMarkets == nil {
+					v.AvailableMarkets = []string{}
+				}
+				v.AvailableMarkets = v.AvailableMarkets[:0]
+				for r.nextElem() {
+					var e0 string
+					e0 = r.string()
+					v.AvailableMarkets = append(v.AvailableMarkets, e0)
+				}
+			}
+		case "disc_number":
+			v.DiscNumber = int(r.int64())
+		case "duration_ms":
+			v.DurationMS = int(r.int64())
+		case "explicit":
+			v.Explicit = r.bool()
+		case "external_ids":
+			r.unmarshal(&v.ExternalIDs)
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = ID(r.string())
+		case "is_playable":
+			v.IsPlayable = r.bool()
+		case "name":
+			v.Name = r.string()
+		case "popularity":
+			v.Popularity = Popularity(r.int64())
+		case "preview_url":
+			if r.null() {
+				v.PreviewURL = nil
+			} else {
+				if v.PreviewURL == nil {
+					v.PreviewURL = new(string)
+				}
+				(*v.PreviewURL) = r.string()
+			}
+		case "track_number":
+			v.TrackNumber = int(r.int64())
+		case "type":
+			v.Type = r.string()
+		case "uri":
+			v.URI = URI(r.string())
+		default:
+			r.skip()
+		}
+	}
+}
+
+func (v *User) decodeFast(r *jsonReader) {
+	if !r.beginObject() {
+		return
+	}
+	for r.nextKey() {
+		switch string(r.key) {
+		case "display_name":
+			v.DisplayName = r.string()
+		case "external_urls":
+			if r.null() {
+				v.ExternalURLs = nil
+			} else if r.beginObject() {
+				if v.ExternalURLs == nil {
+					v.ExternalURLs = make(ExternalURLs)
+				}
+				for r.nextKey() {
+					k0 := string(r.key)
+					var e0 string
+					e0 = r.string()
+					v.ExternalURLs[k0] = e0
+				}
+			}
+		case "followers":
+			v.Followers.decodeFast(r)
+		case "href":
+			v.Endpoint = r.string()
+		case "id":
+			v.ID = r.string()
+		case "images":
+			if r.null() {
+				v.Images = nil
+			} else if r.beginArray() {
+				if v.Images == nil {
+					v.Images = Images{}
+				}
+				v.Images = v.Images[:0]
+				for r.nextElem() {
+					var e0 Image
+					e0.decodeFast(r)
+					v.Images = append(v.Images, e0)
+				}
+			}
+		case "uri":
+			v.URI = URI(r.string())
+		case "type":
+			v.Type = r.string()
+		default:
+			r.skip()
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const driftedUser = `{
+	"display_name": "Jason",
+	"id": "jason",
+	"uri": "spotify:user:jason",
+	"pronouns": "they/them"
+}`
+
+func TestLenientDecoding(t *testing.T) {
+	c := testClientString(http.StatusOK, driftedUser)
+	user, err := c.GetUsersPublicProfile("jason")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.DisplayName != "Jason" {
+		t.Errorf("got display name %q, want Jason", user.DisplayName)
+	}
+}
+
+func TestTrailingData(t *testing.T) {
+	// Anything after the first value is ignored, with every codec.
+	for _, codec := range []Codec{nil, StdCodec, FastCodec} {
+		c := testClientString(http.StatusOK, `{"id": "jason"} {"id": "alice"}`)
+		c.codec = codec
+		var raw json.RawMessage
+		WithRawResponses(func(v interface{}, r json.RawMessage) { raw = r })(c)
+		user, err := c.GetUsersPublicProfile("jason")
+		if err != nil {
+			t.Errorf("codec %T: %v", codec, err)
+			continue
+		}
+		if user.ID != "jason" || string(raw) != `{"id": "jason"}` {
+			t.Errorf("codec %T: got ID %q and raw response %s", codec, user.ID, raw)
+		}
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	c := testClientString(http.StatusOK, driftedUser)
+	WithStrictDecoding()(c)
+	_, err := c.GetUsersPublicProfile("jason")
+	e, ok := err.(DecodeError)
+	if !ok {
+		t.Fatalf("got error %v, want a DecodeError", err)
+	}
+	if e.Type != "*spotify.User" || e.Field != "pronouns" {
+		t.Errorf("got type %q and field %q, want *spotify.User and pronouns", e.Type, e.Field)
+	}
+
+	c = testClientString(http.StatusOK, `{"id": 42}`)
+	WithStrictDecoding()(c)
+	_, err = c.GetUsersPublicProfile("jason")
+	if e, ok := err.(DecodeError); !ok || e.Field != "" {
+		t.Errorf("got error %#v, want a DecodeError without a field", err)
+	}
+}
+
+func TestStrictDecodingGolden(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	WithStrictDecoding()(c)
+	if _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRawResponses(t *testing.T) {
+	c := testClientString(http.StatusOK, driftedUser)
+	var pronouns string
+	WithRawResponses(func(v interface{}, raw json.RawMessage) {
+		if _, ok := v.(*User); !ok {
+			t.Errorf("got %T, want *User", v)
+		}
+		var extra struct {
+			Pronouns string `json:"pronouns"`
+		}
+		if err := json.Unmarshal(raw, &extra); err != nil {
+			t.Error(err)
+		}
+		pronouns = extra.Pronouns
+	})(c)
+	user, err := c.GetUsersPublicProfile("jason")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.DisplayName != "Jason" || pronouns != "they/them" {
+		t.Errorf("got display name %q and pronouns %q", user.DisplayName, pronouns)
+	}
+}
+
+// readGolden returns the contents of the golden file.
+func readGolden(tb testing.TB, golden string) []byte {
+	body, err := ioutil.ReadFile("test_data/golden/" + golden)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return body
+}
+
+// benchmarkClient returns a client that answers every request with body.
+func benchmarkClient(body []byte) Client {
+	return NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+	})})
+}
+
+func BenchmarkDecodeRecentTracks(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "play_history.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CurrentUserRecentTracks(50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeAudioFeatures(b *testing.B) {
+	features := readGolden(b, "audio_features.json")
+	c := benchmarkClient([]byte(`{"audio_features":[` + string(features) + `]}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioFeatures("4JpKVNYnVcJ8tuMKjAj50A"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTrack(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "track_full.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	WithMaxResponseBytes(100)(c)
+	_, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY")
+	if e, ok := err.(ResponseTooLargeError); !ok || e.Limit != 100 {
+		t.Errorf("got error %v, want a ResponseTooLargeError", err)
+	}
+
+	c = testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	WithMaxResponseBytes(0)(c)
+	if _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); err != nil {
+		t.Error(err)
+	}
+}
+
+// countingCodec counts the responses it decodes.
+type countingCodec struct {
+	n int
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.n++
+	return json.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	codec := new(countingCodec)
+	c := testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	WithCodec(codec)(c)
+	track, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codec.n != 1 || track.ID != "1zHlj4dQ8ZAtrayhuDDmkY" {
+		t.Errorf("codec decoded %d responses, track has ID %s", codec.n, track.ID)
+	}
+}
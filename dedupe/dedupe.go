@@ -0,0 +1,313 @@
+// Package dedupe finds duplicate tracks across a user's saved tracks and
+// playlists, and can remove them.
+//
+// Tracks are considered duplicates when they have the same Spotify ID, when
+// one has been relinked from the other (see the linked_from attribute), or
+// when their titles, primary artists and durations match closely enough
+// that they are very likely the same recording released more than once,
+// for example on a single and on an album.
+package dedupe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/export"
+)
+
+// DefaultTolerance is the largest difference in duration between two tracks
+// that are fuzzy matched, if Deduper.Tolerance isn't set.
+const DefaultTolerance = 3 * time.Second
+
+// Reason describes why a group of tracks are duplicates.
+type Reason int
+
+const (
+	// SameID means every track in the group has the same Spotify ID.
+	SameID Reason = iota
+	// Relinked means the tracks have different IDs, but were relinked from
+	// the same original track.
+	Relinked
+	// Fuzzy means the tracks have the same title and primary artist and
+	// roughly the same duration.
+	Fuzzy
+)
+
+func (r Reason) String() string {
+	switch r {
+	case SameID:
+		return "same ID"
+	case Relinked:
+		return "relinked"
+	case Fuzzy:
+		return "fuzzy match"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// Occurrence is a track at a particular place in the user's library.
+type Occurrence struct {
+	// Playlist is the playlist containing the track, or nil if the track
+	// is one of the user's saved tracks.
+	Playlist *spotify.SimplePlaylist
+	// Position is the index of the track in the playlist or saved tracks.
+	Position int
+	AddedAt  time.Time
+	Track    spotify.FullTrack
+}
+
+// Saved reports whether the occurrence is in the user's saved tracks.
+func (o Occurrence) Saved() bool {
+	return o.Playlist == nil
+}
+
+// collection identifies the playlist or saved tracks containing o.
+func (o Occurrence) collection() spotify.ID {
+	if o.Playlist == nil {
+		return ""
+	}
+	return o.Playlist.ID
+}
+
+// originalID returns the ID of the track as it was added to the
+// collection, before any relinking.
+func (o Occurrence) originalID() spotify.ID {
+	if o.Track.LinkedFrom != nil {
+		return o.Track.LinkedFrom.ID
+	}
+	return o.Track.ID
+}
+
+// originalURI returns the URI of the track as it was added to the
+// collection, which is what playlist edits refer to.
+func (o Occurrence) originalURI() spotify.URI {
+	if o.Track.LinkedFrom != nil {
+		return o.Track.LinkedFrom.URI
+	}
+	return o.Track.URI
+}
+
+// Group is a set of occurrences of the same track, ordered by when they
+// were added.
+type Group struct {
+	Reason      Reason
+	Occurrences []Occurrence
+}
+
+// Removable returns the occurrences that Deduper.Remove deletes: every
+// occurrence of the track in a playlist or the saved tracks, except the
+// first one added there.  A track that appears once in each of several
+// playlists isn't removed from any of them.
+func (g Group) Removable() []Occurrence {
+	kept := make(map[spotify.ID]bool)
+	var extra []Occurrence
+	for _, o := range g.Occurrences {
+		if kept[o.collection()] {
+			extra = append(extra, o)
+			continue
+		}
+		kept[o.collection()] = true
+	}
+	return extra
+}
+
+// Report lists the duplicates found in a library.
+type Report struct {
+	Groups []Group
+}
+
+// Removable returns the occurrences that Deduper.Remove deletes.
+func (r *Report) Removable() []Occurrence {
+	var extra []Occurrence
+	for _, g := range r.Groups {
+		extra = append(extra, g.Removable()...)
+	}
+	return extra
+}
+
+// WriteText writes a human readable summary of the report to w.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, g := range r.Groups {
+		t := g.Occurrences[0].Track
+		if _, err := fmt.Fprintf(w, "%s - %s (%s)\n", artistName(t), t.Name, g.Reason); err != nil {
+			return err
+		}
+		for _, o := range g.Occurrences {
+			where := "saved tracks"
+			if o.Playlist != nil {
+				where = fmt.Sprintf("playlist %q", o.Playlist.Name)
+			}
+			if _, err := fmt.Fprintf(w, "\t%s #%d %s\n", where, o.Position+1, o.Track.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Occurrences lists every track in the saved tracks and playlists of lib.
+func Occurrences(lib *export.Library) []Occurrence {
+	var occs []Occurrence
+	for i, t := range lib.SavedTracks {
+		occs = append(occs, Occurrence{Position: i, AddedAt: t.AddedAt, Track: t.FullTrack})
+	}
+	for i := range lib.Playlists {
+		p := &lib.Playlists[i]
+		for j, t := range p.Items {
+			occs = append(occs, Occurrence{Playlist: &p.SimplePlaylist, Position: j, AddedAt: t.AddedAt, Track: t.Track})
+		}
+	}
+	return occs
+}
+
+// Detect groups duplicate occurrences.  Fuzzy matching is only done if
+// tolerance is positive; tracks whose durations differ by more than the
+// tolerance aren't matched.
+func Detect(occs []Occurrence, tolerance time.Duration) *Report {
+	parent := make([]int, len(occs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		parent[find(i)] = find(j)
+	}
+
+	byID := make(map[spotify.ID]int)
+	link := func(id spotify.ID, i int) {
+		// local files have no ID
+		if id == "" {
+			return
+		}
+		if j, ok := byID[id]; ok {
+			union(i, j)
+			return
+		}
+		byID[id] = i
+	}
+	for i, o := range occs {
+		link(o.Track.ID, i)
+		link(o.originalID(), i)
+	}
+
+	if tolerance > 0 {
+		buckets := make(map[string][]int)
+		for i, o := range occs {
+			key := normalize(o.Track.Name) + "\x00" + normalize(artistName(o.Track))
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, b := range buckets {
+			sort.Sort(byDuration{occs, b})
+			for k := 1; k < len(b); k++ {
+				d := occs[b[k]].Track.TimeDuration() - occs[b[k-1]].Track.TimeDuration()
+				if d <= tolerance {
+					union(b[k], b[k-1])
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	var roots []int
+	for i := range occs {
+		r := find(i)
+		if members[r] == nil {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], i)
+	}
+	report := &Report{}
+	for _, r := range roots {
+		idx := members[r]
+		if len(idx) < 2 {
+			continue
+		}
+		g := Group{Reason: SameID}
+		for _, i := range idx {
+			o := occs[i]
+			g.Occurrences = append(g.Occurrences, o)
+			first := g.Occurrences[0]
+			switch {
+			case o.Track.ID != "" && o.Track.ID == first.Track.ID:
+			case o.originalID() != "" && (o.originalID() == first.originalID() ||
+				o.originalID() == first.Track.ID || o.Track.ID == first.originalID()):
+				if g.Reason < Relinked {
+					g.Reason = Relinked
+				}
+			default:
+				g.Reason = Fuzzy
+			}
+		}
+		sort.Stable(byAdded(g.Occurrences))
+		report.Groups = append(report.Groups, g)
+	}
+	return report
+}
+
+// byDuration sorts indexes into occs by the duration of the track.
+type byDuration struct {
+	occs []Occurrence
+	idx  []int
+}
+
+func (s byDuration) Len() int      { return len(s.idx) }
+func (s byDuration) Swap(i, j int) { s.idx[i], s.idx[j] = s.idx[j], s.idx[i] }
+func (s byDuration) Less(i, j int) bool {
+	return s.occs[s.idx[i]].Track.Duration < s.occs[s.idx[j]].Track.Duration
+}
+
+// byAdded sorts occurrences by when they were added.
+type byAdded []Occurrence
+
+func (s byAdded) Len() int      { return len(s) }
+func (s byAdded) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byAdded) Less(i, j int) bool {
+	if !s[i].AddedAt.Equal(s[j].AddedAt) {
+		return s[i].AddedAt.Before(s[j].AddedAt)
+	}
+	return s[i].Position < s[j].Position
+}
+
+// artistName returns the name of the track's primary artist.
+func artistName(t spotify.FullTrack) string {
+	if len(t.Artists) == 0 {
+		return ""
+	}
+	return t.Artists[0].Name
+}
+
+// normalize simplifies a title for fuzzy matching.  It ignores case,
+// punctuation and anything in parentheses or brackets or after " - ", which
+// is where Spotify puts annotations like "Remastered 2011".
+func normalize(s string) string {
+	if i := strings.Index(s, " - "); i > 0 {
+		s = s[:i]
+	}
+	var b []rune
+	depth := 0
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth > 0:
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b = append(b, r)
+		}
+	}
+	return string(b)
+}
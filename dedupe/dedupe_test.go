@@ -0,0 +1,211 @@
+package dedupe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func track(id, name, artist string, seconds int) spotify.FullTrack {
+	var t spotify.FullTrack
+	t.ID = spotify.ID(id)
+	t.URI = spotify.BuildURI(spotify.ItemTypeTrack, t.ID)
+	t.Name = name
+	t.Artists = []spotify.SimpleArtist{{Name: artist}}
+	t.Duration = seconds * 1000
+	return t
+}
+
+func added(s string) time.Time {
+	t, _ := time.Parse(spotify.TimestampLayout, s)
+	return t
+}
+
+func relinked(t spotify.FullTrack, from string) spotify.FullTrack {
+	t.LinkedFrom = &spotify.LinkedTrack{ID: spotify.ID(from), URI: spotify.BuildURI(spotify.ItemTypeTrack, spotify.ID(from))}
+	return t
+}
+
+func TestDetect(t *testing.T) {
+	mix := &spotify.SimplePlaylist{Name: "Mix"}
+	mix.ID = "mix"
+	occs := []Occurrence{
+		{Position: 0, AddedAt: added("2017-02-01T00:00:00Z"), Track: track("a", "Song", "Band", 200)},
+		{Playlist: mix, Position: 0, AddedAt: added("2017-01-01T00:00:00Z"), Track: track("a", "Song", "Band", 200)},
+		{Playlist: mix, Position: 1, AddedAt: added("2017-01-02T00:00:00Z"), Track: relinked(track("b", "Other", "Band", 180), "c")},
+		{Playlist: mix, Position: 2, AddedAt: added("2017-01-03T00:00:00Z"), Track: track("c", "Other", "Band", 180)},
+		{Playlist: mix, Position: 3, AddedAt: added("2017-01-04T00:00:00Z"), Track: track("d", "Hit (Radio Edit)", "Singer", 210)},
+		{Playlist: mix, Position: 4, AddedAt: added("2017-01-05T00:00:00Z"), Track: track("e", "hit - Remastered 2011", "Singer", 212)},
+		{Playlist: mix, Position: 5, AddedAt: added("2017-01-06T00:00:00Z"), Track: track("f", "Hit", "Singer", 300)},
+		{Playlist: mix, Position: 6, AddedAt: added("2017-01-07T00:00:00Z"), Track: spotify.FullTrack{}},
+	}
+	r := Detect(occs, DefaultTolerance)
+	if len(r.Groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d\n", len(r.Groups))
+	}
+	want := []struct {
+		reason Reason
+		size   int
+	}{{SameID, 2}, {Relinked, 2}, {Fuzzy, 2}}
+	for i, g := range r.Groups {
+		if g.Reason != want[i].reason || len(g.Occurrences) != want[i].size {
+			t.Errorf("Group %d: got %s with %d tracks, want %s with %d\n", i, g.Reason, len(g.Occurrences), want[i].reason, want[i].size)
+		}
+	}
+	if !r.Groups[0].Occurrences[1].Saved() {
+		t.Error("Occurrences aren't ordered by when they were added")
+	}
+	// a track in a playlist and the saved tracks isn't removable
+	if len(r.Groups[0].Removable()) != 0 {
+		t.Error("Expected no removable occurrences in the first group")
+	}
+	if rm := r.Removable(); len(rm) != 2 || rm[0].Position != 2 || rm[1].Position != 4 {
+		t.Errorf("Unexpected removable occurrences %+v\n", rm)
+	}
+
+	if r := Detect(occs, -1); len(r.Groups) != 2 {
+		t.Errorf("Expected 2 groups without fuzzy matching, got %d\n", len(r.Groups))
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Singer - Hit (Radio Edit) (fuzzy match)") {
+		t.Error("Unexpected report:\n" + buf.String())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Hey Jude - Remastered 2015": "heyjude",
+		"Don't Stop (feat. Someone)": "dontstop",
+		"Song [Live]":                "song",
+		"Café del Mar":               "cafédelmar",
+		"-Dash":                      "dash",
+	}
+	for in, want := range tests {
+		if got := normalize(in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q\n", in, got, want)
+		}
+	}
+}
+
+func TestDeduperFindAndRemove(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	f.AddTrack(track("4iV5W9uYEdYUVa79Axb7Rh", "Song", "Band", 200))
+	f.AddTrack(track("1301WleyT98MSxVHPZCA6M", "Song - 2011 Remaster", "Band", 201))
+	c := f.NewClient()
+
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Dupes", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.AddTracksToPlaylist(spotifytest.UserID, pl.ID,
+		"4iV5W9uYEdYUVa79Axb7Rh", "6rqhFgbbKwnb9MLmUQDhG6", "4iV5W9uYEdYUVa79Axb7Rh", "1301WleyT98MSxVHPZCA6M")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddTracksToLibrary("4iV5W9uYEdYUVa79Axb7Rh"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Deduper{Client: &c, Tolerance: 2 * time.Second}
+	r, err := d.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Groups) != 1 || len(r.Groups[0].Occurrences) != 4 || r.Groups[0].Reason != Fuzzy {
+		t.Fatalf("Unexpected report %+v\n", r.Groups)
+	}
+	if err := d.Remove(r, spotifytest.UserID); err != nil {
+		t.Fatal(err)
+	}
+	ids := f.PlaylistTracks(pl.ID)
+	if len(ids) != 2 || ids[0] != "4iV5W9uYEdYUVa79Axb7Rh" || ids[1] != "6rqhFgbbKwnb9MLmUQDhG6" {
+		t.Error("Unexpected playlist after removal:", ids)
+	}
+	if saved := f.SavedTracks(); len(saved) != 1 {
+		t.Error("Saved tracks shouldn't have changed:", saved)
+	}
+}
+
+func TestDeduperFindInPlaylist(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	f.AddTrack(track("4iV5W9uYEdYUVa79Axb7Rh", "Song", "Band", 200))
+	c := f.NewClient()
+
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Dupes", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.CreatePlaylistForUser(spotifytest.UserID, "Other", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []spotify.ID{pl.ID, other.ID} {
+		if _, err := c.AddTracksToPlaylist(spotifytest.UserID, id, "4iV5W9uYEdYUVa79Axb7Rh", "4iV5W9uYEdYUVa79Axb7Rh"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.AddTracksToLibrary("4iV5W9uYEdYUVa79Axb7Rh"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Deduper{Client: &c}
+	r, err := d.FindInPlaylist(spotifytest.UserID, pl.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Groups) != 1 || len(r.Groups[0].Occurrences) != 2 || len(r.Removable()) != 1 {
+		t.Fatalf("Unexpected report %+v\n", r.Groups)
+	}
+	if err := d.Remove(r, spotifytest.UserID); err != nil {
+		t.Fatal(err)
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 1 {
+		t.Error("Unexpected playlist after removal:", ids)
+	}
+	if ids := f.PlaylistTracks(other.ID); len(ids) != 2 {
+		t.Error("Another playlist was changed:", ids)
+	}
+}
+
+func TestRemoveFromCollaborativePlaylist(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	f.AddTrack(track("4iV5W9uYEdYUVa79Axb7Rh", "Song", "Band", 200))
+	c := f.NewClient()
+	pl, err := c.CreatePlaylistForUser("someone", "Shared", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddTracksToPlaylist("someone", pl.ID, "4iV5W9uYEdYUVa79Axb7Rh", "4iV5W9uYEdYUVa79Axb7Rh"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Deduper{Client: &c}
+	for _, collaborative := range []bool{false, true} {
+		r, err := d.FindInPlaylist(spotifytest.UserID, pl.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Groups[0].Occurrences[0].Playlist.Collaborative = collaborative
+		if err := d.Remove(r, spotifytest.UserID); err != nil {
+			t.Fatal(err)
+		}
+		want := 2
+		if collaborative {
+			want = 1
+		}
+		if ids := f.PlaylistTracks(pl.ID); len(ids) != want {
+			t.Errorf("collaborative %v: got %d tracks, want %d", collaborative, len(ids), want)
+		}
+	}
+}
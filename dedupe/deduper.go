@@ -0,0 +1,127 @@
+package dedupe
+
+import (
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/export"
+)
+
+// maxRemove is the most tracks that can be removed from a playlist in one
+// request.
+const maxRemove = 100
+
+// Deduper finds and removes duplicates in the library of the user a
+// client is authorized for.
+type Deduper struct {
+	Client spotify.SpotifyClient
+	// Tolerance is the largest difference in duration between two fuzzy
+	// matched tracks.  If zero, DefaultTolerance is used.  If negative,
+	// fuzzy matching is disabled.
+	Tolerance time.Duration
+}
+
+// Library fetches the user's saved tracks and playlists.  It requires the
+// ScopeUserReadPrivate, ScopeUserLibraryRead and ScopePlaylistReadPrivate
+// scopes.
+func (d *Deduper) Library() (*export.Library, error) {
+	return d.exporter().ExportTracks()
+}
+
+func (d *Deduper) exporter() *export.Exporter {
+	return &export.Exporter{Client: d.Client}
+}
+
+func (d *Deduper) tolerance() time.Duration {
+	if d.Tolerance == 0 {
+		return DefaultTolerance
+	}
+	return d.Tolerance
+}
+
+// Find fetches the user's library and reports the duplicates in it.
+func (d *Deduper) Find() (*Report, error) {
+	lib, err := d.Library()
+	if err != nil {
+		return nil, err
+	}
+	return Detect(Occurrences(lib), d.tolerance()), nil
+}
+
+// FindInPlaylist fetches a single playlist and reports the duplicates in it,
+// ignoring the rest of the library.  userID is used in the playlist's path,
+// as with Client.GetPlaylist.  It requires the ScopePlaylistReadPrivate or
+// ScopePlaylistReadCollaborative scope if the playlist isn't public.
+func (d *Deduper) FindInPlaylist(userID string, id spotify.ID) (*Report, error) {
+	p, err := d.Client.GetPlaylistOpt(userID, id, "collaborative,id,name,owner,public,snapshot_id,uri")
+	if err != nil {
+		return nil, err
+	}
+	items, err := d.exporter().PlaylistTracks(p.SimplePlaylist)
+	if err != nil {
+		return nil, err
+	}
+	lib := &export.Library{Playlists: []export.Playlist{{SimplePlaylist: p.SimplePlaylist, Items: items}}}
+	return Detect(Occurrences(lib), d.tolerance()), nil
+}
+
+// Remove deletes the removable occurrences in the report (see
+// Group.Removable) from the user's playlists and saved tracks.  Playlists
+// owned by other users are left alone, unless they are collaborative.
+// Playlist edits are made against the snapshot the report was made from,
+// so they fail rather than remove the wrong tracks if a playlist has
+// changed since.  It requires the ScopeUserLibraryModify,
+// ScopePlaylistModifyPublic and ScopePlaylistModifyPrivate scopes.
+func (d *Deduper) Remove(r *Report, userID string) error {
+	var saved []spotify.ID
+	playlists := make(map[spotify.ID]*spotify.SimplePlaylist)
+	var order []spotify.ID
+	positions := make(map[spotify.ID]map[spotify.URI][]int)
+	for _, o := range r.Removable() {
+		if o.Saved() {
+			saved = append(saved, o.originalID())
+			continue
+		}
+		if o.Playlist.Owner.ID != userID && !o.Playlist.Collaborative {
+			continue
+		}
+		id := o.Playlist.ID
+		if positions[id] == nil {
+			playlists[id] = o.Playlist
+			order = append(order, id)
+			positions[id] = make(map[spotify.URI][]int)
+		}
+		positions[id][o.originalURI()] = append(positions[id][o.originalURI()], o.Position)
+	}
+
+	for _, id := range order {
+		var tracks []spotify.TrackToRemove
+		for uri, pos := range positions[id] {
+			tracks = append(tracks, spotify.TrackToRemove{URI: string(uri), Positions: pos})
+		}
+		// positions refer to the playlist as it was before any removals,
+		// so every batch must be made against the same snapshot
+		snapshot := playlists[id].SnapshotID
+		for len(tracks) > 0 {
+			n := len(tracks)
+			if n > maxRemove {
+				n = maxRemove
+			}
+			if _, err := d.Client.RemoveTracksFromPlaylistOpt(playlists[id].Owner.ID, id, tracks[:n], snapshot); err != nil {
+				return err
+			}
+			tracks = tracks[n:]
+		}
+	}
+	for len(saved) > 0 {
+		n := len(saved)
+		if n > 50 {
+			n = 50
+		}
+		if err := d.Client.RemoveTracksFromLibrary(saved[:n]...); err != nil {
+			return err
+		}
+		saved = saved[n:]
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Package digest produces a "new music from artists you follow" digest:
+// the albums and singles released by a user's followed artists since the
+// previous digest.  The Web API has no endpoint for this, so the digest is
+// built by checking each followed artist's albums against a watermark
+// stored for the user.
+package digest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// DefaultLookback is how far back the first digest for a user goes.
+const DefaultLookback = 30 * 24 * time.Hour
+
+// Store persists each user's watermark: the release date up to which they
+// have been sent a digest.
+type Store interface {
+	// LoadWatermark returns the user's watermark, or the zero time if the
+	// user hasn't had a digest before.
+	LoadWatermark(ctx context.Context, userID string) (time.Time, error)
+	SaveWatermark(ctx context.Context, userID string, t time.Time) error
+}
+
+// DatastoreStore is a Store that keeps each user's watermark in an App
+// Engine Datastore entity of the given kind, keyed by user ID.
+type DatastoreStore string
+
+type watermark struct {
+	Watermark time.Time
+}
+
+// LoadWatermark implements Store.
+func (kind DatastoreStore) LoadWatermark(ctx context.Context, userID string) (time.Time, error) {
+	var w watermark
+	err := datastore.Get(ctx, datastore.NewKey(ctx, string(kind), userID, 0, nil), &w)
+	if err != nil && err != datastore.ErrNoSuchEntity {
+		return time.Time{}, err
+	}
+	return w.Watermark, nil
+}
+
+// SaveWatermark implements Store.
+func (kind DatastoreStore) SaveWatermark(ctx context.Context, userID string, t time.Time) error {
+	_, err := datastore.Put(ctx, datastore.NewKey(ctx, string(kind), userID, 0, nil), &watermark{t})
+	return err
+}
+
+// MemoryStore is a Store that keeps watermarks in memory.  It's useful
+// for tests and for applications that run on a single instance.
+type MemoryStore struct {
+	mu         sync.Mutex
+	watermarks map[string]time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{watermarks: make(map[string]time.Time)}
+}
+
+// LoadWatermark implements Store.
+func (m *MemoryStore) LoadWatermark(ctx context.Context, userID string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.watermarks[userID], nil
+}
+
+// SaveWatermark implements Store.
+func (m *MemoryStore) SaveWatermark(ctx context.Context, userID string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watermarks[userID] = t
+	return nil
+}
+
+// Release is an album or single by one or more followed artists.
+type Release struct {
+	Album spotify.SimpleAlbum `json:"album"`
+	// Artists are the followed artists the release is by.
+	Artists  []spotify.SimpleArtist `json:"artists"`
+	Released time.Time              `json:"released"`
+}
+
+// Digest lists the releases with release dates in [Since, Until), newest
+// first.
+type Digest struct {
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+	Releases []Release `json:"releases"`
+}
+
+// Builder builds digests.
+type Builder struct {
+	Store Store
+	// Types are the types of album to include.  If zero, albums and
+	// singles are included.
+	Types spotify.AlbumType
+	// Market is the market to check releases in.  If empty, the market of
+	// the user's account is used, which requires the ScopeUserReadPrivate
+	// scope.
+	Market string
+	// Lookback is how far back the first digest for a user goes.  If zero,
+	// DefaultLookback is used.
+	Lookback time.Duration
+	// Clock is used to determine the current date.  If nil,
+	// spotify.SystemClock is used.
+	Clock spotify.Clock
+}
+
+func (b *Builder) clock() spotify.Clock {
+	if b.Clock == nil {
+		return spotify.SystemClock
+	}
+	return b.Clock
+}
+
+// Build returns the digest for the user the client is authorized for and
+// advances their watermark, which is only saved if every request succeeds.
+//
+// Release dates have a precision of a day, and releases come out at
+// midnight local time in each market, so a digest covers release dates up
+// to, but not including, the current date in UTC.  Each day's releases are
+// included in the first digest built after that day is over.  It requires
+// the ScopeUserFollowRead scope.
+func (b *Builder) Build(ctx context.Context, c spotify.SpotifyClient, userID string) (*Digest, error) {
+	now := b.clock().Now().UTC()
+	until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since, err := b.Store.LoadWatermark(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if since.IsZero() {
+		lookback := b.Lookback
+		if lookback == 0 {
+			lookback = DefaultLookback
+		}
+		since = until.Add(-lookback)
+	}
+	d := &Digest{Since: since, Until: until}
+	if !since.Before(until) {
+		return d, nil
+	}
+
+	artists, err := followed(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[spotify.ID]int)
+	for _, artist := range artists {
+		albums, err := b.releases(ctx, c, artist.ID, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, album := range albums {
+			released := album.ReleaseDateTime()
+			if released.Before(since) || !released.Before(until) {
+				continue
+			}
+			credit := spotify.SimpleArtist{ID: artist.ID, Name: artist.Name, URI: artist.URI}
+			if i, ok := index[album.ID]; ok {
+				d.Releases[i].Artists = append(d.Releases[i].Artists, credit)
+				continue
+			}
+			index[album.ID] = len(d.Releases)
+			d.Releases = append(d.Releases, Release{Album: album, Artists: []spotify.SimpleArtist{credit}, Released: released})
+		}
+	}
+	sort.Stable(byReleased(d.Releases))
+
+	if err := b.Store.SaveWatermark(ctx, userID, until); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// followed returns every artist the user follows.
+func followed(ctx context.Context, c spotify.SpotifyClient) ([]spotify.FullArtist, error) {
+	var artists []spotify.FullArtist
+	after := ""
+	for {
+		page, err := c.CurrentUsersFollowedArtistsWithContext(ctx, 50, after)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, page.Artists...)
+		after = page.Cursor.After
+		if page.Next == "" || after == "" || len(page.Artists) == 0 {
+			return artists, nil
+		}
+	}
+}
+
+// releases returns the artist's albums of each type, newest first, that
+// may have been released since the given time.  Spotify lists an artist's
+// albums of a given type newest first, so paging stops at the first page
+// that ends with an older album.
+func (b *Builder) releases(ctx context.Context, c spotify.SpotifyClient, artist spotify.ID, since time.Time) ([]spotify.SimpleAlbum, error) {
+	types := b.Types
+	if types == 0 {
+		types = spotify.AlbumTypeAlbum | spotify.AlbumTypeSingle
+	}
+	var albums []spotify.SimpleAlbum
+	for _, t := range []spotify.AlbumType{spotify.AlbumTypeAlbum, spotify.AlbumTypeSingle, spotify.AlbummTypeAppearsOn, spotify.AlbumTypeCompilation} {
+		if types&t == 0 {
+			continue
+		}
+		t := t
+		limit, offset := 50, 0
+		market := b.Market
+		if market == "" {
+			market = spotify.MarketFromToken
+		}
+		for {
+			opt := &spotify.Options{Limit: &limit, Offset: &offset, Country: &market}
+			page, err := c.GetArtistAlbumsWithContext(ctx, artist, opt, &t)
+			if err != nil {
+				return nil, err
+			}
+			albums = append(albums, page.Albums...)
+			offset += len(page.Albums)
+			if page.Next == "" || len(page.Albums) == 0 ||
+				page.Albums[len(page.Albums)-1].ReleaseDateTime().Before(since) {
+				break
+			}
+		}
+	}
+	return albums, nil
+}
+
+type byReleased []Release
+
+func (r byReleased) Len() int           { return len(r) }
+func (r byReleased) Less(i, j int) bool { return r[i].Released.After(r[j].Released) }
+func (r byReleased) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
@@ -0,0 +1,96 @@
+package digest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func album(id, date string) string {
+	return fmt.Sprintf(`{"id":"%s","name":"Album %s","release_date":"%s","release_date_precision":"day"}`, id, id, date)
+}
+
+func newServer() *spotifytest.Server {
+	s := spotifytest.NewServer()
+	s.Handle("GET", "me/following", http.StatusOK, `{"artists":{"items":[
+		{"id":"artist1","name":"One"},{"id":"artist2","name":"Two"}],"next":null,"cursors":{"after":null}}}`)
+	s.HandleFunc("GET", "artists/*/albums", func(w http.ResponseWriter, r *http.Request) {
+		var albums []string
+		artist := strings.Split(r.URL.Path, "/")[3]
+		switch r.URL.Query().Get("album_type") + " " + artist {
+		case "album artist1":
+			albums = []string{album("collab", "2017-06-01"), album("old", "2016-01-01")}
+		case "single artist1":
+			albums = []string{album("today", "2017-06-10")}
+		case "album artist2":
+			albums = []string{album("collab", "2017-06-01")}
+		}
+		fmt.Fprintf(w, `{"items":[%s],"next":null}`, strings.Join(albums, ","))
+	})
+	return s
+}
+
+func TestBuild(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	c := s.NewClient()
+	clock := spotifytest.NewFakeClock(time.Date(2017, 6, 10, 12, 0, 0, 0, time.UTC))
+	store := NewMemoryStore()
+	b := &Builder{Store: store, Clock: clock}
+	ctx := context.Background()
+
+	d, err := b.Build(ctx, &c, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Since.Equal(time.Date(2017, 5, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Unexpected start of first digest:", d.Since)
+	}
+	if len(d.Releases) != 1 || d.Releases[0].Album.ID != "collab" || len(d.Releases[0].Artists) != 2 {
+		t.Fatalf("Unexpected releases %+v\n", d.Releases)
+	}
+	if q := s.LastRequest().URL.Query(); q.Get("market") != "from_token" {
+		t.Error("Expected the user's market, got", q.Get("market"))
+	}
+
+	// nothing new until the day is over
+	n := len(s.Requests())
+	if d, err = b.Build(ctx, &c, "user"); err != nil || len(d.Releases) != 0 {
+		t.Fatalf("Unexpected digest %+v, %v\n", d, err)
+	}
+	if len(s.Requests()) != n {
+		t.Error("Expected no requests")
+	}
+
+	clock.Advance(24 * time.Hour)
+	if d, err = b.Build(ctx, &c, "user"); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Releases) != 1 || d.Releases[0].Album.ID != "today" {
+		t.Errorf("Unexpected releases %+v\n", d.Releases)
+	}
+	if w, _ := store.LoadWatermark(ctx, "user"); !w.Equal(time.Date(2017, 6, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Unexpected watermark", w)
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	s.Handle("GET", "artists/*/albums", http.StatusInternalServerError, `{"error":{"status":500,"message":"oops"}}`)
+	c := s.NewClient()
+	store := NewMemoryStore()
+	b := &Builder{Store: store}
+	if _, err := b.Build(context.Background(), &c, "user"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if w, _ := store.LoadWatermark(context.Background(), "user"); !w.IsZero() {
+		t.Error("The watermark shouldn't be saved after an error")
+	}
+}
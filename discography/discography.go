@@ -0,0 +1,238 @@
+// Package discography compares an artist's catalog with a user's library
+// and reports which albums and singles the user is missing, for collectors
+// and completionists.
+package discography
+
+import (
+	"sort"
+	"strings"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/export"
+)
+
+// Status describes how much of an album is in the user's library.
+type Status int
+
+const (
+	// Missing means none of the album's tracks are in the library.
+	Missing Status = iota
+	// Partial means some, but not all, of the album's tracks are saved or
+	// in the user's playlists.
+	Partial
+	// Complete means every track on the album is saved or in the user's
+	// playlists, although the album itself isn't saved.
+	Complete
+	// Saved means the album is saved in the user's library.
+	Saved
+)
+
+func (s Status) String() string {
+	switch s {
+	case Missing:
+		return "missing"
+	case Partial:
+		return "partial"
+	case Complete:
+		return "complete"
+	case Saved:
+		return "saved"
+	}
+	return "unknown"
+}
+
+// Entry is an album or single in the artist's catalog.
+type Entry struct {
+	Album  spotify.SimpleAlbum `json:"album"`
+	Status Status              `json:"status"`
+	// Tracks is the number of tracks on the album, and Owned the number of
+	// them that are saved or in the user's playlists.
+	Tracks int `json:"tracks"`
+	Owned  int `json:"owned"`
+}
+
+// Report lists an artist's catalog, newest first.
+type Report struct {
+	Artist  spotify.ID `json:"artist"`
+	Entries []Entry    `json:"entries"`
+}
+
+// Missing returns the entries the user doesn't have all of.
+func (r *Report) Missing() []Entry {
+	var missing []Entry
+	for _, e := range r.Entries {
+		if e.Status < Complete {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+// Completeness returns the fraction of the catalog's tracks the user has,
+// between 0 and 1.
+func (r *Report) Completeness() float64 {
+	var owned, total int
+	for _, e := range r.Entries {
+		owned += e.Owned
+		total += e.Tracks
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(owned) / float64(total)
+}
+
+// Checker compares artists' catalogs with a user's library.
+type Checker struct {
+	Client spotify.SpotifyClient
+	// Library is the user's library, as returned by export.Exporter.  Only
+	// the saved tracks, saved albums and playlists are used.
+	Library *export.Library
+	// Types are the types of album to check.  If zero, albums and singles
+	// are checked.
+	Types spotify.AlbumType
+	// Market is the market whose catalog is checked.  If empty, the
+	// market of the user's account is used, which requires the
+	// ScopeUserReadPrivate scope.
+	Market string
+
+	tracks map[spotify.ID]bool
+	albums map[spotify.ID]bool
+}
+
+// index records the IDs of the tracks and albums in the library.
+func (c *Checker) index() {
+	if c.tracks != nil {
+		return
+	}
+	c.tracks = make(map[spotify.ID]bool)
+	c.albums = make(map[spotify.ID]bool)
+	add := func(t spotify.FullTrack) {
+		c.tracks[t.ID] = true
+		if t.LinkedFrom != nil {
+			c.tracks[t.LinkedFrom.ID] = true
+		}
+	}
+	for _, t := range c.Library.SavedTracks {
+		add(t.FullTrack)
+	}
+	for _, p := range c.Library.Playlists {
+		for _, t := range p.Items {
+			add(t.Track)
+		}
+	}
+	for _, a := range c.Library.SavedAlbums {
+		c.albums[a.ID] = true
+	}
+}
+
+// Check reports how much of the artist's catalog is in the library.
+// Different editions of an album with the same name and type are counted
+// once.
+func (c *Checker) Check(artist spotify.ID) (*Report, error) {
+	c.index()
+	catalog, err := c.catalog(artist)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Artist: artist}
+	for _, chunk := range spotify.ChunkIDs(albumIDs(catalog), 20) {
+		full, err := c.Client.GetAlbums(chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range full {
+			if a == nil {
+				continue
+			}
+			tracks, err := c.albumTracks(a)
+			if err != nil {
+				return nil, err
+			}
+			e := Entry{Album: a.SimpleAlbum, Tracks: len(tracks)}
+			for _, t := range tracks {
+				if c.tracks[t.ID] {
+					e.Owned++
+				}
+			}
+			switch {
+			case c.albums[a.ID]:
+				e.Status = Saved
+			case e.Owned > 0 && e.Owned == e.Tracks:
+				e.Status = Complete
+			case e.Owned > 0:
+				e.Status = Partial
+			}
+			r.Entries = append(r.Entries, e)
+		}
+	}
+	sort.Stable(byRelease(r.Entries))
+	return r, nil
+}
+
+// catalog returns the artist's albums of the checked types, skipping
+// duplicate editions.
+func (c *Checker) catalog(artist spotify.ID) ([]spotify.SimpleAlbum, error) {
+	types := c.Types
+	if types == 0 {
+		types = spotify.AlbumTypeAlbum | spotify.AlbumTypeSingle
+	}
+	market := c.Market
+	if market == "" {
+		market = spotify.MarketFromToken
+	}
+	seen := make(map[string]bool)
+	var albums []spotify.SimpleAlbum
+	limit, offset := 50, 0
+	for {
+		page, err := c.Client.GetArtistAlbumsOpt(artist, &spotify.Options{Limit: &limit, Offset: &offset, Country: &market}, &types)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.Albums {
+			key := a.AlbumType + "\x00" + strings.ToLower(a.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			albums = append(albums, a)
+		}
+		offset += len(page.Albums)
+		if page.Next == "" || len(page.Albums) == 0 {
+			return albums, nil
+		}
+	}
+}
+
+// albumTracks returns every track on the album, fetching any that weren't
+// included with it.
+func (c *Checker) albumTracks(a *spotify.FullAlbum) ([]spotify.SimpleTrack, error) {
+	tracks := a.Tracks.Tracks
+	for len(tracks) < a.Tracks.Total {
+		page, err := c.Client.GetAlbumTracksOpt(a.ID, 50, len(tracks))
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Tracks) == 0 {
+			break
+		}
+		tracks = append(tracks, page.Tracks...)
+	}
+	return tracks, nil
+}
+
+func albumIDs(albums []spotify.SimpleAlbum) []spotify.ID {
+	ids := make([]spotify.ID, len(albums))
+	for i, a := range albums {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+type byRelease []Entry
+
+func (e byRelease) Len() int      { return len(e) }
+func (e byRelease) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byRelease) Less(i, j int) bool {
+	return spotify.CompareReleaseDates(&e[i].Album, &e[j].Album) > 0
+}
@@ -0,0 +1,88 @@
+package discography
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/export"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+const (
+	lp     = "4aawyAB9vmqN3uQ7FjRGTy"
+	single = "0sNOF9WDwhWunNAHPD3Baj"
+	ep     = "6akEvsycLGftJxYudPjmqK"
+)
+
+func newServer() *spotifytest.Server {
+	s := spotifytest.NewServer()
+	s.Handle("GET", "artists/*/albums", http.StatusOK, `{"items":[
+		{"id":"`+single+`","name":"Single","album_type":"single","release_date":"2017-03-01","release_date_precision":"day"},
+		{"id":"`+lp+`","name":"LP","album_type":"album","release_date":"2016","release_date_precision":"year"},
+		{"id":"`+ep+`","name":"EP","album_type":"single","release_date":"2015-06","release_date_precision":"month"},
+		{"id":"0000000000000000000000","name":"lp","album_type":"album","release_date":"2016","release_date_precision":"year"}
+	],"next":null}`)
+	s.HandleFunc("GET", "albums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"albums":[
+			{"id":"`+single+`","name":"Single","release_date":"2017-03-01","release_date_precision":"day",
+			 "tracks":{"total":1,"items":[{"id":"s1"}]}},
+			{"id":"`+lp+`","name":"LP","release_date":"2016","release_date_precision":"year",
+			 "tracks":{"total":3,"items":[{"id":"l1"},{"id":"l2"}]}},
+			{"id":"`+ep+`","name":"EP","release_date":"2015-06","release_date_precision":"month",
+			 "tracks":{"total":1,"items":[{"id":"e1"}]}}
+		]}`)
+	})
+	s.Handle("GET", "albums/*/tracks", http.StatusOK, `{"total":3,"offset":2,"items":[{"id":"l3"}]}`)
+	return s
+}
+
+func TestCheck(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	lib := &export.Library{}
+	var saved spotify.SavedTrack
+	saved.ID = "s1"
+	lib.SavedTracks = append(lib.SavedTracks, saved)
+	var item spotify.PlaylistTrack
+	item.Track.ID = "relinked"
+	item.Track.LinkedFrom = &spotify.LinkedTrack{ID: "l3"}
+	lib.Playlists = append(lib.Playlists, export.Playlist{Items: []spotify.PlaylistTrack{item}})
+	var album spotify.SavedAlbum
+	album.ID = ep
+	lib.SavedAlbums = append(lib.SavedAlbums, album)
+
+	checker := &Checker{Client: &c, Library: lib, Market: "US"}
+	r, err := checker.Check("artist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v\n", r.Entries)
+	}
+	want := []struct {
+		id     spotify.ID
+		status Status
+		owned  int
+		tracks int
+	}{{single, Complete, 1, 1}, {lp, Partial, 1, 3}, {ep, Saved, 0, 1}}
+	for i, w := range want {
+		e := r.Entries[i]
+		if e.Album.ID != w.id || e.Status != w.status || e.Owned != w.owned || e.Tracks != w.tracks {
+			t.Errorf("Entry %d: got %s %s %d/%d, want %s %s %d/%d\n", i,
+				e.Album.ID, e.Status, e.Owned, e.Tracks, w.id, w.status, w.owned, w.tracks)
+		}
+	}
+	if missing := r.Missing(); len(missing) != 1 || missing[0].Album.ID != lp {
+		t.Errorf("Unexpected missing albums %+v\n", missing)
+	}
+	if got := r.Completeness(); got != 0.4 {
+		t.Errorf("Expected completeness of 0.4, got %g\n", got)
+	}
+	if q := s.Requests()[0].URL.Query(); q.Get("album_type") != "album,single" || q.Get("market") != "US" {
+		t.Error("Unexpected catalog request", q)
+	}
+}
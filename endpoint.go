@@ -0,0 +1,157 @@
+package spotify
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// endpoint builds the URL of a Web API endpoint.  All of the client's
+// methods use it, so that path values are escaped and query parameters are
+// encoded the same way everywhere.
+type endpoint struct {
+	c     *Client
+	path  string
+	query url.Values
+}
+
+// endpoint returns a builder for the endpoint whose path, relative to the
+// Web API's base address, is format with each %s verb replaced by the
+// corresponding arg.  The args are escaped, so IDs and user names that
+// contain unusual characters can't change the path.
+func (c *Client) endpoint(format string, args ...interface{}) *endpoint {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = pathEscape(fmt.Sprint(arg))
+	}
+	return &endpoint{
+		c:     c,
+		path:  fmt.Sprintf(format, escaped...),
+		query: url.Values{},
+	}
+}
+
+// WithDefaultParams adds the query parameters in params to every request
+// the client makes, unless the call sets the parameter itself.  It saves
+// repeating the same options at every call site in applications that always
+// serve one region, for example:
+//
+//    spotify.WithDefaultParams(url.Values{
+//        "country":          {"MX"},
+//        "locale":           {"es_MX"},
+//        "include_external": {"audio"},
+//    })
+//
+// The parameters aren't validated, and are sent even to endpoints that
+// don't use them, which the Web API ignores.  To set the market of only the
+// endpoints that take one, use WithMarket.
+func WithDefaultParams(params url.Values) ClientOption {
+	return func(c *Client) {
+		if c.params == nil {
+			c.params = url.Values{}
+		}
+		for k, v := range params {
+			c.params[k] = append([]string(nil), v...)
+		}
+	}
+}
+
+// pathEscape escapes s so that it can be used as a segment of a URL path.
+func pathEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// set sets the query parameter key to value.
+func (e *endpoint) set(key, value string) {
+	e.query.Set(key, value)
+}
+
+// setInt sets the query parameter key to n.
+func (e *endpoint) setInt(key string, n int) {
+	e.query.Set(key, strconv.Itoa(n))
+}
+
+// setIDs sets the ids query parameter to the comma separated IDs.
+func (e *endpoint) setIDs(ids []ID) {
+	e.query.Set("ids", strings.Join(toStringSlice(ids), ","))
+}
+
+// setPaging sets the limit and offset query parameters from opt, which may
+// be nil.
+func (e *endpoint) setPaging(opt *Options) {
+	if opt == nil {
+		return
+	}
+	if opt.Limit != nil {
+		e.setInt("limit", *opt.Limit)
+	}
+	if opt.Offset != nil {
+		e.setInt("offset", *opt.Offset)
+	}
+}
+
+// setCountry validates the optional country code and, if it is present,
+// sets the query parameter key ("market" or "country", depending on the
+// endpoint) to it.  Only market parameters accept MarketFromToken.
+func (e *endpoint) setCountry(key string, country *string) error {
+	if key != "country" {
+		return setMarket(e.query, key, country)
+	}
+	if country != nil && *country == MarketFromToken {
+		return InvalidMarketError{Market: *country, Country: true}
+	}
+	err := setMarket(e.query, key, country)
+	if m, ok := err.(InvalidMarketError); ok {
+		m.Country = true
+		return m
+	}
+	return err
+}
+
+// setMarket sets the market query parameter from opt, which may be nil, for
+// endpoints whose results include available markets.  If opt doesn't give
+// a country, the client's default market (see WithMarket) is used.
+func (e *endpoint) setMarket(opt *Options) error {
+	if opt != nil && opt.Country != nil {
+		return setMarketOpt(e.query, opt)
+	}
+	if e.c.market != "" {
+		e.set("market", e.c.market)
+		return nil
+	}
+	if opt == nil {
+		return nil
+	}
+	return setMarketOpt(e.query, opt)
+}
+
+// setDefaultMarket sets the market query parameter to the client's default
+// market, if it has one, for market-sensitive endpoints that don't take
+// Options.
+func (e *endpoint) setDefaultMarket() {
+	if e.c.market != "" {
+		e.set("market", e.c.market)
+	}
+}
+
+// String returns the URL of the endpoint.
+func (e *endpoint) String() string {
+	u := baseAddress + e.path
+	query := e.query
+	if len(e.c.params) > 0 {
+		query = url.Values{}
+		for k, v := range e.c.params {
+			query[k] = v
+		}
+		for k, v := range e.query {
+			query[k] = v
+		}
+	}
+	if len(query) == 0 {
+		return u
+	}
+	// Commas separate the values of lists (such as IDs); they are allowed in
+	// a query, and leaving them unescaped keeps URLs readable in logs.
+	return u + "?" + strings.Replace(query.Encode(), "%2C", ",", -1)
+}
@@ -0,0 +1,77 @@
+package spotify
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestEndpoint(t *testing.T) {
+	c := NewClient(http.DefaultClient)
+	tests := []struct {
+		e    func() *endpoint
+		want string
+	}{
+		{
+			func() *endpoint { return c.endpoint("me") },
+			"https://api.spotify.com/v1/me",
+		},
+		{
+			func() *endpoint { return c.endpoint("users/%s/playlists", "a b/c?d") },
+			"https://api.spotify.com/v1/users/a%20b%2Fc%3Fd/playlists",
+		},
+		{
+			func() *endpoint {
+				e := c.endpoint("tracks")
+				e.setIDs([]ID{"a", "b"})
+				e.set("q", "x,y&z")
+				return e
+			},
+			"https://api.spotify.com/v1/tracks?ids=a,b&q=x,y%26z",
+		},
+		{
+			func() *endpoint {
+				limit, offset := 10, 20
+				e := c.endpoint("me/albums")
+				e.setPaging(&Options{Limit: &limit, Offset: &offset})
+				e.setPaging(nil)
+				return e
+			},
+			"https://api.spotify.com/v1/me/albums?limit=10&offset=20",
+		},
+	}
+	for _, test := range tests {
+		if got := test.e().String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestEndpointMarket(t *testing.T) {
+	c := NewClient(http.DefaultClient)
+	e := c.endpoint("search")
+	country := "xx"
+	if err := e.setMarket(&Options{Country: &country}); err == nil {
+		t.Error("expected an error for an invalid market")
+	}
+	if err := e.setMarket(&Options{OmitAvailableMarkets: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), "https://api.spotify.com/v1/search?market=from_token"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestEndpointDefaultParams(t *testing.T) {
+	params := url.Values{"country": {"MX"}, "locale": {"es_MX"}}
+	c := NewClient(http.DefaultClient, WithDefaultParams(params))
+	params.Set("country", "SE")
+	e := c.endpoint("browse/categories")
+	e.set("locale", "en_US")
+	if got, want := e.String(), "https://api.spotify.com/v1/browse/categories?country=MX&locale=en_US"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if len(e.query) != 1 {
+		t.Errorf("the defaults were added to the endpoint's query: %v", e.query)
+	}
+}
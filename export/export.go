@@ -0,0 +1,264 @@
+// Package export saves the contents of a user's Spotify library (saved
+// tracks and albums, playlists and followed artists) so that it can be
+// backed up, moved to another account, or handed to the user in a portable
+// format.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// pageSize is the number of items requested per call.
+const pageSize = 50
+
+// Library is a complete snapshot of a user's library.
+type Library struct {
+	ExportedAt      time.Time            `json:"exported_at"`
+	User            *spotify.PrivateUser `json:"user"`
+	SavedTracks     []spotify.SavedTrack `json:"saved_tracks"`
+	SavedAlbums     []spotify.SavedAlbum `json:"saved_albums"`
+	Playlists       []Playlist           `json:"playlists"`
+	FollowedArtists []spotify.FullArtist `json:"followed_artists"`
+}
+
+// Playlist is a playlist along with all of its tracks.
+type Playlist struct {
+	spotify.SimplePlaylist
+	Items []spotify.PlaylistTrack `json:"items"`
+}
+
+// Stages of an export, reported in Progress.
+const (
+	StageSavedTracks     = "saved tracks"
+	StageSavedAlbums     = "saved albums"
+	StagePlaylists       = "playlists"
+	StageFollowedArtists = "followed artists"
+)
+
+// Progress reports how far an export has got.  Total is zero if it isn't
+// known yet.
+type Progress struct {
+	Stage string
+	Done  int
+	Total int
+}
+
+// Exporter walks a user's library.
+type Exporter struct {
+	Client spotify.SpotifyClient
+	// Progress, if not nil, is called after each page of results.
+	Progress func(Progress)
+}
+
+func (e *Exporter) report(stage string, done, total int) {
+	if e.Progress != nil {
+		e.Progress(Progress{Stage: stage, Done: done, Total: total})
+	}
+}
+
+// Export fetches the library of the user the client is authorized for.
+// It requires the ScopeUserReadPrivate, ScopeUserLibraryRead,
+// ScopePlaylistReadPrivate and ScopeUserFollowRead scopes.
+func (e *Exporter) Export() (*Library, error) {
+	lib, err := e.ExportTracks()
+	if err != nil {
+		return nil, err
+	}
+	if lib.SavedAlbums, err = e.savedAlbums(); err != nil {
+		return nil, err
+	}
+	if lib.FollowedArtists, err = e.followedArtists(); err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+// ExportTracks fetches just the parts of the library that hold tracks: the
+// user's saved tracks and playlists.  It requires the ScopeUserReadPrivate,
+// ScopeUserLibraryRead and ScopePlaylistReadPrivate scopes.
+func (e *Exporter) ExportTracks() (*Library, error) {
+	lib := &Library{ExportedAt: time.Now().UTC()}
+	var err error
+	if lib.User, err = e.Client.CurrentUser(); err != nil {
+		return nil, err
+	}
+	if lib.SavedTracks, err = e.savedTracks(); err != nil {
+		return nil, err
+	}
+	if lib.Playlists, err = e.playlists(); err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+func (e *Exporter) savedTracks() ([]spotify.SavedTrack, error) {
+	var tracks []spotify.SavedTrack
+	limit, offset := pageSize, 0
+	for {
+		page, err := e.Client.CurrentUsersTracksOpt(&spotify.Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, page.Tracks...)
+		e.report(StageSavedTracks, len(tracks), page.Total)
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+func (e *Exporter) savedAlbums() ([]spotify.SavedAlbum, error) {
+	var albums []spotify.SavedAlbum
+	limit, offset := pageSize, 0
+	for {
+		page, err := e.Client.CurrentUsersAlbumsOpt(&spotify.Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, page.Albums...)
+		e.report(StageSavedAlbums, len(albums), page.Total)
+		offset += len(page.Albums)
+		if page.Next == "" || len(page.Albums) == 0 {
+			return albums, nil
+		}
+	}
+}
+
+func (e *Exporter) playlists() ([]Playlist, error) {
+	var simple []spotify.SimplePlaylist
+	limit, offset := pageSize, 0
+	for {
+		page, err := e.Client.CurrentUsersPlaylistsOpt(&spotify.Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		simple = append(simple, page.Playlists...)
+		offset += len(page.Playlists)
+		if page.Next == "" || len(page.Playlists) == 0 {
+			break
+		}
+	}
+	playlists := make([]Playlist, len(simple))
+	for i, p := range simple {
+		items, err := e.PlaylistTracks(p)
+		if err != nil {
+			return nil, err
+		}
+		playlists[i] = Playlist{SimplePlaylist: p, Items: items}
+		e.report(StagePlaylists, i+1, len(simple))
+	}
+	return playlists, nil
+}
+
+// PlaylistTracks fetches all of the tracks in a playlist.
+func (e *Exporter) PlaylistTracks(p spotify.SimplePlaylist) ([]spotify.PlaylistTrack, error) {
+	var items []spotify.PlaylistTrack
+	limit, offset := 100, 0
+	for {
+		page, err := e.Client.GetPlaylistTracksOpt(p.Owner.ID, p.ID, &spotify.Options{Limit: &limit, Offset: &offset}, "")
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Tracks...)
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return items, nil
+		}
+	}
+}
+
+func (e *Exporter) followedArtists() ([]spotify.FullArtist, error) {
+	var artists []spotify.FullArtist
+	after := ""
+	for {
+		page, err := e.Client.CurrentUsersFollowedArtistsOpt(pageSize, after)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, page.Artists...)
+		e.report(StageFollowedArtists, len(artists), page.Total)
+		after = page.Cursor.After
+		if page.Next == "" || after == "" || len(page.Artists) == 0 {
+			return artists, nil
+		}
+	}
+}
+
+// WriteJSON writes the library to w as JSON.  This is the format read by
+// ReadJSON and Restore.
+func WriteJSON(w io.Writer, lib *Library) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(lib)
+}
+
+// ReadJSON reads a library written by WriteJSON.
+func ReadJSON(r io.Reader) (*Library, error) {
+	var lib Library
+	if err := json.NewDecoder(r).Decode(&lib); err != nil {
+		return nil, err
+	}
+	return &lib, nil
+}
+
+// CSVHeader is the first row written by WriteCSV.
+var CSVHeader = []string{"kind", "collection", "id", "uri", "name", "artists", "album", "isrc", "added_at"}
+
+// WriteCSV writes the library to w as CSV, with one row per item: saved
+// tracks and albums, playlist entries (with the playlist name as the
+// collection) and followed artists.  It's intended for people and
+// spreadsheets rather than for restoring.
+func WriteCSV(w io.Writer, lib *Library) error {
+	cw := csv.NewWriter(w)
+	cw.Write(CSVHeader)
+	for _, t := range lib.SavedTracks {
+		cw.Write(trackRow("track", "saved", t.FullTrack, timestamp(t.AddedAt)))
+	}
+	for _, a := range lib.SavedAlbums {
+		cw.Write([]string{"album", "saved", string(a.ID), string(a.URI), a.Name,
+			artistNames(a.Artists), a.Name, "", timestamp(a.AddedAt)})
+	}
+	for _, p := range lib.Playlists {
+		for _, item := range p.Items {
+			cw.Write(trackRow("track", p.Name, item.Track, timestamp(item.AddedAt)))
+		}
+	}
+	for _, a := range lib.FollowedArtists {
+		cw.Write([]string{"artist", "followed", string(a.ID), string(a.URI), a.Name, a.Name, "", "", ""})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// timestamp formats t as Spotify does, or returns the empty string if t is
+// zero, as the AddedAt of tracks in very old playlists is.
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(spotify.TimestampLayout)
+}
+
+func trackRow(kind, collection string, t spotify.FullTrack, addedAt string) []string {
+	var album string
+	if t.Album != nil {
+		album = t.Album.Name
+	}
+	return []string{kind, collection, string(t.ID), string(t.URI), t.Name,
+		artistNames(t.Artists), album, t.ExternalIDs.ISRC, addedAt}
+}
+
+func artistNames(artists []spotify.SimpleArtist) string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return strings.Join(names, "; ")
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func TestExport(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	var stages []string
+	e := &Exporter{Client: &c, Progress: func(p Progress) {
+		stages = append(stages, p.Stage)
+	}}
+	lib, err := e.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.User.ID != spotifytest.UserID {
+		t.Error("Wrong user", lib.User.ID)
+	}
+	if len(lib.SavedTracks) != 1 || len(lib.SavedAlbums) != 1 || len(lib.FollowedArtists) != 1 {
+		t.Errorf("Unexpected library %+v\n", lib)
+	}
+	if len(lib.Playlists) != 1 || len(lib.Playlists[0].Items) != 1 {
+		t.Fatal("Playlist tracks weren't exported")
+	}
+	if len(stages) != 4 {
+		t.Error("Unexpected progress", stages)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, lib); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Playlists[0].Name != lib.Playlists[0].Name || back.Playlists[0].Items[0].Track.ID != spotifytest.TrackID {
+		t.Error("JSON didn't round trip")
+	}
+
+	buf.Reset()
+	if err := WriteCSV(&buf, lib); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || rows[1][7] != "USSUB0665807" {
+		t.Errorf("Unexpected CSV %v\n", rows)
+	}
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// StageResolve is reported in Progress while a restore looks up tracks by
+// ISRC.  The other stages of a restore are the same as those of an export.
+const StageResolve = "resolve"
+
+// Restorer re-creates an exported library, possibly on a different account.
+type Restorer struct {
+	Client spotify.SpotifyClient
+	// Progress, if not nil, is called as each part of the library is
+	// restored.
+	Progress func(Progress)
+	// ResolveByISRC makes the restorer look up every track by its ISRC
+	// instead of trusting the exported ID, for example when moving to an
+	// account in a market where the original releases aren't available.
+	// Tracks without an ID are always looked up by ISRC.
+	ResolveByISRC bool
+}
+
+// RestoreResult describes the outcome of a restore.
+type RestoreResult struct {
+	// Playlists maps the IDs of exported playlists to the IDs of the
+	// playlists that were created for them.
+	Playlists map[spotify.ID]spotify.ID
+	// Unresolved lists the names of tracks that couldn't be found.
+	Unresolved []string
+}
+
+func (r *Restorer) report(stage string, done, total int) {
+	if r.Progress != nil {
+		r.Progress(Progress{Stage: stage, Done: done, Total: total})
+	}
+}
+
+// Restore saves the library's tracks and albums, re-creates its playlists
+// (as new playlists owned by the current user) and follows its artists.
+// Saved tracks and albums are added oldest first, so they keep their order.
+// It requires the ScopeUserLibraryModify, ScopePlaylistModifyPublic,
+// ScopePlaylistModifyPrivate and ScopeUserFollowModify scopes.
+//
+// Restore stops at the first error; the returned result describes what was
+// restored up to that point.
+func (r *Restorer) Restore(lib *Library) (*RestoreResult, error) {
+	result := &RestoreResult{Playlists: make(map[spotify.ID]spotify.ID)}
+	user, err := r.Client.CurrentUser()
+	if err != nil {
+		return result, err
+	}
+	cache := make(map[string]spotify.ID)
+
+	// saved tracks
+	var tracks []spotify.ID
+	for i := len(lib.SavedTracks) - 1; i >= 0; i-- {
+		id, ok, err := r.resolve(lib.SavedTracks[i].FullTrack, cache, result)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			tracks = append(tracks, id)
+		}
+	}
+	for i, chunk := range spotify.ChunkIDs(tracks, 50) {
+		if err := r.Client.AddTracksToLibrary(chunk...); err != nil {
+			return result, err
+		}
+		r.report(StageSavedTracks, min(len(tracks), (i+1)*50), len(tracks))
+	}
+
+	// saved albums
+	var albums []spotify.ID
+	for i := len(lib.SavedAlbums) - 1; i >= 0; i-- {
+		albums = append(albums, lib.SavedAlbums[i].ID)
+	}
+	for i, chunk := range spotify.ChunkIDs(albums, 50) {
+		if err := r.Client.AddAlbumsToLibrary(chunk...); err != nil {
+			return result, err
+		}
+		r.report(StageSavedAlbums, min(len(albums), (i+1)*50), len(albums))
+	}
+
+	// playlists
+	for i, p := range lib.Playlists {
+		created, err := r.Client.CreatePlaylistForUser(user.ID, p.Name, p.IsPublic)
+		if err != nil {
+			return result, err
+		}
+		result.Playlists[p.ID] = created.ID
+		var ids []spotify.ID
+		for _, item := range p.Items {
+			id, ok, err := r.resolve(item.Track, cache, result)
+			if err != nil {
+				return result, err
+			}
+			if ok {
+				ids = append(ids, id)
+			}
+		}
+		for _, chunk := range spotify.ChunkIDs(ids, 100) {
+			if _, err := r.Client.AddTracksToPlaylist(user.ID, created.ID, chunk...); err != nil {
+				return result, err
+			}
+		}
+		r.report(StagePlaylists, i+1, len(lib.Playlists))
+	}
+
+	// followed artists
+	artists := make([]spotify.ID, len(lib.FollowedArtists))
+	for i, a := range lib.FollowedArtists {
+		artists[i] = a.ID
+	}
+	for i, chunk := range spotify.ChunkIDs(artists, 50) {
+		if err := r.Client.FollowArtist(chunk...); err != nil {
+			return result, err
+		}
+		r.report(StageFollowedArtists, min(len(artists), (i+1)*50), len(artists))
+	}
+	return result, nil
+}
+
+// resolve returns the ID to use for an exported track, searching by ISRC
+// when necessary, and whether there is one.  Lookups are cached by ISRC.
+// An error is returned only if the search fails, not if it finds nothing.
+func (r *Restorer) resolve(t spotify.FullTrack, cache map[string]spotify.ID, result *RestoreResult) (spotify.ID, bool, error) {
+	isrc := t.ExternalIDs.ISRC
+	if t.ID != "" && (!r.ResolveByISRC || isrc == "") {
+		return t.ID, true, nil
+	}
+	if isrc == "" {
+		result.Unresolved = append(result.Unresolved, t.Name)
+		return "", false, nil
+	}
+	if id, ok := cache[isrc]; ok {
+		return id, id != "", nil
+	}
+	var id spotify.ID
+	res, err := r.Client.Search("isrc:"+isrc, spotify.SearchTypeTrack)
+	if err != nil {
+		return "", false, err
+	}
+	if res.Tracks != nil && len(res.Tracks.Tracks) > 0 {
+		id = res.Tracks.Tracks[0].ID
+	}
+	if id == "" {
+		// fall back to the exported ID, if there is one
+		id = t.ID
+	}
+	cache[isrc] = id
+	r.report(StageResolve, len(cache), 0)
+	if id == "" {
+		result.Unresolved = append(result.Unresolved, t.Name)
+		return "", false, nil
+	}
+	return id, true, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
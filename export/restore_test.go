@@ -0,0 +1,95 @@
+package export
+
+import (
+	"net/http"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func TestRestore(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	lib, err := (&Exporter{Client: &c}).Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := spotifytest.NewFake()
+	defer f.Close()
+	dst := f.NewClient()
+
+	// a track from a CSV export, with only an ISRC
+	var unknown spotify.FullTrack
+	unknown.Name = "Lost Track"
+	lib.Playlists[0].Items = append(lib.Playlists[0].Items, spotify.PlaylistTrack{Track: unknown})
+
+	r := &Restorer{Client: &dst}
+	result, err := r.Restore(lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved := f.SavedTracks(); len(saved) != 1 || saved[0] != spotifytest.TrackID {
+		t.Error("Saved tracks weren't restored:", saved)
+	}
+	newID, ok := result.Playlists[spotifytest.PlaylistID]
+	if !ok {
+		t.Fatal("Playlist wasn't re-created")
+	}
+	if tracks := f.PlaylistTracks(newID); len(tracks) != 1 || tracks[0] != spotifytest.TrackID {
+		t.Error("Playlist tracks weren't restored:", tracks)
+	}
+	if len(result.Unresolved) != 1 || result.Unresolved[0] != "Lost Track" {
+		t.Error("Unexpected unresolved tracks", result.Unresolved)
+	}
+}
+
+func TestRestoreByISRC(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	var track spotify.FullTrack
+	track.Name = "The Funeral"
+	track.ExternalIDs = spotify.ExternalIDs{ISRC: "USSUB0665807"}
+	lib := &Library{SavedTracks: []spotify.SavedTrack{{FullTrack: track}}}
+
+	if _, err := (&Restorer{Client: &c}).Restore(lib); err != nil {
+		t.Fatal(err)
+	}
+	var searched, saved bool
+	for _, req := range s.Requests() {
+		switch req.URL.Path {
+		case "/v1/search":
+			searched = req.URL.Query().Get("q") == "isrc:USSUB0665807"
+		case "/v1/me/tracks":
+			saved = req.Method == "PUT" && req.URL.Query().Get("ids") == spotifytest.TrackID
+		}
+	}
+	if !searched || !saved {
+		t.Errorf("Expected ISRC search and save, got searched=%v saved=%v\n", searched, saved)
+	}
+}
+
+func TestRestoreSearchError(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	s.Handle("GET", "search", http.StatusServiceUnavailable, `{"error": {"status": 503, "message": "Service unavailable"}}`)
+	c := s.NewClient()
+
+	var track spotify.FullTrack
+	track.Name = "The Funeral"
+	track.ExternalIDs = spotify.ExternalIDs{ISRC: "USSUB0665807"}
+	lib := &Library{SavedTracks: []spotify.SavedTrack{{FullTrack: track}}}
+
+	result, err := (&Restorer{Client: &c}).Restore(lib)
+	if err == nil {
+		t.Fatal("Expected the failed search to stop the restore")
+	}
+	if len(result.Unresolved) != 0 {
+		t.Error("A failed search was taken as no match:", result.Unresolved)
+	}
+}
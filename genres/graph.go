@@ -0,0 +1,244 @@
+// Package genres builds a graph of the genres a user listens to, where
+// two genres are connected when the same artist belongs to both.  The
+// graph can be used to find clusters in a user's taste and to suggest
+// "adjacent" genres to explore.
+package genres
+
+import (
+	"math"
+	"sort"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Graph is a weighted, undirected genre co-occurrence graph.  The weight
+// of a genre is the total weight of the artists in it, and the weight of
+// an edge is the total weight of the artists in both genres.
+type Graph struct {
+	nodes map[string]float64
+	edges map[string]map[string]float64
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]float64),
+		edges: make(map[string]map[string]float64),
+	}
+}
+
+// AddArtist adds an artist's genres to the graph with the given weight.
+// Duplicate genres are counted once.
+func (g *Graph) AddArtist(genres []string, weight float64) {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, genre := range genres {
+		if genre != "" && !seen[genre] {
+			seen[genre] = true
+			unique = append(unique, genre)
+		}
+	}
+	for i, a := range unique {
+		g.nodes[a] += weight
+		for _, b := range unique[i+1:] {
+			g.addEdge(a, b, weight)
+			g.addEdge(b, a, weight)
+		}
+	}
+}
+
+func (g *Graph) addEdge(a, b string, weight float64) {
+	if g.edges[a] == nil {
+		g.edges[a] = make(map[string]float64)
+	}
+	g.edges[a][b] += weight
+}
+
+// Build builds the graph of the genres of the artists the user follows and
+// their top artists.  Followed artists have a weight of 1; top artists have
+// a weight between 1 and 2 for each time range, depending on their rank.
+// It requires the ScopeUserFollowRead and ScopeUserTopRead scopes.
+func Build(c spotify.SpotifyClient) (*Graph, error) {
+	g := NewGraph()
+	after := ""
+	for {
+		page, err := c.CurrentUsersFollowedArtistsOpt(50, after)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.Artists {
+			g.AddArtist(a.Genres, 1)
+		}
+		after = page.Cursor.After
+		if page.Next == "" || after == "" || len(page.Artists) == 0 {
+			break
+		}
+	}
+	for _, rng := range []string{"short_term", "medium_term", "long_term"} {
+		rng := rng
+		limit := 50
+		top, err := c.CurrentUserTopArtists(&spotify.Options{Limit: &limit, Timerange: &rng})
+		if err != nil {
+			return nil, err
+		}
+		for i, a := range top.Items {
+			g.AddArtist(a.Genres, 2-float64(i)/float64(len(top.Items)))
+		}
+	}
+	return g, nil
+}
+
+// Weighted is a genre and a weight or score.
+type Weighted struct {
+	Genre  string  `json:"genre"`
+	Weight float64 `json:"weight"`
+}
+
+type byWeight []Weighted
+
+func (w byWeight) Len() int      { return len(w) }
+func (w byWeight) Swap(i, j int) { w[i], w[j] = w[j], w[i] }
+func (w byWeight) Less(i, j int) bool {
+	if w[i].Weight != w[j].Weight {
+		return w[i].Weight > w[j].Weight
+	}
+	return w[i].Genre < w[j].Genre
+}
+
+// sorted converts a map of weights to a slice, heaviest first.
+func sorted(m map[string]float64) []Weighted {
+	w := make([]Weighted, 0, len(m))
+	for genre, weight := range m {
+		w = append(w, Weighted{genre, weight})
+	}
+	sort.Sort(byWeight(w))
+	return w
+}
+
+// Genres returns every genre in the graph, heaviest first.
+func (g *Graph) Genres() []Weighted {
+	return sorted(g.nodes)
+}
+
+// Weight returns the weight of a genre, or zero if it isn't in the graph.
+func (g *Graph) Weight(genre string) float64 {
+	return g.nodes[genre]
+}
+
+// Neighbors returns the genres that share an artist with genre, most
+// strongly connected first, weighted by the total weight of the shared
+// artists.
+func (g *Graph) Neighbors(genre string) []Weighted {
+	return sorted(g.edges[genre])
+}
+
+// Similarity returns how strongly two genres are associated, between 0
+// (no shared artists) and 1 (exactly the same artists).  Unlike the raw
+// edge weight, it isn't dominated by the user's most listened to genres.
+func (g *Graph) Similarity(a, b string) float64 {
+	if a == b && g.nodes[a] > 0 {
+		return 1
+	}
+	w := g.edges[a][b]
+	if w == 0 {
+		return 0
+	}
+	return w / math.Sqrt(g.nodes[a]*g.nodes[b])
+}
+
+// Explore suggests genres related to genre, up to depth steps away in the
+// graph.  A genre's score is the highest product of the similarities along
+// a path to it, so distant genres are only suggested through strong links.
+// The genre itself isn't included.
+func (g *Graph) Explore(genre string, depth int) []Weighted {
+	best := map[string]float64{genre: 1}
+	frontier := []string{genre}
+	for step := 0; step < depth && len(frontier) > 0; step++ {
+		var next []string
+		for _, from := range frontier {
+			for to := range g.edges[from] {
+				score := best[from] * g.Similarity(from, to)
+				if score > best[to] {
+					if _, ok := best[to]; !ok {
+						next = append(next, to)
+					}
+					best[to] = score
+				}
+			}
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+	delete(best, genre)
+	return sorted(best)
+}
+
+// Adjacent suggests genres to explore next: the genres most similar to the
+// user's n heaviest genres, excluding those genres themselves.
+func (g *Graph) Adjacent(n int) []Weighted {
+	core := make(map[string]bool)
+	for i, w := range g.Genres() {
+		if i == n {
+			break
+		}
+		core[w.Genre] = true
+	}
+	scores := make(map[string]float64)
+	for c := range core {
+		for other := range g.edges[c] {
+			if !core[other] {
+				scores[other] += g.Similarity(c, other)
+			}
+		}
+	}
+	return sorted(scores)
+}
+
+// Clusters groups the genres into sets that are connected by edges with
+// a similarity of at least minSimilarity.  Clusters are returned heaviest
+// first, and the genres in each cluster heaviest first.
+func (g *Graph) Clusters(minSimilarity float64) [][]Weighted {
+	cluster := make(map[string]int)
+	var clusters [][]Weighted
+	var weights []float64
+	for _, start := range g.Genres() {
+		if _, ok := cluster[start.Genre]; ok {
+			continue
+		}
+		id := len(clusters)
+		cluster[start.Genre] = id
+		members := []Weighted{start}
+		total := start.Weight
+		queue := []string{start.Genre}
+		for len(queue) > 0 {
+			from := queue[0]
+			queue = queue[1:]
+			for to := range g.edges[from] {
+				if _, ok := cluster[to]; ok || g.Similarity(from, to) < minSimilarity {
+					continue
+				}
+				cluster[to] = id
+				members = append(members, Weighted{to, g.nodes[to]})
+				total += g.nodes[to]
+				queue = append(queue, to)
+			}
+		}
+		sort.Sort(byWeight(members))
+		clusters = append(clusters, members)
+		weights = append(weights, total)
+	}
+	sort.Stable(byTotal{clusters, weights})
+	return clusters
+}
+
+type byTotal struct {
+	clusters [][]Weighted
+	weights  []float64
+}
+
+func (b byTotal) Len() int           { return len(b.clusters) }
+func (b byTotal) Less(i, j int) bool { return b.weights[i] > b.weights[j] }
+func (b byTotal) Swap(i, j int) {
+	b.clusters[i], b.clusters[j] = b.clusters[j], b.clusters[i]
+	b.weights[i], b.weights[j] = b.weights[j], b.weights[i]
+}
@@ -0,0 +1,98 @@
+package genres
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+func testGraph() *Graph {
+	g := NewGraph()
+	g.AddArtist([]string{"indie rock", "indie pop", "indie rock"}, 2)
+	g.AddArtist([]string{"indie pop", "dream pop"}, 1)
+	g.AddArtist([]string{"dream pop", "shoegaze"}, 1)
+	g.AddArtist([]string{"bebop", "jazz"}, 1)
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph()
+	genres := g.Genres()
+	if len(genres) != 6 || genres[0].Genre != "indie pop" || genres[0].Weight != 3 {
+		t.Errorf("Unexpected genres %+v\n", genres)
+	}
+	if g.Weight("indie rock") != 2 {
+		t.Error("Duplicate genres should be counted once")
+	}
+	n := g.Neighbors("indie pop")
+	if len(n) != 2 || n[0].Genre != "indie rock" || n[0].Weight != 2 {
+		t.Errorf("Unexpected neighbors %+v\n", n)
+	}
+	if s := g.Similarity("indie pop", "indie rock"); math.Abs(s-2/math.Sqrt(6)) > 1e-9 {
+		t.Errorf("Unexpected similarity %g\n", s)
+	}
+	if g.Similarity("jazz", "shoegaze") != 0 || g.Similarity("jazz", "jazz") != 1 {
+		t.Error("Unexpected similarity")
+	}
+}
+
+func TestExplore(t *testing.T) {
+	g := testGraph()
+	near := g.Explore("indie rock", 1)
+	if len(near) != 1 || near[0].Genre != "indie pop" {
+		t.Errorf("Unexpected genres at depth 1 %+v\n", near)
+	}
+	far := g.Explore("indie rock", 3)
+	if len(far) != 3 || far[2].Genre != "shoegaze" {
+		t.Errorf("Unexpected genres at depth 3 %+v\n", far)
+	}
+	for _, w := range far {
+		if w.Genre == "jazz" || w.Genre == "indie rock" {
+			t.Errorf("%s shouldn't be suggested\n", w.Genre)
+		}
+	}
+}
+
+func TestAdjacent(t *testing.T) {
+	// the heaviest genres are indie pop, then dream pop (which ties with
+	// indie rock, but sorts first)
+	adj := testGraph().Adjacent(2)
+	if len(adj) != 2 || adj[0].Genre != "indie rock" || adj[1].Genre != "shoegaze" {
+		t.Errorf("Unexpected adjacent genres %+v\n", adj)
+	}
+}
+
+func TestClusters(t *testing.T) {
+	clusters := testGraph().Clusters(0.5)
+	if len(clusters) != 3 {
+		t.Fatalf("Expected 3 clusters, got %+v\n", clusters)
+	}
+	if len(clusters[0]) != 2 || clusters[0][0].Genre != "indie pop" {
+		t.Errorf("Unexpected first cluster %+v\n", clusters[0])
+	}
+	if all := testGraph().Clusters(0); len(all) != 2 || len(all[0]) != 4 {
+		t.Errorf("Unexpected clusters %+v\n", all)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	s.Handle("GET", "me/following", http.StatusOK,
+		`{"artists":{"items":[{"id":"a","genres":["rock","blues"]}],"next":null,"cursors":{"after":null}}}`)
+	s.HandleFunc("GET", "me/top/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"id":"b","genres":["rock","punk"]},{"id":"c","genres":["punk"]}]}`)
+	})
+	c := s.NewClient()
+	g, err := Build(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rock: 1 for following, 2 for the top artist in each of three ranges
+	if g.Weight("rock") != 7 || g.Weight("punk") != 10.5 || g.Weight("blues") != 1 {
+		t.Errorf("Unexpected weights %+v\n", g.Genres())
+	}
+}
@@ -0,0 +1,335 @@
+package spotify
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// goldenFiles maps each file in test_data/golden to the model it is
+// decoded into.  The files contain real payloads returned by the Web API.
+var goldenFiles = map[string]func() interface{}{
+	"album_full.json":              func() interface{} { return new(FullAlbum) },
+	"album_saved_page.json":        func() interface{} { return new(SavedAlbumPage) },
+	"album_simple_page.json":       func() interface{} { return new(SimpleAlbumPage) },
+	"artist_full.json":             func() interface{} { return new(FullArtist) },
+	"artist_full_cursor_page.json": func() interface{} { return new(FullArtistCursorPage) },
+	"audio_analysis.json":          func() interface{} { return new(AudioAnalysis) },
+	"audio_features.json":          func() interface{} { return new(AudioFeatures) },
+	"category.json":                func() interface{} { return new(Category) },
+	"category_page.json":           func() interface{} { return new(CategoryPage) },
+	"episode_full.json":            func() interface{} { return new(FullEpisode) },
+	"play_history.json":            func() interface{} { return new(PlayHistory) },
+	"playlist_full.json":           func() interface{} { return new(FullPlaylist) },
+	"playlist_simple_page.json":    func() interface{} { return new(SimplePlaylistPage) },
+	"playlist_track_page.json":     func() interface{} { return new(PlaylistTrackPage) },
+	"recommendations.json":         func() interface{} { return new(Recommendations) },
+	"search_result.json":           func() interface{} { return new(SearchResult) },
+	"search_shows.json":            func() interface{} { return new(SearchResult) },
+	"show_full.json":               func() interface{} { return new(FullShow) },
+	"show_saved_page.json":         func() interface{} { return new(SavedShowPage) },
+	"top_artists.json":             func() interface{} { return new(TopArtists) },
+	"top_tracks.json":              func() interface{} { return new(TopTracks) },
+	"track_full.json":              func() interface{} { return new(FullTrack) },
+	"track_saved_page.json":        func() interface{} { return new(SavedTrackPage) },
+	"track_simple_page.json":       func() interface{} { return new(SimpleTrackPage) },
+	"track_unplayable.json":        func() interface{} { return new(FullTrack) },
+	"user_private.json":            func() interface{} { return new(PrivateUser) },
+	"user_public.json":             func() interface{} { return new(User) },
+}
+
+// strictGolden makes TestGoldenRoundTrip fail if a golden payload contains
+// fields that no model decodes, so that a field with a misspelled tag (which
+// is silently dropped) is noticed.  Run with:
+//     go test -run Golden -strict
+var strictGolden = flag.Bool("strict", false, "fail if golden payloads have fields the models don't decode")
+
+// TestGoldenRoundTrip decodes each golden payload into its model, encodes
+// it again and checks that every value produced by the model matches the
+// original payload.  It also checks that every field of every model is
+// present in at least one payload, so a field tagged with the wrong name
+// (which is silently never populated) is caught.
+func TestGoldenRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("test_data/golden/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if _, ok := goldenFiles[filepath.Base(f)]; !ok {
+			t.Errorf("%s has no model in goldenFiles", f)
+		}
+	}
+	names := make([]string, 0, len(goldenFiles))
+	for name := range goldenFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[reflect.Type]map[string]bool)
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join("test_data", "golden", name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		v := goldenFiles[name]()
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Errorf("%s: couldn't decode: %v", name, err)
+			continue
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			t.Errorf("%s: couldn't encode: %v", name, err)
+			continue
+		}
+		var want, got interface{}
+		if err := json.Unmarshal(data, &want); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		for _, diff := range jsonSubset("", got, want) {
+			t.Errorf("%s: %s", name, diff)
+		}
+		coverFields(reflect.TypeOf(v), want, seen)
+		if *strictGolden {
+			for _, path := range unmodeled("", reflect.TypeOf(v), want) {
+				t.Errorf("%s: %s isn't decoded by any field", name, path)
+			}
+		}
+	}
+	for typ, tags := range seen {
+		for tag, ok := range tags {
+			if !ok {
+				t.Errorf("%s field %q doesn't appear in any golden payload", typ.Name(), tag)
+			}
+		}
+	}
+}
+
+// walkable reports whether the fields of the struct type typ map directly
+// to JSON fields: it is one of this package's models, and doesn't have its
+// own UnmarshalJSON method.
+func walkable(typ reflect.Type) bool {
+	unmarshaler := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	return typ.PkgPath() == reflect.TypeOf(Client{}).PkgPath() && !reflect.PtrTo(typ).Implements(unmarshaler)
+}
+
+// coverFields records which of the JSON fields of typ (and the types it
+// contains) are present in the payload v.
+func coverFields(typ reflect.Type, v interface{}, seen map[reflect.Type]map[string]bool) {
+	switch typ.Kind() {
+	case reflect.Ptr:
+		coverFields(typ.Elem(), v, seen)
+	case reflect.Slice:
+		if items, ok := v.([]interface{}); ok {
+			for _, item := range items {
+				coverFields(typ.Elem(), item, seen)
+			}
+		}
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok || !walkable(typ) {
+			return
+		}
+		if seen[typ] == nil {
+			seen[typ] = make(map[string]bool)
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			if f.Anonymous && tag == "" {
+				coverFields(f.Type, v, seen)
+				continue
+			}
+			if f.PkgPath != "" || tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			fv, ok := obj[tag]
+			seen[typ][tag] = seen[typ][tag] || ok
+			if ok {
+				coverFields(f.Type, fv, seen)
+			}
+		}
+	}
+}
+
+// jsonFields returns the struct fields of typ (including those of embedded
+// structs) by JSON name.
+func jsonFields(typ reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			jsonFields(ft, fields)
+			continue
+		}
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		fields[tag] = f.Type
+	}
+}
+
+// unmodeled returns the paths of the keys in the payload v that aren't
+// decoded into any field of typ.
+func unmodeled(path string, typ reflect.Type, v interface{}) []string {
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return unmodeled(path, typ.Elem(), v)
+	case reflect.Slice, reflect.Array:
+		var paths []string
+		if items, ok := v.([]interface{}); ok {
+			for i, item := range items {
+				paths = append(paths, unmodeled(fmt.Sprintf("%s[%d]", path, i), typ.Elem(), item)...)
+			}
+		}
+		return paths
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok || !walkable(typ) {
+			return nil
+		}
+		fields := make(map[string]reflect.Type)
+		jsonFields(typ, fields)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var paths []string
+		for _, k := range keys {
+			ft, ok := fields[k]
+			if !ok {
+				paths = append(paths, path+"."+k)
+				continue
+			}
+			paths = append(paths, unmodeled(path+"."+k, ft, obj[k])...)
+		}
+		return paths
+	}
+	return nil
+}
+
+// isZero reports whether a decoded JSON value is empty.
+func isZero(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, e := range v {
+			if !isZero(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// jsonSubset reports the differences between a re-encoded value and the
+// original payload.  Keys in the original that aren't modeled are ignored,
+// and keys that are missing or null in the original match the zero value
+// of the model field.
+func jsonSubset(path string, got, want interface{}) []string {
+	switch g := got.(type) {
+	case map[string]interface{}:
+		if want == nil && isZero(g) {
+			return nil
+		}
+		w, ok := want.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got object, want %v", path, want)}
+		}
+		var diffs []string
+		for k, gv := range g {
+			wv, ok := w[k]
+			if !ok && isZero(gv) {
+				continue
+			}
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: not in payload (got %v)", path, k, gv))
+				continue
+			}
+			diffs = append(diffs, jsonSubset(path+"."+k, gv, wv)...)
+		}
+		return diffs
+	case []interface{}:
+		if want == nil && len(g) == 0 {
+			return nil
+		}
+		w, ok := want.([]interface{})
+		if !ok || len(w) != len(g) {
+			return []string{fmt.Sprintf("%s: got %d elements, want %v", path, len(g), want)}
+		}
+		var diffs []string
+		for i := range g {
+			diffs = append(diffs, jsonSubset(fmt.Sprintf("%s[%d]", path, i), g[i], w[i])...)
+		}
+		return diffs
+	}
+	if want == nil && isZero(got) {
+		return nil
+	}
+	if !reflect.DeepEqual(got, want) {
+		return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+	}
+	return nil
+}
+
+// TestGoldenReencode checks that decoding a model from its own encoding
+// gives back the same value, so models can be persisted (to the datastore
+// or a file) as JSON and loaded again without losing anything.
+func TestGoldenReencode(t *testing.T) {
+	for name, model := range goldenFiles {
+		data, err := ioutil.ReadFile(filepath.Join("test_data", "golden", name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		first := model()
+		if err := json.Unmarshal(data, first); err != nil {
+			t.Errorf("%s: couldn't decode: %v", name, err)
+			continue
+		}
+		out, err := json.Marshal(first)
+		if err != nil {
+			t.Errorf("%s: couldn't encode: %v", name, err)
+			continue
+		}
+		second := model()
+		if err := json.Unmarshal(out, second); err != nil {
+			t.Errorf("%s: couldn't decode encoded model: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: model changed after encoding and decoding again", name)
+		}
+	}
+}
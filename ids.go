@@ -0,0 +1,48 @@
+package spotify
+
+// DedupeIDs removes duplicate IDs from ids, preserving the order in
+// which each ID first appears.  It also returns an index that can be used
+// to re-align the results of a batch request for the unique IDs with the
+// original slice: index[i] is the position in unique of ids[i].  For example:
+//
+//     unique, index := spotify.DedupeIDs(ids)
+//     tracks, err := client.GetTracks(unique...)
+//     // ...
+//     result := make([]*spotify.FullTrack, len(ids))
+//     for i, j := range index {
+//         result[i] = tracks[j]
+//     }
+//
+func DedupeIDs(ids []ID) (unique []ID, index []int) {
+	seen := make(map[ID]int, len(ids))
+	unique = make([]ID, 0, len(ids))
+	index = make([]int, len(ids))
+	for i, id := range ids {
+		j, ok := seen[id]
+		if !ok {
+			j = len(unique)
+			seen[id] = j
+			unique = append(unique, id)
+		}
+		index[i] = j
+	}
+	return unique, index
+}
+
+// ChunkIDs splits ids into consecutive batches of at most size IDs, for use
+// with endpoints that limit the number of IDs per call.  The batches share
+// storage with ids.  ChunkIDs panics if size is not positive.
+func ChunkIDs(ids []ID, size int) [][]ID {
+	if size <= 0 {
+		panic("spotify: ChunkIDs requires a positive size")
+	}
+	chunks := make([][]ID, 0, (len(ids)+size-1)/size)
+	for len(ids) > size {
+		chunks = append(chunks, ids[:size:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
@@ -0,0 +1,64 @@
+package spotify
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDedupeIDs(t *testing.T) {
+	ids := []ID{"a", "b", "a", "c", "b"}
+	unique, index := DedupeIDs(ids)
+	if want := []ID{"a", "b", "c"}; !reflect.DeepEqual(unique, want) {
+		t.Errorf("Wanted %v, got %v\n", want, unique)
+	}
+	for i, j := range index {
+		if unique[j] != ids[i] {
+			t.Errorf("Index %d doesn't map back to %s\n", i, ids[i])
+		}
+	}
+}
+
+func TestChunkIDs(t *testing.T) {
+	ids := []ID{"a", "b", "c", "d", "e"}
+	chunks := ChunkIDs(ids, 2)
+	want := [][]ID{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Wanted %v, got %v\n", want, chunks)
+	}
+	if l := len(ChunkIDs(nil, 50)); l != 0 {
+		t.Errorf("Wanted no chunks, got %d\n", l)
+	}
+	// appending to a chunk must not overwrite the next one
+	_ = append(chunks[0], "x")
+	if chunks[1][0] != "c" {
+		t.Error("Chunks share capacity")
+	}
+}
+
+func TestIDTextMarshaling(t *testing.T) {
+	m := map[ID]URI{"6rqhFgbbKwnb9MLmUQDhG6": "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); s != `{"6rqhFgbbKwnb9MLmUQDhG6":"spotify:track:6rqhFgbbKwnb9MLmUQDhG6"}` {
+		t.Errorf("Unexpected JSON: %s\n", s)
+	}
+	var back map[ID]URI
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, back) {
+		t.Errorf("Map didn't round trip: %v\n", back)
+	}
+
+	var id ID
+	if err := id.UnmarshalText([]byte("4iV5W9uYEdYUVa79Axb7Rh")); err != nil || id.String() != "4iV5W9uYEdYUVa79Axb7Rh" {
+		t.Errorf("Unexpected ID %s (%v)\n", id, err)
+	}
+	var _ fmt.Stringer = URI("")
+	var _ encoding.TextUnmarshaler = &id
+}
@@ -0,0 +1,268 @@
+package spotify
+
+import (
+	"io"
+
+	"golang.org/x/net/context"
+)
+
+// SpotifyClient is the set of Web API calls provided by Client.  Code that
+// depends on SpotifyClient rather than *Client can substitute a mock or fake
+// implementation in unit tests, without stubbing HTTP responses.
+type SpotifyClient interface {
+	// albums
+	GetAlbum(id ID) (*FullAlbum, error)
+	GetAlbums(ids ...ID) ([]*FullAlbum, error)
+	GetAlbumsBatch(ids ...ID) ([]*FullAlbum, error)
+	GetAlbumTracks(id ID) (*SimpleTrackPage, error)
+	GetAlbumTracksOpt(id ID, limit, offset int) (*SimpleTrackPage, error)
+
+	// artists
+	GetArtist(id ID) (*FullArtist, error)
+	GetArtists(ids ...ID) ([]*FullArtist, error)
+	GetArtistsBatch(ids ...ID) ([]*FullArtist, error)
+	GetArtistAlbums(artistID ID) (*SimpleAlbumPage, error)
+	GetArtistAlbumsOpt(artistID ID, options *Options, t *AlbumType) (*SimpleAlbumPage, error)
+	GetArtistsTopTracks(artistID ID, country string) ([]FullTrack, error)
+	GetRelatedArtists(id ID) ([]FullArtist, error)
+
+	// tracks and audio
+	GetTrack(id ID) (*FullTrack, error)
+	GetTracks(ids ...ID) ([]*FullTrack, error)
+	GetTracksOpt(opt *Options, ids ...ID) ([]*FullTrack, error)
+	GetTracksBatch(ids ...ID) ([]*FullTrack, error)
+	TrackAvailability(ids []ID, markets ...string) ([]*Availability, error)
+	GetAudioAnalysis(id ID) (*AudioAnalysis, error)
+	GetAudioAnalysisLazy(id ID) (*LazyAudioAnalysis, error)
+	GetTrackAudioFeatures(id ID) (*AudioFeatures, error)
+	GetAudioFeatures(ids ...ID) ([]*AudioFeatures, error)
+	GetAudioFeaturesBatch(ids ...ID) ([]*AudioFeatures, error)
+
+	// images
+	DownloadImage(ctx context.Context, img Image, w io.Writer) error
+
+	// browse
+	NewReleases() (albums *SimpleAlbumPage, err error)
+	NewReleasesOpt(opt *Options) (albums *SimpleAlbumPage, err error)
+	FeaturedPlaylists() (message string, playlists *SimplePlaylistPage, e error)
+	FeaturedPlaylistsOpt(opt *PlaylistOptions) (message string, playlists *SimplePlaylistPage, e error)
+	GetCategories() (*CategoryPage, error)
+	GetCategoriesOpt(opt *Options, locale string) (*CategoryPage, error)
+	GetCategory(id string) (Category, error)
+	GetCategoryOpt(id, country, locale string) (Category, error)
+	GetCategoryPlaylists(catID string) (*SimplePlaylistPage, error)
+	GetCategoryPlaylistsOpt(catID string, opt *Options) (*SimplePlaylistPage, error)
+	NextPlaylistsPage(p *SimplePlaylistPage) error
+	NextNewReleasesPage(p *SimpleAlbumPage) error
+	NextCategoriesPage(p *CategoryPage) error
+
+	// recommendations
+	GetRecommendations(seeds Seeds, trackAttributes *TrackAttributes, opt *Options) (*Recommendations, error)
+	GetAvailableGenreSeeds() ([]string, error)
+
+	// search
+	Search(query string, t SearchType) (*SearchResult, error)
+	SearchOpt(query string, t SearchType, opt *Options) (*SearchResult, error)
+	NextAlbumResults(s *SearchResult) error
+	NextArtistResults(s *SearchResult) error
+	NextPlaylistResults(s *SearchResult) error
+	NextTrackResults(s *SearchResult) error
+	PreviousAlbumResults(s *SearchResult) error
+	PreviousArtistResults(s *SearchResult) error
+	PreviousPlaylistResults(s *SearchResult) error
+	PreviousTrackResults(s *SearchResult) error
+	NextShowResults(s *SearchResult) error
+	NextEpisodeResults(s *SearchResult) error
+	PreviousShowResults(s *SearchResult) error
+	PreviousEpisodeResults(s *SearchResult) error
+
+	// users and following
+	CurrentUser() (*PrivateUser, error)
+	GetUsersPublicProfile(userID ID) (*User, error)
+	FollowUser(ids ...ID) error
+	FollowArtist(ids ...ID) error
+	UnfollowUser(ids ...ID) error
+	UnfollowArtist(ids ...ID) error
+	CurrentUserFollows(t string, ids ...ID) ([]bool, error)
+	CurrentUsersFollowedArtists() (*FullArtistCursorPage, error)
+	CurrentUsersFollowedArtistsOpt(limit int, after string) (*FullArtistCursorPage, error)
+	FollowedArtistsIter(limit int) *FollowedArtistsIterator
+	FollowedArtistsIterWithContext(ctx context.Context, limit int) *FollowedArtistsIterator
+
+	// library
+	CurrentUsersTracks() (*SavedTrackPage, error)
+	CurrentUsersTracksOpt(opt *Options) (*SavedTrackPage, error)
+	SavedTracksIter(opt *Options) *SavedTracksIterator
+	SavedTracksIterWithContext(ctx context.Context, opt *Options) *SavedTracksIterator
+	CurrentUsersAlbums() (*SavedAlbumPage, error)
+	CurrentUsersAlbumsOpt(opt *Options) (*SavedAlbumPage, error)
+	UserHasTracks(ids ...ID) ([]bool, error)
+	AddTracksToLibrary(ids ...ID) error
+	RemoveTracksFromLibrary(ids ...ID) error
+	AddAlbumsToLibrary(ids ...ID) error
+	RemoveAlbumsFromLibrary(ids ...ID) error
+
+	// shows
+	GetShow(id ID) (*FullShow, error)
+	GetShowOpt(id ID, opt *Options) (*FullShow, error)
+	GetShowEpisodes(id ID) (*SimpleEpisodePage, error)
+	GetShowEpisodesOpt(id ID, opt *Options) (*SimpleEpisodePage, error)
+	GetEpisode(id ID) (*FullEpisode, error)
+	GetEpisodeOpt(id ID, opt *Options) (*FullEpisode, error)
+	CurrentUsersShows() (*SavedShowPage, error)
+	CurrentUsersShowsOpt(opt *Options) (*SavedShowPage, error)
+	AddShowsToLibrary(ids ...ID) error
+	RemoveShowsFromLibrary(ids ...ID) error
+
+	// personalization
+	CurrentUserRecentTracksWithContext(ctx context.Context, opt *Options) (*PlayHistory, error)
+	CurrentUserTopTracksWithContext(ctx context.Context, opt *Options) (*TopTracks, error)
+	CurrentUserTopArtistsWithContext(ctx context.Context, opt *Options) (*TopArtists, error)
+	CurrentUserRecentTracks(total int) (*PlayHistory, error)
+	CurrentUserTopTracks(opt *Options) (*TopTracks, error)
+	CurrentUserTopArtists(opt *Options) (*TopArtists, error)
+	TopTracksIter(opt *Options) *TopTracksIterator
+	TopTracksIterWithContext(ctx context.Context, opt *Options) *TopTracksIterator
+	TopArtistsIter(opt *Options) *TopArtistsIterator
+	TopArtistsIterWithContext(ctx context.Context, opt *Options) *TopArtistsIterator
+
+	// player
+	PlayerCurrentlyPlayingWithContext(ctx context.Context, opt *Options) (*CurrentlyPlaying, error)
+	PlayerStateWithContext(ctx context.Context, opt *Options) (*PlayerState, error)
+	PlayerCurrentlyPlaying() (*CurrentlyPlaying, error)
+	PlayerState() (*PlayerState, error)
+	PlayWithContext(ctx context.Context, opt *PlayOptions) error
+	PauseWithContext(ctx context.Context, opt *PlayOptions) error
+	NextWithContext(ctx context.Context, opt *PlayOptions) error
+	PreviousWithContext(ctx context.Context, opt *PlayOptions) error
+	SeekWithContext(ctx context.Context, position int, opt *PlayOptions) error
+	Play() error
+	PlayOpt(opt *PlayOptions) error
+	Pause() error
+	PauseOpt(opt *PlayOptions) error
+	Next() error
+	NextOpt(opt *PlayOptions) error
+	Previous() error
+	PreviousOpt(opt *PlayOptions) error
+	Seek(position int) error
+	SeekOpt(position int, opt *PlayOptions) error
+
+	// playlists
+	CurrentUsersPlaylists() (*SimplePlaylistPage, error)
+	CurrentUsersPlaylistsOpt(opt *Options) (*SimplePlaylistPage, error)
+	GetPlaylistsForUser(userID string) (*SimplePlaylistPage, error)
+	GetPlaylistsForUserOpt(userID string, opt *Options) (*SimplePlaylistPage, error)
+	GetPlaylist(userID string, playlistID ID) (*FullPlaylist, error)
+	GetPlaylistOpt(userID string, playlistID ID, fields string) (*FullPlaylist, error)
+	GetPlaylistTracks(userID string, playlistID ID) (*PlaylistTrackPage, error)
+	GetPlaylistTracksOpt(userID string, playlistID ID, opt *Options, fields string) (*PlaylistTrackPage, error)
+	GetPlaylistTracksLazy(userID string, playlistID ID, opt *Options, fields string) (*LazyPlaylistTrackPage, error)
+	EachPlaylistTrack(userID string, playlistID ID, opt *Options, fields string, fn func(i int, t *PlaylistTrack) error) error
+	CreatePlaylistForUser(userID, playlistName string, public bool) (*FullPlaylist, error)
+	ChangePlaylistName(userID string, playlistID ID, newName string) error
+	ChangePlaylistAccess(userID string, playlistID ID, public bool) error
+	ChangePlaylistNameAndAccess(userID string, playlistID ID, newName string, public bool) error
+	ChangePlaylistDetails(userID string, playlistID ID, details *PlaylistDetails) error
+	AddTracksToPlaylist(userID string, playlistID ID, trackIDs ...ID) (snapshotID string, err error)
+	RemoveTracksFromPlaylist(userID string, playlistID ID, trackIDs ...ID) (newSnapshotID string, err error)
+	RemoveTracksFromPlaylistOpt(userID string, playlistID ID, tracks []TrackToRemove, snapshotID string) (newSnapshotID string, err error)
+	ReplacePlaylistTracks(userID string, playlistID ID, trackIDs ...ID) error
+	ReorderPlaylistTracks(userID string, playlistID ID, opt PlaylistReorderOptions) (snapshotID string, err error)
+	FollowPlaylist(owner ID, playlist ID, public bool) error
+	UnfollowPlaylist(owner, playlist ID) error
+	UserFollowsPlaylist(ownerID string, playlistID ID, userIDs ...string) ([]bool, error)
+
+	// the calls above, with a context (see context.go)
+	GetAlbumWithContext(ctx context.Context, id ID) (*FullAlbum, error)
+	GetAlbumsWithContext(ctx context.Context, ids ...ID) ([]*FullAlbum, error)
+	GetAlbumsBatchWithContext(ctx context.Context, ids ...ID) ([]*FullAlbum, error)
+	GetAlbumTracksWithContext(ctx context.Context, id ID, limit, offset int) (*SimpleTrackPage, error)
+
+	GetArtistWithContext(ctx context.Context, id ID) (*FullArtist, error)
+	GetArtistsWithContext(ctx context.Context, ids ...ID) ([]*FullArtist, error)
+	GetArtistsBatchWithContext(ctx context.Context, ids ...ID) ([]*FullArtist, error)
+	GetArtistAlbumsWithContext(ctx context.Context, artistID ID, options *Options, t *AlbumType) (*SimpleAlbumPage, error)
+	GetArtistsTopTracksWithContext(ctx context.Context, artistID ID, country string) ([]FullTrack, error)
+	GetRelatedArtistsWithContext(ctx context.Context, id ID) ([]FullArtist, error)
+
+	GetTrackWithContext(ctx context.Context, id ID) (*FullTrack, error)
+	GetTracksWithContext(ctx context.Context, opt *Options, ids ...ID) ([]*FullTrack, error)
+	GetTracksBatchWithContext(ctx context.Context, ids ...ID) ([]*FullTrack, error)
+	TrackAvailabilityWithContext(ctx context.Context, ids []ID, markets ...string) ([]*Availability, error)
+	GetAudioAnalysisWithContext(ctx context.Context, id ID) (*AudioAnalysis, error)
+	GetAudioAnalysisLazyWithContext(ctx context.Context, id ID) (*LazyAudioAnalysis, error)
+	GetTrackAudioFeaturesWithContext(ctx context.Context, id ID) (*AudioFeatures, error)
+	GetAudioFeaturesWithContext(ctx context.Context, ids ...ID) ([]*AudioFeatures, error)
+	GetAudioFeaturesBatchWithContext(ctx context.Context, ids ...ID) ([]*AudioFeatures, error)
+
+	NewReleasesWithContext(ctx context.Context, opt *Options) (*SimpleAlbumPage, error)
+	FeaturedPlaylistsWithContext(ctx context.Context, opt *PlaylistOptions) (message string, playlists *SimplePlaylistPage, err error)
+	GetCategoriesWithContext(ctx context.Context, opt *Options, locale string) (*CategoryPage, error)
+	GetCategoryWithContext(ctx context.Context, id, country, locale string) (Category, error)
+	GetCategoryPlaylistsWithContext(ctx context.Context, catID string, opt *Options) (*SimplePlaylistPage, error)
+	NextPlaylistsPageWithContext(ctx context.Context, p *SimplePlaylistPage) error
+	NextNewReleasesPageWithContext(ctx context.Context, p *SimpleAlbumPage) error
+	NextCategoriesPageWithContext(ctx context.Context, p *CategoryPage) error
+	GetRecommendationsWithContext(ctx context.Context, seeds Seeds, trackAttributes *TrackAttributes, opt *Options) (*Recommendations, error)
+	GetAvailableGenreSeedsWithContext(ctx context.Context) ([]string, error)
+
+	SearchWithContext(ctx context.Context, query string, t SearchType, opt *Options) (*SearchResult, error)
+	NextAlbumResultsWithContext(ctx context.Context, s *SearchResult) error
+	NextArtistResultsWithContext(ctx context.Context, s *SearchResult) error
+	NextPlaylistResultsWithContext(ctx context.Context, s *SearchResult) error
+	NextTrackResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousAlbumResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousArtistResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousPlaylistResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousTrackResultsWithContext(ctx context.Context, s *SearchResult) error
+	NextShowResultsWithContext(ctx context.Context, s *SearchResult) error
+	NextEpisodeResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousShowResultsWithContext(ctx context.Context, s *SearchResult) error
+	PreviousEpisodeResultsWithContext(ctx context.Context, s *SearchResult) error
+
+	CurrentUserWithContext(ctx context.Context) (*PrivateUser, error)
+	GetUsersPublicProfileWithContext(ctx context.Context, userID ID) (*User, error)
+	FollowUserWithContext(ctx context.Context, ids ...ID) error
+	FollowArtistWithContext(ctx context.Context, ids ...ID) error
+	UnfollowUserWithContext(ctx context.Context, ids ...ID) error
+	UnfollowArtistWithContext(ctx context.Context, ids ...ID) error
+	CurrentUserFollowsWithContext(ctx context.Context, t string, ids ...ID) ([]bool, error)
+	CurrentUsersFollowedArtistsWithContext(ctx context.Context, limit int, after string) (*FullArtistCursorPage, error)
+
+	CurrentUsersTracksWithContext(ctx context.Context, opt *Options) (*SavedTrackPage, error)
+	CurrentUsersAlbumsWithContext(ctx context.Context, opt *Options) (*SavedAlbumPage, error)
+	UserHasTracksWithContext(ctx context.Context, ids ...ID) ([]bool, error)
+	AddTracksToLibraryWithContext(ctx context.Context, ids ...ID) error
+	RemoveTracksFromLibraryWithContext(ctx context.Context, ids ...ID) error
+	AddAlbumsToLibraryWithContext(ctx context.Context, ids ...ID) error
+	RemoveAlbumsFromLibraryWithContext(ctx context.Context, ids ...ID) error
+	GetShowWithContext(ctx context.Context, id ID, opt *Options) (*FullShow, error)
+	GetShowEpisodesWithContext(ctx context.Context, id ID, opt *Options) (*SimpleEpisodePage, error)
+	GetEpisodeWithContext(ctx context.Context, id ID, opt *Options) (*FullEpisode, error)
+	CurrentUsersShowsWithContext(ctx context.Context, opt *Options) (*SavedShowPage, error)
+	AddShowsToLibraryWithContext(ctx context.Context, ids ...ID) error
+	RemoveShowsFromLibraryWithContext(ctx context.Context, ids ...ID) error
+
+	CurrentUsersPlaylistsWithContext(ctx context.Context, opt *Options) (*SimplePlaylistPage, error)
+	GetPlaylistsForUserWithContext(ctx context.Context, userID string, opt *Options) (*SimplePlaylistPage, error)
+	GetPlaylistWithContext(ctx context.Context, userID string, playlistID ID, fields string) (*FullPlaylist, error)
+	GetPlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID, opt *Options, fields string) (*PlaylistTrackPage, error)
+	GetPlaylistTracksLazyWithContext(ctx context.Context, userID string, playlistID ID, opt *Options, fields string) (*LazyPlaylistTrackPage, error)
+	EachPlaylistTrackWithContext(ctx context.Context, userID string, playlistID ID, opt *Options, fields string, fn func(i int, t *PlaylistTrack) error) error
+	CreatePlaylistForUserWithContext(ctx context.Context, userID, playlistName string, public bool) (*FullPlaylist, error)
+	ChangePlaylistNameWithContext(ctx context.Context, userID string, playlistID ID, newName string) error
+	ChangePlaylistAccessWithContext(ctx context.Context, userID string, playlistID ID, public bool) error
+	ChangePlaylistNameAndAccessWithContext(ctx context.Context, userID string, playlistID ID, newName string, public bool) error
+	ChangePlaylistDetailsWithContext(ctx context.Context, userID string, playlistID ID, details *PlaylistDetails) error
+	AddTracksToPlaylistWithContext(ctx context.Context, userID string, playlistID ID, trackIDs ...ID) (snapshotID string, err error)
+	RemoveTracksFromPlaylistWithContext(ctx context.Context, userID string, playlistID ID, trackIDs ...ID) (newSnapshotID string, err error)
+	RemoveTracksFromPlaylistOptWithContext(ctx context.Context, userID string, playlistID ID, tracks []TrackToRemove, snapshotID string) (newSnapshotID string, err error)
+	ReplacePlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID, trackIDs ...ID) error
+	ReorderPlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID, opt PlaylistReorderOptions) (snapshotID string, err error)
+	FollowPlaylistWithContext(ctx context.Context, owner ID, playlist ID, public bool) error
+	UnfollowPlaylistWithContext(ctx context.Context, owner, playlist ID) error
+	UserFollowsPlaylistWithContext(ctx context.Context, ownerID string, playlistID ID, userIDs ...string) ([]bool, error)
+}
+
+// Client must implement SpotifyClient.
+var _ SpotifyClient = (*Client)(nil)
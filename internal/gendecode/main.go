@@ -0,0 +1,280 @@
+// Command gendecode generates the reflection-free JSON decoders used by
+// spotify.FastCodec.  Run it with go generate in the spotify package.
+//
+// It inspects the models with reflection, so it imports the package it
+// generates code for.  If the models change in a way that stops the
+// generated file from compiling, delete decode_gen.go and run it again:
+// the package builds without it, falling back to encoding/json.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// roots are the types that FastCodec decodes with generated code.  The
+// types they contain get decoders too.
+var roots = []interface{}{
+	spotify.AudioAnalysis{},
+	spotify.AudioFeatures{},
+	spotify.PlayHistory{},
+	spotify.TopArtists{},
+	spotify.TopTracks{},
+	spotify.FullTrack{},
+	spotify.CategoryPage{},
+	spotify.FullArtistCursorPage{},
+	spotify.FullArtistPage{},
+	spotify.FullTrackPage{},
+	spotify.PlaylistTrackPage{},
+	spotify.SavedAlbumPage{},
+	spotify.SavedTrackPage{},
+	spotify.SimpleAlbumPage{},
+	spotify.SimplePlaylistPage{},
+	spotify.SimpleTrackPage{},
+}
+
+var (
+	pkgPath     = reflect.TypeOf(spotify.Client{}).PkgPath()
+	unmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+func main() {
+	out := flag.String("o", "decode_gen.go", "the file to write")
+	flag.Parse()
+	g := &generator{done: make(map[reflect.Type]bool)}
+	for _, v := range roots {
+		g.queue = append(g.queue, reflect.TypeOf(v))
+	}
+	for len(g.queue) > 0 {
+		t := g.queue[0]
+		g.queue = g.queue[1:]
+		if !g.done[t] {
+			g.done[t] = true
+			g.decoder(t)
+		}
+	}
+	src, err := format.Source(g.file())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	queue []reflect.Type
+	done  map[reflect.Type]bool
+	// funcs holds the generated decoders, by type name.
+	funcs map[string]string
+}
+
+// file returns the source of the generated file.
+func (g *generator) file() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gendecode. DO NOT EDIT.\n\npackage spotify\n\n")
+	b.WriteString("func init() {\n\tfastDecoders = decodeGenerated\n}\n\n")
+	b.WriteString("// decodeGenerated decodes the value with its generated decoder, if it has one.\n")
+	b.WriteString("func decodeGenerated(r *jsonReader, v interface{}) bool {\n\tswitch v := v.(type) {\n")
+	for _, v := range roots {
+		fmt.Fprintf(&b, "\tcase *%s:\n\t\tv.decodeFast(r)\n", reflect.TypeOf(v).Name())
+	}
+	b.WriteString("\tdefault:\n\t\treturn false\n\t}\n\treturn true\n}\n")
+	names := make([]string, 0, len(g.funcs))
+	for name := range g.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString("\n" + g.funcs[name])
+	}
+	return b.Bytes()
+}
+
+// field is a JSON field of a struct, and the Go expression that selects it.
+type field struct {
+	key   string
+	path  string
+	typ   reflect.Type
+	depth int
+}
+
+// fields returns the JSON fields of t, applying encoding/json's rules for
+// embedded structs: the shallowest field with a name wins, and names that
+// are ambiguous at that depth are dropped.
+func fields(t reflect.Type, path string, depth int) []field {
+	var all []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			all = append(all, fields(f.Type, path+"."+f.Name, depth+1)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(tag, ",string") {
+			log.Fatalf("%s.%s: the string option isn't supported", t, f.Name)
+		}
+		all = append(all, field{key: name, path: path + "." + f.Name, typ: f.Type, depth: depth})
+	}
+	if depth > 0 {
+		return all
+	}
+	byKey := make(map[string][]field)
+	var keys []string
+	for _, f := range all {
+		if byKey[f.key] == nil {
+			keys = append(keys, f.key)
+		}
+		byKey[f.key] = append(byKey[f.key], f)
+	}
+	var result []field
+	for _, k := range keys {
+		var best []field
+		for _, f := range byKey[k] {
+			switch {
+			case len(best) == 0 || f.depth < best[0].depth:
+				best = []field{f}
+			case f.depth == best[0].depth:
+				best = append(best, f)
+			}
+		}
+		if len(best) == 1 {
+			result = append(result, best[0])
+		}
+	}
+	return result
+}
+
+// generated reports whether t gets a generated decoder.
+func generated(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == pkgPath && t.Name() != "" &&
+		!reflect.PtrTo(t).Implements(unmarshaler)
+}
+
+// supported reports whether values of type t can be decoded by generated
+// code, rather than by encoding/json.
+func supported(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(unmarshaler) {
+		return false
+	}
+	if t.Name() != "" && t.PkgPath() != "" && t.PkgPath() != pkgPath {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Ptr, reflect.Slice:
+		return supported(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Key().Name() == "string" && supported(t.Elem())
+	case reflect.Struct:
+		return generated(t)
+	}
+	return false
+}
+
+// typeName returns the Go syntax for t, from within the package.
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map[string]" + typeName(t.Elem())
+	}
+	log.Fatalf("can't name %s", t)
+	return ""
+}
+
+// decoder generates the decoder for the struct type t.
+func (g *generator) decoder(t reflect.Type) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "func (v *%s) decodeFast(r *jsonReader) {\n", t.Name())
+	b.WriteString("\tif !r.beginObject() {\n\t\treturn\n\t}\n")
+	b.WriteString("\tfor r.nextKey() {\n\t\tswitch string(r.key) {\n")
+	for _, f := range fields(t, "v", 0) {
+		fmt.Fprintf(&b, "\t\tcase %q:\n", f.key)
+		b.WriteString(g.value(f.path, f.typ, 0))
+	}
+	b.WriteString("\t\tdefault:\n\t\t\tr.skip()\n\t\t}\n\t}\n}\n")
+	if g.funcs == nil {
+		g.funcs = make(map[string]string)
+	}
+	g.funcs[t.Name()] = b.String()
+}
+
+// value returns the statements that decode a value of type t into target.
+// depth numbers the temporary variables of nested slices and maps.
+func (g *generator) value(target string, t reflect.Type, depth int) string {
+	if !supported(t) {
+		return fmt.Sprintf("r.unmarshal(&%s)\n", target)
+	}
+	conv := func(read, builtin string) string {
+		if t.Name() == builtin {
+			return fmt.Sprintf("%s = %s\n", target, read)
+		}
+		return fmt.Sprintf("%s = %s(%s)\n", target, typeName(t), read)
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return conv("r.bool()", "bool")
+	case reflect.String:
+		return conv("r.string()", "string")
+	case reflect.Float64:
+		return conv("r.float64()", "float64")
+	case reflect.Float32:
+		return conv("r.float64()", "")
+	case reflect.Int64:
+		return conv("r.int64()", "int64")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return conv("r.int64()", "")
+	case reflect.Uint64:
+		return conv("r.uint64()", "uint64")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return conv("r.uint64()", "")
+	case reflect.Struct:
+		g.queue = append(g.queue, t)
+		return fmt.Sprintf("%s.decodeFast(r)\n", target)
+	case reflect.Ptr:
+		return fmt.Sprintf("if r.null() {\n%s = nil\n} else {\nif %s == nil {\n%s = new(%s)\n}\n%s}\n",
+			target, target, target, typeName(t.Elem()), g.value("(*"+target+")", t.Elem(), depth))
+	case reflect.Slice:
+		e := fmt.Sprintf("e%d", depth)
+		// Like encoding/json, decode an empty array as an empty slice
+		// rather than nil.
+		return fmt.Sprintf("if r.null() {\n%s = nil\n} else if r.beginArray() {\nif %s == nil {\n%s = %s{}\n}\n%s = %s[:0]\nfor r.nextElem() {\nvar %s %s\n%s%s = append(%s, %s)\n}\n}\n",
+			target, target, target, typeName(t), target, target, e, typeName(t.Elem()), g.value(e, t.Elem(), depth+1), target, target, e)
+	case reflect.Map:
+		e := fmt.Sprintf("e%d", depth)
+		k := fmt.Sprintf("k%d", depth)
+		return fmt.Sprintf("if r.null() {\n%s = nil\n} else if r.beginObject() {\nif %s == nil {\n%s = make(%s)\n}\nfor r.nextKey() {\n%s := string(r.key)\nvar %s %s\n%s%s[%s] = %s\n}\n}\n",
+			target, target, target, typeName(t), k, e, typeName(t.Elem()), g.value(e, t.Elem(), depth+1), target, k, e)
+	}
+	return fmt.Sprintf("r.unmarshal(&%s)\n", target)
+}
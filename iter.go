@@ -0,0 +1,212 @@
+package spotify
+
+import "golang.org/x/net/context"
+
+// pager follows the next links of a paged endpoint for an iterator.  Its
+// fetch function gets the page at a URL, and returns the number of items
+// on it and the URL of the next page, which is empty on the last page.
+type pager struct {
+	fetch func(url string) (n int, next string, err error)
+	next  string
+	// i is the index of the current item on the page of n items.
+	i, n int
+	err  error
+}
+
+// advance moves to the next item, fetching pages as needed, and reports
+// whether there is one.
+func (p *pager) advance() bool {
+	p.i++
+	for p.i >= p.n {
+		if p.err != nil || p.next == "" {
+			return false
+		}
+		p.i = 0
+		p.n, p.next, p.err = p.fetch(p.next)
+		if p.err != nil {
+			p.n = 0
+			return false
+		}
+	}
+	return true
+}
+
+// TopTracksIterator iterates over the user's top tracks, fetching pages
+// as they're needed.  Use it like this:
+//
+//    it := client.TopTracksIter(opt)
+//    for it.Next() {
+//        track := it.Item()
+//        ...
+//    }
+//    if err := it.Err(); err != nil {
+//        ...
+//    }
+type TopTracksIterator struct {
+	pager
+	page TopTracks
+}
+
+// TopTracksIter returns an iterator over the user's top tracks.  opt is
+// as for CurrentUserTopTracksWithContext, with opt.Limit setting the page
+// size and opt.Offset the position to start at.
+func (c *Client) TopTracksIter(opt *Options) *TopTracksIterator {
+	return c.TopTracksIterWithContext(context.Background(), opt)
+}
+
+// TopTracksIterWithContext is like TopTracksIter, with a context.
+func (c *Client) TopTracksIterWithContext(ctx context.Context, opt *Options) *TopTracksIterator {
+	it := new(TopTracksIterator)
+	it.i = -1
+	it.next = c.topEndpoint("tracks", opt).String()
+	it.fetch = func(url string) (int, string, error) {
+		it.page = TopTracks{}
+		err := c.get(ctx, url, &it.page)
+		return len(it.page.Items), it.page.Next, err
+	}
+	return it
+}
+
+// Next advances to the next track, and reports whether there is one.  It
+// returns false at the end of the tracks, or if getting a page fails.
+func (it *TopTracksIterator) Next() bool { return it.advance() }
+
+// Item returns the current track.
+func (it *TopTracksIterator) Item() TrackItem { return it.page.Items[it.i] }
+
+// Total returns the total number of tracks, once Next has been called.
+func (it *TopTracksIterator) Total() int { return it.page.Total }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TopTracksIterator) Err() error { return it.err }
+
+// TopArtistsIterator iterates over the user's top artists, fetching pages
+// as they're needed.  It is used like TopTracksIterator.
+type TopArtistsIterator struct {
+	pager
+	page TopArtists
+}
+
+// TopArtistsIter returns an iterator over the user's top artists.  opt is
+// as for TopTracksIter.
+func (c *Client) TopArtistsIter(opt *Options) *TopArtistsIterator {
+	return c.TopArtistsIterWithContext(context.Background(), opt)
+}
+
+// TopArtistsIterWithContext is like TopArtistsIter, with a context.
+func (c *Client) TopArtistsIterWithContext(ctx context.Context, opt *Options) *TopArtistsIterator {
+	it := new(TopArtistsIterator)
+	it.i = -1
+	it.next = c.topEndpoint("artists", opt).String()
+	it.fetch = func(url string) (int, string, error) {
+		it.page = TopArtists{}
+		err := c.get(ctx, url, &it.page)
+		return len(it.page.Items), it.page.Next, err
+	}
+	return it
+}
+
+// Next advances to the next artist, and reports whether there is one.  It
+// returns false at the end of the artists, or if getting a page fails.
+func (it *TopArtistsIterator) Next() bool { return it.advance() }
+
+// Item returns the current artist.
+func (it *TopArtistsIterator) Item() ArtistItem { return it.page.Items[it.i] }
+
+// Total returns the total number of artists, once Next has been called.
+func (it *TopArtistsIterator) Total() int { return it.page.Total }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TopArtistsIterator) Err() error { return it.err }
+
+// SavedTracksIterator iterates over the tracks saved in the user's "Your
+// Music" library, most recently saved first, fetching pages as they're
+// needed.  It is used like TopTracksIterator.
+type SavedTracksIterator struct {
+	pager
+	page SavedTrackPage
+}
+
+// SavedTracksIter returns an iterator over the user's saved tracks.  opt
+// is as for CurrentUsersTracksOpt, with opt.Limit (up to 50) setting the
+// page size and opt.Offset the position to start at.
+func (c *Client) SavedTracksIter(opt *Options) *SavedTracksIterator {
+	return c.SavedTracksIterWithContext(context.Background(), opt)
+}
+
+// SavedTracksIterWithContext is like SavedTracksIter, with a context.
+func (c *Client) SavedTracksIterWithContext(ctx context.Context, opt *Options) *SavedTracksIterator {
+	it := new(SavedTracksIterator)
+	it.i = -1
+	e, err := c.savedTracksEndpoint(opt)
+	if err != nil {
+		it.err = err
+		return it
+	}
+	it.next = e.String()
+	it.fetch = func(url string) (int, string, error) {
+		it.page = SavedTrackPage{}
+		err := c.get(ctx, url, &it.page)
+		return len(it.page.Tracks), it.page.Next, err
+	}
+	return it
+}
+
+// Next advances to the next track, and reports whether there is one.  It
+// returns false at the end of the tracks, or if getting a page fails.
+func (it *SavedTracksIterator) Next() bool { return it.advance() }
+
+// Item returns the current track.
+func (it *SavedTracksIterator) Item() SavedTrack { return it.page.Tracks[it.i] }
+
+// Total returns the total number of tracks, once Next has been called.
+func (it *SavedTracksIterator) Total() int { return it.page.Total }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SavedTracksIterator) Err() error { return it.err }
+
+// FollowedArtistsIterator iterates over the artists the user follows,
+// fetching pages as they're needed.  It is used like TopTracksIterator.
+type FollowedArtistsIterator struct {
+	pager
+	page *FullArtistCursorPage
+}
+
+// FollowedArtistsIter returns an iterator over the artists the user
+// follows.  limit is the page size, as for CurrentUsersFollowedArtistsOpt
+// (-1 for the default).  The pages are fetched by cursor, so artists
+// followed during the iteration don't shift the rest.
+func (c *Client) FollowedArtistsIter(limit int) *FollowedArtistsIterator {
+	return c.FollowedArtistsIterWithContext(context.Background(), limit)
+}
+
+// FollowedArtistsIterWithContext is like FollowedArtistsIter, with a
+// context.
+func (c *Client) FollowedArtistsIterWithContext(ctx context.Context, limit int) *FollowedArtistsIterator {
+	it := &FollowedArtistsIterator{page: new(FullArtistCursorPage)}
+	it.i = -1
+	it.next = c.followedArtistsEndpoint(limit, "").String()
+	it.fetch = func(url string) (int, string, error) {
+		page, err := c.followedArtists(ctx, url)
+		if err != nil {
+			return 0, "", err
+		}
+
+		it.page = page
+		return len(page.Artists), page.Next, nil
+	}
+	return it
+}
+
+// Next advances to the next artist, and reports whether there is one.  It
+// returns false at the end of the artists, or if getting a page fails.
+func (it *FollowedArtistsIterator) Next() bool { return it.advance() }
+
+// Item returns the current artist.
+func (it *FollowedArtistsIterator) Item() FullArtist { return it.page.Artists[it.i] }
+
+// Total returns the total number of artists, once Next has been called.
+func (it *FollowedArtistsIterator) Total() int { return it.page.Total }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *FollowedArtistsIterator) Err() error { return it.err }
@@ -0,0 +1,82 @@
+package spotify
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTopTracksIter(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"items": [{"name": "Speak"}, {"name": "Roam"}], "total": 3, "next": "https://api.spotify.com/v1/me/top/tracks?offset=2&limit=2"}`,
+		"2": `{"items": [{"name": "Holocene"}], "total": 3, "next": null}`,
+	}
+	var requests int
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		offset := req.URL.Query().Get("offset")
+		if offset == "" {
+			offset = "0"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(pages[offset])),
+		}, nil
+	})})
+
+	limit := 2
+	it := c.TopTracksIter(&Options{Limit: &limit})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "Speak,Roam,Holocene" || it.Total() != 3 {
+		t.Errorf("Got %v of %d tracks\n", names, it.Total())
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d\n", requests)
+	}
+	if it.Next() {
+		t.Error("Next returned true after the last track")
+	}
+}
+
+func TestTopArtistsIterError(t *testing.T) {
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection reset")
+	})})
+	it := c.TopArtistsIter(nil)
+	if it.Next() {
+		t.Error("Next returned true after an error")
+	}
+	if it.Err() == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestTopArtistsIterEmpty(t *testing.T) {
+	c := testClientString(http.StatusOK, `{"items": [], "total": 0}`)
+	it := c.TopArtistsIter(nil)
+	if it.Next() || it.Err() != nil {
+		t.Errorf("Expected no artists and no error, got %v\n", it.Err())
+	}
+}
+
+func TestSavedTracksIter(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"items": [{"added_at": "2016-10-24T15:03:07Z", "track": {"name": "Speak"}}], "total": 1, "next": null}`)
+	it := client.SavedTracksIter(nil)
+	if !it.Next() || it.Item().Name != "Speak" || it.Item().AddedAt.Year() != 2016 {
+		t.Fatalf("Got %v\n", it.Err())
+	}
+	if it.Next() || it.Err() != nil || it.Total() != 1 {
+		t.Errorf("Expected the end of the tracks, got %v\n", it.Err())
+	}
+	if p := getLastRequest(client).URL.Path; p != "/v1/me/tracks" {
+		t.Errorf("Got path %s\n", p)
+	}
+}
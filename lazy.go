@@ -0,0 +1,139 @@
+package spotify
+
+import (
+	"encoding/json"
+
+	"golang.org/x/net/context"
+)
+
+// This file contains variants of models with very large arrays, which
+// leave the arrays undecoded until they're needed.  When only the other
+// fields are used (say, a track's tempo and key from its analysis), this
+// saves the time and memory it would take to decode thousands of objects.
+// Each accessor decodes the array again, so callers that need an array
+// more than once should keep the result.
+
+// LazyAudioAnalysis is an AudioAnalysis whose bars, beats, sections,
+// segments and tatums are only decoded when their accessors are called.
+type LazyAudioAnalysis struct {
+	Meta      Meta      `json:"meta"`
+	TrackInfo TrackInfo `json:"track"`
+
+	RawBars     json.RawMessage `json:"bars"`
+	RawBeats    json.RawMessage `json:"beats"`
+	RawSections json.RawMessage `json:"sections"`
+	RawSegments json.RawMessage `json:"segments"`
+	RawTatums   json.RawMessage `json:"tatums"`
+}
+
+// decodeRaw decodes raw into v, treating a missing array as empty.
+func decodeRaw(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Bars decodes the analysis's bars.
+func (a *LazyAudioAnalysis) Bars() ([]BeatBar, error) {
+	var bars []BeatBar
+	err := decodeRaw(a.RawBars, &bars)
+	return bars, err
+}
+
+// Beats decodes the analysis's beats.
+func (a *LazyAudioAnalysis) Beats() ([]BeatBar, error) {
+	var beats []BeatBar
+	err := decodeRaw(a.RawBeats, &beats)
+	return beats, err
+}
+
+// Sections decodes the analysis's sections.
+func (a *LazyAudioAnalysis) Sections() ([]Section, error) {
+	var sections []Section
+	err := decodeRaw(a.RawSections, &sections)
+	return sections, err
+}
+
+// Segments decodes the analysis's segments, which is usually by far the
+// largest array.
+func (a *LazyAudioAnalysis) Segments() ([]Segment, error) {
+	var segments []Segment
+	err := decodeRaw(a.RawSegments, &segments)
+	return segments, err
+}
+
+// Tatums decodes the analysis's tatums.
+func (a *LazyAudioAnalysis) Tatums() ([]Tatum, error) {
+	var tatums []Tatum
+	err := decodeRaw(a.RawTatums, &tatums)
+	return tatums, err
+}
+
+// Full decodes all of the arrays, returning the equivalent AudioAnalysis.
+func (a *LazyAudioAnalysis) Full() (*AudioAnalysis, error) {
+	full := &AudioAnalysis{Meta: a.Meta, TrackInfo: a.TrackInfo}
+	for _, f := range []struct {
+		raw json.RawMessage
+		v   interface{}
+	}{
+		{a.RawBars, &full.Bars},
+		{a.RawBeats, &full.Beats},
+		{a.RawSections, &full.Sections},
+		{a.RawSegments, &full.Segments},
+		{a.RawTatums, &full.Tatums},
+	} {
+		if err := decodeRaw(f.raw, f.v); err != nil {
+			return nil, err
+		}
+	}
+	return full, nil
+}
+
+// GetAudioAnalysisLazy is like GetAudioAnalysis, but leaves the arrays of
+// the analysis undecoded until they are needed.
+func (c *Client) GetAudioAnalysisLazy(id ID) (*LazyAudioAnalysis, error) {
+	return c.GetAudioAnalysisLazyWithContext(context.Background(), id)
+}
+
+// GetAudioAnalysisLazyWithContext is like GetAudioAnalysisLazy, with a
+// context.
+func (c *Client) GetAudioAnalysisLazyWithContext(ctx context.Context, id ID) (*LazyAudioAnalysis, error) {
+	var a LazyAudioAnalysis
+	if err := c.getAudioAnalysis(ctx, id, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// LazyPlaylistTrackPage is a PlaylistTrackPage whose items are only
+// decoded when Tracks is called.
+type LazyPlaylistTrackPage struct {
+	basePage
+	RawTracks json.RawMessage `json:"items"`
+}
+
+// Tracks decodes the page's items.
+func (p *LazyPlaylistTrackPage) Tracks() ([]PlaylistTrack, error) {
+	var tracks []PlaylistTrack
+	err := decodeRaw(p.RawTracks, &tracks)
+	return tracks, err
+}
+
+// GetPlaylistTracksLazy is like GetPlaylistTracksOpt, but leaves the items
+// on the page undecoded until they are needed.  It's useful for reading
+// just the total number of tracks, or for skipping pages that haven't
+// changed.
+func (c *Client) GetPlaylistTracksLazy(userID string, playlistID ID, opt *Options, fields string) (*LazyPlaylistTrackPage, error) {
+	return c.GetPlaylistTracksLazyWithContext(context.Background(), userID, playlistID, opt, fields)
+}
+
+// GetPlaylistTracksLazyWithContext is like GetPlaylistTracksLazy, with a
+// context.
+func (c *Client) GetPlaylistTracksLazyWithContext(ctx context.Context, userID string, playlistID ID, opt *Options, fields string) (*LazyPlaylistTrackPage, error) {
+	var result LazyPlaylistTrackPage
+	if err := c.getPlaylistTracks(ctx, userID, playlistID, opt, fields, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
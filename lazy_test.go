@@ -0,0 +1,80 @@
+package spotify
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetAudioAnalysisLazy(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/audio_analysis.json")
+	want, err := c.GetAudioAnalysis("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = testClientFile(http.StatusOK, "test_data/golden/audio_analysis.json")
+	lazy, err := c.GetAudioAnalysisLazy("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lazy.TrackInfo, want.TrackInfo) {
+		t.Errorf("got track info %+v, want %+v", lazy.TrackInfo, want.TrackInfo)
+	}
+	segments, err := lazy.Segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(segments, want.Segments) {
+		t.Error("lazily decoded segments don't match")
+	}
+	full, err := lazy.Full()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(full, want) {
+		t.Error("lazily decoded analysis doesn't match")
+	}
+}
+
+func TestGetPlaylistTracksLazy(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/playlist_track_page.json")
+	want, err := c.GetPlaylistTracks("user", "playlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = testClientFile(http.StatusOK, "test_data/golden/playlist_track_page.json")
+	lazy, err := c.GetPlaylistTracksLazy("user", "playlist", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lazy.Total != want.Total {
+		t.Errorf("got total %d, want %d", lazy.Total, want.Total)
+	}
+	tracks, err := lazy.Tracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tracks, want.Tracks) {
+		t.Error("lazily decoded tracks don't match")
+	}
+}
+
+func BenchmarkDecodeAudioAnalysis(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "audio_analysis.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioAnalysis("foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeAudioAnalysisLazy(b *testing.B) {
+	c := benchmarkClient(readGolden(b, "audio_analysis.json"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetAudioAnalysisLazy("foo"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,69 +1,122 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+
+	"golang.org/x/net/context"
 )
 
+// maxLibraryIDs is the most IDs the library endpoints take per request.
+// The library methods split longer lists into several requests.
+const maxLibraryIDs = 50
+
 // UserHasTracks checks if one or more tracks are saved to the current user's
-// "Your Music" library.  This call requires authorization.
+// "Your Music" library.  This call requires authorization.  It accepts any
+// number of IDs, making one request for every 50 tracks, several at a time
+// (see WithConcurrency).
 func (c *Client) UserHasTracks(ids ...ID) ([]bool, error) {
-	if l := len(ids); l == 0 || l > 50 {
-		return nil, errors.New("spotify: UserHasTracks supports 1 to 50 IDs per call")
+	return c.UserHasTracksWithContext(context.Background(), ids...)
+}
+
+// UserHasTracksWithContext is like UserHasTracks, with a context.
+func (c *Client) UserHasTracksWithContext(ctx context.Context, ids ...ID) ([]bool, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("spotify: UserHasTracks requires at least one ID")
 	}
-	spotifyURL := fmt.Sprintf("%sme/tracks/contains?ids=%s", baseAddress, strings.Join(toStringSlice(ids), ","))
-	resp, err := c.http.Get(spotifyURL)
+	result := make([]bool, len(ids))
+	err := c.batch(ctx, ids, maxLibraryIDs, func(ctx context.Context, chunk []ID, start int) error {
+		contains, err := c.userHasTracks(ctx, chunk)
+		copy(result[start:], contains)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return result, nil
+}
+
+func (c *Client) userHasTracks(ctx context.Context, ids []ID) ([]bool, error) {
+	e := c.endpoint("me/tracks/contains")
+	e.setIDs(ids)
 	var result []bool
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err := c.get(ctx, e.String(), &result)
 	return result, err
 }
 
 // AddTracksToLibrary saves one or more tracks to the current user's
 // "Your Music" library.  This call requires authorization (the
 // ScopeUserLibraryModify scope).
-// A track can only be saved once; duplicate IDs are ignored.
+// A track can only be saved once; duplicate IDs are ignored.  It accepts
+// any number of IDs, making one request for every 50 tracks; if one of the
+// requests fails, the tracks of the others may still have been saved.
 func (c *Client) AddTracksToLibrary(ids ...ID) error {
-	return c.modifyLibraryTracks(true, ids...)
+	return c.AddTracksToLibraryWithContext(context.Background(), ids...)
+}
+
+// AddTracksToLibraryWithContext is like AddTracksToLibrary, with a context.
+func (c *Client) AddTracksToLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/tracks", true, ids)
 }
 
 // RemoveTracksFromLibrary removes one or more tracks from the current user's
 // "Your Music" library.  This call requires authorization (the ScopeUserModifyLibrary
 // scope).  Trying to remove a track when you do not have the user's authorization
-// results in a `spotify.Error` with the status code set to http.StatusUnauthorized.
+// results in a `*spotify.Error` with the status code set to http.StatusUnauthorized.
+// Like AddTracksToLibrary, it accepts any number of IDs.
 func (c *Client) RemoveTracksFromLibrary(ids ...ID) error {
-	return c.modifyLibraryTracks(false, ids...)
+	return c.RemoveTracksFromLibraryWithContext(context.Background(), ids...)
+}
+
+// RemoveTracksFromLibraryWithContext is like RemoveTracksFromLibrary, with a
+// context.
+func (c *Client) RemoveTracksFromLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/tracks", false, ids)
 }
 
-func (c *Client) modifyLibraryTracks(add bool, ids ...ID) error {
-	if l := len(ids); l == 0 || l > 50 {
-		return errors.New("spotify: this call supports 1 to 50 IDs per call")
+// AddAlbumsToLibrary saves one or more albums to the current user's
+// "Your Music" library.  This call requires authorization (the
+// ScopeUserLibraryModify scope).  Like AddTracksToLibrary, it accepts any
+// number of IDs.
+func (c *Client) AddAlbumsToLibrary(ids ...ID) error {
+	return c.AddAlbumsToLibraryWithContext(context.Background(), ids...)
+}
+
+// AddAlbumsToLibraryWithContext is like AddAlbumsToLibrary, with a context.
+func (c *Client) AddAlbumsToLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/albums", true, ids)
+}
+
+// RemoveAlbumsFromLibrary removes one or more albums from the current user's
+// "Your Music" library.  This call requires authorization (the
+// ScopeUserLibraryModify scope).  Like AddTracksToLibrary, it accepts any
+// number of IDs.
+func (c *Client) RemoveAlbumsFromLibrary(ids ...ID) error {
+	return c.RemoveAlbumsFromLibraryWithContext(context.Background(), ids...)
+}
+
+// RemoveAlbumsFromLibraryWithContext is like RemoveAlbumsFromLibrary, with a
+// context.
+func (c *Client) RemoveAlbumsFromLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/albums", false, ids)
+}
+
+// modifyLibrary saves (if add is true) or removes the items with the IDs at
+// the library endpoint path, 50 at a time.
+func (c *Client) modifyLibrary(ctx context.Context, path string, add bool, ids []ID) error {
+	if len(ids) == 0 {
+		return errors.New("spotify: this call requires at least one ID")
 	}
-	spotifyURL := fmt.Sprintf("%sme/tracks?ids=%s", baseAddress, strings.Join(toStringSlice(ids), ","))
+	return c.batch(ctx, ids, maxLibraryIDs, func(ctx context.Context, chunk []ID, start int) error {
+		return c.modifyLibraryChunk(ctx, path, add, chunk)
+	})
+}
+
+func (c *Client) modifyLibraryChunk(ctx context.Context, path string, add bool, ids []ID) error {
+	e := c.endpoint(path)
+	e.setIDs(ids)
 	method := "DELETE"
 	if add {
 		method = "PUT"
 	}
-	req, err := http.NewRequest(method, spotifyURL, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return decodeError(resp.Body)
-	}
-	return nil
+	return c.send(ctx, method, e.String(), nil)
 }
@@ -1,7 +1,12 @@
 package spotify
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -52,3 +57,80 @@ func TestRemoveTracksFromLibrary(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestAddAlbumsToLibrary(t *testing.T) {
+	client := testClientString(http.StatusOK, "")
+	addDummyAuth(client)
+	err := client.AddAlbumsToLibrary("6akEvsycLGftJxYudPjmqK")
+	if err != nil {
+		t.Error(err)
+	}
+	req := getLastRequest(client)
+	if req.Method != "PUT" || req.URL.Path != "/v1/me/albums" || req.URL.Query().Get("ids") != "6akEvsycLGftJxYudPjmqK" {
+		t.Error("Unexpected request", req.Method, req.URL)
+	}
+}
+
+func TestRemoveAlbumsFromLibrary(t *testing.T) {
+	client := testClientString(http.StatusOK, "")
+	addDummyAuth(client)
+	err := client.RemoveAlbumsFromLibrary("6akEvsycLGftJxYudPjmqK")
+	if err != nil {
+		t.Error(err)
+	}
+	if req := getLastRequest(client); req.Method != "DELETE" {
+		t.Error("Expected DELETE, got", req.Method)
+	}
+}
+
+func TestLibraryChunks(t *testing.T) {
+	var mu sync.Mutex
+	var puts int
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		if len(ids) > 50 {
+			t.Errorf("Got %d IDs in one request\n", len(ids))
+		}
+		body := ""
+		switch req.Method {
+		case "PUT":
+			mu.Lock()
+			puts++
+			mu.Unlock()
+		case "GET":
+			// A track is saved if its ID is even.
+			contains := make([]string, len(ids))
+			for i, id := range ids {
+				n, _ := strconv.Atoi(id)
+				contains[i] = strconv.FormatBool(n%2 == 0)
+			}
+			body = "[" + strings.Join(contains, ",") + "]"
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+	ids := make([]ID, 120)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("%022d", i+1))
+	}
+	if err := c.AddTracksToLibrary(ids...); err != nil {
+		t.Fatal(err)
+	}
+	if puts != 3 {
+		t.Errorf("Expected 3 requests, got %d\n", puts)
+	}
+	contains, err := c.UserHasTracks(ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contains) != len(ids) {
+		t.Fatalf("Got %d results for %d tracks\n", len(contains), len(ids))
+	}
+	for i, saved := range contains {
+		if saved != ((i+1)%2 == 0) {
+			t.Errorf("Got %v for track %d\n", saved, i+1)
+		}
+	}
+	if err := c.RemoveAlbumsFromLibrary(); err == nil {
+		t.Error("Expected an error for no IDs")
+	}
+}
@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithLocale makes the client ask for localized responses, by sending an
+// Accept-Language header with every request.  Category names, show and
+// episode descriptions and other localizable fields then come back in the
+// given language, where Spotify has a translation.  The locale is an ISO
+// 639 language code, optionally followed by an ISO 3166-1 alpha-2 country
+// code, such as "es" or "es_MX" (the form of the locale parameter of
+// GetCategoriesOpt; "es-MX" works too).  Passing several locales in order
+// of preference, such as "es_MX,es;q=0.8", is also allowed.
+//
+// WithLocale should come after WithCache and WithCoalescing, so that they
+// see the header and keep the responses for each language apart.
+func WithLocale(locale string) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &localeTransport{
+			base:     hc.Transport,
+			language: strings.Replace(locale, "_", "-", -1),
+		}
+		c.http = &hc
+	}
+}
+
+// localeTransport sets the Accept-Language header of requests that don't
+// already have one.
+type localeTransport struct {
+	base     http.RoundTripper
+	language string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *localeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.language == "" || req.Header.Get("Accept-Language") != "" {
+		return base.RoundTrip(req)
+	}
+	// A RoundTripper mustn't modify the request, so send a copy.
+	r := new(http.Request)
+	*r = *req
+	r.Header = cloneHeader(req.Header)
+	r.Header.Set("Accept-Language", t.language)
+	return base.RoundTrip(r)
+}
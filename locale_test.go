@@ -0,0 +1,38 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLocale(t *testing.T) {
+	var languages []string
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		languages = append(languages, req.Header.Get("Accept-Language"))
+		body := `{"audio_features": [{"id": "6rqhFgbbKwnb9MLmUQDhG6", "tempo": 118.211}]}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+	cache := NewMemoryCache(&stoppedClock{t: time.Now()})
+	hc := &http.Client{Transport: tr}
+	clients := []Client{
+		NewClient(hc, WithCache(cache), WithLocale("es_MX")),
+		NewClient(hc, WithCache(cache), WithLocale("es_MX")),
+		NewClient(hc, WithCache(cache), WithLocale("fr")),
+		NewClient(hc, WithCache(cache)),
+	}
+	for _, c := range clients {
+		if _, err := c.GetAudioFeatures("6rqhFgbbKwnb9MLmUQDhG6"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"es-MX", "fr", ""}
+	if strings.Join(languages, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests for languages %q, got %q\n", want, languages)
+	}
+}
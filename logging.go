@@ -0,0 +1,151 @@
+package spotify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoggingTransport is an http.RoundTripper that writes a structured log
+// entry for every request, in the JSON format understood by Google Cloud
+// Logging.  On App Engine, lines written to stdout are ingested
+// automatically, and entries carrying the trace of the incoming request are
+// grouped with that request's log.
+type LoggingTransport struct {
+	// Base is used to send the requests.  If nil, http.DefaultTransport is
+	// used.
+	Base http.RoundTripper
+	// Out receives one JSON object per line.  If nil, os.Stdout is used.
+	Out io.Writer
+	// Trace is the trace of the App Engine request being served, in the
+	// form "projects/PROJECT_ID/traces/TRACE_ID" (see TraceFromRequest).
+	Trace string
+	// User identifies the user, without revealing who they are (see
+	// HashUserID).
+	User string
+	// Clock is used to measure latency.  If nil, SystemClock is used.
+	Clock Clock
+
+	mu sync.Mutex
+}
+
+// WithLogging makes the client log each request to out with a
+// LoggingTransport.  The user ID is hashed before being logged.
+func WithLogging(out io.Writer, trace, userID string) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &LoggingTransport{
+			Base:  hc.Transport,
+			Out:   out,
+			Trace: trace,
+			User:  HashUserID(userID),
+			Clock: clientClock{c},
+		}
+		c.http = &hc
+	}
+}
+
+// TraceFromRequest returns the Cloud Logging trace for an incoming App
+// Engine request, from its X-Cloud-Trace-Context header, or the empty string
+// if it has none.
+func TraceFromRequest(r *http.Request, projectID string) string {
+	h := r.Header.Get("X-Cloud-Trace-Context")
+	if h == "" {
+		return ""
+	}
+	traceID := strings.SplitN(h, "/", 2)[0]
+	return "projects/" + projectID + "/traces/" + traceID
+}
+
+// HashUserID returns an opaque identifier for a user, suitable for logs.
+// The same ID always hashes to the same value.
+func HashUserID(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:8])
+}
+
+type logEntry struct {
+	Severity    string         `json:"severity"`
+	Message     string         `json:"message"`
+	Trace       string         `json:"logging.googleapis.com/trace,omitempty"`
+	HTTPRequest logHTTPRequest `json:"httpRequest"`
+	Endpoint    string         `json:"endpoint"`
+	LatencyMS   int64          `json:"latency_ms"`
+	User        string         `json:"user,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+type logHTTPRequest struct {
+	RequestMethod string `json:"requestMethod"`
+	RequestURL    string `json:"requestUrl"`
+	Status        int    `json:"status,omitempty"`
+	Latency       string `json:"latency"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clock := t.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	start := clock.Now()
+	resp, err := base.RoundTrip(req)
+	latency := clock.Now().Sub(start)
+
+	e := logEntry{
+		Severity: "INFO",
+		Trace:    t.Trace,
+		HTTPRequest: logHTTPRequest{
+			RequestMethod: req.Method,
+			RequestURL:    req.URL.String(),
+			Latency:       fmt.Sprintf("%.3fs", latency.Seconds()),
+		},
+		Endpoint:  req.URL.Path,
+		LatencyMS: int64(latency / time.Millisecond),
+		User:      t.User,
+	}
+	switch {
+	case err != nil:
+		e.Severity = "ERROR"
+		e.Error = err.Error()
+		e.Message = fmt.Sprintf("spotify: %s %s failed: %v", req.Method, req.URL.Path, err)
+	default:
+		e.HTTPRequest.Status = resp.StatusCode
+		if resp.StatusCode >= 500 {
+			e.Severity = "ERROR"
+		} else if resp.StatusCode >= 400 {
+			e.Severity = "WARNING"
+		}
+		e.Message = fmt.Sprintf("spotify: %s %s %d (%v)", req.Method, req.URL.Path, resp.StatusCode, latency)
+	}
+	t.write(e)
+	return resp, err
+}
+
+func (t *LoggingTransport) write(e logEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	out := t.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out.Write(append(b, '\n'))
+}
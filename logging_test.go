@@ -0,0 +1,50 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	var buf bytes.Buffer
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"status": 404, "message": "non existing id"}}`)),
+		}, nil
+	})
+	c := NewClient(&http.Client{Transport: tr}, WithLogging(&buf, "projects/p/traces/abc", "wizzler"))
+	c.GetAlbum("asdf")
+
+	var e map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e["severity"] != "WARNING" || e["endpoint"] != "/v1/albums/asdf" {
+		t.Errorf("Unexpected entry %v\n", e)
+	}
+	if e["logging.googleapis.com/trace"] != "projects/p/traces/abc" {
+		t.Error("Trace missing from", e)
+	}
+	if e["user"] != HashUserID("wizzler") || strings.Contains(buf.String(), "wizzler") {
+		t.Error("User ID wasn't hashed:", e["user"])
+	}
+	if req, ok := e["httpRequest"].(map[string]interface{}); !ok || req["status"] != 404.0 {
+		t.Error("Unexpected httpRequest", e["httpRequest"])
+	}
+}
+
+func TestTraceFromRequest(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	if tr := TraceFromRequest(r, "proj"); tr != "" {
+		t.Error("Expected no trace, got", tr)
+	}
+	r.Header.Set("X-Cloud-Trace-Context", "105445aa7843bc8bf206b120001000/1;o=1")
+	if tr := TraceFromRequest(r, "proj"); tr != "projects/proj/traces/105445aa7843bc8bf206b120001000" {
+		t.Error("Unexpected trace", tr)
+	}
+}
@@ -0,0 +1,70 @@
+package spotify
+
+import "math"
+
+// ReferenceLoudness is the playback loudness, in dB, that Spotify normalizes
+// tracks to by default.  It can be passed to NormalizationGain to volume-match
+// tracks the same way the Spotify clients do.
+const ReferenceLoudness = -14.0
+
+// IntegratedLoudness returns the overall loudness of the analyzed track in dB.
+// The track level loudness reported by Spotify is preferred.  If it is missing,
+// the loudness is computed from the segment loudness curve as a power average
+// weighted by segment duration.
+func (a *AudioAnalysis) IntegratedLoudness() float64 {
+	if a.TrackInfo.Loudness != 0 {
+		return a.TrackInfo.Loudness
+	}
+	return segmentLoudness(a.Segments)
+}
+
+// PeakLoudness returns the loudest point of the track in dB, taken from the
+// maximum loudness of each segment.  It returns zero if the analysis contains
+// no segments.
+func (a *AudioAnalysis) PeakLoudness() float64 {
+	if len(a.Segments) == 0 {
+		return 0
+	}
+	peak := math.Inf(-1)
+	for _, s := range a.Segments {
+		if s.LoudnessMax > peak {
+			peak = s.LoudnessMax
+		}
+	}
+	return peak
+}
+
+// NormalizationGain returns the adjustment, in dB, that should be applied
+// to the track so that it plays back at the target loudness (for example,
+// ReferenceLoudness).  A positive gain makes the track louder.  Positive gain
+// is limited so that the loudest segment of the track doesn't exceed 0 dB,
+// which would cause clipping.
+func (a *AudioAnalysis) NormalizationGain(target float64) float64 {
+	gain := target - a.IntegratedLoudness()
+	if gain > 0 && len(a.Segments) > 0 {
+		if headroom := -a.PeakLoudness(); gain > headroom {
+			gain = math.Max(headroom, 0)
+		}
+	}
+	return gain
+}
+
+// GainToAmplitude converts a gain in dB to a linear amplitude multiplier
+// suitable for use as a player's volume scale factor.
+func GainToAmplitude(gain float64) float64 {
+	return math.Pow(10, gain/20)
+}
+
+// segmentLoudness computes the duration weighted power average of the
+// maximum loudness of each segment.
+func segmentLoudness(segments []Segment) float64 {
+	var power, duration float64
+	for _, s := range segments {
+		power += s.Duration * math.Pow(10, s.LoudnessMax/10)
+		duration += s.Duration
+	}
+	if duration == 0 || power == 0 {
+		return 0
+	}
+	return 10 * math.Log10(power/duration)
+}
@@ -0,0 +1,43 @@
+package spotify
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizationGain(t *testing.T) {
+	a := AudioAnalysis{
+		TrackInfo: TrackInfo{Loudness: -8},
+		Segments: []Segment{
+			{Duration: 1, LoudnessMax: -6},
+			{Duration: 1, LoudnessMax: -2},
+		},
+	}
+	if g := a.NormalizationGain(ReferenceLoudness); g != -6 {
+		t.Errorf("Wanted gain of -6 dB, got %v\n", g)
+	}
+
+	// a quiet track shouldn't be boosted past 0 dB
+	a.TrackInfo.Loudness = -20
+	if g := a.NormalizationGain(ReferenceLoudness); g != 2 {
+		t.Errorf("Wanted gain limited to 2 dB, got %v\n", g)
+	}
+}
+
+func TestIntegratedLoudnessFromSegments(t *testing.T) {
+	a := AudioAnalysis{
+		Segments: []Segment{
+			{Duration: 2, LoudnessMax: -10},
+			{Duration: 2, LoudnessMax: -10},
+		},
+	}
+	if l := a.IntegratedLoudness(); math.Abs(l+10) > 1e-9 {
+		t.Errorf("Wanted loudness of -10 dB, got %v\n", l)
+	}
+}
+
+func TestGainToAmplitude(t *testing.T) {
+	if amp := GainToAmplitude(-20); math.Abs(amp-0.1) > 1e-9 {
+		t.Errorf("Wanted amplitude 0.1, got %v\n", amp)
+	}
+}
@@ -0,0 +1,109 @@
+package spotify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Market is an ISO 3166-1 alpha-2 country code identifying the market
+// that results should be relevant to, or the special value MarketFromToken.
+type Market string
+
+// isoCountries contains every assigned ISO 3166-1 alpha-2 code.
+var isoCountries = map[Market]bool{}
+
+func init() {
+	const codes = "AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+		"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+		"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+		"DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR " +
+		"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY " +
+		"HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP " +
+		"KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY " +
+		"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+		"NA NC NE NF NG NI NL NO NP NR NU NZ OM " +
+		"PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW " +
+		"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+		"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ " +
+		"VA VC VE VG VI VN VU WF WS XK YE YT ZA ZM ZW"
+	for _, c := range strings.Fields(codes) {
+		isoCountries[Market(c)] = true
+	}
+}
+
+// Valid reports whether m is an assigned ISO 3166-1 alpha-2 code (in upper
+// case) or MarketFromToken.
+func (m Market) Valid() bool {
+	return m == MarketFromToken || isoCountries[m]
+}
+
+// InvalidMarketError is returned when a market or country parameter isn't
+// a valid ISO 3166-1 alpha-2 code.
+type InvalidMarketError struct {
+	Market string
+	// Country is set if the parameter is a country, such as that of the
+	// browse endpoints, which unlike a market can't be MarketFromToken.
+	Country bool
+}
+
+func (e InvalidMarketError) Error() string {
+	if e.Country {
+		return fmt.Sprintf("spotify: invalid country %q (expected an ISO 3166-1 alpha-2 code)", e.Market)
+	}
+	return fmt.Sprintf("spotify: invalid market %q (expected an ISO 3166-1 alpha-2 code or %q)", e.Market, MarketFromToken)
+}
+
+// ParseMarket converts s to a Market, accepting country codes in either
+// case.  It returns an InvalidMarketError if s is not a valid market.
+func ParseMarket(s string) (Market, error) {
+	m := Market(s)
+	if s != MarketFromToken {
+		m = Market(strings.ToUpper(s))
+	}
+	if !m.Valid() {
+		return "", InvalidMarketError{Market: s}
+	}
+	return m, nil
+}
+
+// WithMarket sets the market used by calls whose results depend on one,
+// when the call doesn't give a country itself.  Pass MarketFromToken to
+// use the market of the authorized user's account, so that track relinking
+// gives tracks the user can actually play, without every call site
+// remembering to ask for it.  The market isn't validated here; use
+// ParseMarket for markets that come from users.
+//
+// It applies to tracks, albums (including an artist's albums, instead of
+// the usual fallback to the US), playlists and their tracks, the user's
+// saved tracks and albums, search, recommendations and the player.
+func WithMarket(market string) ClientOption {
+	return func(c *Client) {
+		c.market = market
+	}
+}
+
+// setMarket validates the optional country code and, if it is present,
+// stores it in v under the specified query parameter name ("market" or
+// "country", depending on the endpoint).
+func setMarket(v url.Values, key string, country *string) error {
+	if country == nil {
+		return nil
+	}
+	m, err := ParseMarket(*country)
+	if err != nil {
+		return err
+	}
+	v.Set(key, string(m))
+	return nil
+}
+
+// setMarketOpt sets the "market" query parameter from opt, for endpoints
+// whose results include available markets.
+func setMarketOpt(v url.Values, opt *Options) error {
+	if opt.Country == nil && opt.OmitAvailableMarkets {
+		v.Set("market", MarketFromToken)
+		return nil
+	}
+	return setMarket(v, "market", opt.Country)
+}
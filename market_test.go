@@ -0,0 +1,85 @@
+package spotify
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseMarket(t *testing.T) {
+	for in, want := range map[string]Market{
+		"US":            "US",
+		"se":            "SE",
+		MarketFromToken: MarketFromToken,
+	} {
+		m, err := ParseMarket(in)
+		if err != nil {
+			t.Errorf("%s: %v\n", in, err)
+		}
+		if m != want {
+			t.Errorf("%s: wanted %s, got %s\n", in, want, m)
+		}
+	}
+	for _, in := range []string{"", "USA", "XX", "FROM_TOKEN"} {
+		if _, err := ParseMarket(in); err == nil {
+			t.Errorf("Expected %q to be rejected\n", in)
+		}
+	}
+}
+
+func TestSearchInvalidMarket(t *testing.T) {
+	client := testClientString(http.StatusOK, "{}")
+	market := "Sweden"
+	_, err := client.SearchOpt("abba", SearchTypeArtist, &Options{Country: &market})
+	if _, ok := err.(InvalidMarketError); !ok {
+		t.Errorf("Expected InvalidMarketError, got %v\n", err)
+	}
+	if getLastRequest(client) != nil {
+		t.Error("Request shouldn't be sent for an invalid market")
+	}
+}
+
+func TestWithMarket(t *testing.T) {
+	se := "SE"
+	calls := []struct {
+		call   func(c *Client) error
+		market string
+	}{
+		{func(c *Client) error { _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); return err }, MarketFromToken},
+		{func(c *Client) error { _, err := c.GetTracksOpt(nil, "1zHlj4dQ8ZAtrayhuDDmkY"); return err }, MarketFromToken},
+		{func(c *Client) error {
+			_, err := c.GetTracksOpt(&Options{Country: &se}, "1zHlj4dQ8ZAtrayhuDDmkY")
+			return err
+		}, "SE"},
+		{func(c *Client) error {
+			_, err := c.CurrentUsersTracksOpt(&Options{OmitAvailableMarkets: true})
+			return err
+		}, MarketFromToken},
+		{func(c *Client) error {
+			_, err := c.GetArtistAlbumsOpt("1vCWHaC5f2uS3yhpwWbIA6", &Options{}, nil)
+			return err
+		}, MarketFromToken},
+		{func(c *Client) error { _, err := c.GetAudioFeatures("1zHlj4dQ8ZAtrayhuDDmkY"); return err }, ""},
+	}
+	for i, call := range calls {
+		client := testClientString(http.StatusOK, `{"tracks": [], "items": [], "audio_features": []}`)
+		WithMarket(MarketFromToken)(client)
+		if err := call.call(client); err != nil {
+			t.Fatal(err)
+		}
+		if m := getLastRequest(client).URL.Query().Get("market"); m != call.market {
+			t.Errorf("%d: Expected market %q, got %q\n", i, call.market, m)
+		}
+	}
+}
+
+func TestCountryFromToken(t *testing.T) {
+	client := testClientString(http.StatusOK, "{}")
+	country := MarketFromToken
+	_, _, err := client.FeaturedPlaylistsOpt(&PlaylistOptions{Options: Options{Country: &country}})
+	if e, ok := err.(InvalidMarketError); !ok || !e.Country {
+		t.Errorf("Expected InvalidMarketError for a country, got %v\n", err)
+	}
+	if getLastRequest(client) != nil {
+		t.Error("Request shouldn't be sent for from_token as a country")
+	}
+}
@@ -0,0 +1,186 @@
+// Package nowplaying renders what a user is listening to in a terminal: the
+// track, its artists and album, a progress bar and the device it's playing
+// on.  It is driven by the events of a watch.Watcher, and moves the progress
+// bar along between them, so the Web API only needs to be polled every few
+// seconds.
+package nowplaying
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/watch"
+)
+
+// Screen is what is shown.
+type Screen struct {
+	// Track is the track that is playing, or nil if nothing is.
+	Track   *spotify.FullTrack
+	Playing bool
+	// Progress is the position of playback within the track.
+	Progress time.Duration
+	// Device is the name of the device the track is playing on, if it is
+	// known.
+	Device string
+}
+
+// State follows the events of a watcher, to work out what to show at any
+// moment.  It is safe for concurrent use, so it can be the watcher's Sink
+// while another goroutine draws it.
+type State struct {
+	mu     sync.Mutex
+	screen Screen
+	// at is the time of the last event, when screen.Progress was exact.
+	at time.Time
+}
+
+// Send updates the state with the event.  It implements watch.Sink.
+func (s *State) Send(e watch.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Type == watch.TrackChanged || e.Type == watch.Stopped {
+		s.screen.Device = ""
+	}
+	s.screen.Track = e.Track
+	s.screen.Playing = e.Playing
+	s.screen.Progress = time.Duration(e.Progress) * time.Millisecond
+	s.at = e.Time
+	return nil
+}
+
+// SetDevice sets the name of the device that is playing.  Device names
+// aren't part of the watcher's events, so they are looked up separately,
+// such as with Client.PlayerState.
+func (s *State) SetDevice(name string) {
+	s.mu.Lock()
+	s.screen.Device = name
+	s.mu.Unlock()
+}
+
+// At returns the screen at the time now.  If the track is playing, its
+// progress is moved along by the time since the last event, up to the end
+// of the track.
+func (s *State) At(now time.Time) Screen {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc := s.screen
+	if sc.Track != nil && sc.Playing && now.After(s.at) {
+		sc.Progress += now.Sub(s.at)
+		if d := time.Duration(sc.Track.Duration) * time.Millisecond; sc.Progress > d {
+			sc.Progress = d
+		}
+	}
+	return sc
+}
+
+// ProgressBar returns a bar width characters wide, filled in proportion to
+// progress out of total.
+func ProgressBar(progress, total time.Duration, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	filled := 0
+	if total > 0 {
+		filled = int(int64(width) * int64(progress) / int64(total))
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > width {
+		filled = width
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// clock formats a duration as minutes and seconds.
+func clock(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// Lines returns the lines of text that show the screen, at most width
+// characters wide.
+func (sc Screen) Lines(width int) []string {
+	if sc.Track == nil {
+		return []string{"Nothing is playing."}
+	}
+	names := make([]string, len(sc.Track.Artists))
+	for i, a := range sc.Track.Artists {
+		names[i] = a.Name
+	}
+	state := "▶"
+	if !sc.Playing {
+		state = "⏸"
+	}
+	total := time.Duration(sc.Track.Duration) * time.Millisecond
+	times := clock(sc.Progress) + " / " + clock(total)
+	lines := []string{
+		state + " " + sc.Track.Name,
+		"  " + strings.Join(names, ", "),
+	}
+	if sc.Track.Album != nil && sc.Track.Album.Name != "" {
+		lines = append(lines, "  "+sc.Track.Album.Name)
+	}
+	// Two spaces of indent, and one between the bar and the times.
+	lines = append(lines, "  "+ProgressBar(sc.Progress, total, width-3-len(times))+" "+times)
+	if sc.Device != "" {
+		lines = append(lines, "  on "+sc.Device)
+	}
+	for i, l := range lines {
+		lines[i] = truncate(l, width)
+	}
+	return lines
+}
+
+// truncate shortens s to at most width characters.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// Renderer draws screens on a terminal, replacing the one it drew last.
+type Renderer struct {
+	W io.Writer
+	// Width is the width of the terminal.  If zero, 80 is used.
+	Width int
+	// lines is the number of lines drawn last time.
+	lines int
+}
+
+// Draw draws the screen in place of the last one, using ANSI escape
+// sequences to move the cursor back up and clear the old lines.
+func (r *Renderer) Draw(sc Screen) error {
+	width := r.Width
+	if width == 0 {
+		width = 80
+	}
+	var b bytes.Buffer
+	if r.lines > 0 {
+		fmt.Fprintf(&b, "\033[%dA", r.lines)
+	}
+	lines := sc.Lines(width)
+	for _, l := range lines {
+		b.WriteString("\033[2K" + l + "\n")
+	}
+	// Clear the lines left over from a taller screen.
+	for i := len(lines); i < r.lines; i++ {
+		b.WriteString("\033[2K\n")
+	}
+	if r.lines > len(lines) {
+		fmt.Fprintf(&b, "\033[%dA", r.lines-len(lines))
+	}
+	r.lines = len(lines)
+	_, err := io.WriteString(r.W, b.String())
+	return err
+}
@@ -0,0 +1,84 @@
+package nowplaying
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/watch"
+)
+
+var epoch = time.Date(2017, 5, 19, 9, 0, 0, 0, time.UTC)
+
+func track() *spotify.FullTrack {
+	t := new(spotify.FullTrack)
+	t.Name = "Speak"
+	t.Duration = 180000
+	t.Artists = []spotify.SimpleArtist{{Name: "Band of Horses"}}
+	return t
+}
+
+func TestState(t *testing.T) {
+	s := new(State)
+	s.Send(watch.Event{Type: watch.TrackChanged, Time: epoch, Track: track(), Playing: true, Progress: 1000})
+	s.SetDevice("Kitchen")
+	sc := s.At(epoch.Add(10 * time.Second))
+	if sc.Progress != 11*time.Second || !sc.Playing || sc.Device != "Kitchen" {
+		t.Errorf("got %+v", sc)
+	}
+	if sc := s.At(epoch.Add(time.Hour)); sc.Progress != 3*time.Minute {
+		t.Errorf("got progress %v past the end of the track", sc.Progress)
+	}
+
+	s.Send(watch.Event{Type: watch.Paused, Time: epoch.Add(20 * time.Second), Track: track(), Progress: 21000})
+	if sc := s.At(epoch.Add(time.Minute)); sc.Progress != 21*time.Second || sc.Playing || sc.Device != "Kitchen" {
+		t.Errorf("got %+v after pausing", sc)
+	}
+	s.Send(watch.Event{Type: watch.Stopped, Time: epoch.Add(time.Minute)})
+	if sc := s.At(epoch.Add(time.Minute)); sc.Track != nil || sc.Device != "" {
+		t.Errorf("got %+v after stopping", sc)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		progress, total time.Duration
+		want            string
+	}{
+		{0, time.Minute, "░░░░"},
+		{30 * time.Second, time.Minute, "██░░"},
+		{2 * time.Minute, time.Minute, "████"},
+		{time.Second, 0, "░░░░"},
+	}
+	for _, test := range tests {
+		if got := ProgressBar(test.progress, test.total, 4); got != test.want {
+			t.Errorf("ProgressBar(%v, %v) = %q, want %q", test.progress, test.total, got, test.want)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	sc := Screen{Track: track(), Playing: true, Progress: 90 * time.Second}
+	lines := sc.Lines(20)
+	want := []string{"▶ Speak", "  Band of Horses", "  ███░░░ 1:30 / 3:00"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if got := sc.Lines(10)[1]; got != "  Band of…" {
+		t.Errorf("got truncated line %q", got)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	var b bytes.Buffer
+	r := &Renderer{W: &b}
+	r.Draw(Screen{Track: track(), Device: "Kitchen"})
+	b.Reset()
+	r.Draw(Screen{})
+	want := "\033[4A\033[2KNothing is playing.\n\033[2K\n\033[2K\n\033[2K\n\033[3A"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
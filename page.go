@@ -1,14 +1,70 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
 )
 
 // ErrNoMorePages is the error returned when you attempt to get the next
 // (or previous) set of data but you've reached the end of the data set.
 var ErrNoMorePages = errors.New("spotify: no more pages")
 
+// ErrPaginationLimit is returned when a page is requested beyond the
+// deepest offset that the endpoint supports.  Some endpoints cap how far
+// their results can be paged through, whatever their Total says; search,
+// for example, only returns the first 1000 results of each type.  For
+// endpoints whose cap is known, the page isn't requested; for the others,
+// the Web API's 400 Bad Request response is reported as this error.
+var ErrPaginationLimit = errors.New("spotify: offset is beyond the endpoint's pagination limit")
+
+// maxOffsets maps the paths of endpoints, relative to the Web API's base
+// address, to the largest offset they accept.
+var maxOffsets = map[string]int{
+	"search": 1000,
+}
+
+// pageOffset returns the endpoint path and the offset of a request for a
+// page.
+func pageOffset(rawurl string) (path string, offset int) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", 0
+	}
+	offset, _ = strconv.Atoi(u.Query().Get("offset"))
+	return strings.TrimPrefix(u.Path, "/v1/"), offset
+}
+
+// checkOffset returns ErrPaginationLimit if the request for a page at
+// rawurl is beyond its endpoint's known limit.
+func checkOffset(rawurl string) error {
+	path, offset := pageOffset(rawurl)
+	if max, ok := maxOffsets[path]; ok && offset >= max {
+		return ErrPaginationLimit
+	}
+	return nil
+}
+
+// decodePageError decodes the error response to the request for a page at
+// rawurl.  A 400 Bad Request about the offset of a page past the first is
+// reported as ErrPaginationLimit.
+func decodePageError(rawurl string, resp *http.Response) error {
+	err := decodeError(resp)
+	e, ok := err.(*Error)
+	if !ok || e.Status != http.StatusBadRequest {
+		return err
+	}
+	if _, offset := pageOffset(rawurl); offset > 0 && strings.Contains(strings.ToLower(e.Message), "offset") {
+		return ErrPaginationLimit
+	}
+	return err
+}
+
 // This file contains the types that implement Spotify's paging object.
 // See: https://developer.spotify.com/web-api/object-model/#paging-object
 
@@ -81,6 +137,24 @@ type PlaylistTrackPage struct {
 	Tracks []PlaylistTrack `json:"items"`
 }
 
+// SimpleShowPage contains SimpleShows returned by the Web API.
+type SimpleShowPage struct {
+	basePage
+	Shows []SimpleShow `json:"items"`
+}
+
+// SimpleEpisodePage contains SimpleEpisodes returned by the Web API.
+type SimpleEpisodePage struct {
+	basePage
+	Episodes []SimpleEpisode `json:"items"`
+}
+
+// SavedShowPage contains SavedShows returned by the Web API.
+type SavedShowPage struct {
+	basePage
+	Shows []SavedShow `json:"items"`
+}
+
 // CategoryPage contains Category objects returned by the Web API.
 type CategoryPage struct {
 	basePage
@@ -88,11 +162,18 @@ type CategoryPage struct {
 }
 
 // getPage GETs the data at the specified URL and unmarshals it into page.
-func (c *Client) getPage(url string, page interface{}) error {
-	resp, err := c.http.Get(url)
+// The request can be canceled through ctx.
+func (c *Client) getPage(ctx context.Context, url string, page interface{}) error {
+	if err := checkOffset(url); err != nil {
+		return err
+	}
+	resp, err := ctxhttp.Get(ctx, c.http, url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(page)
+	if resp.StatusCode != http.StatusOK {
+		return decodePageError(url, resp)
+	}
+	return c.decode(resp.Body, page)
 }
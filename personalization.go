@@ -1,12 +1,10 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
 )
 
 // PlayHistory contains a user's play history.
@@ -15,21 +13,24 @@ type PlayHistory struct {
 	Next     string        `json:"next"`
 	Limit    int           `json:"limit"`
 	Endpoint string        `json:"href"`
+	Cursor   Cursor        `json:"cursors"`
 }
 
 // TrackContext contains metadata on the context in which the track was listened to.
 type TrackContext struct {
-	Type         string            `json:"type"`
-	Endpoint     string            `json:"href"`
-	ExternalURLS map[string]string `json:"external_urls"`
-	URI          URI               `json:"uri"`
+	Type         string       `json:"type"`
+	Endpoint     string       `json:"href"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	URI          URI          `json:"uri"`
 }
 
-// HistoryItem contains the track and its metadata.
+// HistoryItem contains the track and its metadata.  Context is nil if the
+// track wasn't played from a playlist, album or artist.
 type HistoryItem struct {
-	Track    SimpleTrack  `json:"track"`
-	PlayedAt string       `json:"played_at"`
-	Context  TrackContext `json:"context"`
+	Track SimpleTrack `json:"track"`
+	// PlayedAt is when the track was played, in UTC, to the millisecond.
+	PlayedAt time.Time     `json:"played_at"`
+	Context  *TrackContext `json:"context"`
 }
 
 // TopTracks contains both a list of tracks and paging information.
@@ -45,34 +46,39 @@ type TopTracks struct {
 
 // TrackItem contains basic info about a track.
 type TrackItem struct {
-	Album        AlbumInfo         `json:"album"`
-	Artists      []ArtistInfo      `json:"artists"`
-	DiscNumber   int               `json:"disc_number"`
-	DurationMS   int               `json:"duration_ms"`
-	Explicit     bool              `json:"explicit"`
-	ExternalIDs  map[string]string `json:"external_ids"`
-	ExternalURLs map[string]string `json:"external_urls"`
-	Endpoint     URI               `json:"href"`
-	ID           ID                `json:"id"`
-	IsPlayable   bool              `json:"is_playable"`
-	Name         string            `json:"name"`
-	Popularity   int               `json:"popularity"`
-	PreviewURL   string            `json:"preview_url"`
-	TrackNumber  int               `json:"track_number"`
-	Type         string            `json:"track"`
-	URI          URI               `json:"uri"`
+	Album            AlbumInfo    `json:"album"`
+	Artists          []ArtistInfo `json:"artists"`
+	AvailableMarkets []string     `json:"available_markets"`
+	DiscNumber       int          `json:"disc_number"`
+	DurationMS       int          `json:"duration_ms"`
+	Explicit         bool         `json:"explicit"`
+	ExternalIDs      ExternalIDs  `json:"external_ids"`
+	ExternalURLs     ExternalURLs `json:"external_urls"`
+	Endpoint         string       `json:"href"`
+	ID               ID           `json:"id"`
+	IsPlayable       bool         `json:"is_playable"`
+	Name             string       `json:"name"`
+	Popularity       Popularity   `json:"popularity"`
+	PreviewURL       *string      `json:"preview_url"`
+	TrackNumber      int          `json:"track_number"`
+	Type             string       `json:"type"`
+	URI              URI          `json:"uri"`
 }
 
 // AlbumInfo contains album information for a particular album.
 type AlbumInfo struct {
-	AlbumType    string            `json:"album_type"`
-	ExternalURLs map[string]string `json:"external_urls"`
-	Endpoint     string            `json:"href"`
-	ID           ID                `json:"id"`
-	Images       []Image           `json:"images"`
-	Name         string            `json:"name"`
-	ItemType     string            `json:"type"`
-	URI          URI               `json:"uri"`
+	AlbumType            string        `json:"album_type"`
+	Artists              []ArtistInfo  `json:"artists"`
+	AvailableMarkets     []string      `json:"available_markets"`
+	ExternalURLs         ExternalURLs  `json:"external_urls"`
+	Endpoint             string        `json:"href"`
+	ID                   ID            `json:"id"`
+	Images               Images        `json:"images"`
+	Name                 string        `json:"name"`
+	ItemType             string        `json:"type"`
+	ReleaseDate          string        `json:"release_date"`
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	URI                  URI           `json:"uri"`
 }
 
 // TopArtists contains both a list of artists and paging information.
@@ -88,88 +94,153 @@ type TopArtists struct {
 
 // ArtistItem contains extensive info about an artist.
 type ArtistItem struct {
-	ExternalURLs map[string]string `json:"external_urls"`
-	Followers    Followers         `json:"followers"`
-	Genres       []string          `json:"genres"`
-	Endpoint     string            `json:"href"`
-	ID           ID                `json:"id"`
-	Images       []Image           `json:"images"`
-	Name         string            `json:"name"`
-	Popularity   int               `json:"popularity"`
-	Type         string            `json:"type"`
-	URI          URI               `json:"uri"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	Followers    Followers    `json:"followers"`
+	Genres       []string     `json:"genres"`
+	Endpoint     string       `json:"href"`
+	ID           ID           `json:"id"`
+	Images       Images       `json:"images"`
+	Name         string       `json:"name"`
+	Popularity   Popularity   `json:"popularity"`
+	Type         string       `json:"type"`
+	URI          URI          `json:"uri"`
 }
 
 // ArtistInfo contains basic artist information for a particular artist.
 type ArtistInfo struct {
-	ExternalURLs map[string]string `json:"external_urls"`
-	Endpoint     string            `json:"href"`
-	ID           ID                `json:"id"`
-	Name         string            `json:"name"`
-	Type         string            `json:"type"`
-	URI          URI               `json:"uri"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	Endpoint     string       `json:"href"`
+	ID           ID           `json:"id"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	URI          URI          `json:"uri"`
 }
 
-// CurrentUserRecentTracks returns the user's most recently played tracks in a single PlayHistory
-// object. It supports up to 50 tracks in a single call with only the 50 most recent tracks available
-// for each user. Requires authorization under user-read-recently-played scope.
-func (c *Client) CurrentUserRecentTracks(total int) (*PlayHistory, error) {
-	if total <= 0 || total > 50 {
-		return nil, errors.New("CurrentUserRecentTracks supports up to 50 tracks per call")
+// IDs returns the IDs of the tracks, in order, for looking them up with
+// GetTracksBatch or fetching their audio features.
+func (t *TopTracks) IDs() []ID {
+	ids := make([]ID, len(t.Items))
+	for i, item := range t.Items {
+		ids[i] = item.ID
 	}
-	spotifyURL := baseAddress + "me/player/recently-played?limit=" + strconv.Itoa(total)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		fmt.Println("resp err")
-		return nil, err
+	return ids
+}
+
+// IDs returns the IDs of the artists, in order, for looking up their albums
+// or top tracks, or their full details with GetArtistsBatch.
+func (t *TopArtists) IDs() []ID {
+	ids := make([]ID, len(t.Items))
+	for i, item := range t.Items {
+		ids[i] = item.ID
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+	return ids
+}
+
+// TrackIDs returns the IDs of the tracks played, most recently played
+// first, with each track included once, for looking up their full details
+// (including their albums and popularity) with GetTracksBatch.
+func (h *PlayHistory) TrackIDs() []ID {
+	seen := make(map[ID]bool, len(h.Items))
+	var ids []ID
+	for _, item := range h.Items {
+		if !seen[item.Track.ID] {
+			seen[item.Track.ID] = true
+			ids = append(ids, item.Track.ID)
+		}
 	}
+	return ids
+}
 
+// CurrentUserRecentTracksWithContext returns the user's most recently
+// played tracks, most recent first.  opt may be nil; its Limit is the
+// number of tracks, up to 50 (the default is 20), and its Before or After
+// cursor returns the tracks played before or after that time.  To page
+// backwards through the history, pass the Before cursor of each result
+// (h.Cursor.Before) to the next call; to collect new plays, pass the After
+// cursor of the last result.  Only the 50 most recent tracks are
+// available for each user.  Requires authorization under the
+// ScopeUserReadRecentlyPlayed scope.
+func (c *Client) CurrentUserRecentTracksWithContext(ctx context.Context, opt *Options) (*PlayHistory, error) {
+	e := c.endpoint("me/player/recently-played")
+	if opt != nil {
+		if opt.Limit != nil {
+			if *opt.Limit <= 0 || *opt.Limit > 50 {
+				return nil, errors.New("spotify: CurrentUserRecentTracks supports up to 50 tracks per call")
+			}
+			e.setInt("limit", *opt.Limit)
+		}
+		if opt.Before != nil && opt.After != nil {
+			return nil, errors.New("spotify: CurrentUserRecentTracks takes a Before or an After cursor, not both")
+		}
+		if opt.Before != nil {
+			e.set("before", *opt.Before)
+		}
+		if opt.After != nil {
+			e.set("after", *opt.After)
+		}
+	}
 	var h PlayHistory
-	err = json.NewDecoder(resp.Body).Decode(&h)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &h); err != nil {
 		return nil, err
 	}
-
 	return &h, nil
 }
 
-// CurrentUserTopTracks returns the user's top tracks in a single TopTracks object.
-// It supports up to 50 tracks in a single call with only the top 50 tracks available
-// for each user. It also supports three different time ranges from where to fetch the
-// tracks. Valid ranges include "short_term" (4 weeks), "medium_term" (6 months), and
-// "long_term" (years). Requires authorization under user-top-read scope.
-func (c *Client) CurrentUserTopTracks(opt *Options) (*TopTracks, error) {
-	v := url.Values{}
+// CurrentUserRecentTracks returns the user's most recently played tracks in a single PlayHistory
+// object. It supports up to 50 tracks in a single call with only the 50 most recent tracks available
+// for each user. Requires authorization under user-read-recently-played scope.
+//
+// Deprecated: use CurrentUserRecentTracksWithContext, which can be canceled.
+func (c *Client) CurrentUserRecentTracks(total int) (*PlayHistory, error) {
+	return c.CurrentUserRecentTracksWithContext(context.Background(), &Options{Limit: &total})
+}
 
+// topEndpoint returns the endpoint for the user's top items of type t
+// ("tracks" or "artists"), with the parameters from opt.
+func (c *Client) topEndpoint(t string, opt *Options) *endpoint {
+	e := c.endpoint("me/top/%s", t)
 	if opt != nil {
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
+		e.setPaging(opt)
 		if opt.Timerange != nil {
-			v.Set("time_range", *opt.Timerange)
+			e.set("time_range", *opt.Timerange)
 		}
 	}
+	return e
+}
 
-	spotifyURL := baseAddress + "me/top/tracks?" + v.Encode()
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
+// CurrentUserTopTracksWithContext returns the user's top tracks.  opt may
+// be nil; its Limit (up to 50) and Offset page through the results, and
+// its Timerange is one of "short_term" (4 weeks), "medium_term" (6 months,
+// the default) and "long_term" (years).  Only the top 50 tracks are
+// available for each user.  Requires authorization under the
+// ScopeUserTopRead scope.
+func (c *Client) CurrentUserTopTracksWithContext(ctx context.Context, opt *Options) (*TopTracks, error) {
+	var t TopTracks
+	if err := c.get(ctx, c.topEndpoint("tracks", opt).String(), &t); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return &t, nil
+}
 
-	var t TopTracks
-	err = json.NewDecoder(resp.Body).Decode(&t)
-	if err != nil {
+// CurrentUserTopTracks returns the user's top tracks in a single TopTracks object.
+// It supports up to 50 tracks in a single call with only the top 50 tracks available
+// for each user. It also supports three different time ranges from where to fetch the
+// tracks. Valid ranges include "short_term" (4 weeks), "medium_term" (6 months), and
+// "long_term" (years). Requires authorization under user-top-read scope.
+//
+// Deprecated: use CurrentUserTopTracksWithContext, which can be canceled.
+func (c *Client) CurrentUserTopTracks(opt *Options) (*TopTracks, error) {
+	return c.CurrentUserTopTracksWithContext(context.Background(), opt)
+}
+
+// CurrentUserTopArtistsWithContext returns the user's top artists.  opt is
+// used as by CurrentUserTopTracksWithContext.  Requires authorization
+// under the ScopeUserTopRead scope.
+func (c *Client) CurrentUserTopArtistsWithContext(ctx context.Context, opt *Options) (*TopArtists, error) {
+	var t TopArtists
+	if err := c.get(ctx, c.topEndpoint("artists", opt).String(), &t); err != nil {
 		return nil, err
 	}
-
 	return &t, nil
 }
 
@@ -178,33 +249,8 @@ func (c *Client) CurrentUserTopTracks(opt *Options) (*TopTracks, error) {
 // for each user. It also supports three different time ranges from where to fetch the
 // artists. Valid ranges include "short_term" (4 weeks), "medium_term" (6 months), and
 // "long_term" (years). Requires authorization under user-top-read scope.
+//
+// Deprecated: use CurrentUserTopArtistsWithContext, which can be canceled.
 func (c *Client) CurrentUserTopArtists(opt *Options) (*TopArtists, error) {
-	v := url.Values{}
-
-	if opt != nil {
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Timerange != nil {
-			v.Set("time_range", *opt.Timerange)
-		}
-	}
-
-	spotifyURL := baseAddress + "me/top/artists?" + v.Encode()
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
-
-	var t TopArtists
-	err = json.NewDecoder(resp.Body).Decode(&t)
-	if err != nil {
-		return nil, err
-	}
-
-	return &t, nil
+	return c.CurrentUserTopArtistsWithContext(context.Background(), opt)
 }
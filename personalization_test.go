@@ -0,0 +1,94 @@
+package spotify
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestCurrentUserTopTracksWithContext(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"items": [{"name": "Speak"}], "total": 50}`)
+	limit, offset, rng := 10, 20, "long_term"
+	top, err := client.CurrentUserTopTracksWithContext(context.Background(), &Options{Limit: &limit, Offset: &offset, Timerange: &rng})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top.Items) != 1 || top.Items[0].Name != "Speak" {
+		t.Errorf("Got %+v\n", top)
+	}
+	q := getLastRequest(client).URL.Query()
+	if q.Get("limit") != "10" || q.Get("offset") != "20" || q.Get("time_range") != "long_term" {
+		t.Errorf("Got query %v\n", q)
+	}
+}
+
+func TestPersonalizationIDs(t *testing.T) {
+	top := &TopArtists{Items: []ArtistItem{{ID: "a"}, {ID: "b"}}}
+	if ids := top.IDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("Got artist IDs %v\n", ids)
+	}
+	var h PlayHistory
+	for _, id := range []ID{"x", "y", "x"} {
+		var item HistoryItem
+		item.Track.ID = id
+		h.Items = append(h.Items, item)
+	}
+	if ids := h.TrackIDs(); len(ids) != 2 || ids[0] != "x" || ids[1] != "y" {
+		t.Errorf("Got track IDs %v, want each track once\n", ids)
+	}
+}
+
+func TestCurrentUserRecentTracksLimit(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"items": []}`)
+	limit := 51
+	if _, err := client.CurrentUserRecentTracksWithContext(context.Background(), &Options{Limit: &limit}); err == nil {
+		t.Error("Expected an error for more than 50 tracks")
+	}
+	if _, err := client.CurrentUserRecentTracks(0); err == nil {
+		t.Error("Expected an error for 0 tracks")
+	}
+}
+
+func TestCurrentUserRecentTracksPlayedAt(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"items": [{"track": {"name": "Speak"}, "played_at": "2017-05-19T09:13:23.631Z"}]}`)
+	h, err := client.CurrentUserRecentTracksWithContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2017, 5, 19, 9, 13, 23, 631000000, time.UTC)
+	if len(h.Items) != 1 || !h.Items[0].PlayedAt.Equal(want) {
+		t.Errorf("Got %+v, want a track played at %v\n", h.Items, want)
+	}
+}
+
+func TestCurrentUserRecentTracksCursors(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"items": [], "cursors": {"after": "1495185203631", "before": "1495185200000"}}`)
+	before := TimeCursor(time.Unix(1495185203, 631000000))
+	h, err := client.CurrentUserRecentTracksWithContext(context.Background(), &Options{Before: &before})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := getLastRequest(client).URL.Query(); q.Get("before") != "1495185203631" || q.Get("after") != "" {
+		t.Errorf("Got query %v\n", q)
+	}
+	if h.Cursor.Before != "1495185200000" || h.Cursor.After != "1495185203631" {
+		t.Errorf("Got cursors %+v\n", h.Cursor)
+	}
+	if _, err := client.CurrentUserRecentTracksWithContext(context.Background(), &Options{Before: &before, After: &before}); err == nil {
+		t.Error("Expected an error for both cursors")
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	client := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// A transport stops sending a request whose context is done.
+		return nil, req.Context().Err()
+	})})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.CurrentUserTopArtistsWithContext(ctx, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+}
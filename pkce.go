@@ -0,0 +1,90 @@
+package spotify
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// The Authorization Code with PKCE flow (RFC 7636) lets applications that
+// can't keep a client secret, such as native apps and single page apps,
+// get tokens for users.  Create the authenticator with an empty secret:
+//
+//     a := spotify.NewAuthenticator(redirectURL, spotify.ScopeUserTopRead)
+//     a.SetAuthInfo(clientID, "")
+//     verifier, err := spotify.NewCodeVerifier()
+//     // keep the verifier (in the user's session, say), and send them to
+//     http.Redirect(w, r, a.AuthURLWithPKCE("state-string", spotify.CodeChallenge(verifier)), http.StatusFound)
+//
+//     // then, in the redirect handler:
+//     token, err := a.TokenWithPKCE("state-string", r, verifier)
+//     client := a.NewClient(token)
+//
+// Tokens from this flow are refreshed without the secret as well.
+
+// NewCodeVerifier returns a new random code verifier for the PKCE flow.
+// Use a new one for each authorization.
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 code challenge of verifier, which is sent
+// with the authorization request.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURLWithPKCE is like AuthURL, for the PKCE flow.  challenge is the
+// CodeChallenge of the verifier later passed to TokenWithPKCE.
+func (a Authenticator) AuthURLWithPKCE(state, challenge string) string {
+	return a.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		oauth2.SetAuthURLParam("code_challenge", challenge))
+}
+
+// TokenWithPKCE is like Token, for the PKCE flow.  verifier is the code
+// verifier whose challenge was passed to AuthURLWithPKCE.
+func (a Authenticator) TokenWithPKCE(state string, r *http.Request, verifier string) (*oauth2.Token, error) {
+	values := r.URL.Query()
+	if e := values.Get("error"); e != "" {
+		return nil, errors.New("spotify: auth failed - " + e)
+	}
+	code := values.Get("code")
+	if code == "" {
+		return nil, errors.New("spotify: didn't get access code")
+	}
+	if values.Get("state") != state {
+		return nil, errors.New("spotify: redirect state parameter doesn't match")
+	}
+	return a.ExchangeWithPKCE(code, verifier)
+}
+
+// ExchangeWithPKCE is like TokenWithPKCE, except it allows you to manually
+// specify the access code instead of pulling it out of an HTTP request.
+func (a Authenticator) ExchangeWithPKCE(code, verifier string) (*oauth2.Token, error) {
+	if verifier == "" {
+		return nil, errors.New("spotify: no code verifier")
+	}
+	return a.oauthConfig().Exchange(a.context, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+}
+
+// oauthConfig returns the OAuth2 configuration to get tokens with.  Without
+// a client secret, the client ID is sent in the body of token requests, as
+// the PKCE flow requires, rather than in an Authorization header.
+func (a Authenticator) oauthConfig() *oauth2.Config {
+	if a.config.ClientSecret != "" {
+		return a.config
+	}
+	cfg := *a.config
+	cfg.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	return &cfg
+}
@@ -0,0 +1,66 @@
+package spotify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// The example from RFC 7636, appendix B.
+	if got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("Got challenge %s\n", got)
+	}
+	v, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) < 43 || len(v) > 128 {
+		t.Errorf("Got a verifier of %d characters, want 43 to 128\n", len(v))
+	}
+}
+
+func TestPKCEFlow(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Error("Got an Authorization header without a client secret")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "abc", "token_type": "Bearer", "expires_in": 3600, "refresh_token": "def",
+		})
+	}))
+	defer server.Close()
+
+	a := NewAuthenticator("http://localhost/callback", ScopeUserTopRead)
+	a.SetAuthInfo("client-id", "")
+	a.config.Endpoint.TokenURL = server.URL
+
+	u, err := url.Parse(a.AuthURLWithPKCE("xyz", CodeChallenge("verifier")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := u.Query(); q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") != CodeChallenge("verifier") {
+		t.Errorf("Got query %v\n", q)
+	}
+
+	r := httptest.NewRequest("GET", "http://localhost/callback?code=the-code&state=xyz", nil)
+	token, err := a.TokenWithPKCE("xyz", r, "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "abc" {
+		t.Errorf("Got token %+v\n", token)
+	}
+	if form.Get("code_verifier") != "verifier" || form.Get("client_id") != "client-id" || form.Get("client_secret") != "" {
+		t.Errorf("Got token request %v\n", form)
+	}
+	if _, err := a.TokenWithPKCE("abc", r, "verifier"); err == nil {
+		t.Error("Expected an error for a mismatched state")
+	}
+}
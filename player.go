@@ -0,0 +1,250 @@
+package spotify
+
+import (
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/net/context"
+)
+
+// CurrentlyPlaying contains information about the track that a user is
+// listening to.
+type CurrentlyPlaying struct {
+	// Timestamp is when the information was fetched, in milliseconds since
+	// the Unix epoch.
+	Timestamp int64 `json:"timestamp"`
+	// PlaybackContext is the playlist, album or artist the track is being
+	// played from, or nil.
+	PlaybackContext *TrackContext `json:"context"`
+	// Progress is the position of playback within the track, in
+	// milliseconds.
+	Progress int `json:"progress_ms"`
+	// Playing is true if the track is playing, and false if it's paused.
+	Playing bool `json:"is_playing"`
+	// Item is the track, or nil if nothing is playing (or if an ad or an
+	// episode is).
+	Item *FullTrack `json:"item"`
+}
+
+// PlayerDevice is a device that can play music, such as a phone or a speaker.
+type PlayerDevice struct {
+	// ID is the ID of the device.  It may be empty.
+	ID ID `json:"id"`
+	// Active is true if the device is the one currently playing.
+	Active bool `json:"is_active"`
+	// Restricted is true if the device can't be controlled through the
+	// Web API.
+	Restricted bool `json:"is_restricted"`
+	// Name is the name the user gave the device, such as "Kitchen speaker".
+	Name string `json:"name"`
+	// Type is the kind of device, such as "Computer", "Smartphone" or
+	// "Speaker".
+	Type string `json:"type"`
+	// Volume is the volume, as a percentage.
+	Volume int `json:"volume_percent"`
+}
+
+// PlayerState is the state of a user's player: what is playing, and on
+// which device.
+type PlayerState struct {
+	CurrentlyPlaying
+	// Device is the device that is active.
+	Device PlayerDevice `json:"device"`
+	// ShuffleState is true if shuffle is on.
+	ShuffleState bool `json:"shuffle_state"`
+	// RepeatState is "off", "track" or "context".
+	RepeatState string `json:"repeat_state"`
+}
+
+// PlayerStateWithContext returns the state of the user's player, including
+// the device that is active.  If no device is active, the result is empty.
+// opt may be nil; its Country is the market used for track relinking.
+// Requires authorization under the ScopeUserReadPlaybackState scope.
+func (c *Client) PlayerStateWithContext(ctx context.Context, opt *Options) (*PlayerState, error) {
+	e := c.endpoint("me/player")
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	var result PlayerState
+	if err := c.get(ctx, e.String(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PlayerState returns the state of the user's player, including the device
+// that is active.  If no device is active, the result is empty.  Requires
+// authorization under the ScopeUserReadPlaybackState scope.
+//
+// Deprecated: use PlayerStateWithContext, which can be canceled.
+func (c *Client) PlayerState() (*PlayerState, error) {
+	return c.PlayerStateWithContext(context.Background(), nil)
+}
+
+// PlayerCurrentlyPlayingWithContext returns the track that the user is
+// listening to.  If nothing is playing, or the user is in a private
+// session, the Item of the result is nil.  opt may be nil; its Country is
+// the market used for track relinking.  Requires authorization under the
+// ScopeUserReadCurrentlyPlaying or ScopeUserReadPlaybackState scope.
+func (c *Client) PlayerCurrentlyPlayingWithContext(ctx context.Context, opt *Options) (*CurrentlyPlaying, error) {
+	e := c.endpoint("me/player/currently-playing")
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	var result CurrentlyPlaying
+	if err := c.get(ctx, e.String(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PlayerCurrentlyPlaying returns the track that the user is listening to.
+// If nothing is playing, or the user is in a private session, the Item of
+// the result is nil.  Requires authorization under the
+// ScopeUserReadCurrentlyPlaying or ScopeUserReadPlaybackState scope.
+//
+// Deprecated: use PlayerCurrentlyPlayingWithContext, which can be canceled.
+func (c *Client) PlayerCurrentlyPlaying() (*CurrentlyPlaying, error) {
+	return c.PlayerCurrentlyPlayingWithContext(context.Background(), nil)
+}
+
+// PlayOptions are the optional parameters of the player controls.
+type PlayOptions struct {
+	// DeviceID is the device to control.  If nil, the user's active
+	// device is used.
+	DeviceID *ID `json:"-"`
+	// PlaybackContext is the album, artist or playlist to play, for
+	// PlayOpt.  Only one of PlaybackContext and URIs may be set.
+	PlaybackContext *URI `json:"context_uri,omitempty"`
+	// URIs are the tracks to play, for PlayOpt.
+	URIs []URI `json:"uris,omitempty"`
+	// PlaybackOffset is where to start in the PlaybackContext (which must
+	// be an album or a playlist) or URIs, for PlayOpt.
+	PlaybackOffset *PlaybackOffset `json:"offset,omitempty"`
+}
+
+// PlaybackOffset is where playback starts.  Only one of its fields may be
+// set.
+type PlaybackOffset struct {
+	// Position is the index of the track to start at, from zero.
+	Position int `json:"position"`
+	// URI is the track to start at.
+	URI URI `json:"uri,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, leaving out the position when the
+// URI is set.
+func (o PlaybackOffset) MarshalJSON() ([]byte, error) {
+	if o.URI != "" {
+		return json.Marshal(struct {
+			URI URI `json:"uri"`
+		}{o.URI})
+	}
+	return json.Marshal(struct {
+		Position int `json:"position"`
+	}{o.Position})
+}
+
+// playerEndpoint returns the endpoint of a player control.
+func (c *Client) playerEndpoint(control string, opt *PlayOptions) *endpoint {
+	e := c.endpoint("me/player/%s", control)
+	if opt != nil && opt.DeviceID != nil {
+		e.set("device_id", string(*opt.DeviceID))
+	}
+	return e
+}
+
+// PlayWithContext starts or resumes playback.  With no PlaybackContext or
+// URIs in opt, it resumes what was playing.  opt may be nil.  The player
+// controls require authorization under the ScopeUserModifyPlaybackState
+// scope, and a Premium account.
+func (c *Client) PlayWithContext(ctx context.Context, opt *PlayOptions) error {
+	if opt != nil && opt.PlaybackContext != nil && len(opt.URIs) > 0 {
+		return errors.New("spotify: can't play both a context and URIs")
+	}
+	var body interface{}
+	if opt != nil && (opt.PlaybackContext != nil || len(opt.URIs) > 0) {
+		body = opt
+	}
+	return c.send(ctx, "PUT", c.playerEndpoint("play", opt).String(), body)
+}
+
+// Play resumes playback on the user's active device.
+func (c *Client) Play() error {
+	return c.PlayWithContext(context.Background(), nil)
+}
+
+// PlayOpt is like Play, but it accepts optional parameters that choose
+// what to play, and on which device.
+func (c *Client) PlayOpt(opt *PlayOptions) error {
+	return c.PlayWithContext(context.Background(), opt)
+}
+
+// PauseWithContext pauses playback.  Only opt.DeviceID is used.
+func (c *Client) PauseWithContext(ctx context.Context, opt *PlayOptions) error {
+	return c.send(ctx, "PUT", c.playerEndpoint("pause", opt).String(), nil)
+}
+
+// Pause pauses playback on the user's active device.
+func (c *Client) Pause() error {
+	return c.PauseWithContext(context.Background(), nil)
+}
+
+// PauseOpt is like Pause, but it accepts the device to pause.
+func (c *Client) PauseOpt(opt *PlayOptions) error {
+	return c.PauseWithContext(context.Background(), opt)
+}
+
+// NextWithContext skips to the next track.  Only opt.DeviceID is used.
+func (c *Client) NextWithContext(ctx context.Context, opt *PlayOptions) error {
+	return c.send(ctx, "POST", c.playerEndpoint("next", opt).String(), nil)
+}
+
+// Next skips to the next track on the user's active device.
+func (c *Client) Next() error {
+	return c.NextWithContext(context.Background(), nil)
+}
+
+// NextOpt is like Next, but it accepts the device to control.
+func (c *Client) NextOpt(opt *PlayOptions) error {
+	return c.NextWithContext(context.Background(), opt)
+}
+
+// PreviousWithContext skips to the previous track.  Only opt.DeviceID is
+// used.
+func (c *Client) PreviousWithContext(ctx context.Context, opt *PlayOptions) error {
+	return c.send(ctx, "POST", c.playerEndpoint("previous", opt).String(), nil)
+}
+
+// Previous skips to the previous track on the user's active device.
+func (c *Client) Previous() error {
+	return c.PreviousWithContext(context.Background(), nil)
+}
+
+// PreviousOpt is like Previous, but it accepts the device to control.
+func (c *Client) PreviousOpt(opt *PlayOptions) error {
+	return c.PreviousWithContext(context.Background(), opt)
+}
+
+// SeekWithContext moves playback to position milliseconds into the current
+// track.  Only opt.DeviceID is used.
+func (c *Client) SeekWithContext(ctx context.Context, position int, opt *PlayOptions) error {
+	if position < 0 {
+		return errors.New("spotify: seek position must not be negative")
+	}
+	e := c.playerEndpoint("seek", opt)
+	e.setInt("position_ms", position)
+	return c.send(ctx, "PUT", e.String(), nil)
+}
+
+// Seek moves playback on the user's active device to position milliseconds
+// into the current track.  A position past the end of the track skips to
+// the next one.
+func (c *Client) Seek(position int) error {
+	return c.SeekWithContext(context.Background(), position, nil)
+}
+
+// SeekOpt is like Seek, but it accepts the device to control.
+func (c *Client) SeekOpt(position int, opt *PlayOptions) error {
+	return c.SeekWithContext(context.Background(), position, opt)
+}
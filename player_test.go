@@ -0,0 +1,132 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const currentlyPlayingResponse = `{
+	"timestamp": 1495185203631,
+	"context": {
+		"type": "album",
+		"href": "https://api.spotify.com/v1/albums/6akEvsycLGftJxYudPjmqK",
+		"external_urls": {"spotify": "https://open.spotify.com/album/6akEvsycLGftJxYudPjmqK"},
+		"uri": "spotify:album:6akEvsycLGftJxYudPjmqK"
+	},
+	"progress_ms": 44272,
+	"is_playing": true,
+	"item": {
+		"id": "6rqhFgbbKwnb9MLmUQDhG6",
+		"name": "Speak",
+		"duration_ms": 180000,
+		"uri": "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"
+	}
+}`
+
+func TestPlayerCurrentlyPlaying(t *testing.T) {
+	c := testClientString(http.StatusOK, currentlyPlayingResponse)
+	cp, err := c.PlayerCurrentlyPlaying()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cp.Playing || cp.Progress != 44272 || cp.Item == nil || cp.Item.Name != "Speak" {
+		t.Errorf("got %+v", cp)
+	}
+	if cp.PlaybackContext == nil || cp.PlaybackContext.Type != "album" {
+		t.Errorf("got context %+v", cp.PlaybackContext)
+	}
+	if r := getLastRequest(c); r.URL.Path != "/v1/me/player/currently-playing" {
+		t.Errorf("requested %s", r.URL.Path)
+	}
+}
+
+func TestPlayerCurrentlyPlayingNothing(t *testing.T) {
+	c := testClientString(http.StatusNoContent, "")
+	cp, err := c.PlayerCurrentlyPlaying()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Playing || cp.Item != nil {
+		t.Errorf("got %+v, want nothing playing", cp)
+	}
+}
+
+func TestPlayerState(t *testing.T) {
+	body := `{"device": {"id": "d1", "is_active": true, "name": "Kitchen", "type": "Speaker", "volume_percent": 70},
+		"shuffle_state": true, "repeat_state": "context", ` + currentlyPlayingResponse[1:]
+	c := testClientString(http.StatusOK, body)
+	st, err := c.PlayerState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Device.Name != "Kitchen" || st.Device.Volume != 70 || !st.ShuffleState || st.RepeatState != "context" {
+		t.Errorf("got %+v", st)
+	}
+	if st.Item == nil || st.Item.Name != "Speak" || !st.Playing {
+		t.Errorf("got currently playing %+v", st.CurrentlyPlaying)
+	}
+}
+
+func TestPlayOpt(t *testing.T) {
+	c := testClientString(http.StatusNoContent, "")
+	device := ID("5fbb3ba6aa454b5534c4ba43a8c7e8e45a63ad0e")
+	album := URI("spotify:album:6akEvsycLGftJxYudPjmqK")
+	err := c.PlayOpt(&PlayOptions{
+		DeviceID:        &device,
+		PlaybackContext: &album,
+		PlaybackOffset:  &PlaybackOffset{Position: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := getLastRequest(c)
+	if req.Method != "PUT" || req.URL.Path != "/v1/me/player/play" || req.URL.Query().Get("device_id") != string(device) {
+		t.Errorf("Got request %s %s\n", req.Method, req.URL)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	want := `{"context_uri":"spotify:album:6akEvsycLGftJxYudPjmqK","offset":{"position":5}}`
+	if string(body) != want {
+		t.Errorf("Got body %s, want %s\n", body, want)
+	}
+
+	uris := []URI{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6"}
+	if err := c.PlayOpt(&PlayOptions{PlaybackContext: &album, URIs: uris}); err == nil {
+		t.Error("Expected an error for both a context and URIs")
+	}
+}
+
+func TestPlayerControls(t *testing.T) {
+	tests := []struct {
+		name   string
+		call   func(c *Client) error
+		method string
+		url    string
+	}{
+		{"Play", (*Client).Play, "PUT", "/v1/me/player/play"},
+		{"Pause", (*Client).Pause, "PUT", "/v1/me/player/pause"},
+		{"Next", (*Client).Next, "POST", "/v1/me/player/next"},
+		{"Previous", (*Client).Previous, "POST", "/v1/me/player/previous"},
+		{"Seek", func(c *Client) error { return c.Seek(25000) }, "PUT", "/v1/me/player/seek?position_ms=25000"},
+	}
+	for _, test := range tests {
+		c := testClientString(http.StatusNoContent, "")
+		if err := test.call(c); err != nil {
+			t.Errorf("%s: %v\n", test.name, err)
+			continue
+		}
+		req := getLastRequest(c)
+		if req.Method != test.method || req.URL.RequestURI() != test.url {
+			t.Errorf("%s: got request %s %s, want %s %s\n", test.name, req.Method, req.URL.RequestURI(), test.method, test.url)
+		}
+	}
+}
+
+func TestPlayerControlError(t *testing.T) {
+	c := testClientString(http.StatusForbidden, `{"error": {"status": 403, "message": "Player command failed: Premium required"}}`)
+	err := c.Pause()
+	if e, ok := err.(*Error); !ok || !strings.Contains(e.Message, "Premium required") {
+		t.Errorf("Got error %v\n", err)
+	}
+}
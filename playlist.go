@@ -1,13 +1,10 @@
 package spotify
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
+	"errors"
 	"strings"
+
+	"golang.org/x/net/context"
 )
 
 // PlaylistTracks contains details about the tracks in a playlist.
@@ -23,18 +20,18 @@ type PlaylistTracks struct {
 type SimplePlaylist struct {
 	// Indicates whether the playlist owner allows others to modify the playlist.
 	// Note: only non-collaborative playlists are currently returned by Spotify's Web API.
-	Collaborative bool              `json:"collaborative"`
-	ExternalURLs  map[string]string `json:"external_urls"`
+	Collaborative bool         `json:"collaborative"`
+	ExternalURLs  ExternalURLs `json:"external_urls"`
 	// A link to the Web API endpoint providing full details of the playlist.
 	Endpoint string `json:"href"`
 	ID       ID     `json:"id"`
 	// The playlist image.  Note: this field is only  returned for modified,
 	// verified playlists. Otherwise the slice is empty.  If returned, the source
 	// URL for the image is temporary and will expire in less than a day.
-	Images   []Image `json:"images"`
-	Name     string  `json:"name"`
-	Owner    User    `json:"owner"`
-	IsPublic bool    `json:"public"`
+	Images   Images `json:"images"`
+	Name     string `json:"name"`
+	Owner    User   `json:"owner"`
+	IsPublic bool   `json:"public"`
 	// The version identifier for the current playlist. Can be supplied in other
 	// requests to target a specific playlist version.
 	SnapshotID string `json:"snapshot_id"`
@@ -42,6 +39,8 @@ type SimplePlaylist struct {
 	// tracks can be retrieved, along with the total number of tracks in the playlist.
 	Tracks PlaylistTracks `json:"tracks"`
 	URI    URI            `json:"uri"`
+	// The object type: "playlist".
+	Type string `json:"type"`
 }
 
 // FullPlaylist provides extra playlist data in addition to the data provided by SimplePlaylist.
@@ -54,76 +53,6 @@ type FullPlaylist struct {
 	Tracks    PlaylistTrackPage `json:"tracks"`
 }
 
-// PlaylistOptions contains optional parameters that can be used when querying
-// for featured playlists.  Only the non-nil fields are used in the request.
-type PlaylistOptions struct {
-	Options
-	// The desired language, consisting of a lowercase IO 639
-	// language code and an uppercase ISO 3166-1 alpha-2
-	// country code, joined by an underscore.  Provide this
-	// parameter if you want the results returned in a particular
-	// language.  If not specified, the result will be returned
-	// in the Spotify default language (American English).
-	Locale *string
-	// A timestamp in ISO 8601 format (yyyy-MM-ddTHH:mm:ss).
-	// use this paramter to specify the user's local time to
-	// get results tailored for that specific date and time
-	// in the day.  If not provided, the response defaults to
-	// the current UTC time.
-	Timestamp *string
-}
-
-// FeaturedPlaylistsOpt gets a list of playlists featured by Spotify.
-// It accepts a number of optional parameters via the opt argument.
-// This call requires authorization.
-func (c *Client) FeaturedPlaylistsOpt(opt *PlaylistOptions) (message string, playlists *SimplePlaylistPage, e error) {
-	spotifyURL := baseAddress + "browse/featured-playlists"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Locale != nil {
-			v.Set("locale", *opt.Locale)
-		}
-		if opt.Country != nil {
-			v.Set("country", *opt.Country)
-		}
-		if opt.Timestamp != nil {
-			v.Set("timestamp", *opt.Timestamp)
-		}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return "", nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", nil, decodeError(resp.Body)
-	}
-	var result struct {
-		Playlists SimplePlaylistPage `json:"playlists"`
-		Message   string             `json:"message"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return "", nil, err
-	}
-	return result.Message, &result.Playlists, nil
-}
-
-// FeaturedPlaylists gets a list of playlists featured by Spotify.
-// It is equivalent to c.FeaturedPlaylistsOpt(nil).
-func (c *Client) FeaturedPlaylists() (message string, playlists *SimplePlaylistPage, e error) {
-	return c.FeaturedPlaylistsOpt(nil)
-}
-
 // FollowPlaylist adds the current user as a follower of the specified
 // playlist.  Any playlist can be followed, regardless of its private/public
 // status, as long as you know the owner and playlist ID.
@@ -133,22 +62,12 @@ func (c *Client) FeaturedPlaylists() (message string, playlists *SimplePlaylistP
 // must have granted the ScopePlaylistModifyPrivate scope.  The
 // ScopePlaylistModifyPublic scope is required to follow playlists publicly.
 func (c *Client) FollowPlaylist(owner ID, playlist ID, public bool) error {
-	spotifyURL := buildFollowURI(owner, playlist)
-	body := strings.NewReader(strconv.FormatBool(public))
-	req, err := http.NewRequest("PUT", spotifyURL, body)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return decodeError(resp.Body)
-	}
-	return nil
+	return c.FollowPlaylistWithContext(context.Background(), owner, playlist, public)
+}
+
+// FollowPlaylistWithContext is like FollowPlaylist, with a context.
+func (c *Client) FollowPlaylistWithContext(ctx context.Context, owner ID, playlist ID, public bool) error {
+	return c.send(ctx, "PUT", c.followURL(owner, playlist), public)
 }
 
 // UnfollowPlaylist removes the current user as a follower of a playlist.
@@ -156,25 +75,16 @@ func (c *Client) FollowPlaylist(owner ID, playlist ID, public bool) error {
 // requires the ScopePlaylistModifyPublic scope.  Unfolowing a privately followed,
 // playlist requies the ScopePlaylistModifyPrivate scope.
 func (c *Client) UnfollowPlaylist(owner, playlist ID) error {
-	spotifyURL := buildFollowURI(owner, playlist)
-	req, err := http.NewRequest("DELETE", spotifyURL, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return decodeError(resp.Body)
-	}
-	return nil
+	return c.UnfollowPlaylistWithContext(context.Background(), owner, playlist)
+}
+
+// UnfollowPlaylistWithContext is like UnfollowPlaylist, with a context.
+func (c *Client) UnfollowPlaylistWithContext(ctx context.Context, owner, playlist ID) error {
+	return c.send(ctx, "DELETE", c.followURL(owner, playlist), nil)
 }
 
-func buildFollowURI(owner, playlist ID) string {
-	return fmt.Sprintf("%susers/%s/playlists/%s/followers",
-		baseAddress, string(owner), string(playlist))
+func (c *Client) followURL(owner, playlist ID) string {
+	return c.endpoint("users/%s/playlists/%s/followers", owner, playlist).String()
 }
 
 // GetPlaylistsForUser gets a list of the playlists owned or followed by a
@@ -187,35 +97,22 @@ func buildFollowURI(owner, playlist ID) string {
 // order to read collaborative playlists, the user must have granted the
 // ScopePlaylistReadCollaborative scope.
 func (c *Client) GetPlaylistsForUser(userID string) (*SimplePlaylistPage, error) {
-	return c.GetPlaylistsForUserOpt(userID, nil)
+	return c.GetPlaylistsForUserWithContext(context.Background(), userID, nil)
 }
 
 // GetPlaylistsForUserOpt is like PlaylistsForUser, but it accepts optional paramters
 // for filtering the results.
 func (c *Client) GetPlaylistsForUserOpt(userID string, opt *Options) (*SimplePlaylistPage, error) {
-	spotifyURL := baseAddress + "users/" + userID + "/playlists"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetPlaylistsForUserWithContext(context.Background(), userID, opt)
+}
+
+// GetPlaylistsForUserWithContext is like GetPlaylistsForUserOpt, with a
+// context.
+func (c *Client) GetPlaylistsForUserWithContext(ctx context.Context, userID string, opt *Options) (*SimplePlaylistPage, error) {
+	e := c.endpoint("users/%s/playlists", userID)
+	e.setPaging(opt)
 	var result SimplePlaylistPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err := c.get(ctx, e.String(), &result)
 	return &result, err
 }
 
@@ -223,7 +120,7 @@ func (c *Client) GetPlaylistsForUserOpt(userID string, opt *Options) (*SimplePla
 // authorization.  Both public and private playlists belonging to any user
 // are retrievable with a valid access token.
 func (c *Client) GetPlaylist(userID string, playlistID ID) (*FullPlaylist, error) {
-	return c.GetPlaylistOpt(userID, playlistID, "")
+	return c.GetPlaylistWithContext(context.Background(), userID, playlistID, "")
 }
 
 // GetPlaylistOpt is like GetPlaylist, but it accepts an optional fields parameter
@@ -245,20 +142,18 @@ func (c *Client) GetPlaylist(userID string, playlistID ID) (*FullPlaylist, error
 // Fields can be excluded by prefixing them with an exclamation mark, for example;
 //    fields = "tracks.items(track(name,href,album(!name,href)))"
 func (c *Client) GetPlaylistOpt(userID string, playlistID ID, fields string) (*FullPlaylist, error) {
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s", baseAddress, userID, playlistID)
+	return c.GetPlaylistWithContext(context.Background(), userID, playlistID, fields)
+}
+
+// GetPlaylistWithContext is like GetPlaylistOpt, with a context.
+func (c *Client) GetPlaylistWithContext(ctx context.Context, userID string, playlistID ID, fields string) (*FullPlaylist, error) {
+	e := c.endpoint("users/%s/playlists/%s", userID, playlistID)
 	if fields != "" {
-		spotifyURL += "?fields=" + url.QueryEscape(fields)
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+		e.set("fields", fields)
 	}
+	e.setDefaultMarket()
 	var playlist FullPlaylist
-	err = json.NewDecoder(resp.Body).Decode(&playlist)
+	err := c.get(ctx, e.String(), &playlist)
 	return &playlist, err
 }
 
@@ -266,7 +161,7 @@ func (c *Client) GetPlaylistOpt(userID string, playlistID ID, fields string) (*F
 // owner of the playlist and the playlist's Spotify ID.
 // This call requires authorization.
 func (c *Client) GetPlaylistTracks(userID string, playlistID ID) (*PlaylistTrackPage, error) {
-	return c.GetPlaylistTracksOpt(userID, playlistID, nil, "")
+	return c.GetPlaylistTracksWithContext(context.Background(), userID, playlistID, nil, "")
 }
 
 // GetPlaylistTracksOpt is like GetPlaylistTracks, but it accepts optional parameters
@@ -287,36 +182,98 @@ func (c *Client) GetPlaylistTracks(userID string, playlistID ID) (*PlaylistTrack
 //
 // Fields can be excluded by prefixing them with an exclamation mark.  For example:
 //     fields = "items.track.album(!external_urls,images)"
+//
+// If opt.Country is set, track relinking is applied for that market, and
+// each track's IsPlayable field reports whether it can be played there.
 func (c *Client) GetPlaylistTracksOpt(userID string, playlistID ID,
 	opt *Options, fields string) (*PlaylistTrackPage, error) {
 
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/tracks", baseAddress, userID, playlistID)
-	v := url.Values{}
+	return c.GetPlaylistTracksWithContext(context.Background(), userID, playlistID, opt, fields)
+}
+
+// GetPlaylistTracksWithContext is like GetPlaylistTracksOpt, with a context.
+func (c *Client) GetPlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID,
+	opt *Options, fields string) (*PlaylistTrackPage, error) {
+
+	var result PlaylistTrackPage
+	err := c.getPlaylistTracks(ctx, userID, playlistID, opt, fields, &result)
+	return &result, err
+}
+
+// getPlaylistTracks gets a page of a playlist's tracks, decoding it into
+// page.
+func (c *Client) getPlaylistTracks(ctx context.Context, userID string, playlistID ID, opt *Options, fields string, page interface{}) error {
+	e := c.endpoint("users/%s/playlists/%s/tracks", userID, playlistID)
 	if fields != "" {
-		v.Set("fields", fields)
+		e.set("fields", fields)
 	}
+	e.setPaging(opt)
+	if err := e.setMarket(opt); err != nil {
+		return err
+	}
+	return c.getPage(ctx, e.String(), page)
+}
+
+// ErrStopPaging can be returned by the function passed to
+// EachPlaylistTrack to stop reading the playlist early.  EachPlaylistTrack
+// then returns nil.
+var ErrStopPaging = errors.New("spotify: stop paging")
+
+// EachPlaylistTrack calls fn with each track in a playlist, in order, along
+// with its position in the playlist.  It reads the playlist a page at a time
+// and reuses the memory of each page for the next, so even very large
+// playlists can be processed in constant memory; fn must therefore not keep
+// t after it returns (copy *t to keep it).  opt and fields are as for
+// GetPlaylistTracksOpt, with opt.Limit setting the page size (100 if nil)
+// and opt.Offset the position to start at.  If fn returns an error,
+// EachPlaylistTrack stops and returns it, unless it is ErrStopPaging.
+func (c *Client) EachPlaylistTrack(userID string, playlistID ID, opt *Options, fields string,
+	fn func(i int, t *PlaylistTrack) error) error {
+
+	return c.EachPlaylistTrackWithContext(context.Background(), userID, playlistID, opt, fields, fn)
+}
+
+// EachPlaylistTrackWithContext is like EachPlaylistTrack, with a context.
+func (c *Client) EachPlaylistTrackWithContext(ctx context.Context, userID string, playlistID ID, opt *Options, fields string,
+	fn func(i int, t *PlaylistTrack) error) error {
+
+	var o Options
 	if opt != nil {
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
+		o = *opt
+	}
+	limit, offset := 100, 0
+	if o.Limit != nil {
+		limit = *o.Limit
+	}
+	if o.Offset != nil {
+		offset = *o.Offset
+	}
+	o.Limit, o.Offset = &limit, &offset
+	var page PlaylistTrackPage
+	for {
+		// Clear the previous page's tracks so that fields missing from
+		// this page aren't left over from it.
+		for i := range page.Tracks {
+			page.Tracks[i] = PlaylistTrack{}
 		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
+		page.Tracks = page.Tracks[:0]
+		page.Next = ""
+		if err := c.getPlaylistTracks(ctx, userID, playlistID, &o, fields, &page); err != nil {
+			return err
+		}
+		for i := range page.Tracks {
+			if err := fn(offset+i, &page.Tracks[i]); err != nil {
+				if err == ErrStopPaging {
+					return nil
+				}
+				return err
+			}
+		}
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return nil
 		}
 	}
-	if params := v.Encode(); params != "" {
-		spotifyURL += "?" + params
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
-	var result PlaylistTrackPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	return &result, err
 }
 
 // CreatePlaylistForUser creates a playlist for a Spotify user.
@@ -331,7 +288,13 @@ func (c *Client) GetPlaylistTracksOpt(userID string, playlistID ID,
 //
 // On success, the newly created playlist is returned.
 func (c *Client) CreatePlaylistForUser(userID, playlistName string, public bool) (*FullPlaylist, error) {
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists", baseAddress, userID)
+	return c.CreatePlaylistForUserWithContext(context.Background(), userID, playlistName, public)
+}
+
+// CreatePlaylistForUserWithContext is like CreatePlaylistForUser, with a
+// context.
+func (c *Client) CreatePlaylistForUserWithContext(ctx context.Context, userID, playlistName string, public bool) (*FullPlaylist, error) {
+	spotifyURL := c.endpoint("users/%s/playlists", userID).String()
 	body := struct {
 		Name   string `json:"name"`
 		Public bool   `json:"public"`
@@ -339,25 +302,8 @@ func (c *Client) CreatePlaylistForUser(userID, playlistName string, public bool)
 		playlistName,
 		public,
 	}
-	bodyJSON, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", spotifyURL, bytes.NewReader(bodyJSON))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, decodeError(resp.Body)
-	}
 	var p FullPlaylist
-	err = json.NewDecoder(resp.Body).Decode(&p)
+	err := c.sendDecode(ctx, "POST", spotifyURL, body, &p)
 	return &p, err
 }
 
@@ -366,7 +312,12 @@ func (c *Client) CreatePlaylistForUser(userID, playlistName string, public bool)
 // scopes (depending on whether the playlist is public or private).
 // The current user must own the playlist in order to modify it.
 func (c *Client) ChangePlaylistName(userID string, playlistID ID, newName string) error {
-	return c.modifyPlaylist(userID, playlistID, newName, nil)
+	return c.ChangePlaylistNameWithContext(context.Background(), userID, playlistID, newName)
+}
+
+// ChangePlaylistNameWithContext is like ChangePlaylistName, with a context.
+func (c *Client) ChangePlaylistNameWithContext(ctx context.Context, userID string, playlistID ID, newName string) error {
+	return c.modifyPlaylist(ctx, userID, playlistID, newName, nil)
 }
 
 // ChangePlaylistAccess modifies the public/private status of a playlist.  This call
@@ -374,7 +325,13 @@ func (c *Client) ChangePlaylistName(userID string, playlistID ID, newName string
 // ScopePlaylistModifyPrivate scopes (depending on whether the playlist is
 // currently public or private).  The current user must own the playlist in order to modify it.
 func (c *Client) ChangePlaylistAccess(userID string, playlistID ID, public bool) error {
-	return c.modifyPlaylist(userID, playlistID, "", &public)
+	return c.ChangePlaylistAccessWithContext(context.Background(), userID, playlistID, public)
+}
+
+// ChangePlaylistAccessWithContext is like ChangePlaylistAccess, with a
+// context.
+func (c *Client) ChangePlaylistAccessWithContext(ctx context.Context, userID string, playlistID ID, public bool) error {
+	return c.modifyPlaylist(ctx, userID, playlistID, "", &public)
 }
 
 // ChangePlaylistNameAndAccess combines ChangePlaylistName and ChangePlaylistAccess into
@@ -382,36 +339,51 @@ func (c *Client) ChangePlaylistAccess(userID string, playlistID ID, public bool)
 // or ScopePlaylistModifyPrivate scopes (depending on whether the playlist is currently
 // public or private).  The current user must own the playlist in order to modify it.
 func (c *Client) ChangePlaylistNameAndAccess(userID string, playlistID ID, newName string, public bool) error {
-	return c.modifyPlaylist(userID, playlistID, newName, &public)
+	return c.ChangePlaylistNameAndAccessWithContext(context.Background(), userID, playlistID, newName, public)
 }
 
-func (c *Client) modifyPlaylist(userID string, playlistID ID, newName string, public *bool) error {
-	body := struct {
-		Name   string `json:"name,omitempty"`
-		Public *bool  `json:"public,omitempty"`
-	}{
-		newName,
-		public,
-	}
-	bodyJSON, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s", baseAddress, userID, string(playlistID))
-	req, err := http.NewRequest("PUT", spotifyURL, bytes.NewReader(bodyJSON))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
+// ChangePlaylistNameAndAccessWithContext is like ChangePlaylistNameAndAccess,
+// with a context.
+func (c *Client) ChangePlaylistNameAndAccessWithContext(ctx context.Context, userID string, playlistID ID, newName string, public bool) error {
+	return c.modifyPlaylist(ctx, userID, playlistID, newName, &public)
+}
+
+func (c *Client) modifyPlaylist(ctx context.Context, userID string, playlistID ID, newName string, public *bool) error {
+	details := &PlaylistDetails{Public: public}
+	if newName != "" {
+		details.Name = &newName
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return decodeError(resp.Body)
+	return c.ChangePlaylistDetailsWithContext(ctx, userID, playlistID, details)
+}
+
+// PlaylistDetails are the details of a playlist changed by
+// ChangePlaylistDetails.  Only the non-nil fields are changed.
+type PlaylistDetails struct {
+	Name   *string `json:"name,omitempty"`
+	Public *bool   `json:"public,omitempty"`
+	// Collaborative playlists can be changed by other users.  Only private
+	// playlists can be collaborative.
+	Collaborative *bool   `json:"collaborative,omitempty"`
+	Description   *string `json:"description,omitempty"`
+}
+
+// ChangePlaylistDetails changes the name, access and description of a
+// playlist in a single Web API call.  It requires that the user has
+// authorized the ScopePlaylistModifyPublic or ScopePlaylistModifyPrivate
+// scopes (depending on whether the playlist is currently public or private).
+// The current user must own the playlist in order to modify it.
+func (c *Client) ChangePlaylistDetails(userID string, playlistID ID, details *PlaylistDetails) error {
+	return c.ChangePlaylistDetailsWithContext(context.Background(), userID, playlistID, details)
+}
+
+// ChangePlaylistDetailsWithContext is like ChangePlaylistDetails, with a
+// context.
+func (c *Client) ChangePlaylistDetailsWithContext(ctx context.Context, userID string, playlistID ID, details *PlaylistDetails) error {
+	if details.Collaborative != nil && *details.Collaborative && details.Public != nil && *details.Public {
+		return errors.New("spotify: a collaborative playlist can't be public")
 	}
-	return nil
+	spotifyURL := c.endpoint("users/%s/playlists/%s", userID, playlistID).String()
+	return c.send(ctx, "PUT", spotifyURL, details)
 }
 
 // AddTracksToPlaylist adds one or more tracks to a user's playlist.  This call
@@ -422,30 +394,24 @@ func (c *Client) modifyPlaylist(userID string, playlistID ID, newName string, pu
 func (c *Client) AddTracksToPlaylist(userID string, playlistID ID,
 	trackIDs ...ID) (snapshotID string, err error) {
 
+	return c.AddTracksToPlaylistWithContext(context.Background(), userID, playlistID, trackIDs...)
+}
+
+// AddTracksToPlaylistWithContext is like AddTracksToPlaylist, with a
+// context.
+func (c *Client) AddTracksToPlaylistWithContext(ctx context.Context, userID string, playlistID ID,
+	trackIDs ...ID) (snapshotID string, err error) {
+
 	uris := make([]string, len(trackIDs))
 	for i, id := range trackIDs {
-		uris[i] = fmt.Sprintf("spotify:track:%s", id)
-	}
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/tracks?uris=%s",
-		baseAddress, userID, string(playlistID), strings.Join(uris, ","))
-	req, err := http.NewRequest("POST", spotifyURL, nil)
-	if err != nil {
-		return "", err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return "", decodeError(resp.Body)
+		uris[i] = string(BuildURI(ItemTypeTrack, id))
 	}
+	e := c.endpoint("users/%s/playlists/%s/tracks", userID, playlistID)
+	e.set("uris", strings.Join(uris, ","))
 	body := struct {
 		SnapshotID string `json:"snapshot_id"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&body)
-	if err != nil {
-		// the response code indicates success..
+	if err := c.sendDecode(ctx, "POST", e.String(), nil, &body); err != nil {
 		return "", err
 	}
 	return body.SnapshotID, nil
@@ -461,14 +427,22 @@ func (c *Client) AddTracksToPlaylist(userID string, playlistID ID,
 func (c *Client) RemoveTracksFromPlaylist(userID string, playlistID ID,
 	trackIDs ...ID) (newSnapshotID string, err error) {
 
+	return c.RemoveTracksFromPlaylistWithContext(context.Background(), userID, playlistID, trackIDs...)
+}
+
+// RemoveTracksFromPlaylistWithContext is like RemoveTracksFromPlaylist, with
+// a context.
+func (c *Client) RemoveTracksFromPlaylistWithContext(ctx context.Context, userID string, playlistID ID,
+	trackIDs ...ID) (newSnapshotID string, err error) {
+
 	tracks := make([]struct {
 		URI string `json:"uri"`
 	}, len(trackIDs))
 
 	for i, u := range trackIDs {
-		tracks[i].URI = fmt.Sprintf("spotify:track:%s", u)
+		tracks[i].URI = string(BuildURI(ItemTypeTrack, u))
 	}
-	return c.removeTracksFromPlaylist(userID, playlistID, tracks, "")
+	return c.removeTracksFromPlaylist(ctx, userID, playlistID, tracks, "")
 }
 
 // TrackToRemove specifies a track to be removed from a playlist.
@@ -483,7 +457,7 @@ type TrackToRemove struct {
 // track ID and playlist locations.
 func NewTrackToRemove(trackID string, positions []int) TrackToRemove {
 	return TrackToRemove{
-		URI:       fmt.Sprintf("spotify:track:%s", trackID),
+		URI:       string(BuildURI(ItemTypeTrack, ID(trackID))),
 		Positions: positions,
 	}
 }
@@ -503,10 +477,18 @@ func NewTrackToRemove(trackID string, positions []int) TrackToRemove {
 func (c *Client) RemoveTracksFromPlaylistOpt(userID string, playlistID ID,
 	tracks []TrackToRemove, snapshotID string) (newSnapshotID string, err error) {
 
-	return c.removeTracksFromPlaylist(userID, playlistID, tracks, snapshotID)
+	return c.removeTracksFromPlaylist(context.Background(), userID, playlistID, tracks, snapshotID)
+}
+
+// RemoveTracksFromPlaylistOptWithContext is like RemoveTracksFromPlaylistOpt,
+// with a context.
+func (c *Client) RemoveTracksFromPlaylistOptWithContext(ctx context.Context, userID string, playlistID ID,
+	tracks []TrackToRemove, snapshotID string) (newSnapshotID string, err error) {
+
+	return c.removeTracksFromPlaylist(ctx, userID, playlistID, tracks, snapshotID)
 }
 
-func (c *Client) removeTracksFromPlaylist(userID string, playlistID ID,
+func (c *Client) removeTracksFromPlaylist(ctx context.Context, userID string, playlistID ID,
 	tracks interface{}, snapshotID string) (newSnapshotID string, err error) {
 
 	m := make(map[string]interface{})
@@ -515,29 +497,11 @@ func (c *Client) removeTracksFromPlaylist(userID string, playlistID ID,
 		m["snapshot_id"] = snapshotID
 	}
 
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/tracks",
-		baseAddress, userID, string(playlistID))
-	body, err := json.Marshal(m)
-	if err != nil {
-		return "", err
-	}
-	req, err := http.NewRequest("DELETE", spotifyURL, bytes.NewReader(body))
-	if err != nil {
-		return "", nil
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", nil
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", decodeError(resp.Body)
-	}
+	spotifyURL := c.endpoint("users/%s/playlists/%s/tracks", userID, playlistID).String()
 	result := struct {
 		SnapshotID string `json:"snapshot_id"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = c.sendDecode(ctx, "DELETE", spotifyURL, m, &result)
 	return result.SnapshotID, err
 }
 
@@ -552,25 +516,19 @@ func (c *Client) removeTracksFromPlaylist(userID string, playlistID ID,
 // A maximum of 100 tracks is permited in this call.  Additional tracks must be
 // added via AddTracksToPlaylist.
 func (c *Client) ReplacePlaylistTracks(userID string, playlistID ID, trackIDs ...ID) error {
+	return c.ReplacePlaylistTracksWithContext(context.Background(), userID, playlistID, trackIDs...)
+}
+
+// ReplacePlaylistTracksWithContext is like ReplacePlaylistTracks, with a
+// context.
+func (c *Client) ReplacePlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID, trackIDs ...ID) error {
 	trackURIs := make([]string, len(trackIDs))
 	for i, u := range trackIDs {
-		trackURIs[i] = fmt.Sprintf("spotify:track:%s", u)
-	}
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/tracks?uris=%s",
-		baseAddress, userID, playlistID, strings.Join(trackURIs, ","))
-	req, err := http.NewRequest("PUT", spotifyURL, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
+		trackURIs[i] = string(BuildURI(ItemTypeTrack, u))
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return decodeError(resp.Body)
-	}
-	return nil
+	e := c.endpoint("users/%s/playlists/%s/tracks", userID, playlistID)
+	e.set("uris", strings.Join(trackURIs, ","))
+	return c.send(ctx, "PUT", e.String(), nil)
 }
 
 // UserFollowsPlaylist checks if one or more (up to 5) Spotify users are following
@@ -581,18 +539,16 @@ func (c *Client) ReplacePlaylistTracks(userID string, playlistID ID, trackIDs ..
 // Checking if the user is privately following a playlist is only possible for the
 // current user when that user has granted access to the ScopePlaylistReadPrivate scope.
 func (c *Client) UserFollowsPlaylist(ownerID string, playlistID ID, userIDs ...string) ([]bool, error) {
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/followers/contains?ids=%s",
-		baseAddress, ownerID, playlistID, strings.Join(userIDs, ","))
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.UserFollowsPlaylistWithContext(context.Background(), ownerID, playlistID, userIDs...)
+}
+
+// UserFollowsPlaylistWithContext is like UserFollowsPlaylist, with a
+// context.
+func (c *Client) UserFollowsPlaylistWithContext(ctx context.Context, ownerID string, playlistID ID, userIDs ...string) ([]bool, error) {
+	e := c.endpoint("users/%s/playlists/%s/followers/contains", ownerID, playlistID)
+	e.set("ids", strings.Join(userIDs, ","))
 	follows := make([]bool, len(userIDs))
-	err = json.NewDecoder(resp.Body).Decode(&follows)
+	err := c.get(ctx, e.String(), &follows)
 	return follows, err
 }
 
@@ -635,27 +591,16 @@ type PlaylistReorderOptions struct {
 // the user's private playlists (including collaborative playlists) requires
 // ScopePlaylistModifyPrivate.
 func (c *Client) ReorderPlaylistTracks(userID string, playlistID ID, opt PlaylistReorderOptions) (snapshotID string, err error) {
-	spotifyURL := fmt.Sprintf("%susers/%s/playlists/%s/tracks", baseAddress, userID, playlistID)
-	j, err := json.Marshal(opt)
-	if err != nil {
-		return "", err
-	}
-	req, err := http.NewRequest("PUT", spotifyURL, bytes.NewReader(j))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", decodeError(resp.Body)
-	}
+	return c.ReorderPlaylistTracksWithContext(context.Background(), userID, playlistID, opt)
+}
+
+// ReorderPlaylistTracksWithContext is like ReorderPlaylistTracks, with a
+// context.
+func (c *Client) ReorderPlaylistTracksWithContext(ctx context.Context, userID string, playlistID ID, opt PlaylistReorderOptions) (snapshotID string, err error) {
+	spotifyURL := c.endpoint("users/%s/playlists/%s/tracks", userID, playlistID).String()
 	result := struct {
 		SnapshotID string `json:"snapshot_id"`
 	}{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = c.sendDecode(ctx, "PUT", spotifyURL, opt, &result)
 	return result.SnapshotID, err
 }
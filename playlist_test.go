@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
-	"time"
 )
 
 func TestFeaturedPlaylists(t *testing.T) {
@@ -51,7 +52,7 @@ func TestFeaturedPlaylistsExpiredToken(t *testing.T) {
 		t.Error("Expected an error")
 		return
 	}
-	serr, ok := err.(Error)
+	serr, ok := err.(*Error)
 	if !ok {
 		t.Error("Expected spotify Error")
 		return
@@ -137,13 +138,61 @@ func TestGetPlaylistTracks(t *testing.T) {
 	if expected != actual {
 		t.Errorf("Got '%s', expected '%s'\n", actual, expected)
 	}
-	added := tracks.Tracks[0].AddedAt
-	tm, err := time.Parse(TimestampLayout, added)
+	if f := tracks.Tracks[0].AddedAt.Format(DateLayout); f != "2014-11-25" {
+		t.Errorf("Expected added at 2014-11-25, got %s\n", f)
+	}
+}
+
+// pagedPlaylist returns a client for a playlist of n tracks, which are
+// returned in pages according to the limit and offset of each request.
+func pagedPlaylist(n int) Client {
+	return NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		q := req.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		var items []string
+		for i := offset; i < offset+limit && i < n; i++ {
+			items = append(items, fmt.Sprintf(`{"track": {"name": "track %d"}}`, i))
+		}
+		next := ""
+		if offset+limit < n {
+			next = "next"
+		}
+		body := fmt.Sprintf(`{"total": %d, "next": %q, "items": [%s]}`, n, next, strings.Join(items, ","))
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+}
+
+func TestEachPlaylistTrack(t *testing.T) {
+	c := pagedPlaylist(5)
+	limit := 2
+	var names []string
+	err := c.EachPlaylistTrack("user", "playlistID", &Options{Limit: &limit}, "", func(i int, pt *PlaylistTrack) error {
+		if want := fmt.Sprintf("track %d", i); pt.Track.Name != want {
+			t.Errorf("track %d is named %q", i, pt.Track.Name)
+		}
+		names = append(names, pt.Track.Name)
+		return nil
+	})
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if f := tm.Format(DateLayout); f != "2014-11-25" {
-		t.Errorf("Expected added at 2014-11-25, got %s\n", f)
+	if len(names) != 5 {
+		t.Errorf("got %d tracks, want 5", len(names))
+	}
+}
+
+func TestEachPlaylistTrackStop(t *testing.T) {
+	c := pagedPlaylist(250)
+	var count int
+	err := c.EachPlaylistTrack("user", "playlistID", nil, "", func(i int, pt *PlaylistTrack) error {
+		if count++; i == 120 {
+			return ErrStopPaging
+		}
+		return nil
+	})
+	if err != nil || count != 121 {
+		t.Errorf("got error %v after %d tracks, want nil after 121", err, count)
 	}
 }
 
@@ -247,6 +296,31 @@ func TestChangePlaylistNameFailure(t *testing.T) {
 	}
 }
 
+func TestChangePlaylistDetails(t *testing.T) {
+	client := testClientString(http.StatusOK, "")
+	addDummyAuth(client)
+	public, collaborative, description := false, true, "Songs for the bus"
+	err := client.ChangePlaylistDetails("user", ID("playlist-id"), &PlaylistDetails{
+		Public:        &public,
+		Collaborative: &collaborative,
+		Description:   &description,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := getLastRequest(client)
+	body, _ := ioutil.ReadAll(req.Body)
+	want := `{"public":false,"collaborative":true,"description":"Songs for the bus"}`
+	if req.Method != "PUT" || string(body) != want {
+		t.Errorf("Got %s request with body %s, want %s\n", req.Method, body, want)
+	}
+
+	public = true
+	if err := client.ChangePlaylistDetails("user", ID("playlist-id"), &PlaylistDetails{Public: &public, Collaborative: &collaborative}); err == nil {
+		t.Error("Expected an error for a public collaborative playlist")
+	}
+}
+
 func TestAddTracksToPlaylist(t *testing.T) {
 	client := testClientString(http.StatusCreated, `{ "snapshot_id" : "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" }`)
 	addDummyAuth(client)
@@ -0,0 +1,29 @@
+package spotify
+
+// Popularity is the popularity of a track, artist or album, between 0 and
+// 100 with 100 being the most popular.  The Web API calculates it from the
+// total number of plays and how recent they are, so it changes over time.
+type Popularity int
+
+// ObscurePopularity is the popularity below which an item is considered
+// obscure by IsObscure.
+const ObscurePopularity Popularity = 20
+
+// IsObscure reports whether the popularity is below ObscurePopularity.
+func (p Popularity) IsObscure() bool {
+	return p < ObscurePopularity
+}
+
+// Bucket divides the range of popularities into n equal buckets and returns
+// the one p falls in, from 0 (least popular) to n-1 (most popular).  For
+// example, Bucket(4) gives the quartile and Bucket(10) the decile.  Values
+// outside 0 to 100 are put in the first or last bucket.
+func (p Popularity) Bucket(n int) int {
+	if n <= 1 || p <= 0 {
+		return 0
+	}
+	if p >= 100 {
+		return n - 1
+	}
+	return int(p) * n / 100
+}
@@ -0,0 +1,33 @@
+package spotify
+
+import "testing"
+
+func TestPopularityBucket(t *testing.T) {
+	tests := []struct {
+		p    Popularity
+		n    int
+		want int
+	}{
+		{0, 4, 0},
+		{24, 4, 0},
+		{25, 4, 1},
+		{74, 4, 2},
+		{99, 4, 3},
+		{100, 4, 3},
+		{130, 10, 9},
+		{-5, 10, 0},
+		{55, 10, 5},
+		{55, 1, 0},
+	}
+	for _, test := range tests {
+		if got := test.p.Bucket(test.n); got != test.want {
+			t.Errorf("Popularity(%d).Bucket(%d) = %d, want %d\n", test.p, test.n, got, test.want)
+		}
+	}
+}
+
+func TestPopularityIsObscure(t *testing.T) {
+	if !Popularity(5).IsObscure() || Popularity(20).IsObscure() {
+		t.Error("Expected only popularities below 20 to be obscure")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package spotify
+
+// raceEnabled is set when testing with the race detector, whose
+// instrumentation allocates.
+const raceEnabled = true
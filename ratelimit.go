@@ -0,0 +1,125 @@
+package spotify
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/memcache"
+)
+
+// Counter is a shared counter used for rate limiting.  To limit requests
+// across all instances of an application, the counter must be shared by
+// them, as MemcacheCounter is.
+type Counter interface {
+	// Increment adds one to the counter identified by key and returns the
+	// new value.  Counters that don't exist start at zero.
+	Increment(key string) (int64, error)
+}
+
+// RateLimitTransport is an http.RoundTripper that limits the number of
+// requests sent in each window of time.  All transports (and instances)
+// sharing a Counter and Name share the limit, so an application with many
+// instances and a single Spotify client ID stays under its rate limit as a
+// whole, rather than each instance throttling independently.
+//
+// When the limit for the current window has been reached, requests wait for
+// the next window.  If the counter fails, requests are sent anyway.
+type RateLimitTransport struct {
+	// Base is used to send the requests.  If nil, http.DefaultTransport is
+	// used.
+	Base    http.RoundTripper
+	Counter Counter
+	// Name distinguishes limits that share a Counter, for example one per
+	// client ID.
+	Name string
+	// Limit is the maximum number of requests per Window.
+	Limit  int64
+	Window time.Duration
+	// Clock is used to find the current window and to wait.  If nil,
+	// SystemClock is used.
+	Clock Clock
+}
+
+// WithRateLimit makes the client send at most limit requests per window,
+// counted in counter under name.
+func WithRateLimit(counter Counter, name string, limit int64, window time.Duration) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &RateLimitTransport{
+			Base:    hc.Transport,
+			Counter: counter,
+			Name:    name,
+			Limit:   limit,
+			Window:  window,
+			Clock:   clientClock{c},
+		}
+		c.http = &hc
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clock := t.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	for t.Limit > 0 && t.Window > 0 {
+		now := clock.Now()
+		window := now.UnixNano() / int64(t.Window)
+		n, err := t.Counter.Increment("ratelimit:" + t.Name + ":" + strconv.FormatInt(window, 10))
+		if err != nil || n <= t.Limit {
+			break
+		}
+		next := time.Unix(0, (window+1)*int64(t.Window))
+		select {
+		case <-clock.After(next.Sub(now)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return base.RoundTrip(req)
+}
+
+// MemoryCounter is a Counter for a single process.  Old counters are
+// never removed, so it's mainly useful for tests.
+type MemoryCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// Increment implements Counter.
+func (m *MemoryCounter) Increment(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[key]++
+	return m.counts[key], nil
+}
+
+// MemcacheCounter is a Counter backed by App Engine memcache, shared by all
+// instances of the application.  Memcache increments are atomic, and counters
+// for past windows are eventually evicted.
+type MemcacheCounter struct {
+	ctx context.Context
+}
+
+// NewMemcacheCounter creates a MemcacheCounter for the App Engine request
+// context ctx.
+func NewMemcacheCounter(ctx context.Context) *MemcacheCounter {
+	return &MemcacheCounter{ctx: ctx}
+}
+
+// Increment implements Counter.
+func (m *MemcacheCounter) Increment(key string) (int64, error) {
+	n, err := memcache.Increment(m.ctx, key, 1, 0)
+	return int64(n), err
+}
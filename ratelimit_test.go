@@ -0,0 +1,70 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type sleepingClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (c *sleepingClock) Now() time.Time { return c.now }
+func (c *sleepingClock) Sleep(d time.Duration) {
+	c.slept += d
+	c.now = c.now.Add(d)
+}
+func (c *sleepingClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`)),
+		}, nil
+	})
+	clock := &sleepingClock{now: time.Unix(1000, 0)}
+	counter := &MemoryCounter{}
+	// two clients (say, on different instances) sharing a limit
+	a := NewClient(&http.Client{Transport: tr}, WithClock(clock), WithRateLimit(counter, "app", 2, time.Second))
+	b := NewClient(&http.Client{Transport: tr}, WithClock(clock), WithRateLimit(counter, "app", 2, time.Second))
+
+	for _, c := range []Client{a, b, a, b, a} {
+		if _, err := c.CurrentUser(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clock.slept != 2*time.Second {
+		t.Errorf("Expected to wait 2 seconds, waited %v\n", clock.slept)
+	}
+}
+
+func TestRateLimitTransportCanceled(t *testing.T) {
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`)),
+		}, nil
+	})
+	c := NewClient(&http.Client{Transport: tr}, WithRateLimit(&MemoryCounter{}, "app", 1, time.Hour))
+	if _, err := c.CurrentUser(); err != nil {
+		t.Fatal(err)
+	}
+	// The next request would wait for the next window, up to an hour.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.CurrentUserWithContext(ctx); err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("Expected the wait to time out, got %v\n", err)
+	}
+}
@@ -1,12 +1,12 @@
 package spotify
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+
+	"golang.org/x/net/context"
 )
 
 // Seeds contains IDs of artists, genres and/or tracks
@@ -25,7 +25,7 @@ func (s Seeds) count() int {
 // Recommendations contains a list of recommended tracks based on seeds
 type Recommendations struct {
 	Seeds  []RecommendationSeed `json:"seeds"`
-	Tracks []SimpleTrack        `json:"tracks"`
+	Tracks []FullTrack          `json:"tracks"`
 }
 
 // RecommendationSeed represents a recommendation seed after
@@ -42,6 +42,36 @@ type RecommendationSeed struct {
 // MaxNumberOfSeeds allowed by Spotify for a recommendation request
 const MaxNumberOfSeeds = 5
 
+// SeedsFromTop returns seeds for recommendations like a user's top
+// listens: their top artists and tracks, taken in turn (starting with an
+// artist) until there are MaxNumberOfSeeds of them.  Either of artists and
+// tracks may be nil.  For example:
+//
+//    tracks, _ := client.CurrentUserTopTracksWithContext(ctx, nil)
+//    artists, _ := client.CurrentUserTopArtistsWithContext(ctx, nil)
+//    recs, err := client.GetRecommendations(spotify.SeedsFromTop(tracks, artists), nil, nil)
+func SeedsFromTop(tracks *TopTracks, artists *TopArtists) Seeds {
+	var s Seeds
+	var a, t int
+	for s.count() < MaxNumberOfSeeds {
+		added := false
+		if artists != nil && a < len(artists.Items) {
+			s.Artists = append(s.Artists, artists.Items[a].ID)
+			a++
+			added = true
+		}
+		if s.count() < MaxNumberOfSeeds && tracks != nil && t < len(tracks.Items) {
+			s.Tracks = append(s.Tracks, tracks.Items[t].ID)
+			t++
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+	return s
+}
+
 // setSeedValues sets url values into v for each seed in seeds
 func setSeedValues(seeds Seeds, v url.Values) {
 	if len(seeds.Artists) != 0 {
@@ -75,8 +105,11 @@ func setTrackAttributesValues(trackAttributes *TrackAttributes, values url.Value
 // For artists and tracks that are very new or obscure
 // there might not be enough data to generate a list of tracks.
 func (c *Client) GetRecommendations(seeds Seeds, trackAttributes *TrackAttributes, opt *Options) (*Recommendations, error) {
-	v := url.Values{}
+	return c.GetRecommendationsWithContext(context.Background(), seeds, trackAttributes, opt)
+}
 
+// GetRecommendationsWithContext is like GetRecommendations, with a context.
+func (c *Client) GetRecommendationsWithContext(ctx context.Context, seeds Seeds, trackAttributes *TrackAttributes, opt *Options) (*Recommendations, error) {
 	if seeds.count() == 0 {
 		return nil, fmt.Errorf("spotify: at least one seed is required")
 	}
@@ -84,53 +117,37 @@ func (c *Client) GetRecommendations(seeds Seeds, trackAttributes *TrackAttribute
 		return nil, fmt.Errorf("spotify: exceeded maximum of %d seeds", MaxNumberOfSeeds)
 	}
 
-	setSeedValues(seeds, v)
-	setTrackAttributesValues(trackAttributes, v)
+	e := c.endpoint("recommendations")
+	setSeedValues(seeds, e.query)
+	setTrackAttributesValues(trackAttributes, e.query)
 
 	if opt != nil {
 		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
+			e.setInt("limit", *opt.Limit)
 		}
-		if opt.Country != nil {
-			v.Set("market", *opt.Country)
+		if err := e.setMarket(opt); err != nil {
+			return nil, err
 		}
 	}
 
-	spotifyURL := baseAddress + "recommendations?" + v.Encode()
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
-
 	var recommendations Recommendations
-	err = json.NewDecoder(resp.Body).Decode(&recommendations)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &recommendations); err != nil {
 		return nil, err
 	}
-	return &recommendations, err
+	return &recommendations, nil
 }
 
 // GetAvailableGenreSeeds retrieves a list of available genres seed parameter values for
 // recommendations.
 func (c *Client) GetAvailableGenreSeeds() ([]string, error) {
-	spotifyURL := baseAddress + "recommendations/available-genre-seeds"
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.GetAvailableGenreSeedsWithContext(context.Background())
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+// GetAvailableGenreSeedsWithContext is like GetAvailableGenreSeeds, with a
+// context.
+func (c *Client) GetAvailableGenreSeedsWithContext(ctx context.Context) ([]string, error) {
 	genreSeeds := make(map[string][]string)
-	err = json.NewDecoder(resp.Body).Decode(&genreSeeds)
-	if err != nil {
+	if err := c.get(ctx, c.endpoint("recommendations/available-genre-seeds").String(), &genreSeeds); err != nil {
 		return nil, err
 	}
 	return genreSeeds["genres"], nil
@@ -72,3 +72,22 @@ func TestSetEmptyTrackAttributesValues(t *testing.T) {
 		t.Errorf("Expected track attributes values to be empty but got %s", actualValues)
 	}
 }
+
+func TestSeedsFromTop(t *testing.T) {
+	tracks := &TopTracks{Items: []TrackItem{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}, {ID: "t4"}}}
+	artists := &TopArtists{Items: []ArtistItem{{ID: "a1"}}}
+	seeds := SeedsFromTop(tracks, artists)
+	if seeds.count() != MaxNumberOfSeeds || len(seeds.Artists) != 1 || seeds.Tracks[3] != "t4" {
+		t.Errorf("Got seeds %+v\n", seeds)
+	}
+
+	artists.Items = append(artists.Items, ArtistItem{ID: "a2"}, ArtistItem{ID: "a3"})
+	seeds = SeedsFromTop(tracks, artists)
+	if len(seeds.Artists) != 3 || len(seeds.Tracks) != 2 {
+		t.Errorf("Got seeds %+v, want 3 artists and 2 tracks\n", seeds)
+	}
+
+	if seeds := SeedsFromTop(nil, nil); seeds.count() != 0 {
+		t.Errorf("Got seeds %+v, want none\n", seeds)
+	}
+}
@@ -0,0 +1,158 @@
+package spotify
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// OnTokenRefreshed sets a function that is called with the new token each
+// time a client made by the authenticator refreshes its access token, so
+// that the application can save it (with the refresh token, which Spotify
+// may have replaced) for the user's next session.  fn is called before the
+// request that needed the new token is sent.
+func (a *Authenticator) OnTokenRefreshed(fn func(*oauth2.Token)) {
+	a.onRefresh = fn
+}
+
+// newHTTPClient returns an http.Client that authorizes its requests with
+// token and sends them through base.  The token is refreshed through the
+// client of ctx when it expires, or when the Web API rejects it before
+// then, and saved with save, which may be nil.  Expiry is checked with
+// clock.
+func (a Authenticator) newHTTPClient(ctx context.Context, base http.RoundTripper,
+	token *oauth2.Token, save func(*oauth2.Token) error, clock Clock) *http.Client {
+
+	src := &refreshingTokenSource{
+		ctx:    ctx,
+		config: a.oauthConfig(),
+		clock:  clock,
+		token:  token,
+		save: func(t *oauth2.Token) error {
+			if save != nil {
+				if err := save(t); err != nil {
+					return err
+				}
+			}
+			if a.onRefresh != nil {
+				a.onRefresh(t)
+			}
+			return nil
+		},
+	}
+	return &http.Client{Transport: &refreshingTransport{base: base, source: src}}
+}
+
+// refreshingTokenSource is an oauth2.TokenSource that refreshes its token
+// when it expires, or when asked to.
+type refreshingTokenSource struct {
+	ctx    context.Context
+	config *oauth2.Config
+	clock  Clock
+	// save is called with each new token.  If it fails, the request that
+	// needed the token does too.
+	save func(*oauth2.Token) error
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// Token implements oauth2.TokenSource.
+func (s *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.valid() {
+		return s.token, nil
+	}
+	return s.refresh()
+}
+
+// tokenExpiryDelta is how long before its expiry a token is refreshed, so
+// that it doesn't expire on the way to the Web API.  It's the same as the
+// oauth2 package's.
+const tokenExpiryDelta = 10 * time.Second
+
+// valid reports whether the token can still be used, like Token.Valid, but
+// according to the source's clock.  s.mu must be held.
+func (s *refreshingTokenSource) valid() bool {
+	if s.token == nil || s.token.AccessToken == "" {
+		return false
+	}
+	if s.token.Expiry.IsZero() {
+		return true
+	}
+	return s.clock.Now().Add(tokenExpiryDelta).Before(s.token.Expiry)
+}
+
+// refreshIf refreshes the token if its access token is still stale, and
+// returns the current token.  Concurrent requests rejected with the same
+// token refresh it only once.
+func (s *refreshingTokenSource) refreshIf(stale string) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != stale {
+		return s.token, nil
+	}
+	return s.refresh()
+}
+
+// refresh gets a new token with the refresh token.  s.mu must be held.
+func (s *refreshingTokenSource) refresh() (*oauth2.Token, error) {
+	if s.token.RefreshToken == "" {
+		return nil, errors.New("spotify: token expired and there is no refresh token")
+	}
+	t, err := s.config.TokenSource(s.ctx, &oauth2.Token{RefreshToken: s.token.RefreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = t
+	if err := s.save(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// refreshingTransport authorizes requests with the token of its source.  If
+// the Web API rejects a token before it was due to expire (because it was
+// revoked, or the clocks disagree), the token is refreshed and the request
+// sent again, once.
+type refreshingTransport struct {
+	base   http.RoundTripper
+	source *refreshingTokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *refreshingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(authorize(req, token))
+	// A request with a body can't be sent again, as the body has been read.
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || req.Body != nil || token.RefreshToken == "" {
+		return resp, err
+	}
+	resp.Body.Close()
+	token, err = t.source.refreshIf(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	return base.RoundTrip(authorize(req, token))
+}
+
+// authorize returns a copy of req with token's Authorization header.
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.Header = cloneHeader(req.Header)
+	token.SetAuthHeader(r)
+	return r
+}
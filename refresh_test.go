@@ -0,0 +1,92 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestTokenRefresh(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/token") {
+			refreshes++
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "fresh%d", "token_type": "Bearer", "expires_in": 3600}`, refreshes)
+			return
+		}
+		// The Web API revoked the first token early.
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "Bearer fresh") {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": {"status": 401, "message": "The access token expired"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "wizzler"}`)
+	}))
+	defer server.Close()
+
+	a := NewAuthenticator("http://localhost/callback")
+	a.config.Endpoint.TokenURL = server.URL + "/api/token"
+	var saved []*oauth2.Token
+	a.OnTokenRefreshed(func(t *oauth2.Token) { saved = append(saved, t) })
+
+	c := a.NewClient(&oauth2.Token{AccessToken: "stale", RefreshToken: "r", Expiry: time.Now().Add(time.Hour)})
+	for i := 0; i < 2; i++ {
+		resp, err := c.http.Get(server.URL + "/v1/me")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Request %d: got status %d\n", i, resp.StatusCode)
+		}
+	}
+	if refreshes != 1 || len(saved) != 1 {
+		t.Fatalf("Got %d refreshes and %d saved tokens, want 1\n", refreshes, len(saved))
+	}
+	// The refresh token is kept when the response doesn't replace it.
+	if saved[0].AccessToken != "fresh1" || saved[0].RefreshToken != "r" {
+		t.Errorf("Saved %+v\n", saved[0])
+	}
+}
+
+func TestTokenRefreshWithoutRefreshToken(t *testing.T) {
+	a := NewAuthenticator("http://localhost/callback")
+	c := a.NewClient(&oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(-time.Minute)})
+	if _, err := c.CurrentUser(); err == nil {
+		t.Error("Expected an error for an expired token that can't be refreshed")
+	}
+}
+
+func TestTokenRefreshUsesClock(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/token") {
+			refreshes++
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "fresh", "token_type": "Bearer", "expires_in": 3600}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "wizzler"}`)
+	}))
+	defer server.Close()
+
+	a := NewAuthenticator("http://localhost/callback")
+	a.config.Endpoint.TokenURL = server.URL + "/api/token"
+	// The token has expired by the client's clock, which is given last.
+	clock := stoppedClock{t: time.Now().Add(2 * time.Hour)}
+	c := a.NewClient(&oauth2.Token{AccessToken: "old", RefreshToken: "r", Expiry: time.Now().Add(time.Hour)}, WithRetry(0), WithClock(clock))
+	resp, err := c.http.Get(server.URL + "/v1/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if refreshes != 1 {
+		t.Errorf("Got %d refreshes, want 1\n", refreshes)
+	}
+}
@@ -0,0 +1,121 @@
+package spotify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Defaults for RetryTransport.
+const (
+	// DefaultMaxRetries is how many times a request is retried.
+	DefaultMaxRetries = 3
+	// DefaultMaxRetryWait is the longest a request waits before a retry.
+	// If the Web API asks for a longer wait, the 429 response is returned.
+	DefaultMaxRetryWait = time.Minute
+)
+
+type noRetryKey struct{}
+
+// WithoutRetry returns a copy of ctx whose requests aren't retried by a
+// RetryTransport, for calls that would rather fail fast (pass it to the
+// WithContext variant of the call).
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// WithRetry makes the client retry requests that the Web API turns down
+// with 429 Too Many Requests, up to maxRetries times, waiting as long as
+// its Retry-After header says.  It should come after WithCache and before
+// WithScheduler, if they're used, so that the Scheduler sees every 429.
+func WithRetry(maxRetries int) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &RetryTransport{Base: hc.Transport, MaxRetries: maxRetries, Clock: clientClock{c}}
+		c.http = &hc
+	}
+}
+
+// RetryTransport is an http.RoundTripper that retries requests rejected
+// with 429 Too Many Requests.  It waits for the time given by the
+// response's Retry-After header, or, if there is none, for one second,
+// doubling with each retry.  When the retries run out, or the wait would be
+// longer than MaxWait, the last 429 response is returned, and the call
+// fails with an Error whose Status is 429.
+type RetryTransport struct {
+	// Base is used to send the requests.  If nil, http.DefaultTransport is
+	// used.
+	Base http.RoundTripper
+	// MaxRetries is the number of retries of each request.  If zero,
+	// DefaultMaxRetries is used; if negative, requests aren't retried.
+	MaxRetries int
+	// MaxWait is the longest wait before a retry.  If zero,
+	// DefaultMaxRetryWait is used.
+	MaxWait time.Duration
+	// Clock is used to wait.  If nil, SystemClock is used.
+	Clock Clock
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clock := t.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	retries := t.MaxRetries
+	if retries == 0 {
+		retries = DefaultMaxRetries
+	}
+	maxWait := t.MaxWait
+	if maxWait == 0 {
+		maxWait = DefaultMaxRetryWait
+	}
+	if noRetry, _ := req.Context().Value(noRetryKey{}).(bool); noRetry || retries < 0 {
+		return base.RoundTrip(req)
+	}
+	// The body is read by each attempt, so keep a copy.
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			r = new(http.Request)
+			*r = *req
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := base.RoundTrip(r)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt == retries {
+			return resp, err
+		}
+		wait := backoff
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(s) * time.Second
+		}
+		if wait > maxWait {
+			return resp, nil
+		}
+		resp.Body.Close()
+		select {
+		case <-clock.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
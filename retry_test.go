@@ -0,0 +1,101 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// rateLimited returns a transport that turns down the first n requests
+// with 429 Too Many Requests, and counts the requests it gets.
+func rateLimited(n int, retryAfter string, requests *int) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*requests++
+		if *requests <= n {
+			h := make(http.Header)
+			if retryAfter != "" {
+				h.Set("Retry-After", retryAfter)
+			}
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     h,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"status": 429, "message": "API rate limit exceeded"}}`)),
+			}, nil
+		}
+		if req.Body != nil {
+			if b, _ := ioutil.ReadAll(req.Body); string(b) != `{"name":"Bus"}` {
+				return nil, http.ErrBodyNotAllowed
+			}
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`))}, nil
+	})
+}
+
+func TestRetryTransport(t *testing.T) {
+	var requests int
+	clock := &sleepingClock{now: time.Now()}
+	c := NewClient(&http.Client{Transport: rateLimited(2, "3", &requests)}, WithClock(clock), WithRetry(0))
+	user, err := c.CurrentUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "wizzler" || requests != 3 {
+		t.Errorf("Got %+v after %d requests\n", user, requests)
+	}
+	if clock.slept != 6*time.Second {
+		t.Errorf("Waited %v, want 6s\n", clock.slept)
+	}
+
+	// The body of a write is sent again.
+	requests = 0
+	if err := c.ChangePlaylistName("wizzler", "playlist-id", "Bus"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("Got %d requests\n", requests)
+	}
+}
+
+func TestRetryBeforeClock(t *testing.T) {
+	var requests int
+	clock := &sleepingClock{now: time.Now()}
+	c := NewClient(&http.Client{Transport: rateLimited(1, "3", &requests)}, WithRetry(0), WithClock(clock))
+	if _, err := c.CurrentUser(); err != nil {
+		t.Fatal(err)
+	}
+	if clock.slept != 3*time.Second {
+		t.Errorf("Waited %v on the clock, want 3s\n", clock.slept)
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var requests int
+	clock := &sleepingClock{now: time.Now()}
+	c := NewClient(&http.Client{Transport: rateLimited(10, "", &requests)}, WithClock(clock), WithRetry(2))
+	_, err := c.CurrentUser()
+	if e, ok := err.(*Error); !ok || e.Status != http.StatusTooManyRequests {
+		t.Errorf("Got error %v\n", err)
+	}
+	if requests != 3 || clock.slept != 3*time.Second {
+		t.Errorf("Got %d requests after waiting %v, want 3 after 3s\n", requests, clock.slept)
+	}
+
+	// A Retry-After beyond MaxWait isn't waited for.
+	requests, clock.slept = 0, 0
+	c = NewClient(&http.Client{Transport: rateLimited(10, "3600", &requests)}, WithClock(clock), WithRetry(2))
+	if _, err := c.CurrentUser(); err == nil || requests != 1 || clock.slept != 0 {
+		t.Errorf("Got %d requests after waiting %v (%v)\n", requests, clock.slept, err)
+	}
+}
+
+func TestWithoutRetry(t *testing.T) {
+	var requests int
+	c := NewClient(&http.Client{Transport: rateLimited(1, "1", &requests)}, WithClock(&sleepingClock{}), WithRetry(0))
+	if _, err := c.CurrentUserWithContext(WithoutRetry(context.Background())); err == nil || requests != 1 {
+		t.Errorf("Got %d requests (%v), want 1 failed request\n", requests, err)
+	}
+}
@@ -0,0 +1,254 @@
+package rules
+
+import (
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// SmartPlaylist keeps a playlist in sync with a rule: after each Sync, the
+// playlist contains exactly the saved tracks that match the rule.
+//
+// Metadata fetched for the rules (artists' genres, audio features and
+// release years) is remembered between syncs, so only newly saved tracks
+// need to be looked up.  A SmartPlaylist must not be used concurrently.
+type SmartPlaylist struct {
+	Client spotify.SpotifyClient
+	Rule   Rule
+	// OwnerID and PlaylistID identify the playlist to keep in sync.
+	OwnerID    string
+	PlaylistID spotify.ID
+	// Limit is the most tracks to keep in the playlist, preferring the
+	// most recently saved.  Zero means no limit.
+	Limit int
+	// Clock is used to evaluate rules relative to the current time.  If
+	// nil, spotify.SystemClock is used.
+	Clock spotify.Clock
+
+	genres   map[spotify.ID][]string
+	features map[spotify.ID]*spotify.AudioFeatures
+	years    map[spotify.ID]int
+}
+
+// Result describes the changes a Sync made to the playlist.
+type Result struct {
+	Added   []spotify.ID
+	Removed []spotify.ID
+}
+
+func (s *SmartPlaylist) clock() spotify.Clock {
+	if s.Clock == nil {
+		return spotify.SystemClock
+	}
+	return s.Clock
+}
+
+// Library fetches the user's saved tracks, most recently saved first,
+// along with the metadata the rules need.  It requires the
+// ScopeUserLibraryRead scope.
+func (s *SmartPlaylist) Library() ([]*Track, error) {
+	var tracks []*Track
+	limit, offset := 50, 0
+	for {
+		page, err := s.Client.CurrentUsersTracksOpt(&spotify.Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, saved := range page.Tracks {
+			if saved.ID == "" {
+				continue
+			}
+			tracks = append(tracks, &Track{FullTrack: saved.FullTrack, AddedAt: saved.AddedAt})
+		}
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			break
+		}
+	}
+	if err := s.annotate(tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// annotate fills in the genres, audio features and release year of the
+// tracks, looking up whatever hasn't been seen before.
+func (s *SmartPlaylist) annotate(tracks []*Track) error {
+	if s.genres == nil {
+		s.genres = make(map[spotify.ID][]string)
+		s.features = make(map[spotify.ID]*spotify.AudioFeatures)
+		s.years = make(map[spotify.ID]int)
+	}
+	var artists, trackIDs, albums []spotify.ID
+	for _, t := range tracks {
+		for _, a := range t.Artists {
+			if _, ok := s.genres[a.ID]; !ok && a.ID != "" {
+				artists = append(artists, a.ID)
+			}
+		}
+		if _, ok := s.features[t.ID]; !ok {
+			trackIDs = append(trackIDs, t.ID)
+		}
+		if t.Album == nil {
+			continue
+		}
+		if _, ok := s.years[t.Album.ID]; !ok && t.Album.ID != "" {
+			if t.Album.ReleaseDate != "" {
+				s.years[t.Album.ID] = t.Album.ReleaseDateTime().Year()
+			} else {
+				albums = append(albums, t.Album.ID)
+			}
+		}
+	}
+
+	artists, _ = spotify.DedupeIDs(artists)
+	for _, chunk := range spotify.ChunkIDs(artists, 50) {
+		full, err := s.Client.GetArtists(chunk...)
+		if err != nil {
+			return err
+		}
+		for i, a := range full {
+			var genres []string
+			if a != nil {
+				genres = a.Genres
+			}
+			s.genres[chunk[i]] = genres
+		}
+	}
+	trackIDs, _ = spotify.DedupeIDs(trackIDs)
+	for _, chunk := range spotify.ChunkIDs(trackIDs, 100) {
+		features, err := s.Client.GetAudioFeatures(chunk...)
+		if err != nil {
+			return err
+		}
+		for i, f := range features {
+			s.features[chunk[i]] = f
+		}
+	}
+	albums, _ = spotify.DedupeIDs(albums)
+	for _, chunk := range spotify.ChunkIDs(albums, 20) {
+		full, err := s.Client.GetAlbums(chunk...)
+		if err != nil {
+			return err
+		}
+		for i, a := range full {
+			year := 0
+			if a != nil && a.ReleaseDate != "" {
+				year = a.ReleaseDateTime().Year()
+			}
+			s.years[chunk[i]] = year
+		}
+	}
+
+	for _, t := range tracks {
+		t.Genres = nil
+		for _, a := range t.Artists {
+			t.Genres = append(t.Genres, s.genres[a.ID]...)
+		}
+		t.Features = s.features[t.ID]
+		if t.Album != nil {
+			t.ReleaseYear = s.years[t.Album.ID]
+		}
+	}
+	return nil
+}
+
+// Select returns the IDs of the tracks that match the rule, in the same
+// order as tracks, up to the limit.
+func (s *SmartPlaylist) Select(tracks []*Track) []spotify.ID {
+	now := s.clock().Now()
+	var ids []spotify.ID
+	for _, t := range tracks {
+		if s.Limit > 0 && len(ids) == s.Limit {
+			break
+		}
+		if s.Rule.Match(t, now) {
+			ids = append(ids, t.ID)
+		}
+	}
+	ids, _ = spotify.DedupeIDs(ids)
+	return ids
+}
+
+// Sync updates the playlist to contain exactly the saved tracks that
+// match the rule.  Tracks that no longer match are removed and new matches
+// are appended, so tracks that stay in the playlist keep their position.
+// It requires the ScopeUserLibraryRead scope, and ScopePlaylistModifyPublic
+// or ScopePlaylistModifyPrivate.
+func (s *SmartPlaylist) Sync() (*Result, error) {
+	tracks, err := s.Library()
+	if err != nil {
+		return nil, err
+	}
+	want := s.Select(tracks)
+	have, err := s.playlistTracks()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[spotify.ID]bool, len(want))
+	for _, id := range want {
+		wanted[id] = true
+	}
+	present := make(map[spotify.ID]bool, len(have))
+	result := &Result{}
+	for _, id := range have {
+		if !present[id] && !wanted[id] {
+			result.Removed = append(result.Removed, id)
+		}
+		present[id] = true
+	}
+	for _, id := range want {
+		if !present[id] {
+			result.Added = append(result.Added, id)
+		}
+	}
+
+	for _, chunk := range spotify.ChunkIDs(result.Removed, 100) {
+		if _, err := s.Client.RemoveTracksFromPlaylist(s.OwnerID, s.PlaylistID, chunk...); err != nil {
+			return nil, err
+		}
+	}
+	for _, chunk := range spotify.ChunkIDs(result.Added, 100) {
+		if _, err := s.Client.AddTracksToPlaylist(s.OwnerID, s.PlaylistID, chunk...); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *SmartPlaylist) playlistTracks() ([]spotify.ID, error) {
+	var ids []spotify.ID
+	limit, offset := 100, 0
+	for {
+		page, err := s.Client.GetPlaylistTracksOpt(s.OwnerID, s.PlaylistID, &spotify.Options{Limit: &limit, Offset: &offset}, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range page.Tracks {
+			if t.Track.ID != "" {
+				ids = append(ids, t.Track.ID)
+			}
+		}
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return ids, nil
+		}
+	}
+}
+
+// Run syncs the playlist every interval, until stop is closed.  Errors are
+// passed to onError, if it isn't nil.  On App Engine, call Sync from a cron
+// or task queue handler instead.
+func (s *SmartPlaylist) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	for {
+		if _, err := s.Sync(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-s.clock().After(interval):
+		}
+	}
+}
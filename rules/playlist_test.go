@@ -0,0 +1,110 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+// metadataStub serves the artist, audio feature and album lookups that the
+// fake server doesn't support, and counts them.
+type metadataStub struct {
+	spotify.SpotifyClient
+	lookups int
+}
+
+func (m *metadataStub) GetArtists(ids ...spotify.ID) ([]*spotify.FullArtist, error) {
+	m.lookups++
+	artists := make([]*spotify.FullArtist, len(ids))
+	for i, id := range ids {
+		artists[i] = &spotify.FullArtist{Genres: []string{string(id) + " rock"}}
+	}
+	return artists, nil
+}
+
+func (m *metadataStub) GetAudioFeatures(ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	m.lookups++
+	features := make([]*spotify.AudioFeatures, len(ids))
+	for i, id := range ids {
+		features[i] = &spotify.AudioFeatures{ID: id, Energy: 0.9}
+	}
+	return features, nil
+}
+
+func (m *metadataStub) GetAlbums(ids ...spotify.ID) ([]*spotify.FullAlbum, error) {
+	m.lookups++
+	albums := make([]*spotify.FullAlbum, len(ids))
+	for i := range ids {
+		albums[i] = new(spotify.FullAlbum)
+		albums[i].ReleaseDate = "1999-03-01"
+		albums[i].ReleaseDatePrecision = "day"
+	}
+	return albums, nil
+}
+
+func addTrack(f *spotifytest.Fake, id, artist string) {
+	var t spotify.FullTrack
+	t.ID = spotify.ID(id)
+	t.URI = spotify.BuildURI(spotify.ItemTypeTrack, t.ID)
+	t.Artists = []spotify.SimpleArtist{{ID: spotify.ID(artist), Name: artist}}
+	t.Album = &spotify.SimpleAlbum{ID: "album"}
+	f.AddTrack(t)
+}
+
+func TestSmartPlaylistSync(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	addTrack(f, "4iV5W9uYEdYUVa79Axb7Rh", "indie")
+	addTrack(f, "1301WleyT98MSxVHPZCA6M", "jazz")
+	addTrack(f, "6rqhFgbbKwnb9MLmUQDhG6", "indie")
+	c := f.NewClient()
+	client := &metadataStub{SpotifyClient: &c}
+
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Indie", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a track that doesn't belong, added by hand
+	if _, err := c.AddTracksToPlaylist(spotifytest.UserID, pl.ID, "1301WleyT98MSxVHPZCA6M"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddTracksToLibrary("4iV5W9uYEdYUVa79Axb7Rh", "1301WleyT98MSxVHPZCA6M"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &SmartPlaylist{
+		Client:     client,
+		Rule:       All(Genre("indie"), Between(Energy, 0.5, 1), ReleasedBetween(1990, 1999)),
+		OwnerID:    spotifytest.UserID,
+		PlaylistID: pl.ID,
+		Clock:      spotifytest.NewFakeClock(time.Now()),
+	}
+	r, err := s.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Added) != 1 || len(r.Removed) != 1 || r.Removed[0] != "1301WleyT98MSxVHPZCA6M" {
+		t.Errorf("Unexpected result %+v\n", r)
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 1 || ids[0] != "4iV5W9uYEdYUVa79Axb7Rh" {
+		t.Error("Unexpected playlist:", ids)
+	}
+
+	// a newly saved track is added, and only its metadata is looked up
+	if err := c.AddTracksToLibrary("6rqhFgbbKwnb9MLmUQDhG6"); err != nil {
+		t.Fatal(err)
+	}
+	lookups := client.lookups
+	r, err = s.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Added) != 1 || r.Added[0] != "6rqhFgbbKwnb9MLmUQDhG6" || len(r.Removed) != 0 {
+		t.Errorf("Unexpected result %+v\n", r)
+	}
+	if client.lookups-lookups != 1 {
+		t.Errorf("Expected 1 metadata lookup, got %d\n", client.lookups-lookups)
+	}
+}
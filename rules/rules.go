@@ -0,0 +1,150 @@
+// Package rules builds "smart" playlists: playlists whose tracks are the
+// tracks in the user's library that satisfy a set of rules, such as "rock
+// released before 1980 that was saved in the last month".
+package rules
+
+import (
+	"strings"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Track is a saved track along with the metadata rules are evaluated
+// against.
+type Track struct {
+	spotify.FullTrack
+	// AddedAt is when the track was saved to the library.
+	AddedAt time.Time
+	// Genres are the genres of the track's artists.
+	Genres []string
+	// Features are the track's audio features, or nil if Spotify has none.
+	Features *spotify.AudioFeatures
+	// ReleaseYear is the year the track's album was released.
+	ReleaseYear int
+}
+
+// Rule decides whether a track belongs in a playlist.  Now is the time
+// the rule is being evaluated at, for rules relative to the current time.
+type Rule interface {
+	Match(t *Track, now time.Time) bool
+}
+
+// RuleFunc adapts a function to the Rule interface.
+type RuleFunc func(t *Track, now time.Time) bool
+
+// Match calls f(t, now).
+func (f RuleFunc) Match(t *Track, now time.Time) bool {
+	return f(t, now)
+}
+
+// All matches tracks that match every one of the rules.
+func All(rules ...Rule) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		for _, r := range rules {
+			if !r.Match(t, now) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Any matches tracks that match at least one of the rules.
+func Any(rules ...Rule) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		for _, r := range rules {
+			if r.Match(t, now) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not matches tracks that don't match r.
+func Not(r Rule) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		return !r.Match(t, now)
+	})
+}
+
+// Genre matches tracks by an artist in any of the genres.  Genres match
+// case insensitively, and a genre matches any more specific genre that
+// contains it, so "rock" matches "indie rock" and "rock-and-roll".
+func Genre(genres ...string) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		for _, have := range t.Genres {
+			have = strings.ToLower(have)
+			for _, want := range genres {
+				if strings.Contains(have, strings.ToLower(want)) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// Feature extracts a value from a track's audio features.
+type Feature func(f *spotify.AudioFeatures) float64
+
+// The audio features rules can be written against.
+var (
+	Acousticness     Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Acousticness) }
+	Danceability     Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Danceability) }
+	Energy           Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Energy) }
+	Instrumentalness Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Instrumentalness) }
+	Liveness         Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Liveness) }
+	Loudness         Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Loudness) }
+	Speechiness      Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Speechiness) }
+	Tempo            Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Tempo) }
+	Valence          Feature = func(f *spotify.AudioFeatures) float64 { return float64(f.Valence) }
+)
+
+// Between matches tracks whose feature is between min and max, inclusive.
+// Tracks without audio features never match.
+func Between(f Feature, min, max float64) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		if t.Features == nil {
+			return false
+		}
+		v := f(t.Features)
+		return v >= min && v <= max
+	})
+}
+
+// ReleasedBetween matches tracks released between the years from and to,
+// inclusive.  A zero year is unbounded.
+func ReleasedBetween(from, to int) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		if t.ReleaseYear == 0 {
+			return false
+		}
+		return (from == 0 || t.ReleaseYear >= from) && (to == 0 || t.ReleaseYear <= to)
+	})
+}
+
+// Popularity matches tracks whose popularity (0 to 100) is between min and
+// max, inclusive.
+func Popularity(min, max spotify.Popularity) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		return t.Popularity >= min && t.Popularity <= max
+	})
+}
+
+// AddedWithin matches tracks saved within d of the time the rule is
+// evaluated, for example "saved in the last 30 days".
+func AddedWithin(d time.Duration) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		return !t.AddedAt.Before(now.Add(-d))
+	})
+}
+
+// AddedBetween matches tracks saved between from and to.  A zero time is
+// unbounded.
+func AddedBetween(from, to time.Time) Rule {
+	return RuleFunc(func(t *Track, now time.Time) bool {
+		return (from.IsZero() || !t.AddedAt.Before(from)) && (to.IsZero() || !t.AddedAt.After(to))
+	})
+}
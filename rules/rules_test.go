@@ -0,0 +1,52 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func TestRules(t *testing.T) {
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	track := &Track{
+		AddedAt:     now.Add(-48 * time.Hour),
+		Genres:      []string{"Indie Rock", "dream pop"},
+		Features:    &spotify.AudioFeatures{Energy: 0.8, Tempo: 120},
+		ReleaseYear: 1979,
+	}
+	track.Popularity = 40
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"genre", Genre("rock"), true},
+		{"other genre", Genre("jazz", "hip hop"), false},
+		{"feature", Between(Energy, 0.7, 1), true},
+		{"feature out of range", Between(Tempo, 60, 100), false},
+		{"released", ReleasedBetween(0, 1980), true},
+		{"released later", ReleasedBetween(1980, 0), false},
+		{"popularity", Popularity(30, 50), true},
+		{"added within", AddedWithin(72 * time.Hour), true},
+		{"added too long ago", AddedWithin(24 * time.Hour), false},
+		{"added between", AddedBetween(now.Add(-72*time.Hour), time.Time{}), true},
+		{"all", All(Genre("pop"), Popularity(0, 50)), true},
+		{"all fails", All(Genre("pop"), Popularity(50, 100)), false},
+		{"any", Any(Genre("jazz"), Popularity(0, 50)), true},
+		{"not", Not(Genre("jazz")), true},
+	}
+	for _, test := range tests {
+		if got := test.rule.Match(track, now); got != test.want {
+			t.Errorf("%s: got %v, want %v\n", test.name, got, test.want)
+		}
+	}
+
+	if Between(Energy, 0, 1).Match(&Track{}, now) {
+		t.Error("A track without audio features shouldn't match a feature rule")
+	}
+	if ReleasedBetween(0, 0).Match(&Track{}, now) {
+		t.Error("A track without a release year shouldn't match a release rule")
+	}
+}
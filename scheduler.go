@@ -0,0 +1,153 @@
+package spotify
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Priority tells a Scheduler how urgent a request is.
+type Priority int
+
+// Priorities of requests.
+const (
+	// Interactive requests are made for a user who is waiting for the
+	// result, such as showing what is playing now.  They are never held
+	// back.
+	Interactive Priority = iota
+	// Background requests are made by batch jobs, such as syncing
+	// libraries or analyzing playlists.  They are held back while the Web
+	// API is limiting the rate of requests.
+	Background
+)
+
+// DefaultCooldown is how long a Scheduler holds back background requests
+// after the Retry-After time of a 429 Too Many Requests response, if its
+// Cooldown is zero.
+const DefaultCooldown = 5 * time.Second
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx that gives the requests made with it
+// priority p, overriding the priority of the client's SchedulerTransport.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set by WithPriority, if any.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	return p, ok
+}
+
+// Scheduler watches the responses to requests for signs of rate limiting,
+// and holds back background requests while the Web API is limiting the
+// rate, so that the requests users are waiting for get through first.
+// Spotify limits the rate for each client ID, so all clients for a client
+// ID in a process should share one Scheduler.  It is safe for concurrent
+// use.
+type Scheduler struct {
+	// Cooldown is how long background requests are held back after the
+	// Retry-After time of a 429 Too Many Requests response, giving
+	// interactive requests a head start.  If zero, DefaultCooldown is
+	// used.
+	Cooldown time.Duration
+	// Clock is used to hold back requests.  If nil, SystemClock is used.
+	Clock Clock
+
+	mu sync.Mutex
+	// until is when background requests may be sent again.
+	until time.Time
+}
+
+func (s *Scheduler) clock() Clock {
+	if s.Clock == nil {
+		return SystemClock
+	}
+	return s.Clock
+}
+
+// Limited reports whether background requests are being held back.
+func (s *Scheduler) Limited() bool {
+	return s.wait() > 0
+}
+
+// wait returns how long background requests must wait.
+func (s *Scheduler) wait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.until.Sub(s.clock().Now())
+}
+
+// observe records the rate limiting signalled by resp.
+func (s *Scheduler) observe(resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	cooldown := s.Cooldown
+	if cooldown == 0 {
+		cooldown = DefaultCooldown
+	}
+	retry, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+	until := s.clock().Now().Add(time.Duration(retry)*time.Second + cooldown)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until.After(s.until) {
+		s.until = until
+	}
+}
+
+// WithScheduler sends the client's requests through s, with priority p
+// unless the request's context sets another (see WithPriority).  Use
+// Background for the clients of batch jobs.  It should come after
+// WithCache, so that cached responses aren't held back.
+func WithScheduler(s *Scheduler, p Priority) ClientOption {
+	return func(c *Client) {
+		hc := *c.http
+		hc.Transport = &SchedulerTransport{Base: hc.Transport, Scheduler: s, Priority: p}
+		c.http = &hc
+	}
+}
+
+// SchedulerTransport is an http.RoundTripper that sends requests through a
+// Scheduler.  Background requests wait while the Scheduler is holding them
+// back, or until their context is done.
+type SchedulerTransport struct {
+	// Base is used to send the requests.  If nil, http.DefaultTransport is
+	// used.
+	Base      http.RoundTripper
+	Scheduler *Scheduler
+	// Priority is the priority of requests whose context doesn't set one.
+	Priority Priority
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *SchedulerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	p, ok := PriorityFromContext(req.Context())
+	if !ok {
+		p = t.Priority
+	}
+	for p == Background {
+		d := t.Scheduler.wait()
+		if d <= 0 {
+			break
+		}
+		select {
+		case <-t.Scheduler.clock().After(d):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil {
+		t.Scheduler.observe(resp)
+	}
+	return resp, err
+}
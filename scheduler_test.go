@@ -0,0 +1,76 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestScheduler(t *testing.T) {
+	limited := true
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if limited {
+			limited = false
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": {"2"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"status": 429, "message": "API rate limit exceeded"}}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "wizzler"}`)),
+		}, nil
+	})
+	clock := &sleepingClock{now: time.Unix(1000, 0)}
+	s := &Scheduler{Clock: clock}
+	interactive := NewClient(&http.Client{Transport: tr}, WithScheduler(s, Interactive))
+	background := NewClient(&http.Client{Transport: tr}, WithScheduler(s, Background))
+
+	if _, err := background.CurrentUser(); err == nil {
+		t.Fatal("Expected an error for 429 Too Many Requests")
+	}
+	if !s.Limited() {
+		t.Error("Expected the scheduler to hold back background requests")
+	}
+	if _, err := interactive.CurrentUser(); err != nil {
+		t.Fatal(err)
+	}
+	if clock.slept != 0 {
+		t.Errorf("Interactive request waited %v\n", clock.slept)
+	}
+	if _, err := background.CurrentUser(); err != nil {
+		t.Fatal(err)
+	}
+	if want := 2*time.Second + DefaultCooldown; clock.slept != want {
+		t.Errorf("Background request waited %v, want %v\n", clock.slept, want)
+	}
+	if s.Limited() {
+		t.Error("Expected the scheduler to stop holding back requests")
+	}
+}
+
+func TestSchedulerContext(t *testing.T) {
+	tr := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+		}, nil
+	})
+	clock := &sleepingClock{now: time.Unix(1000, 0)}
+	s := &Scheduler{Clock: clock}
+	s.until = clock.now.Add(time.Minute)
+	c := NewClient(&http.Client{Transport: tr}, WithScheduler(s, Interactive))
+
+	ctx := WithPriority(context.Background(), Background)
+	if _, err := c.CurrentUserTopTracksWithContext(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if clock.slept != time.Minute {
+		t.Errorf("Background request waited %v, want 1m\n", clock.slept)
+	}
+}
@@ -0,0 +1,119 @@
+package scrobble
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// LastFMURL is the Last.fm API endpoint.
+const LastFMURL = "https://ws.audioscrobbler.com/2.0/"
+
+// maxLastFMBatch is the most scrobbles Last.fm accepts in one request.
+const maxLastFMBatch = 50
+
+// LastFMParams returns the parameters of track.scrobble requests for the
+// scrobbles, in batches of up to 50.  The parameters don't include the
+// method, API key, session key or signature, which LastFMSubmitter adds.
+func LastFMParams(scrobbles []Scrobble) []url.Values {
+	var batches []url.Values
+	for start := 0; start < len(scrobbles); start += maxLastFMBatch {
+		end := start + maxLastFMBatch
+		if end > len(scrobbles) {
+			end = len(scrobbles)
+		}
+		v := url.Values{}
+		for i, s := range scrobbles[start:end] {
+			idx := "[" + strconv.Itoa(i) + "]"
+			v.Set("artist"+idx, s.Artist)
+			v.Set("track"+idx, s.Track)
+			v.Set("timestamp"+idx, strconv.FormatInt(s.Timestamp.Unix(), 10))
+			if s.Album != "" {
+				v.Set("album"+idx, s.Album)
+			}
+			if s.TrackNumber > 0 {
+				v.Set("trackNumber"+idx, strconv.Itoa(s.TrackNumber))
+			}
+			v.Set("duration"+idx, strconv.Itoa(int(s.Duration.Seconds())))
+		}
+		batches = append(batches, v)
+	}
+	return batches
+}
+
+// LastFMSubmitter submits scrobbles to Last.fm on behalf of a user who has
+// authorized the application.
+type LastFMSubmitter struct {
+	APIKey string
+	Secret string
+	// SessionKey identifies the user's session, obtained through the
+	// Last.fm authentication flow.
+	SessionKey string
+	// HTTPClient is used to make requests.  If nil, http.DefaultClient is
+	// used.  On App Engine, use a urlfetch client.
+	HTTPClient *http.Client
+	// URL overrides LastFMURL.
+	URL string
+}
+
+// sign adds the Last.fm API signature to v.
+func (l *LastFMSubmitter) sign(v url.Values) {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteString(v.Get(k))
+	}
+	b.WriteString(l.Secret)
+	sum := md5.Sum([]byte(b.String()))
+	v.Set("api_sig", hex.EncodeToString(sum[:]))
+}
+
+// Submit sends the scrobbles, in batches if necessary.
+func (l *LastFMSubmitter) Submit(scrobbles []Scrobble) error {
+	hc := l.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	endpoint := l.URL
+	if endpoint == "" {
+		endpoint = LastFMURL
+	}
+	for _, v := range LastFMParams(scrobbles) {
+		v.Set("method", "track.scrobble")
+		v.Set("api_key", l.APIKey)
+		v.Set("sk", l.SessionKey)
+		l.sign(v)
+		v.Set("format", "json")
+		resp, err := hc.PostForm(endpoint, v)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Error   int    `json:"error"`
+			Message string `json:"message"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if result.Error != 0 {
+			return fmt.Errorf("scrobble: Last.fm error %d: %s", result.Error, result.Message)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("scrobble: Last.fm returned %s", resp.Status)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
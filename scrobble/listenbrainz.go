@@ -0,0 +1,113 @@
+package scrobble
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// ListenBrainzURL is the ListenBrainz submission endpoint.
+const ListenBrainzURL = "https://api.listenbrainz.org/1/submit-listens"
+
+// maxListens is the most listens ListenBrainz accepts in one submission.
+const maxListens = 1000
+
+type listenBrainzSubmission struct {
+	ListenType string               `json:"listen_type"`
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+type listenBrainzListen struct {
+	ListenedAt    int64                `json:"listened_at"`
+	TrackMetadata listenBrainzMetadata `json:"track_metadata"`
+}
+
+type listenBrainzMetadata struct {
+	ArtistName     string                 `json:"artist_name"`
+	TrackName      string                 `json:"track_name"`
+	ReleaseName    string                 `json:"release_name,omitempty"`
+	AdditionalInfo map[string]interface{} `json:"additional_info,omitempty"`
+}
+
+// ListenBrainzPayload returns the JSON body of a ListenBrainz "import"
+// submission containing the scrobbles.
+func ListenBrainzPayload(scrobbles []Scrobble) ([]byte, error) {
+	sub := listenBrainzSubmission{ListenType: "import"}
+	if len(scrobbles) == 1 {
+		sub.ListenType = "single"
+	}
+	for _, s := range scrobbles {
+		info := map[string]interface{}{
+			"duration_ms":   int64(s.Duration.Seconds() * 1000),
+			"music_service": "spotify.com",
+		}
+		if s.SpotifyID != "" {
+			info["spotify_id"] = "https://open.spotify.com/track/" + string(s.SpotifyID)
+		}
+		if s.TrackNumber > 0 {
+			info["tracknumber"] = s.TrackNumber
+		}
+		sub.Payload = append(sub.Payload, listenBrainzListen{
+			ListenedAt: s.Timestamp.Unix(),
+			TrackMetadata: listenBrainzMetadata{
+				ArtistName:     s.Artist,
+				TrackName:      s.Track,
+				ReleaseName:    s.Album,
+				AdditionalInfo: info,
+			},
+		})
+	}
+	return json.Marshal(sub)
+}
+
+// ListenBrainzSubmitter submits scrobbles to ListenBrainz.
+type ListenBrainzSubmitter struct {
+	// Token is the user's ListenBrainz user token.
+	Token string
+	// HTTPClient is used to make requests.  If nil, http.DefaultClient is
+	// used.  On App Engine, use a urlfetch client.
+	HTTPClient *http.Client
+	// URL overrides ListenBrainzURL.
+	URL string
+}
+
+// Submit sends the scrobbles, in batches if necessary.
+func (l *ListenBrainzSubmitter) Submit(scrobbles []Scrobble) error {
+	hc := l.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	endpoint := l.URL
+	if endpoint == "" {
+		endpoint = ListenBrainzURL
+	}
+	for len(scrobbles) > 0 {
+		n := len(scrobbles)
+		if n > maxListens {
+			n = maxListens
+		}
+		body, err := ListenBrainzPayload(scrobbles[:n])
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Token "+l.Token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := hc.Do(req)
+		if err != nil {
+			return err
+		}
+		msg, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("scrobble: ListenBrainz returned %s: %s", resp.Status, msg)
+		}
+		scrobbles = scrobbles[n:]
+	}
+	return nil
+}
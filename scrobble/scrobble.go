@@ -0,0 +1,81 @@
+// Package scrobble converts a user's Spotify listening history into
+// scrobbles for Last.fm and ListenBrainz, and can submit them.
+package scrobble
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// minDuration is the shortest track that may be scrobbled, according to
+// the Last.fm scrobbling rules.
+const minDuration = 30 * time.Second
+
+// Scrobble is a single listen.
+type Scrobble struct {
+	Artist      string
+	Track       string
+	Album       string
+	TrackNumber int
+	Duration    time.Duration
+	// Timestamp is when the track started playing.
+	Timestamp time.Time
+	// SpotifyID identifies the track on Spotify.
+	SpotifyID spotify.ID
+}
+
+// FromHistory converts play history items into scrobbles, oldest first.
+// Spotify records when a track finished playing, whereas scrobbles record
+// when it started, so each timestamp is adjusted by the track's duration.
+// Tracks shorter than 30 seconds aren't scrobbled, and repeated items (as
+// returned by overlapping calls to CurrentUserRecentTracks) are included only
+// once, so histories can be accumulated and converted together.
+func FromHistory(items ...spotify.HistoryItem) ([]Scrobble, error) {
+	seen := make(map[string]bool)
+	var scrobbles []Scrobble
+	for _, item := range items {
+		key := item.PlayedAt.Format(time.RFC3339Nano) + "|" + string(item.Track.ID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		d := time.Duration(item.Track.Duration) * time.Millisecond
+		if d < minDuration {
+			continue
+		}
+		playedAt := item.PlayedAt
+		if playedAt.IsZero() {
+			return nil, fmt.Errorf("scrobble: %s has no play time", item.Track.ID)
+		}
+		scrobbles = append(scrobbles, Scrobble{
+			Artist:      artistNames(item.Track.Artists),
+			Track:       item.Track.Name,
+			TrackNumber: item.Track.TrackNumber,
+			Duration:    d,
+			Timestamp:   playedAt.Add(-d),
+			SpotifyID:   item.Track.ID,
+		})
+	}
+	sort.Sort(byTimestamp(scrobbles))
+	return scrobbles, nil
+}
+
+// artistNames joins the names of a track's artists, the way Last.fm
+// displays tracks with several artists.
+func artistNames(artists []spotify.SimpleArtist) string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+type byTimestamp []Scrobble
+
+func (s byTimestamp) Len() int           { return len(s) }
+func (s byTimestamp) Less(i, j int) bool { return s[i].Timestamp.Before(s[j].Timestamp) }
+func (s byTimestamp) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,169 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func historyItem(id, name, playedAt string, durationMS int) spotify.HistoryItem {
+	var item spotify.HistoryItem
+	item.Track.ID = spotify.ID(id)
+	item.Track.Name = name
+	item.Track.Duration = durationMS
+	item.Track.TrackNumber = 3
+	item.Track.Artists = []spotify.SimpleArtist{{Name: "Simon"}, {Name: "Garfunkel"}}
+	// Timestamps that don't parse are left zero, as if they were missing.
+	item.PlayedAt, _ = time.Parse(time.RFC3339Nano, playedAt)
+	return item
+}
+
+func testScrobbles(t *testing.T) []Scrobble {
+	items := []spotify.HistoryItem{
+		historyItem("b", "Second", "2017-05-19T09:20:00.000Z", 180000),
+		historyItem("a", "First", "2017-05-19T09:13:23.631Z", 200000),
+		historyItem("c", "Jingle", "2017-05-19T09:21:00Z", 15000),
+		// overlapping history
+		historyItem("b", "Second", "2017-05-19T09:20:00.000Z", 180000),
+	}
+	scrobbles, err := FromHistory(items...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scrobbles
+}
+
+func TestFromHistory(t *testing.T) {
+	scrobbles := testScrobbles(t)
+	if len(scrobbles) != 2 {
+		t.Fatalf("Expected 2 scrobbles, got %d\n", len(scrobbles))
+	}
+	first := scrobbles[0]
+	if first.Track != "First" || first.Artist != "Simon, Garfunkel" || first.SpotifyID != "a" {
+		t.Errorf("Unexpected scrobble %+v\n", first)
+	}
+	want := time.Date(2017, 5, 19, 9, 10, 3, 631000000, time.UTC)
+	if !first.Timestamp.Equal(want) {
+		t.Errorf("Expected start time %v, got %v\n", want, first.Timestamp)
+	}
+	if scrobbles[1].Track != "Second" {
+		t.Error("Scrobbles aren't sorted by time")
+	}
+}
+
+func TestFromHistoryBadTimestamp(t *testing.T) {
+	_, err := FromHistory(historyItem("a", "First", "yesterday", 200000))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestListenBrainzPayload(t *testing.T) {
+	data, err := ListenBrainzPayload(testScrobbles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sub struct {
+		ListenType string `json:"listen_type"`
+		Payload    []struct {
+			ListenedAt    int64 `json:"listened_at"`
+			TrackMetadata struct {
+				ArtistName     string                 `json:"artist_name"`
+				TrackName      string                 `json:"track_name"`
+				AdditionalInfo map[string]interface{} `json:"additional_info"`
+			} `json:"track_metadata"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &sub); err != nil {
+		t.Fatal(err)
+	}
+	if sub.ListenType != "import" || len(sub.Payload) != 2 {
+		t.Fatalf("Unexpected submission %s\n", data)
+	}
+	p := sub.Payload[0]
+	if p.ListenedAt != 1495185003 || p.TrackMetadata.TrackName != "First" {
+		t.Errorf("Unexpected listen %+v\n", p)
+	}
+	if p.TrackMetadata.AdditionalInfo["spotify_id"] != "https://open.spotify.com/track/a" {
+		t.Error("Missing Spotify ID:", p.TrackMetadata.AdditionalInfo)
+	}
+}
+
+func TestLastFMParams(t *testing.T) {
+	scrobbles := make([]Scrobble, 51)
+	for i := range scrobbles {
+		scrobbles[i] = Scrobble{Artist: "Artist", Track: "Track", Timestamp: time.Unix(int64(i), 0), Duration: time.Minute}
+	}
+	batches := LastFMParams(scrobbles)
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d\n", len(batches))
+	}
+	if batches[0].Get("timestamp[49]") != "49" || batches[1].Get("timestamp[0]") != "50" {
+		t.Error("Unexpected timestamps")
+	}
+	if batches[0].Get("duration[0]") != "60" {
+		t.Error("Unexpected duration", batches[0].Get("duration[0]"))
+	}
+}
+
+func TestListenBrainzSubmitter(t *testing.T) {
+	var auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	s := &ListenBrainzSubmitter{Token: "secret", URL: srv.URL}
+	if err := s.Submit(testScrobbles(t)); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Token secret" {
+		t.Error("Unexpected Authorization header:", auth)
+	}
+	if body == "" {
+		t.Error("Expected a request body")
+	}
+}
+
+func TestLastFMSubmitter(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"scrobbles":{}}`))
+	}))
+	defer srv.Close()
+
+	s := &LastFMSubmitter{APIKey: "key", Secret: "secret", SessionKey: "session", URL: srv.URL}
+	if err := s.Submit(testScrobbles(t)); err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("method") != "track.scrobble" || form.Get("sk") != "session" {
+		t.Errorf("Unexpected form %v\n", form)
+	}
+	if len(form.Get("api_sig")) != 32 {
+		t.Error("Missing signature")
+	}
+}
+
+func TestLastFMSubmitterError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":9,"message":"Invalid session key"}`))
+	}))
+	defer srv.Close()
+
+	s := &LastFMSubmitter{URL: srv.URL}
+	if err := s.Submit(testScrobbles(t)); err == nil {
+		t.Error("Expected an error")
+	}
+}
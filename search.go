@@ -1,11 +1,9 @@
 package spotify
 
 import (
-	"encoding/json"
-	"net/http"
-	"net/url"
-	"strconv"
 	"strings"
+
+	"golang.org/x/net/context"
 )
 
 const (
@@ -28,6 +26,8 @@ const (
 	SearchTypeArtist              = 1 << iota
 	SearchTypePlaylist            = 1 << iota
 	SearchTypeTrack               = 1 << iota
+	SearchTypeShow                = 1 << iota
+	SearchTypeEpisode             = 1 << iota
 )
 
 func (st SearchType) encode() string {
@@ -44,6 +44,12 @@ func (st SearchType) encode() string {
 	if st&SearchTypeTrack != 0 {
 		types = append(types, "track")
 	}
+	if st&SearchTypeShow != 0 {
+		types = append(types, "show")
+	}
+	if st&SearchTypeEpisode != 0 {
+		types = append(types, "episode")
+	}
 	return strings.Join(types, ",")
 }
 
@@ -54,6 +60,8 @@ type SearchResult struct {
 	Albums    *SimpleAlbumPage    `json:"albums"`
 	Playlists *SimplePlaylistPage `json:"playlists"`
 	Tracks    *FullTrackPage      `json:"tracks"`
+	Shows     *SimpleShowPage     `json:"shows"`
+	Episodes  *SimpleEpisodePage  `json:"episodes"`
 }
 
 // Search is a wrapper around DefaultClient.Search.
@@ -67,7 +75,7 @@ func SearchOpt(query string, t SearchType, opt *Options) (*SearchResult, error)
 }
 
 // Search gets Spotify catalog information about artists, albums, tracks,
-// or playlists that match a keyword string.  t is a mask containing one or more
+// playlists, shows or episodes that match a keyword string.  t is a mask containing one or more
 // search types.  For example, `Search(query, SearchTypeArtist|SearchTypeAlbum)`
 // will search for artists or albums matching the specified keywords.
 //
@@ -114,7 +122,7 @@ func SearchOpt(query string, t SearchType, opt *Options) (*SearchResult, error)
 // Other possible field filters, depending on object types being searched,
 // include "genre", "upc", and "isrc".  For example "damian genre:reggae-pop".
 func (c *Client) Search(query string, t SearchType) (*SearchResult, error) {
-	return c.SearchOpt(query, t, nil)
+	return c.SearchWithContext(context.Background(), query, t, nil)
 }
 
 // SearchOpt works just like Search, but it accepts additional
@@ -122,105 +130,185 @@ func (c *Client) Search(query string, t SearchType) (*SearchResult, error) {
 // more information.
 //
 // If the Country field is specified in the options, then the results will only
-// contain artists, albums, and tracks playable in the specified country
-// (playlist results are not affected by the Country option).  Additionally,
+// contain artists, albums, tracks, shows and episodes playable in the specified
+// country (playlist results are not affected by the Country option).  The Web
+// API only returns shows and episodes that are available in the market, so
+// searches for them should give one (or use WithMarket).  Additionally,
 // the constant MarketFromToken can be used with authenticated clients.
 // If the client has a valid access token, then the results will only include
 // content playable in the user's country.
 func (c *Client) SearchOpt(query string, t SearchType, opt *Options) (*SearchResult, error) {
-	v := url.Values{}
-	v.Set("q", query)
-	v.Set("type", t.encode())
-	if opt != nil {
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Country != nil {
-			v.Set("market", *opt.Country)
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-	}
-	spotifyURL := baseAddress + "search?" + v.Encode()
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.SearchWithContext(context.Background(), query, t, opt)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+// SearchWithContext is like SearchOpt, with a context.
+func (c *Client) SearchWithContext(ctx context.Context, query string, t SearchType, opt *Options) (*SearchResult, error) {
+	e := c.endpoint("search")
+	e.set("q", query)
+	e.set("type", t.encode())
+	e.setPaging(opt)
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
 	}
-
 	var result SearchResult
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.getPage(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
-	return &result, err
+	return &result, nil
 }
 
 // NextArtistResults loads the next page of artists into the specified search result.
 func (c *Client) NextArtistResults(s *SearchResult) error {
+	return c.NextArtistResultsWithContext(context.Background(), s)
+}
+
+// NextArtistResultsWithContext is like NextArtistResults, with a context.
+func (c *Client) NextArtistResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Artists == nil || s.Artists.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Artists.Next, s)
+	return c.getPage(ctx, s.Artists.Next, s)
 }
 
 // PreviousArtistResults loads the previous page of artists into the specified search result.
 func (c *Client) PreviousArtistResults(s *SearchResult) error {
+	return c.PreviousArtistResultsWithContext(context.Background(), s)
+}
+
+// PreviousArtistResultsWithContext is like PreviousArtistResults, with a context.
+func (c *Client) PreviousArtistResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Artists == nil || s.Artists.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Artists.Previous, s)
+	return c.getPage(ctx, s.Artists.Previous, s)
 }
 
 // NextAlbumResults loads the next page of albums into the specified search result.
 func (c *Client) NextAlbumResults(s *SearchResult) error {
+	return c.NextAlbumResultsWithContext(context.Background(), s)
+}
+
+// NextAlbumResultsWithContext is like NextAlbumResults, with a context.
+func (c *Client) NextAlbumResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Albums == nil || s.Albums.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Albums.Next, s)
+	return c.getPage(ctx, s.Albums.Next, s)
 }
 
 // PreviousAlbumResults loads the previous page of albums into the specified search result.
 func (c *Client) PreviousAlbumResults(s *SearchResult) error {
+	return c.PreviousAlbumResultsWithContext(context.Background(), s)
+}
+
+// PreviousAlbumResultsWithContext is like PreviousAlbumResults, with a context.
+func (c *Client) PreviousAlbumResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Albums == nil || s.Albums.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Albums.Previous, s)
+	return c.getPage(ctx, s.Albums.Previous, s)
 }
 
 // NextPlaylistResults loads the next page of playlists into the specified search result.
 func (c *Client) NextPlaylistResults(s *SearchResult) error {
+	return c.NextPlaylistResultsWithContext(context.Background(), s)
+}
+
+// NextPlaylistResultsWithContext is like NextPlaylistResults, with a context.
+func (c *Client) NextPlaylistResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Playlists == nil || s.Playlists.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Playlists.Next, s)
+	return c.getPage(ctx, s.Playlists.Next, s)
 }
 
 // PreviousPlaylistResults loads the previous page of playlists into the specified search result.
 func (c *Client) PreviousPlaylistResults(s *SearchResult) error {
+	return c.PreviousPlaylistResultsWithContext(context.Background(), s)
+}
+
+// PreviousPlaylistResultsWithContext is like PreviousPlaylistResults, with a context.
+func (c *Client) PreviousPlaylistResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Playlists == nil || s.Playlists.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Playlists.Previous, s)
+	return c.getPage(ctx, s.Playlists.Previous, s)
 }
 
 // PreviousTrackResults loads the previous page of tracks into the specified search result.
 func (c *Client) PreviousTrackResults(s *SearchResult) error {
+	return c.PreviousTrackResultsWithContext(context.Background(), s)
+}
+
+// PreviousTrackResultsWithContext is like PreviousTrackResults, with a context.
+func (c *Client) PreviousTrackResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Tracks == nil || s.Tracks.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Tracks.Previous, s)
+	return c.getPage(ctx, s.Tracks.Previous, s)
 }
 
 // NextTrackResults loads the next page of tracks into the specified search result.
 func (c *Client) NextTrackResults(s *SearchResult) error {
+	return c.NextTrackResultsWithContext(context.Background(), s)
+}
+
+// NextTrackResultsWithContext is like NextTrackResults, with a context.
+func (c *Client) NextTrackResultsWithContext(ctx context.Context, s *SearchResult) error {
 	if s.Tracks == nil || s.Tracks.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.getPage(s.Tracks.Next, s)
+	return c.getPage(ctx, s.Tracks.Next, s)
+}
+
+// NextShowResults loads the next page of shows into the specified search result.
+func (c *Client) NextShowResults(s *SearchResult) error {
+	return c.NextShowResultsWithContext(context.Background(), s)
+}
+
+// NextShowResultsWithContext is like NextShowResults, with a context.
+func (c *Client) NextShowResultsWithContext(ctx context.Context, s *SearchResult) error {
+	if s.Shows == nil || s.Shows.Next == "" {
+		return ErrNoMorePages
+	}
+	return c.getPage(ctx, s.Shows.Next, s)
+}
+
+// PreviousShowResults loads the previous page of shows into the specified search result.
+func (c *Client) PreviousShowResults(s *SearchResult) error {
+	return c.PreviousShowResultsWithContext(context.Background(), s)
+}
+
+// PreviousShowResultsWithContext is like PreviousShowResults, with a context.
+func (c *Client) PreviousShowResultsWithContext(ctx context.Context, s *SearchResult) error {
+	if s.Shows == nil || s.Shows.Previous == "" {
+		return ErrNoMorePages
+	}
+	return c.getPage(ctx, s.Shows.Previous, s)
+}
+
+// NextEpisodeResults loads the next page of episodes into the specified search result.
+func (c *Client) NextEpisodeResults(s *SearchResult) error {
+	return c.NextEpisodeResultsWithContext(context.Background(), s)
+}
+
+// NextEpisodeResultsWithContext is like NextEpisodeResults, with a context.
+func (c *Client) NextEpisodeResultsWithContext(ctx context.Context, s *SearchResult) error {
+	if s.Episodes == nil || s.Episodes.Next == "" {
+		return ErrNoMorePages
+	}
+	return c.getPage(ctx, s.Episodes.Next, s)
+}
+
+// PreviousEpisodeResults loads the previous page of episodes into the specified search result.
+func (c *Client) PreviousEpisodeResults(s *SearchResult) error {
+	return c.PreviousEpisodeResultsWithContext(context.Background(), s)
+}
+
+// PreviousEpisodeResultsWithContext is like PreviousEpisodeResults, with a context.
+func (c *Client) PreviousEpisodeResultsWithContext(ctx context.Context, s *SearchResult) error {
+	if s.Episodes == nil || s.Episodes.Previous == "" {
+		return ErrNoMorePages
+	}
+	return c.getPage(ctx, s.Episodes.Previous, s)
 }
@@ -100,22 +100,50 @@ func TestSearchPlaylistTrack(t *testing.T) {
 	}
 }
 
+func TestSearchShowsAndEpisodes(t *testing.T) {
+	client := testClientString(http.StatusOK, `{
+		"shows": {"items": [{"id": "38bS44xjbVVZ3No3ByF1dJ", "name": "Vetenskapsradion Historia", "publisher": "Sveriges Radio", "type": "show"}], "total": 1},
+		"episodes": {"items": [{"id": "512ojhOuo1ktJprKbVcKyQ", "name": "Tredje rikets knarkande granskas", "duration_ms": 1502795, "release_date": "2015-10-01", "release_date_precision": "day", "type": "episode"}], "total": 1}
+	}`)
+	country := "SE"
+	result, err := client.SearchOpt("historia", SearchTypeShow|SearchTypeEpisode, &Options{Country: &country})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := getLastRequest(client).URL.Query(); q.Get("type") != "show,episode" || q.Get("market") != "SE" {
+		t.Errorf("Got query %v\n", q)
+	}
+	if result.Tracks != nil || result.Shows == nil || result.Episodes == nil {
+		t.Fatalf("Got %+v\n", result)
+	}
+	if s := result.Shows.Shows[0]; s.Publisher != "Sveriges Radio" {
+		t.Errorf("Got show %+v\n", s)
+	}
+	if e := result.Episodes.Episodes[0]; e.Duration != 1502795 || e.ReleaseDatePrecision != PrecisionDay {
+		t.Errorf("Got episode %+v\n", e)
+	}
+}
+
 func TestPrevNextSearchPageErrors(t *testing.T) {
 	// we expect to get ErrNoMorePages when trying to get the prev/next page
 	// under either of these conditions:
 
 	//  1) there are no results (nil)
-	nilResults := &SearchResult{nil, nil, nil, nil}
+	nilResults := &SearchResult{}
 	if DefaultClient.NextAlbumResults(nilResults) != ErrNoMorePages ||
 		DefaultClient.NextArtistResults(nilResults) != ErrNoMorePages ||
 		DefaultClient.NextPlaylistResults(nilResults) != ErrNoMorePages ||
-		DefaultClient.NextTrackResults(nilResults) != ErrNoMorePages {
+		DefaultClient.NextTrackResults(nilResults) != ErrNoMorePages ||
+		DefaultClient.NextShowResults(nilResults) != ErrNoMorePages ||
+		DefaultClient.NextEpisodeResults(nilResults) != ErrNoMorePages {
 		t.Error("Next search result page should have failed for nil results")
 	}
 	if DefaultClient.PreviousAlbumResults(nilResults) != ErrNoMorePages ||
 		DefaultClient.PreviousArtistResults(nilResults) != ErrNoMorePages ||
 		DefaultClient.PreviousPlaylistResults(nilResults) != ErrNoMorePages ||
-		DefaultClient.PreviousTrackResults(nilResults) != ErrNoMorePages {
+		DefaultClient.PreviousTrackResults(nilResults) != ErrNoMorePages ||
+		DefaultClient.PreviousShowResults(nilResults) != ErrNoMorePages ||
+		DefaultClient.PreviousEpisodeResults(nilResults) != ErrNoMorePages {
 		t.Error("Previous search result page should have failed for nil results")
 	}
 	//  2) the prev/next URL is empty
@@ -124,17 +152,23 @@ func TestPrevNextSearchPageErrors(t *testing.T) {
 		Albums:    new(SimpleAlbumPage),
 		Playlists: new(SimplePlaylistPage),
 		Tracks:    new(FullTrackPage),
+		Shows:     new(SimpleShowPage),
+		Episodes:  new(SimpleEpisodePage),
 	}
 	if DefaultClient.NextAlbumResults(emptyURL) != ErrNoMorePages ||
 		DefaultClient.NextArtistResults(emptyURL) != ErrNoMorePages ||
 		DefaultClient.NextPlaylistResults(emptyURL) != ErrNoMorePages ||
-		DefaultClient.NextTrackResults(emptyURL) != ErrNoMorePages {
+		DefaultClient.NextTrackResults(emptyURL) != ErrNoMorePages ||
+		DefaultClient.NextShowResults(emptyURL) != ErrNoMorePages ||
+		DefaultClient.NextEpisodeResults(emptyURL) != ErrNoMorePages {
 		t.Error("Next search result page should have failed with empty URL")
 	}
 	if DefaultClient.PreviousAlbumResults(emptyURL) != ErrNoMorePages ||
 		DefaultClient.PreviousArtistResults(emptyURL) != ErrNoMorePages ||
 		DefaultClient.PreviousPlaylistResults(emptyURL) != ErrNoMorePages ||
-		DefaultClient.PreviousTrackResults(emptyURL) != ErrNoMorePages {
+		DefaultClient.PreviousTrackResults(emptyURL) != ErrNoMorePages ||
+		DefaultClient.PreviousShowResults(emptyURL) != ErrNoMorePages ||
+		DefaultClient.PreviousEpisodeResults(emptyURL) != ErrNoMorePages {
 		t.Error("Previous search result page should have failed with empty URL")
 	}
 }
@@ -164,3 +198,37 @@ func TestSearchAgainstAPI(t *testing.T) {
 		t.Error("Failed to get previous page")
 	}
 }
+
+func TestSearchPaginationLimit(t *testing.T) {
+	client := testClientString(http.StatusOK, "{}")
+	offset := 1000
+	if _, err := client.SearchOpt("abba", SearchTypeTrack, &Options{Offset: &offset}); err != ErrPaginationLimit {
+		t.Errorf("Expected ErrPaginationLimit, got %v\n", err)
+	}
+	result := &SearchResult{Tracks: new(FullTrackPage)}
+	result.Tracks.Next = "https://api.spotify.com/v1/search?query=abba&type=track&offset=1000&limit=20"
+	if err := client.NextTrackResults(result); err != ErrPaginationLimit {
+		t.Errorf("Expected ErrPaginationLimit, got %v\n", err)
+	}
+	if getLastRequest(client) != nil {
+		t.Error("Request shouldn't be sent beyond the pagination limit")
+	}
+}
+
+func TestPaginationLimitResponse(t *testing.T) {
+	body := `{"error": {"status": 400, "message": "Bad search offset"}}`
+	client := testClientString(http.StatusBadRequest, body)
+	result := &SearchResult{Tracks: new(FullTrackPage)}
+	result.Tracks.Next = "https://api.spotify.com/v1/search?query=abba&type=track&offset=500&limit=20"
+	if err := client.NextTrackResults(result); err != ErrPaginationLimit {
+		t.Errorf("Expected ErrPaginationLimit, got %v\n", err)
+	}
+
+	client = testClientString(http.StatusBadRequest, body)
+	result.Tracks.Next = "https://api.spotify.com/v1/search?query=abba&type=track&limit=20"
+	if err := client.NextTrackResults(result); err == ErrPaginationLimit {
+		t.Error("Expected the Web API's error for the first page")
+	} else if e, ok := err.(*Error); !ok || e.Status != http.StatusBadRequest {
+		t.Errorf("Expected an Error, got %v\n", err)
+	}
+}
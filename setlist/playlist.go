@@ -0,0 +1,75 @@
+package setlist
+
+import (
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// ids returns the IDs of the ordered tracks followed by the missing ones.
+func ids(tracks []Track, missing []spotify.ID) []spotify.ID {
+	ids := make([]spotify.ID, 0, len(tracks)+len(missing))
+	for _, t := range tracks {
+		ids = append(ids, t.ID)
+	}
+	return append(ids, missing...)
+}
+
+// Build creates a playlist for userID containing the tracks in the given
+// order.  Tracks without audio features are added at the end.  It requires
+// the ScopePlaylistModifyPublic or ScopePlaylistModifyPrivate scope.
+func Build(c spotify.SpotifyClient, userID, name string, public bool, trackIDs []spotify.ID, order Order) (*spotify.FullPlaylist, error) {
+	tracks, missing, err := Load(c, trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	pl, err := c.CreatePlaylistForUser(userID, name, public)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range spotify.ChunkIDs(ids(order(tracks), missing), 100) {
+		if _, err := c.AddTracksToPlaylist(userID, pl.ID, chunk...); err != nil {
+			return nil, err
+		}
+	}
+	return pl, nil
+}
+
+// Reorder rearranges an existing playlist.  Tracks without audio features
+// are moved to the end, and local files, which can't be added through the
+// Web API, are dropped.  It requires the ScopePlaylistModifyPublic or
+// ScopePlaylistModifyPrivate scope.
+func Reorder(c spotify.SpotifyClient, userID string, playlistID spotify.ID, order Order) error {
+	var trackIDs []spotify.ID
+	limit, offset := 100, 0
+	for {
+		page, err := c.GetPlaylistTracksOpt(userID, playlistID, &spotify.Options{Limit: &limit, Offset: &offset}, "items(track(id)),next")
+		if err != nil {
+			return err
+		}
+		for _, t := range page.Tracks {
+			if t.Track.ID != "" {
+				trackIDs = append(trackIDs, t.Track.ID)
+			}
+		}
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			break
+		}
+	}
+	tracks, missing, err := Load(c, trackIDs)
+	if err != nil {
+		return err
+	}
+	chunks := spotify.ChunkIDs(ids(order(tracks), missing), 100)
+	if len(chunks) == 0 {
+		return nil
+	}
+	if err := c.ReplacePlaylistTracks(userID, playlistID, chunks[0]...); err != nil {
+		return err
+	}
+	for _, chunk := range chunks[1:] {
+		if _, err := c.AddTracksToPlaylist(userID, playlistID, chunk...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package setlist
+
+import (
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+// featureStub serves audio features, which the fake server doesn't track.
+type featureStub struct {
+	spotify.SpotifyClient
+	tempo map[spotify.ID]float32
+}
+
+func (f *featureStub) GetAudioFeatures(ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	features := make([]*spotify.AudioFeatures, len(ids))
+	for i, id := range ids {
+		if tempo, ok := f.tempo[id]; ok {
+			features[i] = &spotify.AudioFeatures{ID: id, Tempo: tempo}
+		}
+	}
+	return features, nil
+}
+
+const (
+	slow   = spotify.ID("4iV5W9uYEdYUVa79Axb7Rh")
+	fast   = spotify.ID("1301WleyT98MSxVHPZCA6M")
+	noData = spotify.ID("6rqhFgbbKwnb9MLmUQDhG6")
+)
+
+func TestReorder(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	client := &featureStub{SpotifyClient: &c, tempo: map[spotify.ID]float32{slow: 90, fast: 140}}
+
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Set", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddTracksToPlaylist(spotifytest.UserID, pl.ID, noData, fast, slow); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reorder(client, spotifytest.UserID, pl.ID, ByTempo); err != nil {
+		t.Fatal(err)
+	}
+	got := f.PlaylistTracks(pl.ID)
+	if len(got) != 3 || got[0] != slow || got[1] != fast || got[2] != noData {
+		t.Error("Unexpected order:", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	client := &featureStub{SpotifyClient: &c, tempo: map[spotify.ID]float32{slow: 90, fast: 140}}
+
+	pl, err := Build(client, spotifytest.UserID, "Set", true, []spotify.ID{fast, slow}, ByTempo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.PlaylistTracks(pl.ID); len(got) != 2 || got[0] != slow {
+		t.Error("Unexpected order:", got)
+	}
+}
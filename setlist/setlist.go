@@ -0,0 +1,178 @@
+// Package setlist orders tracks by their audio features, the way a DJ
+// plans a set: by tempo, along an energy arc, or so that each track mixes
+// harmonically into the next.
+package setlist
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Track is a track and its audio features.
+type Track struct {
+	ID       spotify.ID
+	Features spotify.AudioFeatures
+}
+
+// Order arranges tracks.  It returns a new slice, leaving tracks
+// unchanged.
+type Order func(tracks []Track) []Track
+
+// Load fetches the audio features of the tracks.  Tracks that Spotify has
+// no features for are returned in missing, in their original order.
+func Load(c spotify.SpotifyClient, ids []spotify.ID) (tracks []Track, missing []spotify.ID, err error) {
+	for _, chunk := range spotify.ChunkIDs(ids, 100) {
+		features, err := c.GetAudioFeatures(chunk...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, id := range chunk {
+			if i >= len(features) || features[i] == nil {
+				missing = append(missing, id)
+				continue
+			}
+			tracks = append(tracks, Track{ID: id, Features: *features[i]})
+		}
+	}
+	return tracks, missing, nil
+}
+
+// ByTempo orders tracks from slowest to fastest.
+func ByTempo(tracks []Track) []Track {
+	sorted := append([]Track(nil), tracks...)
+	sort.Stable(byTempo(sorted))
+	return sorted
+}
+
+type byTempo []Track
+
+func (t byTempo) Len() int           { return len(t) }
+func (t byTempo) Less(i, j int) bool { return t[i].Features.Tempo < t[j].Features.Tempo }
+func (t byTempo) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+type byEnergy []Track
+
+func (t byEnergy) Len() int           { return len(t) }
+func (t byEnergy) Less(i, j int) bool { return t[i].Features.Energy > t[j].Features.Energy }
+func (t byEnergy) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+// EnergyArc returns an Order that builds energy up to the most energetic
+// track and then winds it down again.  Peak is where the most energetic
+// track falls, as a fraction of the way through the set: 0.5 puts it in
+// the middle, and 0.7 gives a longer build up and a shorter cool down.
+func EnergyArc(peak float64) Order {
+	return func(tracks []Track) []Track {
+		if len(tracks) == 0 {
+			return nil
+		}
+		sorted := append([]Track(nil), tracks...)
+		sort.Stable(byEnergy(sorted))
+		// deal the tracks, most energetic first, to the build up or the
+		// cool down, keeping the peak at the right place
+		var up, down []Track
+		for _, t := range sorted[1:] {
+			if float64(len(up)) < peak*float64(len(up)+len(down)+1) {
+				up = append(up, t)
+			} else {
+				down = append(down, t)
+			}
+		}
+		set := make([]Track, 0, len(tracks))
+		for i := len(up) - 1; i >= 0; i-- {
+			set = append(set, up[i])
+		}
+		set = append(set, sorted[0])
+		return append(set, down...)
+	}
+}
+
+// Camelot is a key in the Camelot notation used by DJs: a number from 1
+// to 12 around the circle of fifths, and a letter, A for minor keys and B
+// for major keys.  Keys with the same number, or adjacent numbers and the
+// same letter, mix harmonically.
+type Camelot struct {
+	Number int
+	Minor  bool
+}
+
+// CamelotKey returns the Camelot key of a track, or the zero Camelot if
+// the key wasn't detected.
+func CamelotKey(f *spotify.AudioFeatures) Camelot {
+	if f.Key < 0 || f.Key > 11 {
+		return Camelot{}
+	}
+	key := f.Key
+	minor := spotify.Mode(f.Mode) == spotify.Minor
+	if minor {
+		// use the relative major
+		key = (key + 3) % 12
+	}
+	return Camelot{Number: (key*7+7)%12 + 1, Minor: minor}
+}
+
+func (c Camelot) String() string {
+	if c.Number == 0 {
+		return "?"
+	}
+	letter := "B"
+	if c.Minor {
+		letter = "A"
+	}
+	return fmt.Sprintf("%d%s", c.Number, letter)
+}
+
+// distance returns the number of steps between two keys on the Camelot
+// wheel, counting a change of letter as one step.  Keys that mix
+// harmonically are at most one step apart.
+func (c Camelot) distance(other Camelot) int {
+	if c.Number == 0 || other.Number == 0 {
+		return 12
+	}
+	d := c.Number - other.Number
+	if d < 0 {
+		d = -d
+	}
+	if d > 6 {
+		d = 12 - d
+	}
+	if c.Minor != other.Minor {
+		d++
+	}
+	return d
+}
+
+// Compatible reports whether two keys mix harmonically: they're the same,
+// relative major and minor, or a fifth apart.
+func Compatible(a, b Camelot) bool {
+	return a.distance(b) <= 1
+}
+
+// Harmonic orders tracks so that each mixes into the next as smoothly as
+// possible.  Starting with the slowest track, it repeatedly picks the
+// remaining track closest in key, breaking ties by the closest tempo.
+func Harmonic(tracks []Track) []Track {
+	left := ByTempo(tracks)
+	if len(left) == 0 {
+		return nil
+	}
+	set := []Track{left[0]}
+	left = left[1:]
+	for len(left) > 0 {
+		cur := set[len(set)-1]
+		key := CamelotKey(&cur.Features)
+		best, bestDist, bestTempo := 0, math.MaxInt32, math.Inf(1)
+		for i, t := range left {
+			d := key.distance(CamelotKey(&t.Features))
+			tempo := math.Abs(float64(t.Features.Tempo - cur.Features.Tempo))
+			if d < bestDist || (d == bestDist && tempo < bestTempo) {
+				best, bestDist, bestTempo = i, d, tempo
+			}
+		}
+		set = append(set, left[best])
+		left = append(left[:best], left[best+1:]...)
+	}
+	return set
+}
@@ -0,0 +1,97 @@
+package setlist
+
+import (
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func track(id string, tempo, energy float32, key int, mode spotify.Mode) Track {
+	return Track{ID: spotify.ID(id), Features: spotify.AudioFeatures{Tempo: tempo, Energy: energy, Key: key, Mode: int(mode)}}
+}
+
+func order(tracks []Track) string {
+	var s string
+	for _, t := range tracks {
+		s += string(t.ID)
+	}
+	return s
+}
+
+var tracks = []Track{
+	track("a", 128, 0.9, int(spotify.C), spotify.Major),
+	track("b", 100, 0.3, int(spotify.A), spotify.Minor),
+	track("c", 124, 0.7, int(spotify.G), spotify.Major),
+	track("d", 90, 0.5, int(spotify.FSharp), spotify.Major),
+	track("e", 126, 0.6, int(spotify.E), spotify.Minor),
+}
+
+func TestByTempo(t *testing.T) {
+	if got := order(ByTempo(tracks)); got != "dbcea" {
+		t.Error("Unexpected order", got)
+	}
+	if order(tracks) != "abcde" {
+		t.Error("ByTempo modified its argument")
+	}
+}
+
+func TestEnergyArc(t *testing.T) {
+	tests := map[float64]string{
+		0.5: "dcaeb",
+		0:   "acedb",
+		1:   "bdeca",
+	}
+	for peak, want := range tests {
+		// energies: a 0.9, c 0.7, e 0.6, d 0.5, b 0.3
+		if got := order(EnergyArc(peak)(tracks)); got != want {
+			t.Errorf("EnergyArc(%g) = %s, want %s\n", peak, got, want)
+		}
+	}
+	if EnergyArc(0.5)(nil) != nil {
+		t.Error("Expected no tracks")
+	}
+}
+
+func TestCamelotKey(t *testing.T) {
+	tests := []struct {
+		key  spotify.Key
+		mode spotify.Mode
+		want string
+	}{
+		{spotify.C, spotify.Major, "8B"},
+		{spotify.A, spotify.Minor, "8A"},
+		{spotify.G, spotify.Major, "9B"},
+		{spotify.E, spotify.Minor, "9A"},
+		{spotify.FSharp, spotify.Major, "2B"},
+		{spotify.B, spotify.Major, "1B"},
+		{spotify.F, spotify.Minor, "4A"},
+	}
+	for _, test := range tests {
+		f := &spotify.AudioFeatures{Key: int(test.key), Mode: int(test.mode)}
+		if got := CamelotKey(f).String(); got != test.want {
+			t.Errorf("%s %d: got %s, want %s\n", test.key, test.mode, got, test.want)
+		}
+	}
+	if got := CamelotKey(&spotify.AudioFeatures{Key: -1}).String(); got != "?" {
+		t.Error("Expected an unknown key, got", got)
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	if !Compatible(Camelot{8, false}, Camelot{8, true}) ||
+		!Compatible(Camelot{12, false}, Camelot{1, false}) ||
+		Compatible(Camelot{8, false}, Camelot{10, false}) ||
+		Compatible(Camelot{8, false}, Camelot{9, true}) ||
+		Compatible(Camelot{}, Camelot{}) {
+		t.Error("Unexpected compatibility")
+	}
+}
+
+func TestHarmonic(t *testing.T) {
+	// d (2B) is slowest and nothing is compatible with it, so c (9B) is
+	// the closest key; e (9A) and a (8B) are both compatible with c, but e
+	// is closer in tempo
+	if got := order(Harmonic(tracks)); got != "dceba" {
+		t.Error("Unexpected order", got)
+	}
+}
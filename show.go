@@ -0,0 +1,247 @@
+package spotify
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// SimpleShow contains basic data about a show (a podcast).
+type SimpleShow struct {
+	// The name of the show.
+	Name string `json:"name"`
+	// The SpotifyID for the show.
+	ID ID `json:"id"`
+	// The SpotifyURI for the show.
+	URI URI `json:"uri"`
+	// A description of the show, without HTML tags.
+	Description string `json:"description"`
+	// The name of the show's publisher.
+	Publisher string `json:"publisher"`
+	// The markets in which the show is available, identified using ISO
+	// 3166-1 alpha-2 country codes.
+	AvailableMarkets []string    `json:"available_markets"`
+	Copyrights       []Copyright `json:"copyrights"`
+	// Explicit is true if the show has explicit content.
+	Explicit bool `json:"explicit"`
+	// Known external URLs for this show.
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	// A link to the Web API endpoint providing full details of the show.
+	Endpoint string `json:"href"`
+	// The cover art for the show in various sizes, widest first.
+	Images Images `json:"images"`
+	// IsExternallyHosted is true if the episodes are hosted outside of
+	// Spotify's CDN.
+	IsExternallyHosted bool `json:"is_externally_hosted"`
+	// The languages of the show, as ISO 639 codes.
+	Languages []string `json:"languages"`
+	// The media type of the show: "audio", "video" or "mixed".
+	MediaType string `json:"media_type"`
+	// The object type: "show".
+	Type string `json:"type"`
+}
+
+// SimpleEpisode contains basic data about an episode of a show.
+type SimpleEpisode struct {
+	// The name of the episode.
+	Name string `json:"name"`
+	// The SpotifyID for the episode.
+	ID ID `json:"id"`
+	// The SpotifyURI for the episode.
+	URI URI `json:"uri"`
+	// A description of the episode, without HTML tags.
+	Description string `json:"description"`
+	// The length of the episode in milliseconds.
+	Duration int `json:"duration_ms"`
+	// A URL to a 30 second preview of the episode, or the empty string if
+	// there isn't one.
+	AudioPreviewURL string `json:"audio_preview_url"`
+	// Explicit is true if the episode has explicit content.
+	Explicit bool `json:"explicit"`
+	// Known external URLs for this episode.
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	// A link to the Web API endpoint providing full details of the
+	// episode.
+	Endpoint string `json:"href"`
+	// The cover art for the episode in various sizes, widest first.
+	Images Images `json:"images"`
+	// IsExternallyHosted is true if the episode is hosted outside of
+	// Spotify's CDN.
+	IsExternallyHosted bool `json:"is_externally_hosted"`
+	// IsPlayable is true if the episode is playable in the given market.
+	IsPlayable bool `json:"is_playable"`
+	// The languages of the episode, as ISO 639 codes.
+	Languages []string `json:"languages"`
+	// The date the episode was first released.  For example, "1981-12-15".
+	// Depending on the ReleaseDatePrecision, it might be shown as "1981"
+	// or "1981-12".
+	ReleaseDate string `json:"release_date"`
+	// The precision with which ReleaseDate value is known.
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	// The object type: "episode".
+	Type string `json:"type"`
+}
+
+// ReleaseDateTime converts the episode's ReleaseDate to a time.Time, as
+// SimpleAlbum's ReleaseDateTime does.
+func (e *SimpleEpisode) ReleaseDateTime() time.Time {
+	return parseReleaseDate(e.ReleaseDate, e.ReleaseDatePrecision)
+}
+
+// FullShow provides extra show data in addition to the data provided by
+// SimpleShow.
+type FullShow struct {
+	SimpleShow
+	// The first page of the show's episodes, most recent first.
+	Episodes SimpleEpisodePage `json:"episodes"`
+}
+
+// FullEpisode provides extra episode data in addition to the data provided
+// by SimpleEpisode.
+type FullEpisode struct {
+	SimpleEpisode
+	// The show the episode belongs to.
+	Show SimpleShow `json:"show"`
+}
+
+// SavedShow provides info about a show saved to a user's account.
+type SavedShow struct {
+	// The date and time the show was saved.
+	AddedAt    time.Time `json:"added_at"`
+	SimpleShow `json:"show"`
+}
+
+// GetShowWithContext gets Spotify catalog information for a show, given its
+// Spotify ID, with the first page of its episodes.  opt may be nil; its
+// Country is the market to get the show for, and otherwise the client's
+// default market (see WithMarket) is used.  Shows are only available in
+// some markets, so a client that isn't authorized by a user should give
+// one.
+func (c *Client) GetShowWithContext(ctx context.Context, id ID, opt *Options) (*FullShow, error) {
+	e := c.endpoint("shows/%s", id)
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	var s FullShow
+	if err := c.get(ctx, e.String(), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetShow gets Spotify catalog information for a show, given its Spotify
+// ID.  A TrackContext of type "show", such as that of a podcast in the
+// user's play history, can be resolved with ParseURI and GetShow.
+func (c *Client) GetShow(id ID) (*FullShow, error) {
+	return c.GetShowWithContext(context.Background(), id, nil)
+}
+
+// GetShowOpt is like GetShow, but it accepts the market to get the show for.
+func (c *Client) GetShowOpt(id ID, opt *Options) (*FullShow, error) {
+	return c.GetShowWithContext(context.Background(), id, opt)
+}
+
+// GetShowEpisodesWithContext gets a page of a show's episodes, most recent
+// first.  opt may be nil; its Limit (up to 50) and Offset page through the
+// episodes, and its Country is used as by GetShowWithContext.
+func (c *Client) GetShowEpisodesWithContext(ctx context.Context, id ID, opt *Options) (*SimpleEpisodePage, error) {
+	e := c.endpoint("shows/%s/episodes", id)
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	e.setPaging(opt)
+	var p SimpleEpisodePage
+	if err := c.get(ctx, e.String(), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetShowEpisodes gets the first page of a show's episodes.
+func (c *Client) GetShowEpisodes(id ID) (*SimpleEpisodePage, error) {
+	return c.GetShowEpisodesWithContext(context.Background(), id, nil)
+}
+
+// GetShowEpisodesOpt is like GetShowEpisodes, but it accepts optional
+// parameters for paging and the market.
+func (c *Client) GetShowEpisodesOpt(id ID, opt *Options) (*SimpleEpisodePage, error) {
+	return c.GetShowEpisodesWithContext(context.Background(), id, opt)
+}
+
+// GetEpisodeWithContext gets Spotify catalog information for an episode,
+// given its Spotify ID, with the show it belongs to.  opt is used as by
+// GetShowWithContext.
+func (c *Client) GetEpisodeWithContext(ctx context.Context, id ID, opt *Options) (*FullEpisode, error) {
+	e := c.endpoint("episodes/%s", id)
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	var ep FullEpisode
+	if err := c.get(ctx, e.String(), &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// GetEpisode gets Spotify catalog information for an episode, given its
+// Spotify ID.
+func (c *Client) GetEpisode(id ID) (*FullEpisode, error) {
+	return c.GetEpisodeWithContext(context.Background(), id, nil)
+}
+
+// GetEpisodeOpt is like GetEpisode, but it accepts the market to get the
+// episode for.
+func (c *Client) GetEpisodeOpt(id ID, opt *Options) (*FullEpisode, error) {
+	return c.GetEpisodeWithContext(context.Background(), id, opt)
+}
+
+// CurrentUsersShowsWithContext gets a page of the shows saved in the
+// current user's library, most recently saved first.  opt may be nil; its
+// Limit (up to 50) and Offset page through the shows.  Requires
+// authorization under the ScopeUserLibraryRead scope.
+func (c *Client) CurrentUsersShowsWithContext(ctx context.Context, opt *Options) (*SavedShowPage, error) {
+	e := c.endpoint("me/shows")
+	e.setPaging(opt)
+	var p SavedShowPage
+	if err := c.get(ctx, e.String(), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CurrentUsersShows gets the first page of the shows saved in the current
+// user's library.
+func (c *Client) CurrentUsersShows() (*SavedShowPage, error) {
+	return c.CurrentUsersShowsWithContext(context.Background(), nil)
+}
+
+// CurrentUsersShowsOpt is like CurrentUsersShows, but it accepts optional
+// parameters for paging.
+func (c *Client) CurrentUsersShowsOpt(opt *Options) (*SavedShowPage, error) {
+	return c.CurrentUsersShowsWithContext(context.Background(), opt)
+}
+
+// AddShowsToLibrary saves one or more shows to the current user's library.
+// This call requires authorization (the ScopeUserLibraryModify scope).
+// Like AddTracksToLibrary, it accepts any number of IDs.
+func (c *Client) AddShowsToLibrary(ids ...ID) error {
+	return c.AddShowsToLibraryWithContext(context.Background(), ids...)
+}
+
+// AddShowsToLibraryWithContext is like AddShowsToLibrary, with a context.
+func (c *Client) AddShowsToLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/shows", true, ids)
+}
+
+// RemoveShowsFromLibrary removes one or more shows from the current user's
+// library.  This call requires authorization (the ScopeUserLibraryModify
+// scope).  Like AddTracksToLibrary, it accepts any number of IDs.
+func (c *Client) RemoveShowsFromLibrary(ids ...ID) error {
+	return c.RemoveShowsFromLibraryWithContext(context.Background(), ids...)
+}
+
+// RemoveShowsFromLibraryWithContext is like RemoveShowsFromLibrary, with a
+// context.
+func (c *Client) RemoveShowsFromLibraryWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyLibrary(ctx, "me/shows", false, ids)
+}
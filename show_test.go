@@ -0,0 +1,64 @@
+package spotify
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetShow(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/show_full.json")
+	market := "SE"
+	show, err := c.GetShowOpt("38bS44xjbVVZ3No3ByF1dJ", &Options{Country: &market})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if show.Name != "Vetenskapsradion Historia" || len(show.Episodes.Episodes) != 1 {
+		t.Errorf("Got %+v\n", show)
+	}
+	req := getLastRequest(c)
+	if req.URL.Path != "/v1/shows/38bS44xjbVVZ3No3ByF1dJ" || req.URL.Query().Get("market") != "SE" {
+		t.Errorf("Got request for %s\n", req.URL)
+	}
+	released := show.Episodes.Episodes[0].ReleaseDateTime()
+	if !released.Equal(time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Got release date %v\n", released)
+	}
+}
+
+func TestGetEpisode(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/episode_full.json")
+	ep, err := c.GetEpisode("512ojhOuo1ktJprKbVcKyQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Name != "Tredje rikets knarkande granskas" || ep.Show.ID != "38bS44xjbVVZ3No3ByF1dJ" {
+		t.Errorf("Got %+v\n", ep)
+	}
+}
+
+func TestCurrentUsersShows(t *testing.T) {
+	c := testClientFile(http.StatusOK, "test_data/golden/show_saved_page.json")
+	limit := 20
+	shows, err := c.CurrentUsersShowsOpt(&Options{Limit: &limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shows.Shows) != 1 || shows.Shows[0].Publisher != "Sveriges Radio" || shows.Shows[0].AddedAt.Year() != 2020 {
+		t.Errorf("Got %+v\n", shows)
+	}
+	if q := getLastRequest(c).URL.Query(); q.Get("limit") != "20" {
+		t.Errorf("Got query %v\n", q)
+	}
+}
+
+func TestAddShowsToLibrary(t *testing.T) {
+	c := testClientString(http.StatusOK, "")
+	if err := c.AddShowsToLibrary("38bS44xjbVVZ3No3ByF1dJ"); err != nil {
+		t.Fatal(err)
+	}
+	req := getLastRequest(c)
+	if req.Method != "PUT" || req.URL.Path != "/v1/me/shows" || req.URL.Query().Get("ids") != "38bS44xjbVVZ3No3ByF1dJ" {
+		t.Errorf("Got %s %s\n", req.Method, req.URL)
+	}
+}
@@ -0,0 +1,130 @@
+package spotify
+
+import "math"
+
+// Weights given to each component of the score computed by
+// AnalysisSimilarity.  They sum to 1.
+const (
+	tempoWeight     = 0.25
+	keyWeight       = 0.15
+	timbreWeight    = 0.45
+	structureWeight = 0.15
+)
+
+// timbreScale controls how quickly timbre similarity falls off with
+// distance.  Timbre coefficients are unbounded, but typically vary by
+// tens of units between dissimilar tracks.
+const timbreScale = 40.0
+
+// AnalysisSimilarity compares the audio analyses of two tracks and returns
+// a similarity score between 0.0 (nothing in common) and 1.0 (identical).
+// The score combines tempo (allowing for half and double time), key
+// (using distance on the circle of fifths), timbre statistics and section
+// structure.  It can be used to build "sounds like" features without calling
+// the recommendations endpoint.
+func AnalysisSimilarity(a, b *AudioAnalysis) float64 {
+	return tempoWeight*tempoSimilarity(a.TrackInfo.Tempo, b.TrackInfo.Tempo) +
+		keyWeight*keySimilarity(a.TrackInfo, b.TrackInfo) +
+		timbreWeight*timbreSimilarity(a.Segments, b.Segments) +
+		structureWeight*structureSimilarity(a.Sections, b.Sections)
+}
+
+// tempoSimilarity scores two tempos, treating half and double time as
+// equivalent.  Tempos that differ by 50% or more score zero.
+func tempoSimilarity(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	best := math.Inf(1)
+	for _, m := range []float64{0.5, 1, 2} {
+		if d := math.Abs(math.Log(a / (b * m))); d < best {
+			best = d
+		}
+	}
+	return math.Max(0, 1-best/math.Log(1.5))
+}
+
+// keySimilarity scores two keys by their distance on the circle of fifths.
+// Minor keys are compared using their relative major.
+func keySimilarity(a, b TrackInfo) float64 {
+	if a.Key < 0 || b.Key < 0 {
+		return 0
+	}
+	fifths := func(t TrackInfo) int {
+		key := t.Key
+		if Mode(t.Mode) == Minor {
+			key += 3
+		}
+		// multiplying by 7 maps a pitch class to its position on the circle
+		return (key * 7) % 12
+	}
+	d := fifths(a) - fifths(b)
+	if d < 0 {
+		d = -d
+	}
+	if d > 6 {
+		d = 12 - d
+	}
+	return 1 - float64(d)/6
+}
+
+// timbreStats returns the mean and standard deviation of each timbre
+// coefficient across all segments.
+func timbreStats(segments []Segment) (mean, stddev [12]float64, ok bool) {
+	var n float64
+	for _, s := range segments {
+		if len(s.Timbre) != 12 {
+			continue
+		}
+		n++
+		for i, v := range s.Timbre {
+			mean[i] += v
+			stddev[i] += v * v
+		}
+	}
+	if n == 0 {
+		return mean, stddev, false
+	}
+	for i := range mean {
+		mean[i] /= n
+		stddev[i] = math.Sqrt(math.Max(stddev[i]/n-mean[i]*mean[i], 0))
+	}
+	return mean, stddev, true
+}
+
+func timbreSimilarity(a, b []Segment) float64 {
+	meanA, devA, okA := timbreStats(a)
+	meanB, devB, okB := timbreStats(b)
+	if !okA || !okB {
+		return 0
+	}
+	var sum float64
+	for i := range meanA {
+		sum += (meanA[i]-meanB[i])*(meanA[i]-meanB[i]) + (devA[i]-devB[i])*(devA[i]-devB[i])
+	}
+	return 1 / (1 + math.Sqrt(sum)/timbreScale)
+}
+
+// structureSimilarity compares the number of sections and their average
+// length.
+func structureSimilarity(a, b []Section) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	avg := func(sections []Section) float64 {
+		var d float64
+		for _, s := range sections {
+			d += s.Duration
+		}
+		return d / float64(len(sections))
+	}
+	return (ratio(float64(len(a)), float64(len(b))) + ratio(avg(a), avg(b))) / 2
+}
+
+// ratio returns the smaller of x and y divided by the larger.
+func ratio(x, y float64) float64 {
+	if x <= 0 || y <= 0 {
+		return 0
+	}
+	return math.Min(x, y) / math.Max(x, y)
+}
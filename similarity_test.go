@@ -0,0 +1,42 @@
+package spotify
+
+import (
+	"math"
+	"testing"
+)
+
+func testAnalysis(tempo float64, key int, timbre float64) *AudioAnalysis {
+	t := make([]float64, 12)
+	for i := range t {
+		t[i] = timbre
+	}
+	return &AudioAnalysis{
+		TrackInfo: TrackInfo{Tempo: tempo, Key: key, Mode: int(Major)},
+		Sections:  []Section{{Duration: 30}, {Duration: 30}},
+		Segments:  []Segment{{Timbre: t}},
+	}
+}
+
+func TestAnalysisSimilarityIdentical(t *testing.T) {
+	a := testAnalysis(120, int(C), 10)
+	if s := AnalysisSimilarity(a, a); math.Abs(s-1) > 1e-9 {
+		t.Errorf("Wanted similarity 1.0 for identical analyses, got %v\n", s)
+	}
+}
+
+func TestAnalysisSimilarityHalfTime(t *testing.T) {
+	a := testAnalysis(140, int(C), 10)
+	b := testAnalysis(70, int(C), 10)
+	if s := AnalysisSimilarity(a, b); math.Abs(s-1) > 1e-9 {
+		t.Errorf("Wanted half time tempo to be treated as equal, got %v\n", s)
+	}
+}
+
+func TestAnalysisSimilarityOrdering(t *testing.T) {
+	a := testAnalysis(120, int(C), 10)
+	near := testAnalysis(122, int(G), 12)
+	far := testAnalysis(95, int(FSharp), 80)
+	if AnalysisSimilarity(a, near) <= AnalysisSimilarity(a, far) {
+		t.Error("Expected similar track to score higher than dissimilar track")
+	}
+}
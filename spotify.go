@@ -1,29 +1,41 @@
 // Package spotify provides utilties for interfacing
 // with Spotify's Web API.
+//
+// The WithContext variants of the client's methods make their requests with
+// a context, which can cancel them or give them a deadline.  On App
+// Engine, pass the request's context, so that the calls end with the
+// request.  Where a method has a simple form and an Opt form, the variant
+// takes the arguments of the Opt form.  If the context is done before the
+// call finishes, the variants return the context's error.  The other forms
+// call the variants with context.Background().
 package spotify
 
 import (
-	"crypto/tls"
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
 )
 
 // Version is the version of this library.
-const Version = "1.0.0"
+const Version = "2.0.0"
 
 const (
 	// DateLayout can be used with time.Parse to create time.Time values
 	// from Spotify date strings.  For example, PrivateUser.Birthdate
 	// uses this format.
 	DateLayout = "2006-01-02"
-	// TimestampLayout can be used with time.Parse to create time.Time
-	// values from SpotifyTimestamp strings.  It is an ISO 8601 UTC timestamp
-	// with a zero offset.  For example, PlaylistTrack's AddedAt field uses
-	// this format.
+	// TimestampLayout is the format of the timestamps in Spotify
+	// responses, such as PlaylistTrack's AddedAt field.  It is an ISO 8601
+	// UTC timestamp with a zero offset.  The models parse timestamps into
+	// time.Time values, so it's only needed to format them the same way.
 	TimestampLayout = "2006-01-02T15:04:05Z"
 )
 
@@ -47,25 +59,52 @@ type URI string
 type ID string
 
 func init() {
-	// disable HTTP/2 for DefaultClient, see: https://github.com/zmb3/spotify/issues/20
-	tr := &http.Transport{
-		TLSNextProto: map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
-	}
-	DefaultClient.http.Transport = tr
+	DefaultClient.http.Transport = NewTransport(DefaultTransportOptions)
+}
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(text []byte) error {
+	*id = ID(text)
+	return nil
 }
 
-func (id *ID) String() string {
-	return string(*id)
+func (u URI) String() string {
+	return string(u)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URI) MarshalText() ([]byte, error) {
+	return []byte(u), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *URI) UnmarshalText(text []byte) error {
+	*u = URI(text)
+	return nil
 }
 
 // Followers contains information about the number of people following a
-// particular artist or playlist.
+// particular artist, playlist or user.
 type Followers struct {
 	// The total number of followers.
 	Count uint `json:"total"`
-	// A link to the Web API endpoint providing full details of the followers,
-	// or the empty string if this data is not available.
-	Endpoint string `json:"href"`
+	// A link to the Web API endpoint providing full details of the followers.
+	// The Web API doesn't support this yet, so it is always nil.
+	Endpoint *string `json:"href"`
+}
+
+// Total returns the total number of followers.
+func (f Followers) Total() uint {
+	return f.Count
 }
 
 // Image identifies an image associated with an item.
@@ -78,6 +117,121 @@ type Image struct {
 	URL string `json:"url"`
 }
 
+// Images is a list of images of the same item in different sizes.  The Web
+// API returns them widest first.
+type Images []Image
+
+// Largest returns the widest image, or nil if there are none.
+func (images Images) Largest() *Image {
+	var largest *Image
+	for i := range images {
+		if largest == nil || images[i].Width > largest.Width {
+			largest = &images[i]
+		}
+	}
+	return largest
+}
+
+// Smallest returns the narrowest image, or nil if there are none.
+func (images Images) Smallest() *Image {
+	var smallest *Image
+	for i := range images {
+		if smallest == nil || images[i].Width < smallest.Width {
+			smallest = &images[i]
+		}
+	}
+	return smallest
+}
+
+// BestFor returns the smallest image that is at least width by height
+// pixels, so that it can be scaled down to fit without losing quality.  If
+// none is that large, it returns the largest image.  It returns nil if there
+// are no images.  Images of unknown size (which the Web API returns for some
+// playlists) are only chosen if there's nothing else.
+func (images Images) BestFor(width, height int) *Image {
+	var best *Image
+	for i := range images {
+		img := &images[i]
+		switch {
+		case best == nil:
+			best = img
+		case img.Width == 0 || img.Height == 0:
+		case best.Width == 0 || best.Height == 0:
+			best = img
+		case img.Width >= width && img.Height >= height:
+			if best.Width < width || best.Height < height || img.Width < best.Width {
+				best = img
+			}
+		case best.Width < width || best.Height < height:
+			if img.Width > best.Width {
+				best = img
+			}
+		}
+	}
+	return best
+}
+
+// ExternalURLs maps the name of an external service to the URL of an item
+// on that service.  Spotify currently returns only "spotify", the item's
+// page on open.spotify.com.
+type ExternalURLs map[string]string
+
+// Spotify returns the open.spotify.com URL of the item, or the empty string
+// if it's unknown.
+func (u ExternalURLs) Spotify() string {
+	return u["spotify"]
+}
+
+// ExternalIDs contains the IDs of an item in other catalogs.  Tracks have
+// an ISRC, and albums a UPC or EAN.
+type ExternalIDs struct {
+	// International Standard Recording Code.
+	ISRC string `json:"isrc"`
+	// International Article Number.
+	EAN string `json:"ean"`
+	// Universal Product Code.
+	UPC string `json:"upc"`
+	// Raw contains any other IDs, keyed by the name the Web API uses.
+	Raw map[string]string `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ids *ExternalIDs) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*ids = ExternalIDs{ISRC: m["isrc"], EAN: m["ean"], UPC: m["upc"]}
+	for k, v := range m {
+		if k != "isrc" && k != "ean" && k != "upc" {
+			if ids.Raw == nil {
+				ids.Raw = make(map[string]string)
+			}
+			ids.Raw[k] = v
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the IDs as the Web API
+// does: as a single object with only the IDs that are known.
+func (ids ExternalIDs) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string, len(ids.Raw)+3)
+	for k, v := range ids.Raw {
+		m[k] = v
+	}
+	if ids.ISRC != "" {
+		m["isrc"] = ids.ISRC
+	}
+	if ids.EAN != "" {
+		m["ean"] = ids.EAN
+	}
+	if ids.UPC != "" {
+		m["upc"] = ids.UPC
+	}
+	return json.Marshal(m)
+}
+
 // Download downloads the image and writes its data to the specified io.Writer.
 func (i Image) Download(dst io.Writer) error {
 	resp, err := http.Get(i.URL)
@@ -93,28 +247,128 @@ func (i Image) Download(dst io.Writer) error {
 	return err
 }
 
-// Error represents an error returned by the Spotify Web API.
+// MaxImageSize is the largest image, in bytes, that DownloadImage will
+// download.  Spotify's cover art is well under this.
+var MaxImageSize int64 = 4 << 20
+
+// ErrImageTooLarge is returned by DownloadImage if the image is larger than
+// MaxImageSize.
+var ErrImageTooLarge = errors.New("spotify: image is larger than MaxImageSize")
+
+// DownloadImage downloads the image and writes it to w.  Unlike
+// Image.Download it uses the client's HTTP client (so it works on App
+// Engine), can be canceled with ctx, and refuses images larger than
+// MaxImageSize.  If the image is too large, some of it may already have been
+// written to w.
+func (c *Client) DownloadImage(ctx context.Context, img Image, w io.Writer) error {
+	req, err := http.NewRequest("GET", img.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ctxhttp.Do(ctx, c.http, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("spotify: couldn't download image - HTTP " + strconv.Itoa(resp.StatusCode))
+	}
+	if resp.ContentLength > MaxImageSize {
+		return ErrImageTooLarge
+	}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, MaxImageSize+1))
+	if err != nil {
+		return err
+	}
+	if n > MaxImageSize {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// Error represents an error returned by the Spotify Web API.  Calls that
+// get an error response return a *Error; use IsRateLimited, IsNotFound,
+// IsUnauthorized and IsRetryable to tell the common cases apart.
 type Error struct {
 	// A short description of the error.
 	Message string `json:"message"`
 	// The HTTP status code.
 	Status int `json:"status"`
+	// Reason is Spotify's code for the error, such as "PREMIUM_REQUIRED"
+	// or "NO_ACTIVE_DEVICE".  Only some endpoints, mostly the player's,
+	// give one.
+	Reason string `json:"reason,omitempty"`
+	// HTTPStatus is the status code of the response, which is the same as
+	// Status unless the body of the response said otherwise.
+	HTTPStatus int `json:"-"`
+	// Endpoint is the URL of the request, without its query, if known.
+	Endpoint string `json:"-"`
 }
 
-func (e Error) Error() string {
+func (e *Error) Error() string {
 	return e.Message
 }
 
-// decodeError decodes an Error from an io.Reader.
-func decodeError(r io.Reader) error {
-	var e struct {
-		E Error `json:"error"`
+// errorStatus returns the HTTP status of err, or 0 if it isn't an *Error.
+func errorStatus(err error) int {
+	e, ok := err.(*Error)
+	if !ok {
+		return 0
 	}
-	err := json.NewDecoder(r).Decode(&e)
-	if err != nil {
-		return errors.New("spotify: couldn't decode error")
+	if e.HTTPStatus != 0 {
+		return e.HTTPStatus
+	}
+	return e.Status
+}
+
+// IsRateLimited reports whether err is a 429 Too Many Requests response,
+// which means the call can be retried after a while (see WithRetry).
+func IsRateLimited(err error) bool {
+	return errorStatus(err) == http.StatusTooManyRequests
+}
+
+// IsNotFound reports whether err is a 404 Not Found response.
+func IsNotFound(err error) bool {
+	return errorStatus(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is a 401 Unauthorized response: the
+// access token is missing, expired or revoked, and the user may need to
+// authorize the application again.
+func IsUnauthorized(err error) bool {
+	return errorStatus(err) == http.StatusUnauthorized
+}
+
+// IsRetryable reports whether err is an error response that may go away if
+// the call is made again: rate limiting, or an error on Spotify's side.
+// Other error responses will happen again.  Errors that aren't responses,
+// such as network errors, are left to the caller.
+func IsRetryable(err error) bool {
+	status := errorStatus(err)
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// decodeError decodes the Error of the error response resp.
+func decodeError(resp *http.Response) error {
+	defer drain(resp.Body)
+	e := &Error{HTTPStatus: resp.StatusCode}
+	if resp.Request != nil && resp.Request.URL != nil {
+		u := *resp.Request.URL
+		u.RawQuery = ""
+		e.Endpoint = u.String()
 	}
-	return e.E
+	body := struct {
+		E *Error `json:"error"`
+	}{e}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err != nil {
+		e.Message = "spotify: couldn't decode error"
+	} else if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	if e.Status == 0 {
+		e.Status = resp.StatusCode
+	}
+	return e
 }
 
 // Client is a client for working with the Spotify Web API.
@@ -122,7 +376,94 @@ func decodeError(r io.Reader) error {
 // `Authenticator.NewClient` method.  If you don't need to
 // authenticate, you can use `DefaultClient`.
 type Client struct {
-	http *http.Client
+	http   *http.Client
+	clock  Clock
+	strict bool
+	raw    func(v interface{}, raw json.RawMessage)
+	// concurrency is the most requests batch methods make at once.
+	concurrency int
+	// maxResponse is the largest response body decoded, or 0 for no limit.
+	maxResponse int64
+	codec       Codec
+	// market is the market used when a call doesn't specify one.
+	market string
+	// params are the query parameters added to every request.
+	params url.Values
+}
+
+// NewClient returns a client for working with the Spotify Web API.
+// The provided http.Client must take care of authorization (for example,
+// by adding an OAuth2 bearer token to each request).  Most applications
+// should use `Authenticator.NewClient` instead, which does this for you.
+func NewClient(client *http.Client, opts ...ClientOption) Client {
+	c := Client{
+		http:        client,
+		clock:       SystemClock,
+		concurrency: DefaultConcurrency,
+		maxResponse: DefaultMaxResponseBytes,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// get sends a GET request for url, which can be canceled through ctx, and
+// decodes the response into v.  A 204 No Content response leaves v as it
+// is.  The WithContext variants of the client's methods pass their context
+// to get, getPage or send.
+func (c *Client) get(ctx context.Context, url string, v interface{}) error {
+	resp, err := ctxhttp.Get(ctx, c.http, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return c.decode(resp.Body, v)
+}
+
+// send sends a request with the given method for url, which can be
+// canceled through ctx, with body encoded as JSON unless it's nil.  Any 2xx
+// response is a success.
+func (c *Client) send(ctx context.Context, method, url string, body interface{}) error {
+	return c.sendDecode(ctx, method, url, body, nil)
+}
+
+// sendDecode is like send, but decodes the response into v, unless v is
+// nil.
+func (c *Client) sendDecode(ctx context.Context, method, url string, body, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := ctxhttp.Do(ctx, c.http, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+	if v == nil {
+		return nil
+	}
+	return c.decode(resp.Body, v)
 }
 
 // Options contains optional parameters that can be provided
@@ -133,7 +474,17 @@ type Options struct {
 	// this parameter if you want the list of returned items to
 	// be relevant to a particular country.  If omitted, the
 	// results will be relevant to all countries.
+	//
+	// When a market is given, the Web API leaves the (very long) lists of
+	// available markets out of tracks and albums, and instead reports
+	// whether they are playable there.
 	Country *string
+	// OmitAvailableMarkets asks for results without the lists of available
+	// markets, to make responses smaller.  If Country is nil, the market of
+	// the user's account (MarketFromToken) is used, which requires a client
+	// authorized by a user.  It has no effect on endpoints that don't return
+	// available markets.
+	OmitAvailableMarkets bool
 	// Limit is the maximum number of items to return.
 	Limit *int
 	// Offset is the index of the first item to return.  Use it
@@ -143,45 +494,49 @@ type Options struct {
 	// from in certain API calls. The API is limited to three choices
 	// consisting of "short", "medium", and "long".
 	Timerange *string
+	// Before and After are cursors for endpoints that page by cursor
+	// (see Cursor), such as CurrentUserRecentTracks: the items before or
+	// after the one the cursor points at are returned.  At most one of
+	// them may be set.
+	Before *string
+	After  *string
 }
 
-// NewReleasesOpt is like NewReleases, but it accepts optional parameters
-// for filtering the results.
-func (c *Client) NewReleasesOpt(opt *Options) (albums *SimpleAlbumPage, err error) {
-	spotifyURL := baseAddress + "browse/new-releases"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Country != nil {
-			v.Set("country", *opt.Country)
-		}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+// idLength is the length of a base-62 Spotify ID.
+const idLength = 22
+
+// Valid reports whether the ID is well formed: exactly 22 base-62
+// characters (0-9, a-z and A-Z).  Note that user IDs and category IDs
+// are not base-62 IDs, and are not expected to be valid.
+func (id ID) Valid() bool {
+	if len(id) != idLength {
+		return false
 	}
-	var result SimpleAlbumPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, err
+	for _, r := range id {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return false
+		}
 	}
-	return &result, nil
+	return true
+}
+
+// InvalidIDError is returned when a malformed ID is passed to a call
+// that validates its IDs before contacting the Web API.
+type InvalidIDError struct {
+	ID ID
 }
 
-// NewReleases gets a list of new album releases featured in Spotify.
-// This call requires bearer authorization.
-func (c *Client) NewReleases() (albums *SimpleAlbumPage, err error) {
-	return c.NewReleasesOpt(nil)
+func (e InvalidIDError) Error() string {
+	return fmt.Sprintf("spotify: invalid ID %q (IDs are %d base-62 characters)", string(e.ID), idLength)
+}
+
+// ValidateIDs returns an InvalidIDError for the first malformed ID in ids,
+// or nil if they are all valid.
+func ValidateIDs(ids ...ID) error {
+	for _, id := range ids {
+		if !id.Valid() {
+			return InvalidIDError{id}
+		}
+	}
+	return nil
 }
@@ -1,11 +1,16 @@
 package spotify
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
 	"testing"
+
+	"golang.org/x/net/context"
 )
 
 type stringRoundTripper struct {
@@ -112,3 +117,131 @@ func TestNewReleases(t *testing.T) {
 		return
 	}
 }
+
+func TestImages(t *testing.T) {
+	var none Images
+	if none.Largest() != nil || none.Smallest() != nil {
+		t.Error("Expected no image from an empty list")
+	}
+	images := Images{{Width: 300, URL: "medium"}, {Width: 640, URL: "large"}, {Width: 64, URL: "small"}}
+	if got := images.Largest().URL; got != "large" {
+		t.Errorf("Largest: got %s, want large\n", got)
+	}
+	if got := images.Smallest().URL; got != "small" {
+		t.Errorf("Smallest: got %s, want small\n", got)
+	}
+}
+
+func TestExternalURLs(t *testing.T) {
+	client := testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	track, err := client.GetTrack(ID("1zHlj4dQ8ZAtrayhuDDmkY"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := track.ExternalURLs.Spotify(); !strings.HasPrefix(got, "https://open.spotify.com/track/") {
+		t.Errorf("Got Spotify URL %q\n", got)
+	}
+	if got := track.Album.ExternalURLs.Spotify(); !strings.HasPrefix(got, "https://open.spotify.com/album/") {
+		t.Errorf("Got album Spotify URL %q\n", got)
+	}
+}
+
+func TestExternalIDs(t *testing.T) {
+	var ids ExternalIDs
+	if err := json.Unmarshal([]byte(`{"isrc":"USSUB0665807","upc":"886444160742","catalog":"X1"}`), &ids); err != nil {
+		t.Fatal(err)
+	}
+	if ids.ISRC != "USSUB0665807" || ids.UPC != "886444160742" || ids.EAN != "" {
+		t.Errorf("Got %+v\n", ids)
+	}
+	if len(ids.Raw) != 1 || ids.Raw["catalog"] != "X1" {
+		t.Errorf("Expected the unknown ID in Raw, got %v\n", ids.Raw)
+	}
+	out, err := json.Marshal(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"catalog":"X1","isrc":"USSUB0665807","upc":"886444160742"}`; string(out) != want {
+		t.Errorf("Got %s, want %s\n", out, want)
+	}
+}
+
+func TestImagesBestFor(t *testing.T) {
+	images := Images{
+		{Width: 640, Height: 640, URL: "large"},
+		{Width: 300, Height: 300, URL: "medium"},
+		{Width: 64, Height: 64, URL: "small"},
+	}
+	tests := []struct {
+		width, height int
+		want          string
+	}{
+		{32, 32, "small"},
+		{64, 64, "small"},
+		{65, 40, "medium"},
+		{300, 301, "large"},
+		{1000, 1000, "large"},
+	}
+	for _, test := range tests {
+		if got := images.BestFor(test.width, test.height).URL; got != test.want {
+			t.Errorf("BestFor(%d, %d) = %s, want %s\n", test.width, test.height, got, test.want)
+		}
+	}
+	if (Images{}).BestFor(1, 1) != nil {
+		t.Error("Expected nil from no images")
+	}
+	unknown := Images{{URL: "unknown"}, {Width: 64, Height: 64, URL: "small"}}
+	if got := unknown.BestFor(300, 300).URL; got != "small" {
+		t.Errorf("Expected an image of known size, got %s\n", got)
+	}
+}
+
+func TestDownloadImage(t *testing.T) {
+	client := testClientString(http.StatusOK, "not really a jpeg")
+	var buf bytes.Buffer
+	if err := client.DownloadImage(context.Background(), Image{URL: "https://i.scdn.co/image/abc"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "not really a jpeg" {
+		t.Errorf("Got %q\n", buf.String())
+	}
+
+	defer func(max int64) { MaxImageSize = max }(MaxImageSize)
+	MaxImageSize = 4
+	client = testClientString(http.StatusOK, "too large")
+	if err := client.DownloadImage(context.Background(), Image{URL: "https://i.scdn.co/image/abc"}, ioutil.Discard); err != ErrImageTooLarge {
+		t.Errorf("Expected ErrImageTooLarge, got %v\n", err)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"status": 404, "message": "Device not found", "reason": "NO_ACTIVE_DEVICE"}}`)),
+			Request:    req,
+		}, nil
+	})})
+	err := c.Pause()
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Got %T, want *Error\n", err)
+	}
+	if e.HTTPStatus != http.StatusNotFound || e.Reason != "NO_ACTIVE_DEVICE" || e.Endpoint != "https://api.spotify.com/v1/me/player/pause" {
+		t.Errorf("Got %+v\n", e)
+	}
+	if !IsNotFound(err) || IsRateLimited(err) || IsUnauthorized(err) || IsRetryable(err) {
+		t.Errorf("Misclassified %+v\n", e)
+	}
+}
+
+func TestErrorWithoutBody(t *testing.T) {
+	client := testClientString(http.StatusTooManyRequests, "")
+	_, err := client.CurrentUser()
+	if !IsRateLimited(err) || !IsRetryable(err) {
+		t.Errorf("Got %#v, want a rate limiting error\n", err)
+	}
+	if IsRateLimited(errors.New("connection reset")) {
+		t.Error("A network error was reported as rate limiting")
+	}
+}
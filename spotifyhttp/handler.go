@@ -0,0 +1,224 @@
+// Package spotifyhttp provides an HTTP handler that serves the current
+// user's personalization data as JSON, so that an App Engine application can
+// expose Spotify-backed endpoints with a few lines:
+//
+//	h := &spotifyhttp.Handler{
+//		Client: func(r *http.Request) (*spotify.Client, error) {
+//			ctx := appengine.NewContext(r)
+//			return auth.NewClientFromContext(ctx, store, userID(r))
+//		},
+//		User:  userID,
+//		Cache: cache,
+//	}
+//	http.Handle("/api/", h)
+//
+// The handler serves these endpoints, under the path it is mounted at:
+//
+//	GET top-tracks?limit=20&range=medium_term
+//	GET top-artists?limit=20&range=medium_term
+//	GET recent?limit=20
+//	GET now-playing
+//
+// Responses are the objects returned by the spotify package, encoded as
+// JSON.  Errors are objects like the Web API's: {"error": {"status": 404,
+// "message": "..."}}.
+package spotifyhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Handler serves the personalization endpoints described in the package
+// documentation.
+type Handler struct {
+	// Client returns the client for the user making the request, such as
+	// one created with Authenticator.NewClientFromContext.  If it returns
+	// spotify.ErrNoToken, the response is 401 Unauthorized.
+	Client func(r *http.Request) (*spotify.Client, error)
+	// Cache, if not nil, stores responses so that repeated requests from
+	// the same user don't go back to the Web API.  It is only used if User
+	// is set too.
+	Cache spotify.Cache
+	// User returns an ID for the user making the request, used to keep the
+	// users' cached responses apart.  If it returns the empty string, the
+	// response isn't cached.
+	User func(r *http.Request) string
+	// TTL returns how long the response to r may be cached.  If it is nil,
+	// DefaultTTL is used.
+	TTL func(r *http.Request) time.Duration
+}
+
+// DefaultTTL returns how long responses are cached by default: an hour for
+// the top tracks and artists, which change slowly, a minute for the recently
+// played tracks, and ten seconds for the track that is playing.
+func DefaultTTL(r *http.Request) time.Duration {
+	switch path.Base(r.URL.Path) {
+	case "top-tracks", "top-artists":
+		return time.Hour
+	case "recent":
+		return time.Minute
+	case "now-playing":
+		return 10 * time.Second
+	}
+	return 0
+}
+
+// endpoint fetches the response of one of the handler's endpoints.
+type endpoint struct {
+	// params are the query parameters the endpoint accepts.
+	params []string
+	fetch  func(ctx context.Context, c *spotify.Client, q url.Values) (interface{}, error)
+}
+
+var endpoints = map[string]endpoint{
+	"top-tracks": {[]string{"limit", "range"}, func(ctx context.Context, c *spotify.Client, q url.Values) (interface{}, error) {
+		opt, err := topOptions(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.CurrentUserTopTracksWithContext(ctx, opt)
+	}},
+	"top-artists": {[]string{"limit", "range"}, func(ctx context.Context, c *spotify.Client, q url.Values) (interface{}, error) {
+		opt, err := topOptions(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.CurrentUserTopArtistsWithContext(ctx, opt)
+	}},
+	"recent": {[]string{"limit"}, func(ctx context.Context, c *spotify.Client, q url.Values) (interface{}, error) {
+		n, err := limit(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.CurrentUserRecentTracksWithContext(ctx, &spotify.Options{Limit: &n})
+	}},
+	"now-playing": {nil, func(ctx context.Context, c *spotify.Client, q url.Values) (interface{}, error) {
+		return c.PlayerCurrentlyPlayingWithContext(ctx, nil)
+	}},
+}
+
+// limit returns the limit query parameter, which defaults to 20.
+func limit(q url.Values) (int, error) {
+	s := q.Get("limit")
+	if s == "" {
+		return 20, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 50 {
+		return 0, &spotify.Error{Status: http.StatusBadRequest, Message: "limit must be between 1 and 50"}
+	}
+	return n, nil
+}
+
+// topOptions returns the options for the top tracks or artists.
+func topOptions(q url.Values) (*spotify.Options, error) {
+	n, err := limit(q)
+	if err != nil {
+		return nil, err
+	}
+	opt := &spotify.Options{Limit: &n}
+	switch r := q.Get("range"); r {
+	case "":
+	case "short_term", "medium_term", "long_term":
+		opt.Timerange = &r
+	default:
+		return nil, &spotify.Error{Status: http.StatusBadRequest, Message: "range must be short_term, medium_term or long_term"}
+	}
+	return opt, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, ok := endpoints[path.Base(r.URL.Path)]
+	if !ok {
+		writeError(w, &spotify.Error{Status: http.StatusNotFound, Message: "no such endpoint"})
+		return
+	}
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, &spotify.Error{Status: http.StatusMethodNotAllowed, Message: "only GET is supported"})
+		return
+	}
+
+	// Only the parameters the endpoint uses are part of the cache key, in
+	// a fixed order, so that irrelevant differences don't cause misses.
+	q := r.URL.Query()
+	used := url.Values{}
+	for _, p := range e.params {
+		if v := q.Get(p); v != "" {
+			used.Set(p, v)
+		}
+	}
+	var key string
+	var ttl time.Duration
+	if h.Cache != nil && h.User != nil {
+		if user := h.User(r); user != "" {
+			key = "spotifyhttp:" + user + ":" + path.Base(r.URL.Path) + "?" + used.Encode()
+			if h.TTL != nil {
+				ttl = h.TTL(r)
+			} else {
+				ttl = DefaultTTL(r)
+			}
+		}
+	}
+	if key != "" && ttl > 0 {
+		if body, ok := h.Cache.Get(key); ok {
+			w.Header().Set("X-Cache", "HIT")
+			writeBody(w, http.StatusOK, body)
+			return
+		}
+	}
+
+	c, err := h.Client(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	v, err := e.fetch(r.Context(), c, used)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if key != "" && ttl > 0 {
+		h.Cache.Set(key, body, ttl)
+	}
+	writeBody(w, http.StatusOK, body)
+}
+
+// writeBody writes a JSON response.
+func writeBody(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeError writes err as a JSON error object.  Errors from the Web API
+// keep their status; a missing token is 401 Unauthorized, and anything else
+// is 502 Bad Gateway.
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*spotify.Error)
+	switch {
+	case ok && e.Status != 0:
+	case err == spotify.ErrNoToken:
+		e = &spotify.Error{Status: http.StatusUnauthorized, Message: err.Error()}
+	default:
+		e = &spotify.Error{Status: http.StatusBadGateway, Message: err.Error()}
+	}
+	body, _ := json.Marshal(struct {
+		E *spotify.Error `json:"error"`
+	}{e})
+	writeBody(w, e.Status, body)
+}
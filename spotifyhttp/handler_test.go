@@ -0,0 +1,113 @@
+package spotifyhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+// newHandler returns a handler backed by a spotifytest server, with a
+// memory cache for the user "jo".
+func newHandler(s *spotifytest.Server) *Handler {
+	return &Handler{
+		Client: func(r *http.Request) (*spotify.Client, error) {
+			c := s.NewClient()
+			return &c, nil
+		},
+		Cache: spotify.NewMemoryCache(spotify.SystemClock),
+		User:  func(r *http.Request) string { return "jo" },
+	}
+}
+
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	return w
+}
+
+func TestTopTracks(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	h := newHandler(s)
+
+	w := get(h, "/api/top-tracks?limit=5&range=short_term")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body)
+	}
+	var top spotify.TopTracks
+	if err := json.Unmarshal(w.Body.Bytes(), &top); err != nil {
+		t.Fatal(err)
+	}
+	if len(top.Items) != 1 || top.Items[0].ID != spotifytest.TrackID {
+		t.Errorf("got %+v", top.Items)
+	}
+	q := s.LastRequest().URL.Query()
+	if q.Get("limit") != "5" || q.Get("time_range") != "short_term" {
+		t.Errorf("requested %s", s.LastRequest().URL)
+	}
+
+	// The same request, with the parameters in another order and an extra
+	// one, is served from the cache.
+	w = get(h, "/api/top-tracks?range=short_term&limit=5&x=1")
+	if w.Code != http.StatusOK || w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("got status %d and X-Cache %q, want a cache hit", w.Code, w.Header().Get("X-Cache"))
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestRecentAndNowPlaying(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	h := newHandler(s)
+
+	w := get(h, "/api/recent")
+	var history spotify.PlayHistory
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil || len(history.Items) != 1 {
+		t.Errorf("got %s (%v)", w.Body, err)
+	}
+	w = get(h, "/api/now-playing")
+	var cp spotify.CurrentlyPlaying
+	if err := json.Unmarshal(w.Body.Bytes(), &cp); err != nil || cp.Item == nil || cp.Item.ID != spotifytest.TrackID {
+		t.Errorf("got %s (%v)", w.Body, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	s.Handle("GET", "me/top/artists", http.StatusUnauthorized, `{"error": {"status": 401, "message": "The access token expired"}}`)
+	h := newHandler(s)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/api/top-artists", http.StatusUnauthorized},
+		{"/api/top-tracks?limit=500", http.StatusBadRequest},
+		{"/api/top-tracks?range=forever", http.StatusBadRequest},
+		{"/api/nowhere", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		w := get(h, test.target)
+		var e struct {
+			E spotify.Error `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+			t.Errorf("%s: %v", test.target, err)
+		}
+		if w.Code != test.status || e.E.Status != test.status || e.E.Message == "" {
+			t.Errorf("%s: got status %d and error %+v, want %d", test.target, w.Code, e.E, test.status)
+		}
+	}
+
+	h.Client = func(r *http.Request) (*spotify.Client, error) { return nil, spotify.ErrNoToken }
+	if w := get(h, "/api/recent"); w.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d without a token, want 401", w.Code)
+	}
+}
@@ -0,0 +1,87 @@
+package spotifytest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a spotify.Clock whose time only moves when told to.  Sleep
+// advances the clock instead of blocking, so code that backs off or polls
+// runs instantly under test.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	slept   []time.Duration
+	waiters []waiter
+}
+
+type waiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's current time.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Sleep records the duration and advances the clock by it, without blocking.
+func (f *FakeClock) Sleep(d time.Duration) {
+	f.mu.Lock()
+	f.slept = append(f.slept, d)
+	f.mu.Unlock()
+	f.Advance(d)
+}
+
+// After returns a channel that receives the clock's time once the clock
+// has been advanced by at least d.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{deadline: f.now.Add(d), c: c})
+	return c
+}
+
+// Advance moves the clock forward by d, firing any channels returned by
+// After whose deadline has passed.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	sort.Sort(byDeadline(f.waiters))
+	var pending []waiter
+	for _, w := range f.waiters {
+		if w.deadline.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- f.now
+	}
+	f.waiters = pending
+}
+
+// Slept returns the durations passed to Sleep so far, in order.
+func (f *FakeClock) Slept() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.slept...)
+}
+
+type byDeadline []waiter
+
+func (w byDeadline) Len() int           { return len(w) }
+func (w byDeadline) Less(i, j int) bool { return w[i].deadline.Before(w[j].deadline) }
+func (w byDeadline) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
@@ -0,0 +1,38 @@
+package spotifytest
+
+import (
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+var _ spotify.Clock = (*FakeClock)(nil)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	after := c.After(time.Minute)
+	c.Sleep(30 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("After fired too early")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	select {
+	case now := <-after:
+		if !now.Equal(start.Add(time.Minute)) {
+			t.Errorf("After fired at %v\n", now)
+		}
+	default:
+		t.Fatal("After didn't fire")
+	}
+	if got := c.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Now = %v\n", got)
+	}
+	if s := c.Slept(); len(s) != 1 || s[0] != 30*time.Second {
+		t.Error("Unexpected sleeps:", s)
+	}
+}
@@ -0,0 +1,528 @@
+package spotifytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Fake is a Server whose playlist, library and follow endpoints are backed
+// by in-memory state instead of canned responses.  Playlists created through
+// the client can have tracks added, removed, replaced and reordered, and read
+// back consistently, which makes it suitable for testing higher level sync
+// logic.  Endpoints that aren't stateful fall back to the canned responses.
+type Fake struct {
+	*Server
+
+	mu        sync.Mutex
+	catalog   map[spotify.ID]spotify.FullTrack
+	playlists map[spotify.ID]*fakePlaylist
+	order     []spotify.ID
+	saved     []spotify.ID
+	following map[spotify.ID]bool
+	nextID    int
+}
+
+type fakePlaylist struct {
+	spotify.FullPlaylist
+	tracks  []spotify.ID
+	version int
+}
+
+// NewFake starts a Fake server with an empty library and no playlists.
+// The caller should call Close when finished.
+func NewFake() *Fake {
+	f := &Fake{
+		Server:    NewServer(),
+		catalog:   make(map[spotify.ID]spotify.FullTrack),
+		playlists: make(map[spotify.ID]*fakePlaylist),
+		following: make(map[spotify.ID]bool),
+	}
+	f.HandleFunc("GET", "tracks/*", f.getTrack)
+	f.HandleFunc("GET", "tracks", f.getTracks)
+	f.HandleFunc("GET", "me/playlists", f.listPlaylists)
+	f.HandleFunc("GET", "users/*/playlists", f.listPlaylists)
+	f.HandleFunc("POST", "users/*/playlists", f.createPlaylist)
+	f.HandleFunc("GET", "users/*/playlists/*", f.getPlaylist)
+	f.HandleFunc("PUT", "users/*/playlists/*", f.changePlaylist)
+	f.HandleFunc("GET", "users/*/playlists/*/tracks", f.getPlaylistTracks)
+	f.HandleFunc("POST", "users/*/playlists/*/tracks", f.addPlaylistTracks)
+	f.HandleFunc("DELETE", "users/*/playlists/*/tracks", f.removePlaylistTracks)
+	f.HandleFunc("PUT", "users/*/playlists/*/tracks", f.replaceOrReorderPlaylistTracks)
+	f.HandleFunc("GET", "me/tracks", f.getSavedTracks)
+	f.HandleFunc("PUT", "me/tracks", f.saveTracks)
+	f.HandleFunc("DELETE", "me/tracks", f.removeSavedTracks)
+	f.HandleFunc("GET", "me/tracks/contains", f.containsSavedTracks)
+	f.HandleFunc("PUT", "me/following", f.follow)
+	f.HandleFunc("DELETE", "me/following", f.unfollow)
+	f.HandleFunc("GET", "me/following/contains", f.containsFollowing)
+	return f
+}
+
+// AddTrack adds a track to the fake catalog.  Tracks that are referenced
+// but not in the catalog are synthesized with only their ID, URI and a
+// placeholder name.
+func (f *Fake) AddTrack(t spotify.FullTrack) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.catalog[t.ID] = t
+}
+
+// PlaylistTracks returns the IDs of the tracks in the playlist, in order.
+func (f *Fake) PlaylistTracks(id spotify.ID) []spotify.ID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, ok := f.playlists[id]; ok {
+		return append([]spotify.ID(nil), p.tracks...)
+	}
+	return nil
+}
+
+// SavedTracks returns the IDs of the tracks in the user's library, most
+// recently saved first.
+func (f *Fake) SavedTracks() []spotify.ID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]spotify.ID(nil), f.saved...)
+}
+
+// track returns the catalog entry for id.  f.mu must be held.
+func (f *Fake) track(id spotify.ID) spotify.FullTrack {
+	if t, ok := f.catalog[id]; ok {
+		return t
+	}
+	var t spotify.FullTrack
+	t.ID = id
+	t.URI = spotify.BuildURI(spotify.ItemTypeTrack, id)
+	t.Name = "Track " + string(id)
+	t.Endpoint = apiURL + "tracks/" + string(id)
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]spotify.Error{"error": {Status: status, Message: msg}})
+}
+
+// segment returns the i'th segment of the request path after /v1/.
+func segment(r *http.Request, i int) string {
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, "/v1/"))
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+// paging reads the limit and offset query parameters.
+func paging(r *http.Request, defaultLimit int) (limit, offset int) {
+	limit, offset = defaultLimit, 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
+		offset = o
+	}
+	return limit, offset
+}
+
+// window returns the bounds of the page [offset, offset+limit) in a
+// collection of n items.
+func window(n, limit, offset int) (int, int) {
+	if offset > n {
+		offset = n
+	}
+	end := offset + limit
+	if end > n {
+		end = n
+	}
+	return offset, end
+}
+
+// trackIDs extracts track IDs from a comma separated list of URIs or IDs.
+func trackIDs(list string) []spotify.ID {
+	var ids []spotify.ID
+	for _, s := range strings.Split(list, ",") {
+		if s == "" {
+			continue
+		}
+		if _, id, err := spotify.ParseURI(s); err == nil {
+			ids = append(ids, id)
+		} else {
+			ids = append(ids, spotify.ID(s))
+		}
+	}
+	return ids
+}
+
+func (f *Fake) getTrack(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	writeJSON(w, http.StatusOK, f.track(spotify.ID(segment(r, 1))))
+}
+
+func (f *Fake) getTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result struct {
+		Tracks []spotify.FullTrack `json:"tracks"`
+	}
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		result.Tracks = append(result.Tracks, f.track(id))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (f *Fake) listPlaylists(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	limit, offset := paging(r, 20)
+	start, end := window(len(f.order), limit, offset)
+	var page spotify.SimplePlaylistPage
+	page.Limit, page.Offset, page.Total = limit, offset, len(f.order)
+	page.Endpoint = apiURL + strings.TrimPrefix(r.URL.Path, "/v1/")
+	page.Playlists = []spotify.SimplePlaylist{}
+	for _, id := range f.order[start:end] {
+		page.Playlists = append(page.Playlists, f.playlists[id].simple())
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (p *fakePlaylist) simple() spotify.SimplePlaylist {
+	s := p.SimplePlaylist
+	s.SnapshotID = p.snapshot()
+	s.Tracks.Total = uint(len(p.tracks))
+	return s
+}
+
+func (p *fakePlaylist) snapshot() string {
+	return fmt.Sprintf("%s-%d", p.ID, p.version)
+}
+
+func (f *Fake) createPlaylist(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Public      bool   `json:"public"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	owner := segment(r, 1)
+	id := spotify.ID(fmt.Sprintf("fakeplaylist%010d", f.nextID))
+	p := &fakePlaylist{}
+	p.ID = id
+	p.Name = body.Name
+	p.IsPublic = body.Public
+	p.Description = body.Description
+	p.Owner.ID = owner
+	p.URI = spotify.BuildURI(spotify.ItemTypePlaylist, id)
+	p.Endpoint = apiURL + "users/" + owner + "/playlists/" + string(id)
+	p.SimplePlaylist.Tracks.Endpoint = p.Endpoint + "/tracks"
+	f.playlists[id] = p
+	f.order = append(f.order, id)
+	writeJSON(w, http.StatusCreated, f.full(p, 100, 0))
+}
+
+// full returns the complete playlist object, including the first page of
+// tracks.  f.mu must be held.
+func (f *Fake) full(p *fakePlaylist, limit, offset int) spotify.FullPlaylist {
+	full := p.FullPlaylist
+	full.SimplePlaylist = p.simple()
+	full.Tracks = f.trackPage(p, limit, offset)
+	return full
+}
+
+func (f *Fake) trackPage(p *fakePlaylist, limit, offset int) spotify.PlaylistTrackPage {
+	var page spotify.PlaylistTrackPage
+	page.Endpoint = p.Endpoint + "/tracks"
+	page.Limit, page.Offset, page.Total = limit, offset, len(p.tracks)
+	start, end := window(len(p.tracks), limit, offset)
+	if end < len(p.tracks) {
+		page.Next = fmt.Sprintf("%s?offset=%d&limit=%d", page.Endpoint, end, limit)
+	}
+	page.Tracks = []spotify.PlaylistTrack{}
+	for _, id := range p.tracks[start:end] {
+		var item spotify.PlaylistTrack
+		item.AddedBy.ID = p.Owner.ID
+		item.Track = f.track(id)
+		page.Tracks = append(page.Tracks, item)
+	}
+	return page
+}
+
+// lookup returns the playlist identified by the request path, writing a
+// 404 response if it doesn't exist.  f.mu must be held.
+func (f *Fake) lookup(w http.ResponseWriter, r *http.Request) *fakePlaylist {
+	p, ok := f.playlists[spotify.ID(segment(r, 3))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+	return p
+}
+
+func (f *Fake) getPlaylist(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p := f.lookup(w, r); p != nil {
+		writeJSON(w, http.StatusOK, f.full(p, 100, 0))
+	}
+}
+
+func (f *Fake) changePlaylist(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name          *string `json:"name"`
+		Public        *bool   `json:"public"`
+		Collaborative *bool   `json:"collaborative"`
+		Description   *string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.lookup(w, r)
+	if p == nil {
+		return
+	}
+	if body.Name != nil {
+		p.Name = *body.Name
+	}
+	if body.Public != nil {
+		p.IsPublic = *body.Public
+	}
+	if body.Collaborative != nil {
+		p.Collaborative = *body.Collaborative
+	}
+	if body.Description != nil {
+		p.Description = *body.Description
+	}
+	p.version++
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *Fake) getPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p := f.lookup(w, r); p != nil {
+		limit, offset := paging(r, 100)
+		writeJSON(w, http.StatusOK, f.trackPage(p, limit, offset))
+	}
+}
+
+func (f *Fake) writeSnapshot(w http.ResponseWriter, status int, p *fakePlaylist) {
+	p.version++
+	writeJSON(w, status, map[string]string{"snapshot_id": p.snapshot()})
+}
+
+func (f *Fake) addPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.lookup(w, r)
+	if p == nil {
+		return
+	}
+	ids := trackIDs(r.URL.Query().Get("uris"))
+	if len(ids) > 100 {
+		writeError(w, http.StatusBadRequest, "You can add a maximum of 100 tracks per request.")
+		return
+	}
+	p.tracks = append(p.tracks, ids...)
+	f.writeSnapshot(w, http.StatusCreated, p)
+}
+
+func (f *Fake) removePlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Tracks []struct {
+			URI       string `json:"uri"`
+			Positions []int  `json:"positions"`
+		} `json:"tracks"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.lookup(w, r)
+	if p == nil {
+		return
+	}
+	remove := make([]bool, len(p.tracks))
+	for _, t := range body.Tracks {
+		ids := trackIDs(t.URI)
+		if len(ids) != 1 {
+			writeError(w, http.StatusBadRequest, "Invalid track uri: "+t.URI)
+			return
+		}
+		id := ids[0]
+		if len(t.Positions) == 0 {
+			for i, tid := range p.tracks {
+				if tid == id {
+					remove[i] = true
+				}
+			}
+			continue
+		}
+		for _, pos := range t.Positions {
+			if pos < 0 || pos >= len(p.tracks) || p.tracks[pos] != id {
+				writeError(w, http.StatusBadRequest, "Could not remove tracks, please check parameters.")
+				return
+			}
+			remove[pos] = true
+		}
+	}
+	kept := p.tracks[:0]
+	for i, id := range p.tracks {
+		if !remove[i] {
+			kept = append(kept, id)
+		}
+	}
+	p.tracks = kept
+	f.writeSnapshot(w, http.StatusOK, p)
+}
+
+func (f *Fake) replaceOrReorderPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.lookup(w, r)
+	if p == nil {
+		return
+	}
+	if uris, ok := r.URL.Query()["uris"]; ok {
+		p.tracks = trackIDs(strings.Join(uris, ","))
+		f.writeSnapshot(w, http.StatusCreated, p)
+		return
+	}
+	var opt spotify.PlaylistReorderOptions
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		writeError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return
+	}
+	if opt.RangeLength == 0 {
+		opt.RangeLength = 1
+	}
+	n := len(p.tracks)
+	if opt.RangeStart < 0 || opt.RangeStart+opt.RangeLength > n || opt.InsertBefore < 0 || opt.InsertBefore > n {
+		writeError(w, http.StatusBadRequest, "Index out of bounds.")
+		return
+	}
+	moved := append([]spotify.ID(nil), p.tracks[opt.RangeStart:opt.RangeStart+opt.RangeLength]...)
+	var rest []spotify.ID
+	insert := opt.InsertBefore
+	for i, id := range p.tracks {
+		if i >= opt.RangeStart && i < opt.RangeStart+opt.RangeLength {
+			continue
+		}
+		rest = append(rest, id)
+	}
+	if insert > opt.RangeStart {
+		insert -= opt.RangeLength
+	}
+	p.tracks = append(append(append([]spotify.ID(nil), rest[:insert]...), moved...), rest[insert:]...)
+	f.writeSnapshot(w, http.StatusOK, p)
+}
+
+func (f *Fake) getSavedTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	limit, offset := paging(r, 20)
+	start, end := window(len(f.saved), limit, offset)
+	var page spotify.SavedTrackPage
+	page.Endpoint = apiURL + "me/tracks"
+	page.Limit, page.Offset, page.Total = limit, offset, len(f.saved)
+	if end < len(f.saved) {
+		page.Next = fmt.Sprintf("%s?offset=%d&limit=%d", page.Endpoint, end, limit)
+	}
+	page.Tracks = []spotify.SavedTrack{}
+	for _, id := range f.saved[start:end] {
+		page.Tracks = append(page.Tracks, spotify.SavedTrack{FullTrack: f.track(id)})
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (f *Fake) saveTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		if !contains(f.saved, id) {
+			f.saved = append([]spotify.ID{id}, f.saved...)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *Fake) removeSavedTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := trackIDs(r.URL.Query().Get("ids"))
+	kept := f.saved[:0]
+	for _, id := range f.saved {
+		if !contains(ids, id) {
+			kept = append(kept, id)
+		}
+	}
+	f.saved = kept
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *Fake) containsSavedTracks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := []bool{}
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		result = append(result, contains(f.saved, id))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (f *Fake) follow(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		f.following[id] = true
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *Fake) unfollow(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		delete(f.following, id)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *Fake) containsFollowing(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := []bool{}
+	for _, id := range trackIDs(r.URL.Query().Get("ids")) {
+		result = append(result, f.following[id])
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func contains(ids []spotify.ID, id spotify.ID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
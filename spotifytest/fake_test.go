@@ -0,0 +1,122 @@
+package spotifytest
+
+import (
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+var (
+	trackA = spotify.ID("4iV5W9uYEdYUVa79Axb7Rh")
+	trackB = spotify.ID("1301WleyT98MSxVHPZCA6M")
+	trackC = spotify.ID("6rqhFgbbKwnb9MLmUQDhG6")
+)
+
+func TestFakePlaylistFlow(t *testing.T) {
+	f := NewFake()
+	defer f.Close()
+	c := f.NewClient()
+
+	pl, err := c.CreatePlaylistForUser(UserID, "Road Trip", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pl.Name != "Road Trip" || !pl.IsPublic {
+		t.Errorf("Unexpected playlist %+v\n", pl.SimplePlaylist)
+	}
+	if _, err := c.AddTracksToPlaylist(UserID, pl.ID, trackA, trackB, trackC); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReorderPlaylistTracks(UserID, pl.ID, spotify.PlaylistReorderOptions{RangeStart: 2, InsertBefore: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RemoveTracksFromPlaylist(UserID, pl.ID, trackB); err != nil {
+		t.Fatal(err)
+	}
+
+	tracks, err := c.GetPlaylistTracks(UserID, pl.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracks.Total != 2 || len(tracks.Tracks) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d\n", tracks.Total)
+	}
+	if tracks.Tracks[0].Track.ID != trackC || tracks.Tracks[1].Track.ID != trackA {
+		t.Error("Tracks are in the wrong order")
+	}
+
+	playlists, err := c.CurrentUsersPlaylists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playlists.Playlists) != 1 || playlists.Playlists[0].Tracks.Total != 2 {
+		t.Errorf("Unexpected playlists %+v\n", playlists.Playlists)
+	}
+
+	if err := c.ReplacePlaylistTracks(UserID, pl.ID, trackB); err != nil {
+		t.Fatal(err)
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 1 || ids[0] != trackB {
+		t.Error("Unexpected tracks after replace:", ids)
+	}
+}
+
+func TestFakePlaylistPaging(t *testing.T) {
+	f := NewFake()
+	defer f.Close()
+	c := f.NewClient()
+
+	pl, err := c.CreatePlaylistForUser(UserID, "Paged", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddTracksToPlaylist(UserID, pl.ID, trackA, trackB, trackC); err != nil {
+		t.Fatal(err)
+	}
+	limit, offset := 2, 2
+	page, err := c.GetPlaylistTracksOpt(UserID, pl.ID, &spotify.Options{Limit: &limit, Offset: &offset}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Tracks) != 1 || page.Tracks[0].Track.ID != trackC {
+		t.Errorf("Unexpected page %+v\n", page)
+	}
+}
+
+func TestFakeLibrary(t *testing.T) {
+	f := NewFake()
+	defer f.Close()
+	c := f.NewClient()
+
+	if err := c.AddTracksToLibrary(trackA, trackB); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveTracksFromLibrary(trackA); err != nil {
+		t.Fatal(err)
+	}
+	has, err := c.UserHasTracks(trackA, trackB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has[0] || !has[1] {
+		t.Error("Unexpected library contents:", has)
+	}
+	saved, err := c.CurrentUsersTracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Tracks) != 1 || saved.Tracks[0].ID != trackB {
+		t.Errorf("Unexpected saved tracks %+v\n", saved.Tracks)
+	}
+}
+
+func TestFakeUnknownPlaylist(t *testing.T) {
+	f := NewFake()
+	defer f.Close()
+	c := f.NewClient()
+
+	_, err := c.GetPlaylist(UserID, PlaylistID)
+	if serr, ok := err.(*spotify.Error); !ok || serr.Status != 404 {
+		t.Errorf("Expected HTTP 404, got %v\n", err)
+	}
+}
@@ -0,0 +1,189 @@
+package spotifytest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault identifies a kind of failure injected by a FaultTransport.
+type Fault int
+
+const (
+	// FaultNone means the request was passed through unchanged.
+	FaultNone Fault = iota
+	// FaultRateLimit responds with 429 Too Many Requests and a Retry-After
+	// header.
+	FaultRateLimit
+	// FaultServerError responds with 500 Internal Server Error.
+	FaultServerError
+	// FaultTimeout fails the request with a network timeout error.
+	FaultTimeout
+	// FaultTruncate passes the request through but cuts the response body
+	// short, as if the connection dropped.
+	FaultTruncate
+)
+
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultRateLimit:
+		return "rate limit"
+	case FaultServerError:
+		return "server error"
+	case FaultTimeout:
+		return "timeout"
+	case FaultTruncate:
+		return "truncate"
+	}
+	return "Fault(" + strconv.Itoa(int(f)) + ")"
+}
+
+// FaultTransport is an http.RoundTripper that randomly injects failures
+// into otherwise successful requests, for validating retry and backoff
+// behavior.  Each rate is the probability, between 0 and 1, that a request
+// fails in that way.  The rates are checked in the order they are declared
+// and at most one fault is injected per request.
+type FaultTransport struct {
+	// Base is used to send requests that aren't failed outright.  If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	RateLimitRate   float64
+	ServerErrorRate float64
+	TimeoutRate     float64
+	TruncateRate    float64
+
+	// RetryAfter is the delay suggested by injected 429 responses.  It is
+	// rounded down to whole seconds, and defaults to one second.
+	RetryAfter time.Duration
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	counts map[Fault]int
+}
+
+// NewFaultTransport creates a FaultTransport that sends requests using base
+// and draws from a random source with the given seed, so that a failing
+// test can be reproduced exactly.
+func NewFaultTransport(base http.RoundTripper, seed int64) *FaultTransport {
+	return &FaultTransport{
+		Base: base,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Counts returns the number of requests that were subjected to each kind
+// of fault, including FaultNone.
+func (t *FaultTransport) Counts() map[Fault]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := make(map[Fault]int, len(t.counts))
+	for f, n := range t.counts {
+		counts[f] = n
+	}
+	return counts
+}
+
+// pick chooses the fault for the next request.
+func (t *FaultTransport) pick() Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rand == nil {
+		t.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if t.counts == nil {
+		t.counts = make(map[Fault]int)
+	}
+	f := FaultNone
+	r := t.rand.Float64()
+	for _, c := range []struct {
+		fault Fault
+		rate  float64
+	}{
+		{FaultRateLimit, t.RateLimitRate},
+		{FaultServerError, t.ServerErrorRate},
+		{FaultTimeout, t.TimeoutRate},
+		{FaultTruncate, t.TruncateRate},
+	} {
+		if r < c.rate {
+			f = c.fault
+			break
+		}
+		r -= c.rate
+	}
+	t.counts[f]++
+	return f
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	switch t.pick() {
+	case FaultRateLimit:
+		retry := int(t.RetryAfter / time.Second)
+		if retry < 1 {
+			retry = 1
+		}
+		resp := errorResponse(req, http.StatusTooManyRequests, "API rate limit exceeded")
+		resp.Header.Set("Retry-After", strconv.Itoa(retry))
+		return resp, nil
+	case FaultServerError:
+		return errorResponse(req, http.StatusInternalServerError, "Server error"), nil
+	case FaultTimeout:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, timeoutError{}
+	case FaultTruncate:
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body[:len(body)/2]))
+		resp.ContentLength = -1
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+	return base.RoundTrip(req)
+}
+
+// errorResponse builds a response carrying a Web API error object.
+func errorResponse(req *http.Request, status int, msg string) *http.Response {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	body := fmt.Sprintf(`{"error":{"status":%d,"message":%q}}`, status, msg)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// timeoutError is a net.Error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "spotifytest: injected timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,61 @@
+package spotifytest
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func TestFaultTransport(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ft := NewFaultTransport(s.Transport(), 1)
+	ft.RateLimitRate = 0.25
+	ft.ServerErrorRate = 0.25
+	ft.TimeoutRate = 0.25
+	ft.TruncateRate = 0.25
+	c := spotify.NewClient(&http.Client{Transport: ft})
+
+	for i := 0; i < 40; i++ {
+		_, err := c.GetTrack(TrackID)
+		if err == nil {
+			t.Fatal("Expected every request to fail")
+		}
+		if uerr, ok := err.(*url.Error); ok {
+			if nerr, ok := uerr.Err.(net.Error); !ok || !nerr.Timeout() {
+				t.Error("Expected timeout error, got", err)
+			}
+			continue
+		}
+		if serr, ok := err.(*spotify.Error); ok {
+			if serr.Status != http.StatusTooManyRequests && serr.Status != http.StatusInternalServerError {
+				t.Error("Unexpected status", serr.Status)
+			}
+		}
+	}
+	counts := ft.Counts()
+	for _, f := range []Fault{FaultRateLimit, FaultServerError, FaultTimeout, FaultTruncate} {
+		if counts[f] == 0 {
+			t.Errorf("No %s faults were injected\n", f)
+		}
+	}
+	if counts[FaultNone] != 0 {
+		t.Errorf("%d requests weren't failed\n", counts[FaultNone])
+	}
+}
+
+func TestFaultTransportRetryAfter(t *testing.T) {
+	ft := NewFaultTransport(nil, 1)
+	ft.RateLimitRate = 1
+	req, _ := http.NewRequest("GET", "https://api.spotify.com/v1/me", nil)
+	resp, err := ft.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "1" {
+		t.Errorf("Unexpected response %d, Retry-After %q\n", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
@@ -0,0 +1,449 @@
+package spotifytest
+
+import "net/http"
+
+// fixture is a canned response for one endpoint.
+type fixture struct {
+	method  string
+	pattern string
+	status  int
+	body    string
+}
+
+// Canned objects that are shared between responses.  They are based on
+// real responses from the Spotify Web API.
+const (
+	// ArtistID is the ID of the artist returned by the canned responses.
+	ArtistID = "0OdUWJ0sBjDrqHygGUXeCF"
+	// AlbumID is the ID of the album returned by the canned responses.
+	AlbumID = "6akEvsycLGftJxYudPjmqK"
+	// TrackID is the ID of the track returned by the canned responses.
+	TrackID = "6rqhFgbbKwnb9MLmUQDhG6"
+	// PlaylistID is the ID of the playlist returned by the canned responses.
+	PlaylistID = "59ZbFPES4DQwEjBpWHzrtC"
+	// UserID is the ID of the current user in the canned responses.
+	UserID = "wizzler"
+
+	simpleArtist = `{
+		"external_urls": {"spotify": "https://open.spotify.com/artist/` + ArtistID + `"},
+		"href": "https://api.spotify.com/v1/artists/` + ArtistID + `",
+		"id": "` + ArtistID + `",
+		"name": "Band of Horses",
+		"type": "artist",
+		"uri": "spotify:artist:` + ArtistID + `"
+	}`
+
+	fullArtist = `{
+		"external_urls": {"spotify": "https://open.spotify.com/artist/` + ArtistID + `"},
+		"followers": {"href": null, "total": 1085964},
+		"genres": ["indie folk", "indie pop", "indie rock", "modern rock"],
+		"href": "https://api.spotify.com/v1/artists/` + ArtistID + `",
+		"id": "` + ArtistID + `",
+		"images": [
+			{"height": 640, "url": "https://i.scdn.co/image/0f9a5013134de288af7d49a962417f4200539b47", "width": 640},
+			{"height": 320, "url": "https://i.scdn.co/image/8ae35be1043f8ab2e2d4c1c5d4b9ce43cdd8f0f6", "width": 320},
+			{"height": 160, "url": "https://i.scdn.co/image/602dd7c5ba8fa85e0f5b1ed4be9c0d0c4dbd8ba5", "width": 160}
+		],
+		"name": "Band of Horses",
+		"popularity": 64,
+		"type": "artist",
+		"uri": "spotify:artist:` + ArtistID + `"
+	}`
+
+	images = `[
+		{"height": 640, "url": "https://i.scdn.co/image/ab67616d0000b273a8e0b3b8b8a9ba2f8d7bcb2c", "width": 640},
+		{"height": 300, "url": "https://i.scdn.co/image/ab67616d00001e02a8e0b3b8b8a9ba2f8d7bcb2c", "width": 300},
+		{"height": 64, "url": "https://i.scdn.co/image/ab67616d00004851a8e0b3b8b8a9ba2f8d7bcb2c", "width": 64}
+	]`
+
+	simpleAlbum = `{
+		"album_type": "album",
+		"artists": [` + simpleArtist + `],
+		"available_markets": ["CA", "MX", "US"],
+		"external_urls": {"spotify": "https://open.spotify.com/album/` + AlbumID + `"},
+		"href": "https://api.spotify.com/v1/albums/` + AlbumID + `",
+		"id": "` + AlbumID + `",
+		"images": ` + images + `,
+		"name": "Everything All The Time",
+		"release_date": "2006-03-21",
+		"release_date_precision": "day",
+		"type": "album",
+		"uri": "spotify:album:` + AlbumID + `"
+	}`
+
+	simpleTrack = `{
+		"artists": [` + simpleArtist + `],
+		"available_markets": ["CA", "MX", "US"],
+		"disc_number": 1,
+		"duration_ms": 316813,
+		"explicit": false,
+		"external_urls": {"spotify": "https://open.spotify.com/track/` + TrackID + `"},
+		"href": "https://api.spotify.com/v1/tracks/` + TrackID + `",
+		"id": "` + TrackID + `",
+		"name": "The Funeral",
+		"preview_url": "https://p.scdn.co/mp3-preview/5bba4bf5f3ad4b8e7a7fc5b2f2c5b8c0a1e7bb64",
+		"track_number": 7,
+		"type": "track",
+		"uri": "spotify:track:` + TrackID + `"
+	}`
+
+	fullTrack = `{
+		"album": ` + simpleAlbum + `,
+		"artists": [` + simpleArtist + `],
+		"available_markets": ["CA", "MX", "US"],
+		"disc_number": 1,
+		"duration_ms": 316813,
+		"explicit": false,
+		"external_ids": {"isrc": "USSUB0665807"},
+		"external_urls": {"spotify": "https://open.spotify.com/track/` + TrackID + `"},
+		"href": "https://api.spotify.com/v1/tracks/` + TrackID + `",
+		"id": "` + TrackID + `",
+		"name": "The Funeral",
+		"popularity": 71,
+		"preview_url": "https://p.scdn.co/mp3-preview/5bba4bf5f3ad4b8e7a7fc5b2f2c5b8c0a1e7bb64",
+		"track_number": 7,
+		"type": "track",
+		"uri": "spotify:track:` + TrackID + `"
+	}`
+
+	fullAlbum = `{
+		"album_type": "album",
+		"artists": [` + simpleArtist + `],
+		"available_markets": ["CA", "MX", "US"],
+		"copyrights": [
+			{"text": "(C) 2006 Sub Pop Records", "type": "C"},
+			{"text": "(P) 2006 Sub Pop Records", "type": "P"}
+		],
+		"external_ids": {"upc": "098787069125"},
+		"external_urls": {"spotify": "https://open.spotify.com/album/` + AlbumID + `"},
+		"genres": [],
+		"href": "https://api.spotify.com/v1/albums/` + AlbumID + `",
+		"id": "` + AlbumID + `",
+		"images": ` + images + `,
+		"name": "Everything All The Time",
+		"popularity": 61,
+		"release_date": "2006-03-21",
+		"release_date_precision": "day",
+		"tracks": ` + simpleTrackPage + `,
+		"type": "album",
+		"uri": "spotify:album:` + AlbumID + `"
+	}`
+
+	simpleTrackPage = `{
+		"href": "https://api.spotify.com/v1/albums/` + AlbumID + `/tracks?offset=0&limit=50",
+		"items": [` + simpleTrack + `],
+		"limit": 50,
+		"next": null,
+		"offset": 0,
+		"previous": null,
+		"total": 1
+	}`
+
+	user = `{
+		"display_name": "Lilla Namo",
+		"external_urls": {"spotify": "https://open.spotify.com/user/` + UserID + `"},
+		"followers": {"href": null, "total": 3829},
+		"href": "https://api.spotify.com/v1/users/` + UserID + `",
+		"id": "` + UserID + `",
+		"images": [{"height": null, "url": "https://i.scdn.co/image/ab6775700000ee8555c25988a6ac314394d3fbf5", "width": null}],
+		"type": "user",
+		"uri": "spotify:user:` + UserID + `"
+	}`
+
+	privateUser = `{
+		"birthdate": "1937-06-01",
+		"country": "SE",
+		"display_name": "Lilla Namo",
+		"email": "lilla.namo@example.com",
+		"external_urls": {"spotify": "https://open.spotify.com/user/` + UserID + `"},
+		"followers": {"href": null, "total": 3829},
+		"href": "https://api.spotify.com/v1/users/` + UserID + `",
+		"id": "` + UserID + `",
+		"images": [{"height": null, "url": "https://i.scdn.co/image/ab6775700000ee8555c25988a6ac314394d3fbf5", "width": null}],
+		"product": "premium",
+		"type": "user",
+		"uri": "spotify:user:` + UserID + `"
+	}`
+
+	simplePlaylist = `{
+		"collaborative": false,
+		"external_urls": {"spotify": "https://open.spotify.com/playlist/` + PlaylistID + `"},
+		"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `",
+		"id": "` + PlaylistID + `",
+		"images": ` + images + `,
+		"name": "Road Trip",
+		"owner": ` + user + `,
+		"public": true,
+		"snapshot_id": "MTAsZjEyYTU4ZWM1OWFiNzViNjQ1YjZiZjFkNGY3ZTE0NTcyNzViYjk0Mg==",
+		"tracks": {"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `/tracks", "total": 1},
+		"type": "playlist",
+		"uri": "spotify:user:` + UserID + `:playlist:` + PlaylistID + `"
+	}`
+
+	playlistTrackPage = `{
+		"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `/tracks?offset=0&limit=100",
+		"items": [{
+			"added_at": "2016-10-11T13:44:40Z",
+			"added_by": ` + user + `,
+			"is_local": false,
+			"track": ` + fullTrack + `
+		}],
+		"limit": 100,
+		"next": null,
+		"offset": 0,
+		"previous": null,
+		"total": 1
+	}`
+
+	fullPlaylist = `{
+		"collaborative": false,
+		"description": "Songs for the open road",
+		"external_urls": {"spotify": "https://open.spotify.com/playlist/` + PlaylistID + `"},
+		"followers": {"href": null, "total": 17},
+		"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `",
+		"id": "` + PlaylistID + `",
+		"images": ` + images + `,
+		"name": "Road Trip",
+		"owner": ` + user + `,
+		"public": true,
+		"snapshot_id": "MTAsZjEyYTU4ZWM1OWFiNzViNjQ1YjZiZjFkNGY3ZTE0NTcyNzViYjk0Mg==",
+		"tracks": ` + playlistTrackPage + `,
+		"type": "playlist",
+		"uri": "spotify:user:` + UserID + `:playlist:` + PlaylistID + `"
+	}`
+
+	audioFeatures = `{
+		"acousticness": 0.00242,
+		"analysis_url": "https://api.spotify.com/v1/audio-analysis/` + TrackID + `",
+		"danceability": 0.585,
+		"duration_ms": 316813,
+		"energy": 0.842,
+		"id": "` + TrackID + `",
+		"instrumentalness": 0.00686,
+		"key": 9,
+		"liveness": 0.0866,
+		"loudness": -5.883,
+		"mode": 0,
+		"speechiness": 0.0556,
+		"tempo": 118.211,
+		"time_signature": 4,
+		"track_href": "https://api.spotify.com/v1/tracks/` + TrackID + `",
+		"type": "audio_features",
+		"uri": "spotify:track:` + TrackID + `",
+		"valence": 0.428
+	}`
+
+	audioAnalysis = `{
+		"meta": {
+			"analyzer_version": "4.0.0",
+			"platform": "Linux",
+			"detailed_status": "OK",
+			"status_code": 0,
+			"timestamp": 1495193577,
+			"analysis_time": 6.93906,
+			"input_process": "libvorbisfile L+R 44100->22050"
+		},
+		"track": {
+			"num_samples": 6985794,
+			"duration": 316.8161,
+			"sample_md5": "",
+			"offset_seconds": 0,
+			"window_seconds": 0,
+			"analysis_sample_rate": 22050,
+			"analysis_channels": 1,
+			"end_of_fade_in": 0.24,
+			"start_of_fade_out": 309.53,
+			"loudness": -5.883,
+			"tempo": 118.211,
+			"tempo_confidence": 0.73,
+			"time_signature": 4,
+			"time_signature_confidence": 0.994,
+			"key": 9,
+			"key_confidence": 0.408,
+			"mode": 0,
+			"mode_confidence": 0.485,
+			"codestring": "",
+			"code_version": 3.15,
+			"echoprintstring": "",
+			"echoprint_version": 4.15,
+			"synchstring": "",
+			"synch_version": 1,
+			"rhythmstring": "",
+			"rhythm_version": 1
+		},
+		"bars": [{"start": 0.49567, "duration": 2.18749, "confidence": 0.925}],
+		"beats": [{"start": 0.49567, "duration": 0.52929, "confidence": 0.874}],
+		"sections": [{
+			"start": 0,
+			"duration": 23.33163,
+			"confidence": 1,
+			"loudness": -21.61,
+			"tempo": 98.002,
+			"tempo_confidence": 0.402,
+			"key": 7,
+			"key_confidence": 0.609,
+			"mode": 1,
+			"mode_confidence": 0.6,
+			"time_signature": 4,
+			"time_signature_confidence": 1
+		}],
+		"segments": [{
+			"start": 0.70154,
+			"duration": 0.19891,
+			"confidence": 0.435,
+			"loudness_start": -23.053,
+			"loudness_max_time": 0.07305,
+			"loudness_max": -14.25,
+			"loudness_end": 0,
+			"pitches": [0.212, 0.141, 0.294, 0.048, 0.02, 0.062, 0.135, 1, 0.064, 0.07, 0.059, 0.085],
+			"timbre": [42.115, 64.373, -0.233, -18.713, 51.546, -18.567, 20.345, 4.879, -13.224, 2.098, -1.926, -13.562]
+		}],
+		"tatums": [{"start": 0.49567, "duration": 0.26464, "confidence": 0.874}]
+	}`
+
+	recentlyPlayed = `{
+		"items": [{
+			"track": ` + simpleTrack + `,
+			"played_at": "2017-05-19T09:13:23.631Z",
+			"context": {
+				"type": "playlist",
+				"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `",
+				"external_urls": {"spotify": "https://open.spotify.com/playlist/` + PlaylistID + `"},
+				"uri": "spotify:user:` + UserID + `:playlist:` + PlaylistID + `"
+			}
+		}],
+		"next": "https://api.spotify.com/v1/me/player/recently-played?before=1495185203631&limit=1",
+		"cursors": {"after": "1495185203631", "before": "1495185203631"},
+		"limit": 1,
+		"href": "https://api.spotify.com/v1/me/player/recently-played?limit=1"
+	}`
+
+	currentlyPlaying = `{
+		"timestamp": 1495185203631,
+		"context": {
+			"type": "playlist",
+			"href": "https://api.spotify.com/v1/users/` + UserID + `/playlists/` + PlaylistID + `",
+			"external_urls": {"spotify": "https://open.spotify.com/playlist/` + PlaylistID + `"},
+			"uri": "spotify:user:` + UserID + `:playlist:` + PlaylistID + `"
+		},
+		"progress_ms": 44272,
+		"is_playing": true,
+		"item": ` + fullTrack + `
+	}`
+
+	category = `{
+		"href": "https://api.spotify.com/v1/browse/categories/party",
+		"icons": [{"height": 274, "url": "https://t.scdn.co/media/derived/party-274x274_73d1907a7371c3bb96a288390a96ee27_0_0_274_274.jpg", "width": 274}],
+		"id": "party",
+		"name": "Party"
+	}`
+
+	snapshot = `{"snapshot_id": "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+"}`
+)
+
+func page(href, items string) string {
+	return `{
+		"href": "` + href + `",
+		"items": [` + items + `],
+		"limit": 20,
+		"next": null,
+		"offset": 0,
+		"previous": null,
+		"total": 1
+	}`
+}
+
+const apiURL = "https://api.spotify.com/v1/"
+
+var fixtures = []fixture{
+	// albums
+	{"GET", "albums/*", http.StatusOK, fullAlbum},
+	{"GET", "albums", http.StatusOK, `{"albums": [` + fullAlbum + `]}`},
+	{"GET", "albums/*/tracks", http.StatusOK, simpleTrackPage},
+
+	// artists
+	{"GET", "artists/*", http.StatusOK, fullArtist},
+	{"GET", "artists", http.StatusOK, `{"artists": [` + fullArtist + `]}`},
+	{"GET", "artists/*/albums", http.StatusOK, page(apiURL+"artists/"+ArtistID+"/albums", simpleAlbum)},
+	{"GET", "artists/*/top-tracks", http.StatusOK, `{"tracks": [` + fullTrack + `]}`},
+	{"GET", "artists/*/related-artists", http.StatusOK, `{"artists": [` + fullArtist + `]}`},
+
+	// tracks and audio
+	{"GET", "tracks/*", http.StatusOK, fullTrack},
+	{"GET", "tracks", http.StatusOK, `{"tracks": [` + fullTrack + `]}`},
+	{"GET", "audio-features", http.StatusOK, `{"audio_features": [` + audioFeatures + `]}`},
+	{"GET", "audio-features/*", http.StatusOK, audioFeatures},
+	{"GET", "audio-analysis/*", http.StatusOK, audioAnalysis},
+
+	// browse
+	{"GET", "browse/new-releases", http.StatusOK, `{"albums": ` + page(apiURL+"browse/new-releases", simpleAlbum) + `}`},
+	{"GET", "browse/featured-playlists", http.StatusOK, `{"message": "Monday morning music, coming right up!", "playlists": ` + page(apiURL+"browse/featured-playlists", simplePlaylist) + `}`},
+	{"GET", "browse/categories", http.StatusOK, `{"categories": ` + page(apiURL+"browse/categories", category) + `}`},
+	{"GET", "browse/categories/*", http.StatusOK, category},
+	{"GET", "browse/categories/*/playlists", http.StatusOK, `{"playlists": ` + page(apiURL+"browse/categories/party/playlists", simplePlaylist) + `}`},
+
+	// recommendations
+	{"GET", "recommendations", http.StatusOK, `{
+		"seeds": [{"afterFilteringSize": 251, "afterRelinkingSize": 251, "href": "https://api.spotify.com/v1/artists/` + ArtistID + `", "id": "` + ArtistID + `", "initialPoolSize": 251, "type": "ARTIST"}],
+		"tracks": [` + fullTrack + `]
+	}`},
+	{"GET", "recommendations/available-genre-seeds", http.StatusOK, `{"genres": ["acoustic", "afrobeat", "alt-rock", "alternative", "ambient", "indie", "indie-pop"]}`},
+
+	// search
+	{"GET", "search", http.StatusOK, `{
+		"albums": ` + page(apiURL+"search?type=album", simpleAlbum) + `,
+		"artists": ` + page(apiURL+"search?type=artist", fullArtist) + `,
+		"playlists": ` + page(apiURL+"search?type=playlist", simplePlaylist) + `,
+		"tracks": ` + page(apiURL+"search?type=track", fullTrack) + `
+	}`},
+
+	// users and following
+	{"GET", "me", http.StatusOK, privateUser},
+	{"GET", "users/*", http.StatusOK, user},
+	{"GET", "me/following", http.StatusOK, `{"artists": {
+		"items": [` + fullArtist + `],
+		"next": null,
+		"total": 1,
+		"cursors": {"after": null},
+		"limit": 20,
+		"href": "https://api.spotify.com/v1/me/following?type=artist&limit=20"
+	}}`},
+	{"PUT", "me/following", http.StatusNoContent, ""},
+	{"DELETE", "me/following", http.StatusNoContent, ""},
+	{"GET", "me/following/contains", http.StatusOK, `[true]`},
+
+	// library
+	{"GET", "me/tracks", http.StatusOK, page(apiURL+"me/tracks", `{"added_at": "2016-10-24T15:03:07Z", "track": `+fullTrack+`}`)},
+	{"PUT", "me/tracks", http.StatusOK, ""},
+	{"DELETE", "me/tracks", http.StatusOK, ""},
+	{"GET", "me/tracks/contains", http.StatusOK, `[true]`},
+	{"GET", "me/albums", http.StatusOK, page(apiURL+"me/albums", `{"added_at": "2016-10-24T15:03:07Z", "album": `+fullAlbum+`}`)},
+	{"PUT", "me/albums", http.StatusOK, ""},
+	{"DELETE", "me/albums", http.StatusOK, ""},
+
+	// personalization
+	{"GET", "me/player/recently-played", http.StatusOK, recentlyPlayed},
+	{"GET", "me/top/tracks", http.StatusOK, page(apiURL+"me/top/tracks", fullTrack)},
+	{"GET", "me/top/artists", http.StatusOK, page(apiURL+"me/top/artists", fullArtist)},
+
+	// player
+	{"GET", "me/player", http.StatusOK, `{
+		"device": {"id": "3f228e06c8562e2f439e22932da6c3231715ed53", "is_active": true, "is_restricted": false,
+			"name": "Kitchen speaker", "type": "Speaker", "volume_percent": 70},
+		"shuffle_state": false,
+		"repeat_state": "off",` + currentlyPlaying[1:]},
+	{"GET", "me/player/currently-playing", http.StatusOK, currentlyPlaying},
+
+	// playlists
+	{"GET", "me/playlists", http.StatusOK, page(apiURL+"me/playlists", simplePlaylist)},
+	{"GET", "users/*/playlists", http.StatusOK, page(apiURL+"users/"+UserID+"/playlists", simplePlaylist)},
+	{"POST", "users/*/playlists", http.StatusCreated, fullPlaylist},
+	{"GET", "users/*/playlists/*", http.StatusOK, fullPlaylist},
+	{"PUT", "users/*/playlists/*", http.StatusOK, ""},
+	{"GET", "users/*/playlists/*/tracks", http.StatusOK, playlistTrackPage},
+	{"POST", "users/*/playlists/*/tracks", http.StatusCreated, snapshot},
+	{"DELETE", "users/*/playlists/*/tracks", http.StatusOK, snapshot},
+	{"PUT", "users/*/playlists/*/followers", http.StatusOK, ""},
+	{"DELETE", "users/*/playlists/*/followers", http.StatusOK, ""},
+	{"GET", "users/*/playlists/*/followers/contains", http.StatusOK, `[true]`},
+}
@@ -0,0 +1,86 @@
+package spotifytest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotifytest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cassette := filepath.Join(dir, "cassette.json")
+
+	s := NewServer()
+	s.Handle("POST", "token", http.StatusOK, `{"access_token": "secret-token", "token_type": "Bearer"}`)
+	rec, err := NewRecorder(cassette, ModeRecord, s.Transport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := spotify.NewClient(&http.Client{Transport: rec})
+	if _, err := c.GetTrack(TrackID); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: rec}).Post("https://api.spotify.com/v1/token", "application/x-www-form-urlencoded",
+		strings.NewReader("grant_type=authorization_code&code=abc&client_secret=xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	data, _ := ioutil.ReadFile(cassette)
+	for _, secret := range []string{"secret-token", "code=abc", "xyz"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains unredacted secret %q\n", secret)
+		}
+	}
+
+	// the server is closed, so the response must come from the cassette
+	rep, err := NewRecorder(cassette, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = spotify.NewClient(&http.Client{Transport: rep})
+	track, err := c.GetTrack(TrackID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track.Name != "The Funeral" {
+		t.Errorf("Wanted The Funeral, got %s\n", track.Name)
+	}
+	if _, err := c.GetTrack(TrackID); err == nil {
+		t.Error("Expected error when replaying more requests than were recorded")
+	}
+}
+
+func TestRecorderKeepsRequest(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Handle("POST", "token", http.StatusOK, `{"access_token": "secret-token", "token_type": "Bearer"}`)
+	rec, err := NewRecorder(filepath.Join(os.TempDir(), "unused.json"), ModeRecord, s.Transport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := ioutil.NopCloser(strings.NewReader("grant_type=client_credentials"))
+	req, _ := http.NewRequest("POST", "https://api.spotify.com/v1/token", body)
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("RoundTrip replaced the body of the caller's request")
+	}
+}
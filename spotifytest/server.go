@@ -0,0 +1,205 @@
+// Package spotifytest provides utilities for testing code that uses
+// the spotify package, without contacting the real Spotify Web API.
+package spotifytest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// apiHost is the host name of the Spotify Web API.  Requests to this host
+// are redirected to the test server.
+const apiHost = "api.spotify.com"
+
+// Server is an HTTP server that mimics the Spotify Web API.  It is preloaded
+// with realistic canned responses for every endpoint supported by the spotify
+// package.  Individual responses can be replaced for a particular test using
+// Handle or HandleFunc.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	routes   []route
+	requests []*http.Request
+}
+
+// route associates an HTTP method and path pattern with a handler.
+// Path segments of "*" in the pattern match any single segment.
+type route struct {
+	method  string
+	pattern []string
+	handler http.HandlerFunc
+}
+
+// NewServer starts a Server preloaded with the default canned responses.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{}
+	for _, f := range fixtures {
+		s.Handle(f.method, f.pattern, f.status, f.body)
+	}
+	// replacing a playlist's tracks (URIs in the query string) responds
+	// with 201 Created, while reordering them responds with 200 OK
+	s.HandleFunc("PUT", "users/*/playlists/*/tracks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if r.URL.Query().Get("uris") != "" {
+			w.WriteHeader(http.StatusCreated)
+		}
+		fmt.Fprint(w, snapshot)
+	})
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the server, of the form http://ipaddr:port
+// with no trailing slash.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Handle replaces the response for requests matching the HTTP method and
+// path pattern.  The pattern is relative to the API version, for example
+// "tracks/*" or "me/top/artists".  A "*" segment matches any single path
+// segment.  The most recently registered matching route is used.
+func (s *Server) Handle(method, pattern string, status int, body string) {
+	s.HandleFunc(method, pattern, func(w http.ResponseWriter, r *http.Request) {
+		if body != "" {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	})
+}
+
+// HandleFunc is like Handle, but allows the test to supply an arbitrary
+// handler for matching requests.
+func (s *Server) HandleFunc(method, pattern string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: splitPath(pattern),
+		handler: h,
+	})
+}
+
+// Requests returns the requests received by the server so far, oldest
+// first.  The request bodies have already been consumed.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request received by the server,
+// or nil if no requests have been made.
+func (s *Server) LastRequest() *http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return nil
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// Transport returns an http.RoundTripper that sends requests for the
+// Spotify Web API to the test server instead.  Requests for other hosts
+// are sent unchanged.
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.srv.URL)
+	return &rewriteTransport{target: target, base: http.DefaultTransport}
+}
+
+// HTTPClient returns an http.Client that uses the server's Transport.
+func (s *Server) HTTPClient() *http.Client {
+	return &http.Client{Transport: s.Transport()}
+}
+
+// NewClient returns a spotify.Client whose requests are served by the
+// test server.  The options are passed on to spotify.NewClient.
+func (s *Server) NewClient(opts ...spotify.ClientOption) spotify.Client {
+	return spotify.NewClient(s.HTTPClient(), opts...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// consume the body now, so it can't be read after the handler returns
+	r.Body = ioutil.NopCloser(strings.NewReader(readBody(r)))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	path := splitPath(strings.TrimPrefix(r.URL.Path, "/v1/"))
+	var h http.HandlerFunc
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		if rt := s.routes[i]; rt.method == r.Method && match(rt.pattern, path) {
+			h = rt.handler
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if h == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"error":{"status":404,"message":"spotifytest: no handler for %s %s"}}`, r.Method, r.URL.Path)
+		return
+	}
+	h(w, r)
+}
+
+// readBody returns the request body as a string, and replaces it so
+// handlers can read it again.
+func readBody(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	b, _ := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	return string(b)
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+func match(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rewriteTransport redirects requests for the Spotify Web API to target.
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != apiHost {
+		return t.base.RoundTrip(req)
+	}
+	r := new(http.Request)
+	*r = *req
+	u := *req.URL
+	u.Scheme = t.target.Scheme
+	u.Host = t.target.Host
+	r.URL = &u
+	r.Host = t.target.Host
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,56 @@
+package spotifytest
+
+import (
+	"net/http"
+	"testing"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func TestCannedResponses(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	track, err := c.GetTrack(TrackID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track.Name != "The Funeral" {
+		t.Errorf("Wanted The Funeral, got %s\n", track.Name)
+	}
+	if _, err := c.GetAudioAnalysis(TrackID); err != nil {
+		t.Error(err)
+	}
+	top, err := c.CurrentUserTopArtists(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top.Items) != 1 || top.Items[0].ID != ArtistID {
+		t.Error("Unexpected top artists", top.Items)
+	}
+	if err := c.ReplacePlaylistTracks(UserID, PlaylistID, TrackID); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.ReorderPlaylistTracks(UserID, PlaylistID, spotify.PlaylistReorderOptions{RangeStart: 1}); err != nil {
+		t.Error(err)
+	}
+	if n := len(s.Requests()); n != 5 {
+		t.Errorf("Expected 5 requests, got %d\n", n)
+	}
+}
+
+func TestHandleOverridesFixture(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Handle("GET", "me", http.StatusUnauthorized, `{"error": {"status": 401, "message": "The access token expired"}}`)
+	c := s.NewClient()
+
+	_, err := c.CurrentUser()
+	if serr, ok := err.(*spotify.Error); !ok || serr.Status != http.StatusUnauthorized {
+		t.Errorf("Expected HTTP 401 error, got %v\n", err)
+	}
+	if r := s.LastRequest(); r == nil || r.URL.Path != "/v1/me" {
+		t.Error("Request wasn't recorded")
+	}
+}
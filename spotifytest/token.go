@@ -0,0 +1,75 @@
+package spotifytest
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenSource is an oauth2.TokenSource that returns a fixed token, so code
+// that depends on authentication can be tested without the OAuth2 flow.
+// The token's expiry can be changed at any time, and the source can be made
+// to fail as if a refresh had been rejected.
+type TokenSource struct {
+	mu    sync.Mutex
+	token oauth2.Token
+	err   error
+	calls int
+}
+
+// NewTokenSource returns a TokenSource for a bearer token with the given
+// access token and expiry.  A zero expiry means the token never expires.
+func NewTokenSource(accessToken string, expiry time.Time) *TokenSource {
+	return &TokenSource{
+		token: oauth2.Token{
+			AccessToken:  accessToken,
+			TokenType:    "Bearer",
+			RefreshToken: "refresh-" + accessToken,
+			Expiry:       expiry,
+		},
+	}
+}
+
+// Token implements oauth2.TokenSource.  It returns a copy of the current
+// token, or the error set by SetError.
+func (ts *TokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.calls++
+	if ts.err != nil {
+		return nil, ts.err
+	}
+	t := ts.token
+	return &t, nil
+}
+
+// SetToken replaces the token returned by the source, for example to
+// simulate a refresh.
+func (ts *TokenSource) SetToken(t *oauth2.Token) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.token = *t
+}
+
+// SetExpiry changes the expiry of the current token.
+func (ts *TokenSource) SetExpiry(expiry time.Time) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.token.Expiry = expiry
+}
+
+// SetError makes subsequent calls to Token fail with err.  Passing nil
+// restores normal behavior.
+func (ts *TokenSource) SetError(err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.err = err
+}
+
+// Calls returns the number of times Token has been called.
+func (ts *TokenSource) Calls() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.calls
+}
@@ -0,0 +1,48 @@
+package spotifytest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenSource(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ts := NewTokenSource("abc123", time.Time{})
+	c := spotify.NewClient(&http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: s.Transport()},
+	})
+
+	if _, err := c.CurrentUser(); err != nil {
+		t.Fatal(err)
+	}
+	if auth := s.LastRequest().Header.Get("Authorization"); auth != "Bearer abc123" {
+		t.Errorf("Got Authorization %q\n", auth)
+	}
+
+	ts.SetError(errors.New("invalid_grant"))
+	if _, err := c.CurrentUser(); err == nil {
+		t.Error("Expected error from token source")
+	}
+	if n := ts.Calls(); n != 2 {
+		t.Errorf("Expected 2 calls, got %d\n", n)
+	}
+}
+
+func TestTokenSourceExpiry(t *testing.T) {
+	ts := NewTokenSource("abc123", time.Now().Add(time.Hour))
+	tok, _ := ts.Token()
+	if !tok.Valid() {
+		t.Error("Token should be valid")
+	}
+	ts.SetExpiry(time.Now().Add(-time.Minute))
+	tok, _ = ts.Token()
+	if tok.Valid() {
+		t.Error("Token should have expired")
+	}
+}
@@ -0,0 +1,75 @@
+package stats
+
+import "time"
+
+// ListeningClock counts plays by hour of the day and day of the week, for
+// drawing heatmaps and "when do you listen" charts.
+type ListeningClock struct {
+	// Plays is indexed by weekday (Sunday is 0, as in time.Weekday) and
+	// hour of the day.
+	Plays     [7][24]int `json:"plays"`
+	ByWeekday [7]int     `json:"by_weekday"`
+	ByHour    [24]int    `json:"by_hour"`
+	// Total is the number of plays counted.
+	Total int `json:"total"`
+	// PeakWeekday and PeakHour are the busiest day and hour.  If there
+	// are ties, the earliest is used.
+	PeakWeekday time.Weekday `json:"peak_weekday"`
+	PeakHour    int          `json:"peak_hour"`
+}
+
+// NewListeningClock counts the plays in the given time zone, which should
+// be the user's.  If loc is nil, UTC is used.
+func NewListeningClock(plays []Play, loc *time.Location) *ListeningClock {
+	if loc == nil {
+		loc = time.UTC
+	}
+	c := &ListeningClock{}
+	for _, p := range plays {
+		t := p.PlayedAt.In(loc)
+		c.Plays[t.Weekday()][t.Hour()]++
+		c.ByWeekday[t.Weekday()]++
+		c.ByHour[t.Hour()]++
+		c.Total++
+	}
+	for d, n := range c.ByWeekday {
+		if n > c.ByWeekday[c.PeakWeekday] {
+			c.PeakWeekday = time.Weekday(d)
+		}
+	}
+	for h, n := range c.ByHour {
+		if n > c.ByHour[c.PeakHour] {
+			c.PeakHour = h
+		}
+	}
+	return c
+}
+
+// Max returns the largest count in Plays.
+func (c *ListeningClock) Max() int {
+	max := 0
+	for _, day := range c.Plays {
+		for _, n := range day {
+			if n > max {
+				max = n
+			}
+		}
+	}
+	return max
+}
+
+// Normalized returns Plays scaled to between 0 and 1, relative to the
+// busiest hour, which is convenient for choosing heatmap colours.
+func (c *ListeningClock) Normalized() [7][24]float64 {
+	var heat [7][24]float64
+	max := c.Max()
+	if max == 0 {
+		return heat
+	}
+	for d, day := range c.Plays {
+		for h, n := range day {
+			heat[d][h] = float64(n) / float64(max)
+		}
+	}
+	return heat
+}
@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListeningClock(t *testing.T) {
+	plays := Plays(history, time.Time{}, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewListeningClock(plays, nil)
+	// 2017-01-01 was a Sunday
+	if c.Total != 5 || c.Plays[time.Sunday][10] != 1 || c.Plays[time.Tuesday][23] != 1 {
+		t.Errorf("Unexpected counts %+v\n", c.Plays)
+	}
+	if c.ByHour[10] != 4 || c.PeakHour != 10 || c.PeakWeekday != time.Sunday {
+		t.Errorf("Unexpected peaks %+v\n", c)
+	}
+	if c.Max() != 1 || c.Normalized()[time.Friday][10] != 1 || c.Normalized()[time.Friday][11] != 0 {
+		t.Error("Unexpected normalized counts")
+	}
+
+	// the play at 23:00 UTC on Tuesday is on Wednesday in Tokyo
+	tokyo := time.FixedZone("JST", 9*60*60)
+	c = NewListeningClock(plays, tokyo)
+	if c.Plays[time.Wednesday][8] != 1 {
+		t.Errorf("Unexpected counts in Tokyo %+v\n", c.Plays)
+	}
+	if NewListeningClock(nil, nil).Normalized()[0][0] != 0 {
+		t.Error("Expected an empty clock")
+	}
+}
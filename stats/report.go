@@ -0,0 +1,189 @@
+package stats
+
+import (
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// TopItems are the user's top tracks and artists over one of Spotify's
+// time ranges.
+type TopItems struct {
+	Range   string               `json:"range"`
+	Tracks  []spotify.TrackItem  `json:"tracks"`
+	Artists []spotify.ArtistItem `json:"artists"`
+}
+
+// Features are the average audio features of the tracks played, weighted
+// by the number of times each was played.
+type Features struct {
+	Acousticness float64 `json:"acousticness"`
+	Danceability float64 `json:"danceability"`
+	Energy       float64 `json:"energy"`
+	Valence      float64 `json:"valence"`
+	Tempo        float64 `json:"tempo"`
+	// Plays is the number of plays the averages are based on.
+	Plays int `json:"plays"`
+}
+
+// Report is a summary of a user's listening over a period, ready to be
+// rendered by a template or encoded as JSON.
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Plays is the number of plays in the period, and Minutes their total
+	// length.
+	Plays             int     `json:"plays"`
+	Minutes           int     `json:"minutes"`
+	UniqueTracks      int     `json:"unique_tracks"`
+	UniqueArtists     int     `json:"unique_artists"`
+	MostPlayedTracks  []Count `json:"most_played_tracks"`
+	MostPlayedArtists []Count `json:"most_played_artists"`
+	// Top holds the user's top tracks and artists for each time range.
+	Top []TopItems `json:"top"`
+	// TopGenres are scored by the rank of the top artists in each genre,
+	// across all the time ranges.
+	TopGenres []Count  `json:"top_genres"`
+	Features  Features `json:"features"`
+	// LongestStreak is the longest run of days with plays, and
+	// LatestStreak the run ending on the day of the last play.
+	LongestStreak Streak `json:"longest_streak"`
+	LatestStreak  Streak `json:"latest_streak"`
+	// Clock shows when in the week the user listens.
+	Clock *ListeningClock `json:"clock"`
+}
+
+// Builder builds reports.
+type Builder struct {
+	Client spotify.SpotifyClient
+	// Ranges are the time ranges to fetch top items for.  If nil, all
+	// three ranges are used.
+	Ranges []string
+	// Limit is the number of items in each list.  If zero, 10 is used.
+	Limit int
+	// Location is the time zone used to divide plays into days.  If nil,
+	// UTC is used.
+	Location *time.Location
+}
+
+func (b *Builder) limit() int {
+	if b.Limit == 0 {
+		return 10
+	}
+	return b.Limit
+}
+
+func (b *Builder) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+	return b.Location
+}
+
+// Build produces a report of the user's listening between from and to,
+// based on history the application has stored.  It requires the
+// ScopeUserTopRead scope.
+func (b *Builder) Build(history []spotify.HistoryItem, from, to time.Time) (*Report, error) {
+	r := &Report{From: from, To: to}
+	plays := Plays(history, from, to)
+	r.Plays = len(plays)
+
+	var tracks, artists counter
+	var ms int
+	for _, p := range plays {
+		ms += p.Track.Duration
+		tracks.add(string(p.Track.ID), p.Track.ID, p.Track.Name, 1)
+		for _, a := range p.Track.Artists {
+			artists.add(string(a.ID), a.ID, a.Name, 1)
+		}
+	}
+	r.Minutes = ms / 60000
+	r.UniqueTracks = len(tracks.counts)
+	r.UniqueArtists = len(artists.counts)
+	r.MostPlayedTracks = tracks.top(b.limit())
+	r.MostPlayedArtists = artists.top(b.limit())
+	r.LongestStreak, r.LatestStreak = streaks(plays, b.location())
+	r.Clock = NewListeningClock(plays, b.location())
+
+	ranges := b.Ranges
+	if ranges == nil {
+		ranges = []string{ShortTerm, MediumTerm, LongTerm}
+	}
+	var genres counter
+	for _, rng := range ranges {
+		rng := rng
+		limit := b.limit()
+		opt := &spotify.Options{Limit: &limit, Timerange: &rng}
+		topTracks, err := b.Client.CurrentUserTopTracks(opt)
+		if err != nil {
+			return nil, err
+		}
+		topArtists, err := b.Client.CurrentUserTopArtists(opt)
+		if err != nil {
+			return nil, err
+		}
+		r.Top = append(r.Top, TopItems{Range: rng, Tracks: topTracks.Items, Artists: topArtists.Items})
+		for i, a := range topArtists.Items {
+			for _, g := range a.Genres {
+				genres.add(g, "", g, len(topArtists.Items)-i)
+			}
+		}
+	}
+	r.TopGenres = genres.top(b.limit())
+
+	// weight the features by plays; without any history, fall back to the
+	// user's top tracks
+	weights := make(map[spotify.ID]int)
+	for _, c := range tracks.counts {
+		weights[c.ID] = c.Count
+	}
+	if len(weights) == 0 {
+		for _, top := range r.Top {
+			for _, t := range top.Tracks {
+				weights[t.ID]++
+			}
+		}
+	}
+	var err error
+	if r.Features, err = b.features(weights); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (b *Builder) features(weights map[spotify.ID]int) (Features, error) {
+	var f Features
+	ids := make([]spotify.ID, 0, len(weights))
+	for id := range weights {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	for _, chunk := range spotify.ChunkIDs(ids, 100) {
+		features, err := b.Client.GetAudioFeatures(chunk...)
+		if err != nil {
+			return f, err
+		}
+		for i, af := range features {
+			if af == nil {
+				continue
+			}
+			w := weights[chunk[i]]
+			f.Acousticness += float64(af.Acousticness) * float64(w)
+			f.Danceability += float64(af.Danceability) * float64(w)
+			f.Energy += float64(af.Energy) * float64(w)
+			f.Valence += float64(af.Valence) * float64(w)
+			f.Tempo += float64(af.Tempo) * float64(w)
+			f.Plays += w
+		}
+	}
+	if f.Plays > 0 {
+		n := float64(f.Plays)
+		f.Acousticness /= n
+		f.Danceability /= n
+		f.Energy /= n
+		f.Valence /= n
+		f.Tempo /= n
+	}
+	return f, nil
+}
@@ -0,0 +1,137 @@
+// Package stats summarizes a user's listening, for "year in review" style
+// reports and charts.
+//
+// Spotify only returns a user's 50 most recently played tracks, so reports
+// are built from play history the application has accumulated itself, by
+// calling CurrentUserRecentTracks periodically and storing the items.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Time ranges for the user's top tracks and artists.
+const (
+	// ShortTerm covers approximately the last four weeks.
+	ShortTerm = "short_term"
+	// MediumTerm covers approximately the last six months.
+	MediumTerm = "medium_term"
+	// LongTerm covers several years of data.
+	LongTerm = "long_term"
+)
+
+// Play is a single play parsed from the play history.
+type Play struct {
+	Track    spotify.SimpleTrack
+	PlayedAt time.Time
+}
+
+// Plays parses and de-duplicates play history items, which may overlap if
+// they were collected by repeated calls to CurrentUserRecentTracks, and
+// returns the plays in [from, to), oldest first.  Items without a timestamp
+// are skipped.  A zero from or to is unbounded.
+func Plays(items []spotify.HistoryItem, from, to time.Time) []Play {
+	seen := make(map[string]bool)
+	var plays []Play
+	for _, item := range items {
+		key := item.PlayedAt.Format(time.RFC3339Nano) + "|" + string(item.Track.ID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		at := item.PlayedAt
+		if at.IsZero() {
+			continue
+		}
+		if (!from.IsZero() && at.Before(from)) || (!to.IsZero() && !at.Before(to)) {
+			continue
+		}
+		plays = append(plays, Play{Track: item.Track, PlayedAt: at})
+	}
+	sort.Sort(byTime(plays))
+	return plays
+}
+
+type byTime []Play
+
+func (p byTime) Len() int           { return len(p) }
+func (p byTime) Less(i, j int) bool { return p[i].PlayedAt.Before(p[j].PlayedAt) }
+func (p byTime) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// Count is a name, such as a track, artist or genre, and how often it
+// occurred.  For genres, the count is a weighted score.
+type Count struct {
+	ID    spotify.ID `json:"id,omitempty"`
+	Name  string     `json:"name"`
+	Count int        `json:"count"`
+}
+
+// counter tallies occurrences while remembering the order keys were
+// first seen, so ties are broken consistently.
+type counter struct {
+	counts []Count
+	index  map[string]int
+}
+
+func (c *counter) add(key string, id spotify.ID, name string, n int) {
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+	i, ok := c.index[key]
+	if !ok {
+		i = len(c.counts)
+		c.index[key] = i
+		c.counts = append(c.counts, Count{ID: id, Name: name})
+	}
+	c.counts[i].Count += n
+}
+
+// top returns the n highest counts, highest first.  If n is zero, all the
+// counts are returned.
+func (c *counter) top(n int) []Count {
+	counts := append([]Count(nil), c.counts...)
+	sort.Stable(byCount(counts))
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+type byCount []Count
+
+func (c byCount) Len() int           { return len(c) }
+func (c byCount) Less(i, j int) bool { return c[i].Count > c[j].Count }
+func (c byCount) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// Streak is a run of consecutive days with at least one play.
+type Streak struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Days  int       `json:"days"`
+}
+
+// streaks returns the longest run of consecutive days with plays, and the
+// run ending on the day of the last play.  plays must be sorted.
+func streaks(plays []Play, loc *time.Location) (longest, last Streak) {
+	var cur Streak
+	for _, p := range plays {
+		t := p.PlayedAt.In(loc)
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		switch {
+		case cur.Days > 0 && day.Equal(cur.End):
+			continue
+		case cur.Days > 0 && day.Equal(cur.End.AddDate(0, 0, 1)):
+			cur.End = day
+			cur.Days++
+		default:
+			cur = Streak{Start: day, End: day, Days: 1}
+		}
+		if cur.Days > longest.Days {
+			longest = cur
+		}
+	}
+	return longest, cur
+}
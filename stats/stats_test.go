@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+const (
+	trackA = "4iV5W9uYEdYUVa79Axb7Rh"
+	trackB = "1301WleyT98MSxVHPZCA6M"
+	trackC = "6rqhFgbbKwnb9MLmUQDhG6"
+)
+
+func play(id, artist, playedAt string) spotify.HistoryItem {
+	var item spotify.HistoryItem
+	item.Track.ID = spotify.ID(id)
+	item.Track.Name = "Track " + id
+	item.Track.Duration = 180000
+	item.Track.Artists = []spotify.SimpleArtist{{ID: spotify.ID(artist), Name: artist}}
+	// Timestamps that don't parse are left zero, as if they were missing.
+	item.PlayedAt, _ = time.Parse(time.RFC3339Nano, playedAt)
+	return item
+}
+
+var history = []spotify.HistoryItem{
+	play(trackA, "x", "2017-01-01T10:00:00.000Z"),
+	play(trackA, "x", "2017-01-02T10:00:00Z"),
+	play(trackB, "y", "2017-01-03T23:00:00Z"),
+	play(trackA, "x", "2017-01-05T10:00:00.5Z"),
+	play(trackC, "x", "2017-01-06T10:00:00Z"),
+	// collected twice
+	play(trackC, "x", "2017-01-06T10:00:00Z"),
+	// outside the period
+	play(trackB, "y", "2018-01-01T00:00:00Z"),
+}
+
+func TestPlays(t *testing.T) {
+	from := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	plays := Plays(history, from, from.AddDate(1, 0, 0))
+	if len(plays) != 5 {
+		t.Fatalf("Expected 5 plays, got %d\n", len(plays))
+	}
+	for i := 1; i < len(plays); i++ {
+		if plays[i].PlayedAt.Before(plays[i-1].PlayedAt) {
+			t.Error("Plays aren't sorted")
+		}
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	plays := Plays(history, time.Time{}, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	longest, latest := streaks(plays, time.UTC)
+	if longest.Days != 3 || longest.Start.Day() != 1 || longest.End.Day() != 3 {
+		t.Errorf("Unexpected longest streak %+v\n", longest)
+	}
+	if latest.Days != 2 || latest.End.Day() != 6 {
+		t.Errorf("Unexpected latest streak %+v\n", latest)
+	}
+
+	// in New York, the play at 23:00 UTC on the 3rd is on the 3rd, but
+	// the play at 10:00 UTC on the 1st is still on the 1st
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	if longest, _ := streaks(plays, ny); longest.Days != 3 {
+		t.Errorf("Unexpected longest streak in New York %+v\n", longest)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	s.HandleFunc("GET", "me/top/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"items":[{"id":"a","name":"%s"}]}`, r.URL.Query().Get("time_range"))
+	})
+	s.HandleFunc("GET", "me/top/artists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"id":"x","genres":["indie rock","pop"]},{"id":"y","genres":["pop"]}]}`)
+	})
+	s.HandleFunc("GET", "audio-features", func(w http.ResponseWriter, r *http.Request) {
+		var features []string
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			energy := 0.2
+			if id == trackA {
+				energy = 0.8
+			}
+			features = append(features, fmt.Sprintf(`{"id":"%s","energy":%g}`, id, energy))
+		}
+		fmt.Fprintf(w, `{"audio_features":[%s]}`, strings.Join(features, ","))
+	})
+	c := s.NewClient()
+
+	b := &Builder{Client: &c, Limit: 2}
+	from := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	r, err := b.Build(history, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Plays != 5 || r.Minutes != 15 || r.UniqueTracks != 3 || r.UniqueArtists != 2 {
+		t.Errorf("Unexpected totals %+v\n", r)
+	}
+	if len(r.MostPlayedTracks) != 2 || r.MostPlayedTracks[0].ID != trackA || r.MostPlayedTracks[0].Count != 3 {
+		t.Errorf("Unexpected most played tracks %+v\n", r.MostPlayedTracks)
+	}
+	if r.MostPlayedArtists[0].Name != "x" || r.MostPlayedArtists[0].Count != 4 {
+		t.Errorf("Unexpected most played artists %+v\n", r.MostPlayedArtists)
+	}
+	if len(r.Top) != 3 || r.Top[2].Tracks[0].Name != LongTerm {
+		t.Errorf("Unexpected top items %+v\n", r.Top)
+	}
+	if len(r.TopGenres) != 2 || r.TopGenres[0].Name != "pop" || r.TopGenres[0].Count != 9 {
+		t.Errorf("Unexpected genres %+v\n", r.TopGenres)
+	}
+	// three plays of a at 0.8 and two of the others at 0.2
+	if e := r.Features.Energy; e < 0.559 || e > 0.561 || r.Features.Plays != 5 {
+		t.Errorf("Unexpected features %+v\n", r.Features)
+	}
+	if r.Clock == nil || r.Clock.Total != 5 {
+		t.Errorf("Unexpected clock %+v\n", r.Clock)
+	}
+	if r.LongestStreak.Days != 3 {
+		t.Errorf("Unexpected streak %+v\n", r.LongestStreak)
+	}
+}
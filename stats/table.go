@@ -0,0 +1,184 @@
+package stats
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Format is a format of delimited text that the Write functions produce.
+type Format int
+
+const (
+	// CSV is comma separated values, as described in RFC 4180.
+	CSV Format = iota
+	// TSV is tab separated values.  Fields aren't quoted; tabs and line
+	// breaks in them are replaced with spaces.
+	TSV
+)
+
+// TimeLayout is the layout of the times in the tables.  Spreadsheets
+// recognize it as a date and time, which they don't always do for RFC 3339.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// table writes rows in a Format.
+type table struct {
+	csv *csv.Writer
+	w   io.Writer
+	err error
+}
+
+func newTable(w io.Writer, f Format) *table {
+	if f == TSV {
+		return &table{w: w}
+	}
+	return &table{csv: csv.NewWriter(w)}
+}
+
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func (t *table) write(row ...string) {
+	if t.err != nil {
+		return
+	}
+	if t.csv != nil {
+		t.err = t.csv.Write(row)
+		return
+	}
+	for i, cell := range row {
+		row[i] = tsvReplacer.Replace(cell)
+	}
+	_, t.err = io.WriteString(t.w, strings.Join(row, "\t")+"\n")
+}
+
+func (t *table) flush() error {
+	if t.csv != nil {
+		t.csv.Flush()
+		return t.csv.Error()
+	}
+	return t.err
+}
+
+// artistColumns returns the names and IDs of the artists, each separated by
+// semicolons, so that a track with several artists is still one row.
+func artistColumns(names, ids []string) (string, string) {
+	return strings.Join(names, "; "), strings.Join(ids, "; ")
+}
+
+func simpleArtists(artists []spotify.SimpleArtist) (string, string) {
+	names := make([]string, len(artists))
+	ids := make([]string, len(artists))
+	for i, a := range artists {
+		names[i], ids[i] = a.Name, string(a.ID)
+	}
+	return artistColumns(names, ids)
+}
+
+func artistInfos(artists []spotify.ArtistInfo) (string, string) {
+	names := make([]string, len(artists))
+	ids := make([]string, len(artists))
+	for i, a := range artists {
+		names[i], ids[i] = a.Name, string(a.ID)
+	}
+	return artistColumns(names, ids)
+}
+
+// duration formats a duration in milliseconds as minutes and seconds.
+func duration(ms int) string {
+	s := ms / 1000
+	return strconv.Itoa(s/60) + ":" + pad2(s%60)
+}
+
+func pad2(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// WriteTopTracks writes the top tracks as a table, one row per track, in
+// order, with their rank.
+func WriteTopTracks(w io.Writer, f Format, tracks []spotify.TrackItem) error {
+	t := newTable(w, f)
+	t.write("rank", "id", "name", "artists", "artist_ids", "album", "album_id", "release_date",
+		"duration", "duration_ms", "popularity", "explicit")
+	for i, tr := range tracks {
+		names, ids := artistInfos(tr.Artists)
+		t.write(strconv.Itoa(i+1), string(tr.ID), tr.Name, names, ids, tr.Album.Name, string(tr.Album.ID),
+			tr.Album.ReleaseDate, duration(tr.DurationMS), strconv.Itoa(tr.DurationMS),
+			strconv.Itoa(int(tr.Popularity)), strconv.FormatBool(tr.Explicit))
+	}
+	return t.flush()
+}
+
+// WriteTopArtists writes the top artists as a table, one row per artist, in
+// order, with their rank.  An artist's genres are separated by semicolons.
+func WriteTopArtists(w io.Writer, f Format, artists []spotify.ArtistItem) error {
+	t := newTable(w, f)
+	t.write("rank", "id", "name", "genres", "followers", "popularity")
+	for i, a := range artists {
+		t.write(strconv.Itoa(i+1), string(a.ID), a.Name, strings.Join(a.Genres, "; "),
+			strconv.FormatUint(uint64(a.Followers.Count), 10), strconv.Itoa(int(a.Popularity)))
+	}
+	return t.flush()
+}
+
+// WritePlayHistory writes the plays as a table, one row per play.  The times
+// they were played at are written in the time zone loc, which should be the
+// user's, with TimeLayout; if loc is nil, UTC is used.  Plays without a
+// time are written with an empty time.
+func WritePlayHistory(w io.Writer, f Format, items []spotify.HistoryItem, loc *time.Location) error {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := newTable(w, f)
+	t.write("played_at", "id", "name", "artists", "artist_ids", "duration", "duration_ms", "context_type", "context_uri")
+	for _, item := range items {
+		var playedAt, contextType, contextURI string
+		if !item.PlayedAt.IsZero() {
+			playedAt = item.PlayedAt.In(loc).Format(TimeLayout)
+		}
+		if item.Context != nil {
+			contextType, contextURI = item.Context.Type, string(item.Context.URI)
+		}
+		names, ids := simpleArtists(item.Track.Artists)
+		t.write(playedAt, string(item.Track.ID), item.Track.Name, names, ids,
+			duration(item.Track.Duration), strconv.Itoa(item.Track.Duration), contextType, contextURI)
+	}
+	return t.flush()
+}
+
+// WriteCounts writes counts, such as a report's MostPlayedTracks or
+// TopGenres, as a table with their rank.
+func WriteCounts(w io.Writer, f Format, counts []Count) error {
+	t := newTable(w, f)
+	t.write("rank", "id", "name", "count")
+	for i, c := range counts {
+		t.write(strconv.Itoa(i+1), string(c.ID), c.Name, strconv.Itoa(c.Count))
+	}
+	return t.flush()
+}
+
+// WriteListeningClock writes the clock as a table with a row for each day of
+// the week, starting on Sunday, and a column for each hour, which a
+// spreadsheet can turn into a heatmap.
+func WriteListeningClock(w io.Writer, f Format, c *ListeningClock) error {
+	t := newTable(w, f)
+	header := []string{"weekday"}
+	for h := 0; h < 24; h++ {
+		header = append(header, pad2(h)+":00")
+	}
+	t.write(append(header, "total")...)
+	for d, day := range c.Plays {
+		row := []string{time.Weekday(d).String()}
+		for _, n := range day {
+			row = append(row, strconv.Itoa(n))
+		}
+		t.write(append(row, strconv.Itoa(c.ByWeekday[d]))...)
+	}
+	return t.flush()
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+func TestWritePlayHistory(t *testing.T) {
+	item := play(trackA, "x", "2017-01-05T10:00:00.5Z")
+	item.Track.Name = "One, \"Two\"\tThree"
+	item.Track.Artists = append(item.Track.Artists, spotify.SimpleArtist{ID: "y", Name: "y"})
+	item.Context = &spotify.TrackContext{Type: "album", URI: "spotify:album:z"}
+	items := []spotify.HistoryItem{item, play(trackB, "y", "yesterday")}
+	loc := time.FixedZone("EST", -5*60*60)
+
+	var b bytes.Buffer
+	if err := WritePlayHistory(&b, CSV, items, loc); err != nil {
+		t.Fatal(err)
+	}
+	want := "played_at,id,name,artists,artist_ids,duration,duration_ms,context_type,context_uri\n" +
+		"2017-01-05 05:00:00," + trackA + ",\"One, \"\"Two\"\"\tThree\",x; y,x; y,3:00,180000,album,spotify:album:z\n" +
+		"," + trackB + ",Track " + trackB + ",y,y,3:00,180000,,\n"
+	if b.String() != want {
+		t.Errorf("got CSV\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WritePlayHistory(&b, TSV, items[:1], nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	if want := "2017-01-05 10:00:00\t" + trackA + "\tOne, \"Two\" Three\tx; y\tx; y\t3:00\t180000\talbum\tspotify:album:z"; lines[1] != want {
+		t.Errorf("got TSV row\n%q\nwant\n%q", lines[1], want)
+	}
+}
+
+func TestWriteTopItems(t *testing.T) {
+	var tr spotify.TrackItem
+	tr.ID, tr.Name, tr.DurationMS, tr.Popularity = "t", "Song", 61000, 70
+	tr.Artists = []spotify.ArtistInfo{{ID: "a", Name: "A"}, {ID: "b", Name: "B"}}
+	tr.Album.Name, tr.Album.ReleaseDate = "Record", "2016"
+	var b bytes.Buffer
+	if err := WriteTopTracks(&b, TSV, []spotify.TrackItem{tr}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\tt\tSong\tA; B\ta; b\tRecord\t\t2016\t1:01\t61000\t70\tfalse\n"; !strings.HasSuffix(b.String(), want) {
+		t.Errorf("got\n%s", b.String())
+	}
+
+	var a spotify.ArtistItem
+	a.ID, a.Name, a.Genres, a.Followers.Count = "a", "A", []string{"indie", "folk"}, 12
+	b.Reset()
+	if err := WriteTopArtists(&b, CSV, []spotify.ArtistItem{a}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "rank,id,name,genres,followers,popularity\n1,a,A,indie; folk,12,0\n"; b.String() != want {
+		t.Errorf("got\n%s", b.String())
+	}
+}
+
+func TestWriteListeningClock(t *testing.T) {
+	from := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewListeningClock(Plays(history, from, from.AddDate(1, 0, 0)), nil)
+	var b bytes.Buffer
+	if err := WriteListeningClock(&b, CSV, c); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 8 || !strings.HasPrefix(lines[0], "weekday,00:00,01:00") {
+		t.Fatalf("got\n%s", b.String())
+	}
+	// 2017-01-01 was a Sunday.
+	if want := "Sunday,0,0,0,0,0,0,0,0,0,0,1,0,0,0,0,0,0,0,0,0,0,0,0,0,1"; lines[1] != want {
+		t.Errorf("got Sunday row %s", lines[1])
+	}
+}
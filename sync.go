@@ -0,0 +1,172 @@
+package spotify
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+)
+
+// SyncState is the cursor kept between runs of a LibrarySyncer for one user.
+type SyncState struct {
+	// LastAdded is the AddedAt time of the most recently saved track seen
+	// by the previous run, formatted with TimestampLayout.  It's kept as a
+	// string so that states saved by earlier versions can still be read.
+	LastAdded string
+	// PlaylistIDs and Snapshots record the snapshot ID of each of the
+	// user's playlists, as of the previous run.
+	PlaylistIDs []string `datastore:",noindex"`
+	Snapshots   []string `datastore:",noindex"`
+	// LastRun is when the state was last saved.
+	LastRun time.Time
+}
+
+// snapshots returns the recorded snapshot IDs, keyed by playlist.
+func (s *SyncState) snapshots() map[ID]string {
+	m := make(map[ID]string, len(s.PlaylistIDs))
+	for i, id := range s.PlaylistIDs {
+		if i < len(s.Snapshots) {
+			m[ID(id)] = s.Snapshots[i]
+		}
+	}
+	return m
+}
+
+// SyncStore persists SyncState between runs.
+type SyncStore interface {
+	// LoadSyncState returns the state for the user, or a zero SyncState if
+	// the user hasn't been synced before.
+	LoadSyncState(ctx context.Context, userID string) (*SyncState, error)
+	SaveSyncState(ctx context.Context, userID string, state *SyncState) error
+}
+
+// DatastoreSyncStore is a SyncStore that keeps the state of each user in
+// an App Engine Datastore entity of the given kind, keyed by user ID.
+type DatastoreSyncStore string
+
+// LoadSyncState implements SyncStore.
+func (kind DatastoreSyncStore) LoadSyncState(ctx context.Context, userID string) (*SyncState, error) {
+	var s SyncState
+	err := datastore.Get(ctx, datastore.NewKey(ctx, string(kind), userID, 0, nil), &s)
+	if err != nil && err != datastore.ErrNoSuchEntity {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SaveSyncState implements SyncStore.
+func (kind DatastoreSyncStore) SaveSyncState(ctx context.Context, userID string, state *SyncState) error {
+	_, err := datastore.Put(ctx, datastore.NewKey(ctx, string(kind), userID, 0, nil), state)
+	return err
+}
+
+// LibraryChanges describes what changed in a user's library since the
+// previous sync.
+type LibraryChanges struct {
+	// AddedTracks are the tracks saved since the previous sync, most
+	// recent first.
+	AddedTracks []SavedTrack
+	// ChangedPlaylists are the playlists that are new, or whose snapshot
+	// ID has changed.
+	ChangedPlaylists []SimplePlaylist
+	// RemovedPlaylists are the playlists the user no longer has.
+	RemovedPlaylists []ID
+}
+
+// LibrarySyncer fetches the changes to a user's library since it last ran,
+// using a cursor kept in a SyncStore.  It's designed to be run periodically,
+// for example from an App Engine cron handler that enqueues one task per user.
+//
+// Saved tracks are returned newest first by the Web API, so the syncer stops
+// paging as soon as it reaches a track it has already seen.  Playlists are
+// compared by snapshot ID, so the (potentially large) track lists are only
+// fetched for playlists that have actually changed.  Removing a saved track
+// doesn't change the watermark, so removals aren't detected.
+type LibrarySyncer struct {
+	Store SyncStore
+	// PageSize is the number of items requested per call.  It defaults to
+	// 50, the maximum allowed by the Web API.
+	PageSize int
+	// Clock is used to timestamp the saved state.  If nil, SystemClock
+	// is used.
+	Clock Clock
+}
+
+// Sync returns the changes in the library of the user c is authorized for,
+// and records the new cursor.  The first sync for a user returns the whole
+// library.  The cursor is only updated if every request succeeds, so a
+// failed sync can simply be retried.
+func (s *LibrarySyncer) Sync(ctx context.Context, c SpotifyClient, userID string) (*LibraryChanges, error) {
+	state, err := s.Store.LoadSyncState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	limit := s.PageSize
+	if limit <= 0 {
+		limit = 50
+	}
+	changes := &LibraryChanges{}
+
+	// saved tracks, newest first, until the watermark
+	newest := state.LastAdded
+	offset := 0
+tracks:
+	for {
+		page, err := c.CurrentUsersTracksWithContext(ctx, &Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range page.Tracks {
+			added := t.AddedAt.UTC().Format(TimestampLayout)
+			if state.LastAdded != "" && added <= state.LastAdded {
+				break tracks
+			}
+			if added > newest {
+				newest = added
+			}
+			changes.AddedTracks = append(changes.AddedTracks, t)
+		}
+		offset += len(page.Tracks)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			break
+		}
+	}
+
+	// playlists, compared by snapshot
+	old := state.snapshots()
+	next := &SyncState{LastAdded: newest}
+	offset = 0
+	for {
+		page, err := c.CurrentUsersPlaylistsWithContext(ctx, &Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.Playlists {
+			if snap, ok := old[p.ID]; !ok || snap != p.SnapshotID {
+				changes.ChangedPlaylists = append(changes.ChangedPlaylists, p)
+			}
+			delete(old, p.ID)
+			next.PlaylistIDs = append(next.PlaylistIDs, string(p.ID))
+			next.Snapshots = append(next.Snapshots, p.SnapshotID)
+		}
+		offset += len(page.Playlists)
+		if page.Next == "" || len(page.Playlists) == 0 {
+			break
+		}
+	}
+	for _, id := range state.PlaylistIDs {
+		if _, ok := old[ID(id)]; ok {
+			changes.RemovedPlaylists = append(changes.RemovedPlaylists, ID(id))
+		}
+	}
+
+	clock := s.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	next.LastRun = clock.Now()
+	if err := s.Store.SaveSyncState(ctx, userID, next); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,106 @@
+package spotify
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type memSyncStore map[string]*SyncState
+
+func (m memSyncStore) LoadSyncState(ctx context.Context, userID string) (*SyncState, error) {
+	if s, ok := m[userID]; ok {
+		return s, nil
+	}
+	return &SyncState{}, nil
+}
+
+func (m memSyncStore) SaveSyncState(ctx context.Context, userID string, state *SyncState) error {
+	m[userID] = state
+	return nil
+}
+
+// libraryStub serves a fixed library, one item per page.
+type libraryStub struct {
+	SpotifyClient
+	saved     []SavedTrack
+	playlists []SimplePlaylist
+	calls     int
+}
+
+func (l *libraryStub) CurrentUsersTracksWithContext(ctx context.Context, opt *Options) (*SavedTrackPage, error) {
+	l.calls++
+	p := &SavedTrackPage{}
+	if *opt.Offset < len(l.saved) {
+		p.Tracks = l.saved[*opt.Offset : *opt.Offset+1]
+		if *opt.Offset+1 < len(l.saved) {
+			p.Next = "next"
+		}
+	}
+	return p, nil
+}
+
+func (l *libraryStub) CurrentUsersPlaylistsWithContext(ctx context.Context, opt *Options) (*SimplePlaylistPage, error) {
+	p := &SimplePlaylistPage{}
+	if *opt.Offset < len(l.playlists) {
+		p.Playlists = l.playlists[*opt.Offset : *opt.Offset+1]
+		if *opt.Offset+1 < len(l.playlists) {
+			p.Next = "next"
+		}
+	}
+	return p, nil
+}
+
+func savedTrack(id ID, addedAt string) SavedTrack {
+	var t SavedTrack
+	t.ID = id
+	t.AddedAt, _ = time.Parse(TimestampLayout, addedAt)
+	return t
+}
+
+func playlist(id ID, snapshot string) SimplePlaylist {
+	return SimplePlaylist{ID: id, SnapshotID: snapshot}
+}
+
+func TestLibrarySyncer(t *testing.T) {
+	store := memSyncStore{}
+	s := &LibrarySyncer{Store: store, PageSize: 1}
+	lib := &libraryStub{
+		saved: []SavedTrack{
+			savedTrack("b", "2017-05-02T10:00:00Z"),
+			savedTrack("a", "2017-05-01T10:00:00Z"),
+		},
+		playlists: []SimplePlaylist{playlist("p1", "s1"), playlist("p2", "s1")},
+	}
+	changes, err := s.Sync(context.Background(), lib, "wizzler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.AddedTracks) != 2 || len(changes.ChangedPlaylists) != 2 {
+		t.Errorf("First sync should return everything, got %+v\n", changes)
+	}
+
+	lib.saved = append([]SavedTrack{savedTrack("c", "2017-05-03T10:00:00Z")}, lib.saved...)
+	lib.playlists = []SimplePlaylist{playlist("p1", "s2"), playlist("p3", "s1")}
+	lib.calls = 0
+	changes, err = s.Sync(context.Background(), lib, "wizzler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.AddedTracks) != 1 || changes.AddedTracks[0].ID != "c" {
+		t.Errorf("Expected only track c, got %+v\n", changes.AddedTracks)
+	}
+	if lib.calls != 2 {
+		t.Errorf("Expected to stop paging at the watermark, made %d calls\n", lib.calls)
+	}
+	if len(changes.ChangedPlaylists) != 2 || changes.ChangedPlaylists[0].ID != "p1" || changes.ChangedPlaylists[1].ID != "p3" {
+		t.Errorf("Unexpected changed playlists %+v\n", changes.ChangedPlaylists)
+	}
+	if len(changes.RemovedPlaylists) != 1 || changes.RemovedPlaylists[0] != "p2" {
+		t.Errorf("Unexpected removed playlists %v\n", changes.RemovedPlaylists)
+	}
+	if store["wizzler"].LastAdded != "2017-05-03T10:00:00Z" {
+		t.Error("Watermark wasn't advanced")
+	}
+}
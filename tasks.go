@@ -0,0 +1,152 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/taskqueue"
+)
+
+// Kinds of task created by the Split functions.
+const (
+	TaskAnalyzeTracks = "analyze-tracks"
+	TaskSyncPlaylists = "sync-playlists"
+)
+
+// Batch sizes used when splitting work into tasks.  Audio features can be
+// fetched for up to 100 tracks at once; playlists are synced a few at a time
+// so that each task finishes well within the task queue deadline.
+const (
+	analyzeBatchSize = 100
+	syncBatchSize    = 10
+)
+
+// Task is a serializable unit of work, small enough to be processed within
+// a single task queue request.
+type Task struct {
+	// Kind determines which TaskFunc processes the task.
+	Kind string `json:"kind"`
+	// UserID identifies the user the work is done for, if any.
+	UserID string `json:"user_id,omitempty"`
+	// IDs are the items (tracks, playlists, ...) to process.
+	IDs []ID `json:"ids,omitempty"`
+	// Data holds any additional, application specific parameters.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// RetryCount is the number of times the task queue has already tried
+	// to run this task.  It is set by TaskHandler and isn't serialized.
+	RetryCount int `json:"-"`
+}
+
+// SplitAnalyzeTracks splits a list of tracks into TaskAnalyzeTracks tasks.
+func SplitAnalyzeTracks(ids []ID) []Task {
+	return splitTasks(TaskAnalyzeTracks, "", ids, analyzeBatchSize)
+}
+
+// SplitSyncPlaylists splits a user's playlists into TaskSyncPlaylists tasks.
+func SplitSyncPlaylists(userID string, playlists []ID) []Task {
+	return splitTasks(TaskSyncPlaylists, userID, playlists, syncBatchSize)
+}
+
+func splitTasks(kind, userID string, ids []ID, size int) []Task {
+	unique, _ := DedupeIDs(ids)
+	chunks := ChunkIDs(unique, size)
+	tasks := make([]Task, len(chunks))
+	for i, chunk := range chunks {
+		tasks[i] = Task{Kind: kind, UserID: userID, IDs: chunk}
+	}
+	return tasks
+}
+
+// EnqueueTasks adds tasks to the named queue (the empty string is the
+// default queue), to be POSTed to path, where a TaskHandler should be
+// listening.
+func EnqueueTasks(ctx context.Context, queue, path string, tasks []Task) error {
+	// the task queue accepts at most 100 tasks per call
+	const maxBatch = 100
+	for len(tasks) > 0 {
+		n := len(tasks)
+		if n > maxBatch {
+			n = maxBatch
+		}
+		batch := make([]*taskqueue.Task, n)
+		for i, t := range tasks[:n] {
+			payload, err := json.Marshal(t)
+			if err != nil {
+				return err
+			}
+			batch[i] = &taskqueue.Task{
+				Path:    path,
+				Method:  "POST",
+				Payload: payload,
+				Header:  http.Header{"Content-Type": {"application/json"}},
+			}
+		}
+		if _, err := taskqueue.AddMulti(ctx, batch, queue); err != nil {
+			return err
+		}
+		tasks = tasks[n:]
+	}
+	return nil
+}
+
+// TaskFunc processes a task.  If it returns an error, the task is retried
+// by the task queue, unless the error is permanent (see TaskHandler).
+type TaskFunc func(r *http.Request, t *Task) error
+
+// TaskHandler is an http.Handler that decodes tasks enqueued by EnqueueTasks
+// and dispatches them to the TaskFunc registered for their kind.
+//
+// The response tells the task queue whether to retry.  Malformed tasks,
+// tasks of an unknown kind, and tasks that fail with a Spotify error that
+// won't go away by trying again (any 4xx status other than 429 Too Many
+// Requests) are acknowledged and dropped.  Other errors cause a 503 response,
+// so the task queue retries with backoff.
+type TaskHandler struct {
+	mu    sync.RWMutex
+	funcs map[string]TaskFunc
+}
+
+// NewTaskHandler creates a TaskHandler with no registered kinds.
+func NewTaskHandler() *TaskHandler {
+	return &TaskHandler{funcs: make(map[string]TaskFunc)}
+}
+
+// Handle registers the function that processes tasks of the given kind.
+func (h *TaskHandler) Handle(kind string, f TaskFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.funcs[kind] = f
+}
+
+func (h *TaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var t Task
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "spotify: couldn't decode task: "+err.Error(), http.StatusOK)
+		return
+	}
+	t.RetryCount, _ = strconv.Atoi(r.Header.Get("X-AppEngine-TaskRetryCount"))
+
+	h.mu.RLock()
+	f, ok := h.funcs[t.Kind]
+	h.mu.RUnlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("spotify: no handler for task kind %q", t.Kind), http.StatusOK)
+		return
+	}
+	if err := f(r, &t); err != nil {
+		status := http.StatusServiceUnavailable
+		// a Spotify error that retrying won't fix is acknowledged so that
+		// the task isn't retried
+		if _, ok := err.(*Error); ok && !IsRetryable(err) {
+			status = http.StatusOK
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,68 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSplitAnalyzeTracks(t *testing.T) {
+	ids := make([]ID, 250)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("%022d", i))
+	}
+	tasks := SplitAnalyzeTracks(append(ids, ids[0]))
+	if len(tasks) != 3 {
+		t.Fatalf("Expected 3 tasks, got %d\n", len(tasks))
+	}
+	if len(tasks[2].IDs) != 50 || tasks[0].Kind != TaskAnalyzeTracks {
+		t.Errorf("Unexpected last task %+v\n", tasks[2])
+	}
+	if sync := SplitSyncPlaylists("wizzler", ids[:15]); len(sync) != 2 || sync[1].UserID != "wizzler" {
+		t.Errorf("Unexpected sync tasks %+v\n", sync)
+	}
+}
+
+func TestTaskHandler(t *testing.T) {
+	h := NewTaskHandler()
+	var got *Task
+	h.Handle(TaskSyncPlaylists, func(r *http.Request, task *Task) error {
+		got = task
+		switch task.UserID {
+		case "gone":
+			return &Error{Status: http.StatusNotFound, Message: "Not found"}
+		case "busy":
+			return &Error{Status: http.StatusTooManyRequests, Message: "API rate limit exceeded"}
+		case "flaky":
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"kind": "sync-playlists", "user_id": "wizzler", "ids": ["59ZbFPES4DQwEjBpWHzrtC"]}`, http.StatusOK},
+		{`{"kind": "sync-playlists", "user_id": "gone"}`, http.StatusOK},
+		{`{"kind": "sync-playlists", "user_id": "busy"}`, http.StatusServiceUnavailable},
+		{`{"kind": "sync-playlists", "user_id": "flaky"}`, http.StatusServiceUnavailable},
+		{`{"kind": "unknown"}`, http.StatusOK},
+		{`not json`, http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/tasks", strings.NewReader(test.body))
+		req.Header.Set("X-AppEngine-TaskRetryCount", "2")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != test.status {
+			t.Errorf("%s: got status %d, want %d\n", test.body, w.Code, test.status)
+		}
+	}
+	if got == nil || got.RetryCount != 2 {
+		t.Error("RetryCount wasn't set")
+	}
+}
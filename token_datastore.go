@@ -0,0 +1,133 @@
+package spotify
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"google.golang.org/appengine/datastore"
+)
+
+// DatastoreTokenStore is a TokenStore backed by App Engine Datastore.  Each
+// user's token is kept in an entity keyed by their ID (usually their
+// Spotify user ID).
+//
+// Refresh tokens give lasting access to users' accounts, so they should be
+// encrypted at rest: with a key, tokens are sealed with AES-GCM before
+// they're stored, and bound to their user, so that a token copied to
+// another user's entity can't be opened.  Keep the key out of Datastore,
+// for example in an environment variable set in app.yaml.
+type DatastoreTokenStore struct {
+	// Clock is used to time stamp saved tokens.  If nil, SystemClock is
+	// used.
+	Clock Clock
+
+	kind string
+	aead cipher.AEAD
+}
+
+func (s *DatastoreTokenStore) clock() Clock {
+	if s.Clock == nil {
+		return SystemClock
+	}
+	return s.Clock
+}
+
+// NewDatastoreTokenStore creates a DatastoreTokenStore that keeps tokens in
+// entities of the given kind.  key is an AES key of 16, 24 or 32 bytes to
+// encrypt the tokens with, or nil to store them in the clear.
+func NewDatastoreTokenStore(kind string, key []byte) (*DatastoreTokenStore, error) {
+	s := &DatastoreTokenStore{kind: kind}
+	if key == nil {
+		return s, nil
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if s.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type tokenEntity struct {
+	Data      []byte `datastore:",noindex"`
+	Encrypted bool   `datastore:",noindex"`
+	Updated   time.Time
+}
+
+// errTokenKey is returned when a stored token can't be opened.
+var errTokenKey = errors.New("spotify: can't decrypt the stored token (wrong key?)")
+
+// Load implements TokenStore.
+func (s *DatastoreTokenStore) Load(ctx context.Context, userID string) (*oauth2.Token, error) {
+	var e tokenEntity
+	err := datastore.Get(ctx, datastore.NewKey(ctx, s.kind, userID, 0, nil), &e)
+	if err == datastore.ErrNoSuchEntity {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.open(userID, &e)
+}
+
+// Save implements TokenStore.
+func (s *DatastoreTokenStore) Save(ctx context.Context, userID string, token *oauth2.Token) error {
+	e, err := s.seal(userID, token)
+	if err != nil {
+		return err
+	}
+	e.Updated = s.clock().Now()
+	_, err = datastore.Put(ctx, datastore.NewKey(ctx, s.kind, userID, 0, nil), e)
+	return err
+}
+
+// seal encodes the user's token for storing, encrypting it if the store
+// has a key.
+func (s *DatastoreTokenStore) seal(userID string, token *oauth2.Token) (*tokenEntity, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return nil, err
+	}
+	if s.aead == nil {
+		return &tokenEntity{Data: data}, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return &tokenEntity{Data: s.aead.Seal(nonce, nonce, data, []byte(userID)), Encrypted: true}, nil
+}
+
+// open decodes the user's stored token.  Tokens stored in the clear are
+// read even if the store has a key, so that encryption can be turned on
+// for an existing store; they are encrypted when next saved.
+func (s *DatastoreTokenStore) open(userID string, e *tokenEntity) (*oauth2.Token, error) {
+	data := e.Data
+	if e.Encrypted {
+		n := 0
+		if s.aead != nil {
+			n = s.aead.NonceSize()
+		}
+		if s.aead == nil || len(data) < n {
+			return nil, errTokenKey
+		}
+		var err error
+		if data, err = s.aead.Open(nil, data[:n], data[n:], []byte(userID)); err != nil {
+			return nil, errTokenKey
+		}
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
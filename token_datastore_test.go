@@ -0,0 +1,61 @@
+package spotify
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestDatastoreTokenStoreSeal(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	s, err := NewDatastoreTokenStore("Token", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := &oauth2.Token{AccessToken: "abc", TokenType: "Bearer", RefreshToken: "secret-refresh", Expiry: time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)}
+	e, err := s.seal("wizzler", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Encrypted || bytes.Contains(e.Data, []byte("secret-refresh")) {
+		t.Errorf("The token was stored in the clear: %q\n", e.Data)
+	}
+	got, err := s.open("wizzler", e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RefreshToken != token.RefreshToken || !got.Expiry.Equal(token.Expiry) {
+		t.Errorf("Got %+v, want %+v\n", got, token)
+	}
+
+	// A token can't be opened for another user, or with another key.
+	if _, err := s.open("someone-else", e); err == nil {
+		t.Error("Opened a token for the wrong user")
+	}
+	other, _ := NewDatastoreTokenStore("Token", bytes.Repeat([]byte{8}, 32))
+	if _, err := other.open("wizzler", e); err == nil {
+		t.Error("Opened a token with the wrong key")
+	}
+	plain, _ := NewDatastoreTokenStore("Token", nil)
+	if _, err := plain.open("wizzler", e); err == nil {
+		t.Error("Opened an encrypted token without a key")
+	}
+}
+
+func TestDatastoreTokenStorePlaintext(t *testing.T) {
+	plain, _ := NewDatastoreTokenStore("Token", nil)
+	e, err := plain.seal("wizzler", &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Turning on encryption doesn't lose the tokens stored before.
+	s, _ := NewDatastoreTokenStore("Token", bytes.Repeat([]byte{7}, 16))
+	if got, err := s.open("wizzler", e); err != nil || got.AccessToken != "abc" {
+		t.Errorf("Got %+v (%v)\n", got, err)
+	}
+	if _, err := NewDatastoreTokenStore("Token", []byte("short")); err == nil {
+		t.Error("Expected an error for a bad key size")
+	}
+}
@@ -1,11 +1,10 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
-	"net/http"
-	"strings"
 	"time"
+
+	"golang.org/x/net/context"
 )
 
 // SimpleTrack contains basic info about a track.
@@ -22,40 +21,99 @@ type SimpleTrack struct {
 	// true => yes, it does; false => no, it does not.
 	Explicit bool `json:"explicit"`
 	// External URLs for this track.
-	ExternalURLs map[string]string `json:"external_urls"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
 	// A link to the Web API endpoint providing full details for this track.
 	Endpoint string `json:"href"`
 	ID       ID     `json:"id"`
 	Name     string `json:"name"`
-	// A URL to a 30 second preview (MP3) of the track.
-	PreviewURL string `json:"preview_url"`
+	// A URL to a 30 second preview (MP3) of the track, or nil if there's
+	// no preview.
+	PreviewURL *string `json:"preview_url"`
 	// The number of the track.  If an album has several
 	// discs, the track number is the number on the specified
 	// DiscNumber.
 	TrackNumber int `json:"track_number"`
 	URI         URI `json:"uri"`
+	// Whether or not the track is playable in the market the request was
+	// made for.  Only present when a market is given.
+	IsPlayable bool `json:"is_playable"`
+	// The track that was requested, when track relinking has replaced it
+	// with a different track that is playable in the given market.
+	LinkedFrom *LinkedTrack `json:"linked_from,omitempty"`
+	// Why the track can't be played, if a market was given and the track
+	// isn't playable there.
+	Restrictions *Restrictions `json:"restrictions,omitempty"`
+	// The object type: "track".
+	Type string `json:"type"`
+}
+
+// Restrictions explains why content can't be played.  It is returned for
+// tracks and albums when a market is given.
+type Restrictions struct {
+	// The reason for the restriction.  Spotify may add more reasons, so
+	// callers should handle ones they don't recognize.
+	Reason RestrictionReason `json:"reason"`
+}
+
+// RestrictionReason is the reason content is restricted.
+type RestrictionReason string
+
+// RestrictionReason values.
+const (
+	// RestrictionMarket means the content isn't available in the market.
+	RestrictionMarket RestrictionReason = "market"
+	// RestrictionProduct means the content isn't available for the user's
+	// subscription type.
+	RestrictionProduct RestrictionReason = "product"
+	// RestrictionExplicit means the user's account is set not to play
+	// explicit content.
+	RestrictionExplicit RestrictionReason = "explicit"
+)
+
+// Explain returns a sentence, suitable for showing to the user, that
+// explains why the content can't be played.
+func (r RestrictionReason) Explain() string {
+	switch r {
+	case RestrictionMarket:
+		return "This isn't available in your country."
+	case RestrictionProduct:
+		return "This isn't available with your subscription."
+	case RestrictionExplicit:
+		return "This contains explicit content, which your account is set not to play."
+	}
+	return "This can't be played."
+}
+
+// LinkedTrack identifies the original track requested when Spotify relinks
+// a track to one that is available in the user's market.
+type LinkedTrack struct {
+	ExternalURLs ExternalURLs `json:"external_urls"`
+	Endpoint     string       `json:"href"`
+	ID           ID           `json:"id"`
+	Type         string       `json:"type"`
+	URI          URI          `json:"uri"`
 }
 
 // FullTrack provides extra track data in addition to what is provided by SimpleTrack.
 type FullTrack struct {
 	SimpleTrack
 	// The album on which the track appears. The album object includes a link in href to full information about the album.
-	Album SimpleAlbum `json:"album"`
+	// It is nil when the response has no album, which happens for some local files.
+	Album *SimpleAlbum `json:"album"`
 	// Known external IDs for the track.
-	ExternalIDs map[string]string `json:"external_ids"`
+	ExternalIDs ExternalIDs `json:"external_ids"`
 	// Popularity of the track.  The value will be between 0 and 100,
 	// with 100 being the most popular.  The popularity is calculated from
 	// both total plays and most recent plays.
-	Popularity int `json:"popularity"`
+	Popularity Popularity `json:"popularity"`
 }
 
 // PlaylistTrack contains info about a track in a playlist.
 type PlaylistTrack struct {
 	// The date and time the track was added to the playlist.
-	// You can use the TimestampLayout constant to convert
-	// this field to a time.Time value.
-	// Warning: very old playlists may not populate this value.
-	AddedAt string `json:"added_at"`
+	// Warning: very old playlists may not populate this value,
+	// leaving it the zero time.
+	AddedAt time.Time `json:"added_at"`
 	// The Spotify user who added the track to the playlist.
 	// Warning: vary old playlists may not populate this value.
 	AddedBy User `json:"added_by"`
@@ -65,11 +123,8 @@ type PlaylistTrack struct {
 
 // SavedTrack provides info about a track saved to a user's account.
 type SavedTrack struct {
-	// The date and time the track was saved, represented as an ISO
-	// 8601 UTC timestamp with a zero offset (YYYY-MM-DDTHH:MM:SSZ).
-	// You can use the TimestampLayout constant to convert this to
-	// a time.Time value.
-	AddedAt   string `json:"added_at"`
+	// The date and time the track was saved.
+	AddedAt   time.Time `json:"added_at"`
 	FullTrack `json:"track"`
 }
 
@@ -86,18 +141,15 @@ func GetTrack(id ID) (*FullTrack, error) {
 // GetTrack gets Spotify catalog information for
 // a single track identified by its unique Spotify ID.
 func (c *Client) GetTrack(id ID) (*FullTrack, error) {
-	spotifyURL := baseAddress + "tracks/" + string(id)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetTrackWithContext(context.Background(), id)
+}
+
+// GetTrackWithContext is like GetTrack, with a context.
+func (c *Client) GetTrackWithContext(ctx context.Context, id ID) (*FullTrack, error) {
+	e := c.endpoint("tracks/%s", id)
+	e.setDefaultMarket()
 	var t FullTrack
-	err = json.NewDecoder(resp.Body).Decode(&t)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &t); err != nil {
 		return nil, err
 	}
 	return &t, nil
@@ -114,25 +166,36 @@ func GetTracks(ids ...ID) ([]*FullTrack, error) {
 // result will be nil.  Duplicate ids in the query will result in duplicate
 // tracks in the result.
 func (c *Client) GetTracks(ids ...ID) ([]*FullTrack, error) {
+	return c.GetTracksWithContext(context.Background(), nil, ids...)
+}
+
+// GetTracksOpt is like GetTracks, but it accepts optional parameters.  If
+// opt.Country is set, track relinking is applied for that market: each
+// track's IsPlayable field reports whether it can be played there, and a
+// track that is only available there as a different track is replaced by
+// that track, with LinkedFrom identifying the track requested.
+func (c *Client) GetTracksOpt(opt *Options, ids ...ID) ([]*FullTrack, error) {
+	return c.GetTracksWithContext(context.Background(), opt, ids...)
+}
+
+// GetTracksWithContext is like GetTracksOpt, with a context.
+func (c *Client) GetTracksWithContext(ctx context.Context, opt *Options, ids ...ID) ([]*FullTrack, error) {
 	if len(ids) > 50 {
 		return nil, errors.New("spotify: FindTracks supports up to 50 tracks")
 	}
-	spotifyURL := baseAddress + "tracks?ids=" + strings.Join(toStringSlice(ids), ",")
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
+	if err := ValidateIDs(ids...); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+	e := c.endpoint("tracks")
+	e.setIDs(ids)
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
 	}
-
 	var t struct {
-		Tracks []*FullTrack `jsosn:"tracks"`
+		Tracks []*FullTrack `json:"tracks"`
 	}
-	err = json.NewDecoder(resp.Body).Decode(&t)
-	if err != nil {
-		return nil, errors.New("spotify:  couldn't decode tracks")
+	if err := c.get(ctx, e.String(), &t); err != nil {
+		return nil, err
 	}
 	return t.Tracks, nil
 }
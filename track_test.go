@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 )
@@ -49,3 +50,103 @@ func TestFindTracksNotFound(t *testing.T) {
 		t.Error("Expected nil track (invalid ID) but got valid track")
 	}
 }
+
+func TestGetTracksInvalidID(t *testing.T) {
+	client := testClientString(http.StatusOK, "")
+	_, err := client.GetTracks(ID("0eGsygTp906u18L0Oimnem"), ID("not-an-id"))
+	if _, ok := err.(InvalidIDError); !ok {
+		t.Errorf("Expected InvalidIDError, got %v\n", err)
+	}
+	if getLastRequest(client) != nil {
+		t.Error("Request shouldn't be sent for invalid IDs")
+	}
+}
+
+func TestIDValid(t *testing.T) {
+	if !ID("6rqhFgbbKwnb9MLmUQDhG6").Valid() {
+		t.Error("Expected valid ID")
+	}
+	for _, id := range []ID{"", "6rqhFgbbKwnb9MLmUQDhG", "6rqhFgbbKwnb9MLmUQDh-6", "6rqhFgbbKwnb9MLmUQDhG6x"} {
+		if id.Valid() {
+			t.Errorf("Expected %q to be invalid\n", id)
+		}
+	}
+}
+
+func TestNullTrackFields(t *testing.T) {
+	client := testClientString(http.StatusOK, `{
+		"name": "Demo", "uri": "spotify:local:Band::Demo:120",
+		"album": null, "preview_url": null, "id": null
+	}`)
+	track, err := client.GetTrack(ID("1zHlj4dQ8ZAtrayhuDDmkY"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track.Album != nil || track.PreviewURL != nil {
+		t.Errorf("Expected nil album and preview URL, got %v and %v\n", track.Album, track.PreviewURL)
+	}
+
+	client = testClientFile(http.StatusOK, "test_data/golden/track_full.json")
+	track, err = client.GetTrack(ID("1zHlj4dQ8ZAtrayhuDDmkY"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track.Album == nil || track.PreviewURL == nil {
+		t.Error("Expected album and preview URL to be set")
+	}
+}
+
+func TestRestrictionReasonExplain(t *testing.T) {
+	if RestrictionMarket.Explain() == RestrictionReason("unknown").Explain() {
+		t.Error("Expected a specific explanation for market restrictions")
+	}
+	if RestrictionReason("unknown").Explain() == "" {
+		t.Error("Expected an explanation for unknown reasons")
+	}
+}
+
+func TestGetTracksOmitAvailableMarkets(t *testing.T) {
+	client := testClientString(http.StatusOK, `{"tracks": []}`)
+	if _, err := client.GetTracksOpt(&Options{OmitAvailableMarkets: true}, ID("0eGsygTp906u18L0Oimnem")); err != nil {
+		t.Fatal(err)
+	}
+	if got := getLastRequest(client).URL.Query().Get("market"); got != MarketFromToken {
+		t.Errorf("Expected market %s, got %q\n", MarketFromToken, got)
+	}
+
+	client = testClientString(http.StatusOK, `{"tracks": []}`)
+	country := CountryGermany
+	if _, err := client.GetTracksOpt(&Options{Country: &country, OmitAvailableMarkets: true}, ID("0eGsygTp906u18L0Oimnem")); err != nil {
+		t.Fatal(err)
+	}
+	if got := getLastRequest(client).URL.Query().Get("market"); got != CountryGermany {
+		t.Errorf("Expected market %s, got %q\n", CountryGermany, got)
+	}
+}
+
+func TestTrackMarshalOptionalFields(t *testing.T) {
+	var track FullTrack
+	if err := json.Unmarshal([]byte(`{"id":"0eGsygTp906u18L0Oimnem","album":null,"preview_url":null}`), &track); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(track)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	// Spotify leaves these out unless they apply.
+	for _, name := range []string{"linked_from", "restrictions"} {
+		if _, ok := fields[name]; ok {
+			t.Errorf("Expected %s to be omitted\n", name)
+		}
+	}
+	// Null is meaningful for these.
+	for _, name := range []string{"album", "preview_url"} {
+		if v, ok := fields[name]; !ok || v != nil {
+			t.Errorf("Expected %s to be null, got %v\n", name, v)
+		}
+	}
+}
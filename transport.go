@@ -0,0 +1,64 @@
+package spotify
+
+import (
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes the connection pool of the transport returned by
+// NewTransport.
+type TransportOptions struct {
+	// MaxIdleConns is the most idle connections kept open in total.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the most idle connections kept open to each
+	// host.  All Web API calls go to one host, so this should be at least
+	// the number of calls made concurrently, or connections are closed and
+	// reopened between calls.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultTransportOptions are used by DefaultClient and NewAuthenticator.
+// They keep enough connections open for batch jobs that make thousands of
+// calls, a few at a time.
+var DefaultTransportOptions = TransportOptions{
+	MaxIdleConns:        64,
+	MaxIdleConnsPerHost: 16,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// NewTransport returns an HTTP transport suitable for the Web API, which
+// keeps connections alive between calls.  HTTP/2 is disabled, see:
+// https://github.com/zmb3/spotify/issues/20.  To use it for an authorized
+// client, pass it to Authenticator.SetTransport.  On App Engine, use
+// NewClientFromContext instead, which makes requests through urlfetch.
+func NewTransport(opts TransportOptions) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSNextProto:        map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+		MaxIdleConns:        opts.MaxIdleConns,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+	}
+}
+
+// maxDrain is the most of an unread response body that drain reads.
+const maxDrain = 4 << 10
+
+// drain reads what's left of a response body, so that the connection can
+// be reused once the body is closed.  A json.Decoder stops at the end of
+// the value, usually leaving a trailing newline unread, and a connection
+// whose body wasn't read to the end is closed instead of reused.
+func drain(r io.Reader) {
+	io.Copy(ioutil.Discard, io.LimitReader(r, maxDrain))
+}
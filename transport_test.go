@@ -0,0 +1,83 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// hostTransport sends every request to host instead of the Web API.
+type hostTransport struct {
+	base http.RoundTripper
+	host string
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := *req
+	u := *req.URL
+	u.Scheme, u.Host = "http", t.host
+	r.URL = &u
+	return t.base.RoundTrip(&r)
+}
+
+// newTrackServer returns a server that answers every request with a track,
+// and a counter of the connections made to it.
+func newTrackServer(tb testing.TB) (*httptest.Server, *int32) {
+	body, err := ioutil.ReadFile("test_data/golden/track_full.json")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var conns int32
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	s.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	s.Start()
+	return s, &conns
+}
+
+func newTransportClient(s *httptest.Server, tr *http.Transport) Client {
+	host := s.Listener.Addr().String()
+	return NewClient(&http.Client{Transport: hostTransport{base: tr, host: host}})
+}
+
+func TestConnectionReuse(t *testing.T) {
+	s, conns := newTrackServer(t)
+	defer s.Close()
+	c := newTransportClient(s, NewTransport(DefaultTransportOptions))
+	for i := 0; i < 20; i++ {
+		if _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("20 sequential calls used %d connections, want 1", n)
+	}
+}
+
+func benchmarkSequential(b *testing.B, keepAlive bool) {
+	s, _ := newTrackServer(b)
+	defer s.Close()
+	tr := NewTransport(DefaultTransportOptions)
+	tr.DisableKeepAlives = !keepAlive
+	c := newTransportClient(s, tr)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetTrack("1zHlj4dQ8ZAtrayhuDDmkY"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Compare these to see the cost of opening a connection for every call.
+// The difference is much larger against the real Web API, where each new
+// connection also needs a TLS handshake.
+func BenchmarkSequentialKeepAlive(b *testing.B)   { benchmarkSequential(b, true) }
+func BenchmarkSequentialNoKeepAlive(b *testing.B) { benchmarkSequential(b, false) }
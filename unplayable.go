@@ -0,0 +1,143 @@
+package spotify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reasons a playlist item can't be played, reported by PlaylistScanner.
+const (
+	// UnplayableRemoved means the track no longer exists in the catalog.
+	UnplayableRemoved = "removed"
+	// UnplayableRestricted means the track exists in the market, but has
+	// restrictions (see Restrictions.Reason) that prevent it from playing.
+	UnplayableRestricted = "restricted"
+	// UnplayableUnavailable means the track isn't available in the market,
+	// even through relinking.
+	UnplayableUnavailable = "unavailable"
+)
+
+// UnplayableItem is a playlist item that can't be played.
+type UnplayableItem struct {
+	Playlist SimplePlaylist
+	// Position is the index of the item in the playlist.
+	Position int
+	Track    FullTrack
+	// Reason is one of UnplayableRemoved, UnplayableRestricted or
+	// UnplayableUnavailable.
+	Reason string
+	// Replacements are playable tracks with the same name and artist,
+	// found by searching the catalog.
+	Replacements []FullTrack
+}
+
+// PlaylistScanner finds items in playlists that can no longer be played.
+type PlaylistScanner struct {
+	// Market is the market to check.  If empty, the market of the user's
+	// account is used, which requires the ScopeUserReadPrivate scope.
+	Market string
+	// Suggestions is the maximum number of replacements to suggest for
+	// each unplayable item.  If zero, no searches are made.
+	Suggestions int
+}
+
+func (s *PlaylistScanner) market() string {
+	if s.Market == "" {
+		return MarketFromToken
+	}
+	return s.Market
+}
+
+// ScanAll scans all of the current user's playlists.
+func (s *PlaylistScanner) ScanAll(c SpotifyClient) ([]UnplayableItem, error) {
+	var playlists []SimplePlaylist
+	limit, offset := 50, 0
+	for {
+		page, err := c.CurrentUsersPlaylistsOpt(&Options{Limit: &limit, Offset: &offset})
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, page.Playlists...)
+		offset += len(page.Playlists)
+		if page.Next == "" || len(page.Playlists) == 0 {
+			break
+		}
+	}
+	return s.Scan(c, playlists...)
+}
+
+// Scan walks the playlists and returns the items that can't be played in
+// the market.  Local files aren't checked.
+func (s *PlaylistScanner) Scan(c SpotifyClient, playlists ...SimplePlaylist) ([]UnplayableItem, error) {
+	market := s.market()
+	var items []UnplayableItem
+	for _, p := range playlists {
+		limit, offset := 100, 0
+		for {
+			page, err := c.GetPlaylistTracksOpt(p.Owner.ID, p.ID, &Options{Limit: &limit, Offset: &offset, Country: &market}, "")
+			if err != nil {
+				return nil, err
+			}
+			for i, t := range page.Tracks {
+				reason := unplayableReason(t.Track)
+				if reason == "" {
+					continue
+				}
+				item := UnplayableItem{Playlist: p, Position: offset + i, Track: t.Track, Reason: reason}
+				if s.Suggestions > 0 && t.Track.Name != "" {
+					if item.Replacements, err = s.replacements(c, t.Track); err != nil {
+						return nil, err
+					}
+				}
+				items = append(items, item)
+			}
+			offset += len(page.Tracks)
+			if page.Next == "" || len(page.Tracks) == 0 {
+				break
+			}
+		}
+	}
+	return items, nil
+}
+
+// unplayableReason returns why the track can't be played, or the empty
+// string if it can (or is a local file, which isn't in the catalog).
+func unplayableReason(t FullTrack) string {
+	switch {
+	case strings.HasPrefix(string(t.URI), "spotify:local:"):
+		return ""
+	case t.ID == "":
+		return UnplayableRemoved
+	case t.IsPlayable:
+		return ""
+	case t.Restrictions != nil && t.Restrictions.Reason != RestrictionMarket:
+		return UnplayableRestricted
+	}
+	return UnplayableUnavailable
+}
+
+// replacements searches for playable tracks with the same name as t, by
+// its first artist.
+func (s *PlaylistScanner) replacements(c SpotifyClient, t FullTrack) ([]FullTrack, error) {
+	query := fmt.Sprintf("track:%q", t.Name)
+	if len(t.Artists) > 0 {
+		query += fmt.Sprintf(" artist:%q", t.Artists[0].Name)
+	}
+	market := s.market()
+	limit := s.Suggestions + 1
+	result, err := c.SearchOpt(query, SearchTypeTrack, &Options{Country: &market, Limit: &limit})
+	if err != nil {
+		return nil, err
+	}
+	if result.Tracks == nil {
+		return nil, nil
+	}
+	var tracks []FullTrack
+	for _, r := range result.Tracks.Tracks {
+		if r.ID == t.ID || len(tracks) == s.Suggestions {
+			continue
+		}
+		tracks = append(tracks, r)
+	}
+	return tracks, nil
+}
@@ -0,0 +1,64 @@
+package spotify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPlaylistScanner(t *testing.T) {
+	var searches []string
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body string
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/tracks"):
+			if req.URL.Query().Get("market") != "SE" {
+				t.Error("Expected the market to be set:", req.URL)
+			}
+			body = `{"items":[
+				{"track":{"id":"4iV5W9uYEdYUVa79Axb7Rh","name":"Fine","is_playable":true}},
+				{"track":null},
+				{"track":{"id":"1301WleyT98MSxVHPZCA6M","name":"Rude","is_playable":false,"restrictions":{"reason":"explicit"}}},
+				{"track":{"id":"6rqhFgbbKwnb9MLmUQDhG6","name":"Gone","artists":[{"name":"Band"}],"is_playable":false}},
+				{"track":{"id":null,"uri":"spotify:local:Band:Album:Demo:120","name":"Demo"}}
+			],"next":null}`
+		case strings.HasSuffix(req.URL.Path, "/search"):
+			searches = append(searches, req.URL.Query().Get("q"))
+			body = `{"tracks":{"items":[
+				{"id":"6rqhFgbbKwnb9MLmUQDhG6","name":"Gone"},
+				{"id":"0eGsygTp906u18L0Oimnem","name":"Gone","is_playable":true}]}}`
+		default:
+			t.Fatalf("Unexpected request %s\n", req.URL)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+
+	var p SimplePlaylist
+	p.ID = "playlist"
+	p.Owner.ID = "owner"
+	s := &PlaylistScanner{Market: "SE", Suggestions: 1}
+	items, err := s.Scan(&c, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 unplayable items, got %+v\n", items)
+	}
+	want := []struct {
+		pos    int
+		reason string
+	}{{1, UnplayableRemoved}, {2, UnplayableRestricted}, {3, UnplayableUnavailable}}
+	for i, w := range want {
+		if items[i].Position != w.pos || items[i].Reason != w.reason {
+			t.Errorf("Item %d: got position %d (%s), want %d (%s)\n", i, items[i].Position, items[i].Reason, w.pos, w.reason)
+		}
+	}
+	gone := items[2]
+	if len(gone.Replacements) != 1 || gone.Replacements[0].ID != "0eGsygTp906u18L0Oimnem" {
+		t.Errorf("Unexpected replacements %+v\n", gone.Replacements)
+	}
+	if len(searches) != 2 || searches[1] != `track:"Gone" artist:"Band"` {
+		t.Error("Unexpected searches:", searches)
+	}
+}
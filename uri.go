@@ -0,0 +1,133 @@
+package spotify
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ItemType identifies the kind of Spotify object referenced by a URI
+// or link.
+type ItemType string
+
+// ItemType values found in Spotify URIs and open.spotify.com links.
+const (
+	ItemTypeAlbum    ItemType = "album"
+	ItemTypeArtist   ItemType = "artist"
+	ItemTypeEpisode  ItemType = "episode"
+	ItemTypePlaylist ItemType = "playlist"
+	ItemTypeShow     ItemType = "show"
+	ItemTypeTrack    ItemType = "track"
+	ItemTypeUser     ItemType = "user"
+)
+
+var itemTypes = map[ItemType]bool{
+	ItemTypeAlbum:    true,
+	ItemTypeArtist:   true,
+	ItemTypeEpisode:  true,
+	ItemTypePlaylist: true,
+	ItemTypeShow:     true,
+	ItemTypeTrack:    true,
+	ItemTypeUser:     true,
+}
+
+// ErrInvalidURI is returned when a string can't be parsed as a Spotify
+// URI or link.
+var ErrInvalidURI = errors.New("spotify: invalid URI or link")
+
+// ParseURI splits a Spotify URI such as "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"
+// into its item type and ID.  Legacy user playlist URIs of the form
+// "spotify:user:{user_id}:playlist:{playlist_id}" are also accepted, in which
+// case the playlist is returned.
+func ParseURI(uri string) (ItemType, ID, error) {
+	parts := strings.Split(strings.TrimSpace(uri), ":")
+	if len(parts) < 3 || parts[0] != "spotify" {
+		return "", "", ErrInvalidURI
+	}
+	return parsePath(parts[1:])
+}
+
+// ParseURL extracts the item type and ID from an open.spotify.com share link,
+// such as "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6?si=abc".
+// Query parameters, fragments and localized "/intl-xx/" path prefixes are
+// ignored.  For convenience, ParseURL also accepts Spotify URIs, so it can be
+// used anywhere a user might paste either form.
+func ParseURL(link string) (ItemType, ID, error) {
+	link = strings.TrimSpace(link)
+	if strings.HasPrefix(link, "spotify:") {
+		return ParseURI(link)
+	}
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", ErrInvalidURI
+	}
+	if host := strings.ToLower(u.Host); host != "open.spotify.com" && host != "play.spotify.com" {
+		return "", "", ErrInvalidURI
+	}
+	var parts []string
+	for _, p := range strings.Split(u.Path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) > 0 && strings.HasPrefix(parts[0], "intl-") {
+		parts = parts[1:]
+	}
+	if len(parts) < 2 {
+		return "", "", ErrInvalidURI
+	}
+	return parsePath(parts)
+}
+
+// parsePath interprets the components that follow the "spotify:" prefix of
+// a URI or the host of a link.
+func parsePath(parts []string) (ItemType, ID, error) {
+	// legacy playlist links include the owner: user/{user_id}/playlist/{id}
+	if len(parts) == 4 && ItemType(parts[0]) == ItemTypeUser && ItemType(parts[2]) == ItemTypePlaylist {
+		parts = parts[2:]
+	}
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", ErrInvalidURI
+	}
+	t := ItemType(parts[0])
+	if !itemTypes[t] {
+		return "", "", ErrInvalidURI
+	}
+	return t, ID(parts[1]), nil
+}
+
+// BuildURI returns the Spotify URI for the item with the specified type
+// and ID.  For example, BuildURI(ItemTypeTrack, "6rqhFgbbKwnb9MLmUQDhG6")
+// returns "spotify:track:6rqhFgbbKwnb9MLmUQDhG6".
+func BuildURI(t ItemType, id ID) URI {
+	return URI("spotify:" + string(t) + ":" + string(id))
+}
+
+// BuildURL returns the open.spotify.com web link for the item with the
+// specified type and ID.
+func BuildURL(t ItemType, id ID) string {
+	return "https://open.spotify.com/" + string(t) + "/" + string(id)
+}
+
+// OpenURL returns the open.spotify.com web link for the track.
+func (t *SimpleTrack) OpenURL() string {
+	return BuildURL(ItemTypeTrack, t.ID)
+}
+
+// OpenURL returns the open.spotify.com web link for the album.
+func (a *SimpleAlbum) OpenURL() string {
+	return BuildURL(ItemTypeAlbum, a.ID)
+}
+
+// OpenURL returns the open.spotify.com web link for the artist.
+func (a *SimpleArtist) OpenURL() string {
+	return BuildURL(ItemTypeArtist, a.ID)
+}
+
+// OpenURL returns the open.spotify.com web link for the playlist.
+func (p *SimplePlaylist) OpenURL() string {
+	return BuildURL(ItemTypePlaylist, p.ID)
+}
@@ -0,0 +1,82 @@
+package spotify
+
+import "testing"
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		in  string
+		typ ItemType
+		id  ID
+	}{
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", ItemTypeTrack, "6rqhFgbbKwnb9MLmUQDhG6"},
+		{"spotify:artist:0TnOYISbd1XYRBk9myaseg", ItemTypeArtist, "0TnOYISbd1XYRBk9myaseg"},
+		{"spotify:user:spotify:playlist:59ZbFPES4DQwEjBpWHzrtC", ItemTypePlaylist, "59ZbFPES4DQwEjBpWHzrtC"},
+	}
+	for _, test := range tests {
+		typ, id, err := ParseURI(test.in)
+		if err != nil {
+			t.Errorf("%s: %v\n", test.in, err)
+			continue
+		}
+		if typ != test.typ || id != test.id {
+			t.Errorf("%s: got (%s, %s), wanted (%s, %s)\n", test.in, typ, id, test.typ, test.id)
+		}
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		in  string
+		typ ItemType
+		id  ID
+	}{
+		{"https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6", ItemTypeTrack, "6rqhFgbbKwnb9MLmUQDhG6"},
+		{"https://open.spotify.com/intl-de/album/0sNOF9WDwhWunNAHPD3Baj?si=7cb9c3b4f1a54c3e", ItemTypeAlbum, "0sNOF9WDwhWunNAHPD3Baj"},
+		{"open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M#top", ItemTypePlaylist, "37i9dQZF1DXcBWIGoYBM5M"},
+		{"http://open.spotify.com/user/spotify/playlist/59ZbFPES4DQwEjBpWHzrtC", ItemTypePlaylist, "59ZbFPES4DQwEjBpWHzrtC"},
+		{"spotify:show:5CfCWKI5pZ28U0uOzXkDHe", ItemTypeShow, "5CfCWKI5pZ28U0uOzXkDHe"},
+	}
+	for _, test := range tests {
+		typ, id, err := ParseURL(test.in)
+		if err != nil {
+			t.Errorf("%s: %v\n", test.in, err)
+			continue
+		}
+		if typ != test.typ || id != test.id {
+			t.Errorf("%s: got (%s, %s), wanted (%s, %s)\n", test.in, typ, id, test.typ, test.id)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"spotify:track",
+		"spotify:widget:6rqhFgbbKwnb9MLmUQDhG6",
+		"https://example.com/track/6rqhFgbbKwnb9MLmUQDhG6",
+		"https://open.spotify.com/",
+	} {
+		if _, _, err := ParseURL(in); err != ErrInvalidURI {
+			t.Errorf("%q: expected ErrInvalidURI, got %v\n", in, err)
+		}
+	}
+}
+
+func TestBuildURI(t *testing.T) {
+	uri := BuildURI(ItemTypeTrack, "6rqhFgbbKwnb9MLmUQDhG6")
+	if uri != "spotify:track:6rqhFgbbKwnb9MLmUQDhG6" {
+		t.Errorf("Got unexpected URI %s\n", uri)
+	}
+	typ, id, err := ParseURI(string(uri))
+	if err != nil || typ != ItemTypeTrack || id != "6rqhFgbbKwnb9MLmUQDhG6" {
+		t.Errorf("URI didn't round trip: (%s, %s, %v)\n", typ, id, err)
+	}
+}
+
+func TestOpenURL(t *testing.T) {
+	var track FullTrack
+	track.ID = "6rqhFgbbKwnb9MLmUQDhG6"
+	if u := track.OpenURL(); u != "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6" {
+		t.Errorf("Got unexpected URL %s\n", u)
+	}
+}
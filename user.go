@@ -1,13 +1,9 @@
 package spotify
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
+
+	"golang.org/x/net/context"
 )
 
 // User contains the basic, publicly available information about a Spotify user.
@@ -17,7 +13,7 @@ type User struct {
 	// this field when querying for a playlist.
 	DisplayName string `json:"display_name"`
 	// Known public external URLs for the user.
-	ExternalURLs map[string]string `json:"external_urls"`
+	ExternalURLs ExternalURLs `json:"external_urls"`
 	// Information about followers of the user.
 	Followers Followers `json:"followers"`
 	// A link to the Web API endpoint for this user.
@@ -25,9 +21,11 @@ type User struct {
 	// The Spotify user ID for the user.
 	ID string `json:"id"`
 	// The user's profile image.
-	Images []Image `json:"images"`
+	Images Images `json:"images"`
 	// The Spotify URI for the user.
 	URI URI `json:"uri"`
+	// The object type: "user".
+	Type string `json:"type"`
 }
 
 // PrivateUser contains additional information about a user.
@@ -63,17 +61,14 @@ func GetUsersPublicProfile(userID ID) (*User, error) {
 // GetUsersPublicProfile gets public profile information about a
 // Spotify User.  It does not require authentication.
 func (c *Client) GetUsersPublicProfile(userID ID) (*User, error) {
-	spotifyURL := baseAddress + "users/" + string(userID)
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.GetUsersPublicProfileWithContext(context.Background(), userID)
+}
+
+// GetUsersPublicProfileWithContext is like GetUsersPublicProfile, with a
+// context.
+func (c *Client) GetUsersPublicProfileWithContext(ctx context.Context, userID ID) (*User, error) {
 	var user User
-	err = json.NewDecoder(resp.Body).Decode(&user)
-	if err != nil {
+	if err := c.get(ctx, c.endpoint("users/%s", userID).String(), &user); err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -92,17 +87,13 @@ func (c *Client) GetUsersPublicProfile(userID ID) (*User, error) {
 // This email address is unverified - do not assume that Spotify has
 // checked that the email address actually belongs to the user.
 func (c *Client) CurrentUser() (*PrivateUser, error) {
-	resp, err := c.http.Get(baseAddress + "me")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.CurrentUserWithContext(context.Background())
+}
+
+// CurrentUserWithContext is like CurrentUser, with a context.
+func (c *Client) CurrentUserWithContext(ctx context.Context) (*PrivateUser, error) {
 	var result PrivateUser
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, c.endpoint("me").String(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -111,44 +102,46 @@ func (c *Client) CurrentUser() (*PrivateUser, error) {
 // CurrentUsersTracks gets a list of songs saved in the current
 // Spotify user's "Your Music" library.
 func (c *Client) CurrentUsersTracks() (*SavedTrackPage, error) {
-	return c.CurrentUsersTracksOpt(nil)
+	return c.CurrentUsersTracksWithContext(context.Background(), nil)
 }
 
 // CurrentUsersTracksOpt is like CurrentUsersTracks, but it accepts additional
-// options for sorting and filtering the results.
+// options for sorting and filtering the results.  opt.Country is sent as the
+// market parameter, which the endpoint takes; earlier versions sent it as
+// country, which the endpoint ignored.
 func (c *Client) CurrentUsersTracksOpt(opt *Options) (*SavedTrackPage, error) {
-	spotifyURL := baseAddress + "me/tracks"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Country != nil {
-			v.Set("country", *opt.Country)
-		}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
+	return c.CurrentUsersTracksWithContext(context.Background(), opt)
+}
+
+// CurrentUsersTracksWithContext is like CurrentUsersTracksOpt, with a
+// context.
+func (c *Client) CurrentUsersTracksWithContext(ctx context.Context, opt *Options) (*SavedTrackPage, error) {
+	e, err := c.savedTracksEndpoint(opt)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
 	var result SavedTrackPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// savedTracksEndpoint returns the endpoint for the user's saved tracks,
+// with the parameters from opt.
+func (c *Client) savedTracksEndpoint(opt *Options) (*endpoint, error) {
+	e := c.endpoint("me/tracks")
+	if err := e.setMarket(opt); err != nil {
+		return nil, err
+	}
+	e.setPaging(opt)
+	return e, nil
+}
+
+// maxFollowIDs is the most IDs the follow endpoints take per request.  The
+// follow methods split longer lists into several requests.
+const maxFollowIDs = 50
+
 // FollowUser adds the current user as a follower of one or more
 // spotify users, identified by their Spotify IDs.
 // This call requires authorization.
@@ -156,17 +149,29 @@ func (c *Client) CurrentUsersTracksOpt(opt *Options) (*SavedTrackPage, error) {
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the ScopeUserFollowModify scope.
 func (c *Client) FollowUser(ids ...ID) error {
-	return c.modifyFollowers("user", true, ids...)
+	return c.FollowUserWithContext(context.Background(), ids...)
+}
+
+// FollowUserWithContext is like FollowUser, with a context.
+func (c *Client) FollowUserWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyFollowers(ctx, "user", true, ids...)
 }
 
 // FollowArtist adds the current user as a follower of one or more
-// spotify artists, identified by their Spotify IDs.
-// This call requires authorization.
+// spotify artists, identified by their Spotify IDs, such as the IDs of
+// the user's TopArtists.  This call requires authorization.  Like the
+// other follow methods, it accepts any number of IDs, making one request
+// for every 50.
 //
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the ScopeUserFollowModify scope.
 func (c *Client) FollowArtist(ids ...ID) error {
-	return c.modifyFollowers("artist", true, ids...)
+	return c.FollowArtistWithContext(context.Background(), ids...)
+}
+
+// FollowArtistWithContext is like FollowArtist, with a context.
+func (c *Client) FollowArtistWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyFollowers(ctx, "artist", true, ids...)
 }
 
 // UnfollowUser removes the current user as a follower of one or more
@@ -175,7 +180,12 @@ func (c *Client) FollowArtist(ids ...ID) error {
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the ScopeUserFollowModify scope.
 func (c *Client) UnfollowUser(ids ...ID) error {
-	return c.modifyFollowers("user", false, ids...)
+	return c.UnfollowUserWithContext(context.Background(), ids...)
+}
+
+// UnfollowUserWithContext is like UnfollowUser, with a context.
+func (c *Client) UnfollowUserWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyFollowers(ctx, "user", false, ids...)
 }
 
 // UnfollowArtist removes the current user as a follower of one or more
@@ -184,7 +194,12 @@ func (c *Client) UnfollowUser(ids ...ID) error {
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the ScopeUserFollowModify scope.
 func (c *Client) UnfollowArtist(ids ...ID) error {
-	return c.modifyFollowers("artist", false, ids...)
+	return c.UnfollowArtistWithContext(context.Background(), ids...)
+}
+
+// UnfollowArtistWithContext is like UnfollowArtist, with a context.
+func (c *Client) UnfollowArtistWithContext(ctx context.Context, ids ...ID) error {
+	return c.modifyFollowers(ctx, "artist", false, ids...)
 }
 
 // CurrentUserFollows checks to see if the current user is following
@@ -196,64 +211,78 @@ func (c *Client) UnfollowArtist(ids ...ID) error {
 // "user" or "artist".
 //
 // The result is returned as a slice of bool values in the same order
-// in which the IDs were specified.
+// in which the IDs were specified.  Any number of IDs may be given; they
+// are checked 50 at a time.
 func (c *Client) CurrentUserFollows(t string, ids ...ID) ([]bool, error) {
-	if l := len(ids); l == 0 || l > 50 {
-		return nil, errors.New("spotify: UserFollows supports 1 to 50 IDs")
+	return c.CurrentUserFollowsWithContext(context.Background(), t, ids...)
+}
+
+// CurrentUserFollowsWithContext is like CurrentUserFollows, with a context.
+func (c *Client) CurrentUserFollowsWithContext(ctx context.Context, t string, ids ...ID) ([]bool, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("spotify: UserFollows requires at least one ID")
 	}
 	if t != "artist" && t != "user" {
 		return nil, errors.New("spotify: t must be 'artist' or 'user'")
 	}
-	spotifyURL := fmt.Sprintf("%sme/following/contains?type=%s&ids=%s",
-		baseAddress, t, strings.Join(toStringSlice(ids), ","))
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+	var result []bool
+	for _, chunk := range ChunkIDs(ids, maxFollowIDs) {
+		follows, err := c.currentUserFollows(ctx, t, chunk)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, follows...)
 	}
+	return result, nil
+}
+
+func (c *Client) currentUserFollows(ctx context.Context, t string, ids []ID) ([]bool, error) {
+	e := c.endpoint("me/following/contains")
+	e.set("type", t)
+	e.setIDs(ids)
 	var result []bool
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c *Client) modifyFollowers(usertype string, follow bool, ids ...ID) error {
-	if l := len(ids); l == 0 || l > 50 {
-		return errors.New("spotify: Follow/Unfollow supports 1 to 50 IDs")
+// modifyFollowers follows or unfollows the artists or users, 50 at a time.
+// If a request fails, the ones before it have still taken effect, but
+// following and unfollowing can safely be repeated.
+func (c *Client) modifyFollowers(ctx context.Context, usertype string, follow bool, ids ...ID) error {
+	if len(ids) == 0 {
+		return errors.New("spotify: Follow/Unfollow requires at least one ID")
+	}
+	if usertype == "artist" {
+		if err := ValidateIDs(ids...); err != nil {
+			return err
+		}
 	}
-	v := url.Values{}
-	v.Add("type", usertype)
-	v.Add("ids", strings.Join(toStringSlice(ids), ","))
-	spotifyURL := baseAddress + "me/following?" + v.Encode()
+	for _, chunk := range ChunkIDs(ids, maxFollowIDs) {
+		if err := c.modifyFollowersChunk(ctx, usertype, follow, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) modifyFollowersChunk(ctx context.Context, usertype string, follow bool, ids []ID) error {
+	e := c.endpoint("me/following")
+	e.set("type", usertype)
+	e.setIDs(ids)
 	method := "PUT"
 	if !follow {
 		method = "DELETE"
 	}
-	req, err := http.NewRequest(method, spotifyURL, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusNoContent {
-		return decodeError(resp.Body)
-	}
-	return nil
+	return c.send(ctx, method, e.String(), nil)
 }
 
 // CurrentUsersFollowedArtists gets the current user's followed artists.
 // This call requires authorization, and that the user has granted the
 // ScopeUserFollowRead scope.
 func (c *Client) CurrentUsersFollowedArtists() (*FullArtistCursorPage, error) {
-	return c.CurrentUsersFollowedArtistsOpt(-1, "")
+	return c.CurrentUsersFollowedArtistsWithContext(context.Background(), -1, "")
 }
 
 // CurrentUsersFollowedArtistsOpt is like CurrentUsersFollowedArtists,
@@ -263,31 +292,35 @@ func (c *Client) CurrentUsersFollowedArtists() (*FullArtistCursorPage, error) {
 // wish to specify either of the parameters, use -1 for limit and the empty
 // string for after.
 func (c *Client) CurrentUsersFollowedArtistsOpt(limit int, after string) (*FullArtistCursorPage, error) {
-	spotifyURL := baseAddress + "me/following"
-	v := url.Values{}
-	v.Set("type", "artist")
+	return c.CurrentUsersFollowedArtistsWithContext(context.Background(), limit, after)
+}
+
+// CurrentUsersFollowedArtistsWithContext is like
+// CurrentUsersFollowedArtistsOpt, with a context.
+func (c *Client) CurrentUsersFollowedArtistsWithContext(ctx context.Context, limit int, after string) (*FullArtistCursorPage, error) {
+	return c.followedArtists(ctx, c.followedArtistsEndpoint(limit, after).String())
+}
+
+// followedArtistsEndpoint returns the endpoint for the user's followed
+// artists, with the parameters of CurrentUsersFollowedArtistsOpt.
+func (c *Client) followedArtistsEndpoint(limit int, after string) *endpoint {
+	e := c.endpoint("me/following")
+	e.set("type", "artist")
 	if limit != -1 {
-		v.Set("limit", strconv.Itoa(limit))
+		e.setInt("limit", limit)
 	}
 	if after != "" {
-		v.Set("after", after)
-	}
-	if params := v.Encode(); params != "" {
-		spotifyURL += "?" + params
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
+		e.set("after", after)
 	}
+	return e
+}
+
+// followedArtists gets the page of followed artists at spotifyURL.
+func (c *Client) followedArtists(ctx context.Context, spotifyURL string) (*FullArtistCursorPage, error) {
 	var result struct {
 		A FullArtistCursorPage `json:"artists"`
 	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, spotifyURL, &result); err != nil {
 		return nil, err
 	}
 	return &result.A, nil
@@ -296,39 +329,25 @@ func (c *Client) CurrentUsersFollowedArtistsOpt(limit int, after string) (*FullA
 // CurrentUsersAlbums gets a list of albums saved in the current
 // Spotify user's "Your Music" library.
 func (c *Client) CurrentUsersAlbums() (*SavedAlbumPage, error) {
-	return c.CurrentUsersAlbumsOpt(nil)
+	return c.CurrentUsersAlbumsWithContext(context.Background(), nil)
 }
 
 // CurrentUsersAlbumsOpt is like CurrentUsersAlbums, but it accepts additional
 // options for sorting and filtering the results.
 func (c *Client) CurrentUsersAlbumsOpt(opt *Options) (*SavedAlbumPage, error) {
-	spotifyURL := baseAddress + "me/albums"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Country != nil {
-			v.Set("market", *opt.Country)
-		}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
+	return c.CurrentUsersAlbumsWithContext(context.Background(), opt)
+}
+
+// CurrentUsersAlbumsWithContext is like CurrentUsersAlbumsOpt, with a
+// context.
+func (c *Client) CurrentUsersAlbumsWithContext(ctx context.Context, opt *Options) (*SavedAlbumPage, error) {
+	e := c.endpoint("me/albums")
+	if err := e.setMarket(opt); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	e.setPaging(opt)
 	var result SavedAlbumPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -341,36 +360,22 @@ func (c *Client) CurrentUsersAlbumsOpt(opt *Options) (*SavedAlbumPage, error) {
 // they are always private.  In order to retrieve collaborative playlists
 // the user must authorize the ScopePlaylistReadCollaborative scope.
 func (c *Client) CurrentUsersPlaylists() (*SimplePlaylistPage, error) {
-	return c.CurrentUsersPlaylistsOpt(nil)
+	return c.CurrentUsersPlaylistsWithContext(context.Background(), nil)
 }
 
 // CurrentUsersPlaylistsOpt is like CurrentUsersPlaylists, but it accepts
 // additional options for sorting and filtering the results.
 func (c *Client) CurrentUsersPlaylistsOpt(opt *Options) (*SimplePlaylistPage, error) {
-	spotifyURL := baseAddress + "me/playlists"
-	if opt != nil {
-		v := url.Values{}
-		if opt.Limit != nil {
-			v.Set("limit", strconv.Itoa(*opt.Limit))
-		}
-		if opt.Offset != nil {
-			v.Set("offset", strconv.Itoa(*opt.Offset))
-		}
-		if params := v.Encode(); params != "" {
-			spotifyURL += "?" + params
-		}
-	}
-	resp, err := c.http.Get(spotifyURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, decodeError(resp.Body)
-	}
+	return c.CurrentUsersPlaylistsWithContext(context.Background(), opt)
+}
+
+// CurrentUsersPlaylistsWithContext is like CurrentUsersPlaylistsOpt, with a
+// context.
+func (c *Client) CurrentUsersPlaylistsWithContext(ctx context.Context, opt *Options) (*SimplePlaylistPage, error) {
+	e := c.endpoint("me/playlists")
+	e.setPaging(opt)
 	var result SimplePlaylistPage
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	if err := c.get(ctx, e.String(), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -2,7 +2,9 @@ package spotify
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -90,7 +92,7 @@ func TestFollowUsersMissingScope(t *testing.T) {
 	addDummyAuth(client)
 
 	err := client.FollowUser(ID("exampleuser01"))
-	if serr, ok := err.(Error); !ok {
+	if serr, ok := err.(*Error); !ok {
 		t.Error("Expected insufficient client scope error")
 	} else {
 		if serr.Status != http.StatusForbidden {
@@ -128,7 +130,7 @@ func TestFollowUsersInvalidToken(t *testing.T) {
 	addDummyAuth(client)
 
 	err := client.FollowUser(ID("dummyID"))
-	if serr, ok := err.(Error); !ok {
+	if serr, ok := err.(*Error); !ok {
 		t.Error("Expected invalid token error")
 	} else {
 		if serr.Status != http.StatusUnauthorized {
@@ -154,6 +156,56 @@ func TestUserFollows(t *testing.T) {
 	}
 }
 
+func TestFollowManyUsers(t *testing.T) {
+	var sizes []int
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		sizes = append(sizes, len(ids))
+		status, body := http.StatusNoContent, ""
+		if req.Method == "GET" {
+			status, body = http.StatusOK, "["+strings.TrimSuffix(strings.Repeat("true,", len(ids)), ",")+"]"
+		}
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+	ids := make([]ID, 75)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("user%d", i))
+	}
+	if err := c.FollowUser(ids...); err != nil {
+		t.Fatal(err)
+	}
+	follows, err := c.CurrentUserFollows("user", ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(follows) != 75 {
+		t.Errorf("Got %d results for 75 users\n", len(follows))
+	}
+	if fmt.Sprint(sizes) != "[50 25 50 25]" {
+		t.Errorf("Got requests of %v IDs\n", sizes)
+	}
+}
+
+func TestFollowedArtistsIter(t *testing.T) {
+	pages := map[string]string{
+		"": `{"artists": {"items": [{"name": "Band"}], "total": 2, "cursors": {"after": "a1"},
+			"next": "https://api.spotify.com/v1/me/following?type=artist&after=a1&limit=1"}}`,
+		"a1": `{"artists": {"items": [{"name": "Singer"}], "total": 2, "cursors": {"after": null}, "next": null}}`,
+	}
+	c := NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := pages[req.URL.Query().Get("after")]
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})})
+	it := c.FollowedArtistsIter(1)
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+	if it.Err() != nil || strings.Join(names, ",") != "Band,Singer" || it.Total() != 2 {
+		t.Errorf("Got %v of %d artists (%v)\n", names, it.Total(), it.Err())
+	}
+}
+
 func TestCurrentUsersTracks(t *testing.T) {
 	client := testClientFile(http.StatusOK, "test_data/current_users_tracks.txt")
 	addDummyAuth(client)
@@ -212,8 +264,8 @@ func TestCurrentUsersAlbums(t *testing.T) {
 	}
 
 	upc := "886444160742"
-	u, ok := albums.Albums[0].ExternalIDs["upc"]
-	if !ok {
+	u := albums.Albums[0].ExternalIDs.UPC
+	if u == "" {
 		t.Error("External IDs missing UPC")
 	}
 	if u != upc {
@@ -0,0 +1,155 @@
+package spotify
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// AppTokenKey is the key under which Warmup saves the application's own
+// (client credentials) token in a TokenStore.
+const AppTokenKey = "_app"
+
+// ClientCredentialsToken obtains a token for the application itself, using
+// the client credentials flow.  It can be used for requests that don't access
+// user data.  The context's oauth2.HTTPClient value, if any, is used to make
+// the request.
+func (a Authenticator) ClientCredentialsToken(ctx context.Context) (*oauth2.Token, error) {
+	cfg := clientcredentials.Config{
+		ClientID:     a.config.ClientID,
+		ClientSecret: a.config.ClientSecret,
+		TokenURL:     a.config.Endpoint.TokenURL,
+	}
+	return cfg.Token(ctx)
+}
+
+// Warmup refreshes tokens ahead of time, so that the first requests served
+// by a new App Engine instance don't have to wait for a token refresh.
+// Register it for App Engine's warmup requests:
+//
+//     http.Handle("/_ah/warmup", &spotify.Warmup{Auth: auth, Store: store, HotUsers: recentUsers})
+//
+// (Warmup requests must be enabled with "inbound_services: - warmup" in
+// app.yaml.)  Failures are logged but don't fail the warmup request, since
+// tokens are refreshed on demand anyway.
+type Warmup struct {
+	Auth  Authenticator
+	Store TokenStore
+	// HotUsers returns the users whose tokens should be refreshed, for
+	// example the most recently active ones.  If nil, only the application
+	// token is refreshed.
+	HotUsers func(ctx context.Context) ([]string, error)
+	// Within is how close to expiry a token must be to get refreshed.  It
+	// defaults to ten minutes.
+	Within time.Duration
+	// Clock is used to check token expiry.  If nil, SystemClock is used.
+	Clock Clock
+}
+
+func (w *Warmup) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	ctx := appengine.NewContext(r)
+	for _, err := range w.Run(ctx, urlfetch.Client(ctx)) {
+		log.Warningf(ctx, "spotify: warmup: %v", err)
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+// Run refreshes the application token and the tokens of the hot users,
+// using hc to contact the Spotify Accounts service, and saves them to the
+// token store.  It returns any errors encountered.
+func (w *Warmup) Run(ctx context.Context, hc *http.Client) []error {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, hc)
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	if w.expiring(ctx, AppTokenKey) {
+		tok, err := w.Auth.ClientCredentialsToken(ctx)
+		if err == nil {
+			err = w.Store.Save(ctx, AppTokenKey, tok)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	if w.HotUsers == nil {
+		return errs
+	}
+	users, err := w.HotUsers(ctx)
+	if err != nil {
+		return append(errs, err)
+	}
+	// refresh a few tokens at a time, to finish quickly without
+	// hammering the accounts service
+	sem := make(chan struct{}, 8)
+	var wg sync.WaitGroup
+	for _, u := range users {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(userID string) {
+			defer func() { <-sem; wg.Done() }()
+			if err := w.refresh(ctx, userID); err != nil {
+				fail(err)
+			}
+		}(u)
+	}
+	wg.Wait()
+	return errs
+}
+
+// expiring reports whether the stored token for key is missing or close
+// to expiry.
+func (w *Warmup) expiring(ctx context.Context, key string) bool {
+	tok, err := w.Store.Load(ctx, key)
+	if err != nil {
+		return true
+	}
+	return w.needsRefresh(tok)
+}
+
+func (w *Warmup) needsRefresh(tok *oauth2.Token) bool {
+	if tok.Expiry.IsZero() {
+		return false
+	}
+	within := w.Within
+	if within <= 0 {
+		within = 10 * time.Minute
+	}
+	clock := w.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return tok.Expiry.Before(clock.Now().Add(within))
+}
+
+func (w *Warmup) refresh(ctx context.Context, userID string) error {
+	tok, err := w.Store.Load(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !w.needsRefresh(tok) || tok.RefreshToken == "" {
+		return nil
+	}
+	// force a refresh by presenting the token as already expired
+	expired := *tok
+	expired.Expiry = time.Unix(1, 0)
+	fresh, err := w.Auth.config.TokenSource(ctx, &expired).Token()
+	if err != nil {
+		return err
+	}
+	return w.Store.Save(ctx, userID, fresh)
+}
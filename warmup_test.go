@@ -0,0 +1,55 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+func TestWarmup(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "%s-token", "token_type": "Bearer", "expires_in": 3600}`, r.Form.Get("grant_type"))
+	}))
+	defer server.Close()
+
+	a := NewAuthenticator("http://localhost/callback")
+	a.SetAuthInfo("id", "secret")
+	a.config.Endpoint.TokenURL = server.URL
+	now := time.Now()
+	store := newMemTokenStore(map[string]*oauth2.Token{
+		"stale": {AccessToken: "old", RefreshToken: "r1", Expiry: now.Add(time.Minute)},
+		"fresh": {AccessToken: "current", RefreshToken: "r2", Expiry: now.Add(time.Hour)},
+	})
+	w := &Warmup{
+		Auth:  a,
+		Store: store,
+		HotUsers: func(ctx context.Context) ([]string, error) {
+			return []string{"stale", "fresh", "missing"}, nil
+		},
+	}
+	errs := w.Run(context.Background(), http.DefaultClient)
+	if len(errs) != 1 || errs[0] != ErrNoToken {
+		t.Errorf("Expected a single ErrNoToken, got %v\n", errs)
+	}
+	if refreshes != 2 {
+		t.Errorf("Expected 2 token requests, got %d\n", refreshes)
+	}
+	if tok := store.tokens[AppTokenKey]; tok == nil || tok.AccessToken != "client_credentials-token" {
+		t.Errorf("App token wasn't saved: %v\n", tok)
+	}
+	if store.tokens["stale"].AccessToken != "refresh_token-token" {
+		t.Error("Stale token wasn't refreshed")
+	}
+	if store.tokens["fresh"].AccessToken != "current" {
+		t.Error("Fresh token shouldn't have been refreshed")
+	}
+}
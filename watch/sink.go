@@ -0,0 +1,136 @@
+package watch
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Headers of the requests sent by HTTPSink.
+const (
+	// TimestampHeader holds the time the request was signed, in seconds
+	// since the Unix epoch.
+	TimestampHeader = "X-Watch-Timestamp"
+	// SignatureHeader holds "sha256=" followed by the hex encoded
+	// HMAC-SHA256, keyed with the secret, of the timestamp, a period and
+	// the body.
+	SignatureHeader = "X-Watch-Signature"
+)
+
+// DefaultRetries is how many times HTTPSink retries a failed request, if
+// its Retries is zero.
+const DefaultRetries = 3
+
+// HTTPSink is a Sink that POSTs each event as JSON to a URL.  Requests that
+// fail with a network error, 429 Too Many Requests or a 5xx status are
+// retried, with exponential backoff.
+type HTTPSink struct {
+	URL string
+	// Secret, if not empty, is used to sign the requests (see
+	// SignatureHeader), so that the receiver can check that they came from
+	// the application.  Use Verify to check a signature.
+	Secret []byte
+	// HTTPClient is used to make requests.  If nil, http.DefaultClient is
+	// used.  On App Engine, use a urlfetch client.
+	HTTPClient *http.Client
+	// Retries is how many times a failed request is retried.  If zero,
+	// DefaultRetries is used.  If negative, requests aren't retried.
+	Retries int
+	// Backoff is how long to wait before the first retry, doubling for each
+	// retry after it.  If zero, one second is used.
+	Backoff time.Duration
+	// Clock is used to sign requests and to wait between retries.  If nil,
+	// spotify.SystemClock is used.
+	Clock spotify.Clock
+}
+
+// Send posts e to the sink's URL.
+func (s *HTTPSink) Send(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	clock := s.Clock
+	if clock == nil {
+		clock = spotify.SystemClock
+	}
+	retries := s.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	}
+	backoff := s.Backoff
+	if backoff == 0 {
+		backoff = time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body, clock.Now())
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+		clock.Sleep(backoff << uint(attempt))
+	}
+}
+
+// post makes one request, and reports whether it's worth retrying if it
+// failed.
+func (s *HTTPSink) post(body []byte, now time.Time) (retry bool, err error) {
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.Secret) > 0 {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+sign(s.Secret, ts, body))
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("watch: %s responded with status %d", s.URL, resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// sign returns the hex encoded signature of the timestamp and body.
+func sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a request from an HTTPSink, given its body,
+// and that it was signed within maxAge of now, to make replaying it harder.
+func Verify(secret []byte, header http.Header, body []byte, now time.Time, maxAge time.Duration) bool {
+	ts := header.Get(TimestampHeader)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+		return false
+	}
+	want := "sha256=" + sign(secret, ts, body)
+	return hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(want))
+}
@@ -0,0 +1,147 @@
+// Package watch polls what a user is listening to and turns the changes into
+// events, such as a new track starting or playback being paused, which are
+// delivered to a Sink.  With HTTPSink, this gives webhook-style callbacks on
+// top of the Web API, which only supports polling.
+//
+// On App Engine, where instances don't run indefinitely, call Poll from a
+// cron or task queue handler instead of using Run, and keep the Watcher's
+// State between calls (for example in Datastore).
+package watch
+
+import (
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// DefaultInterval is how often Run polls, if the Watcher's Interval is
+// zero.
+const DefaultInterval = 10 * time.Second
+
+// Types of Event.
+const (
+	// TrackChanged means that a different track started playing, or that
+	// a track started playing when nothing was.
+	TrackChanged = "track_changed"
+	// Paused means that playback of the track was paused.
+	Paused = "paused"
+	// Resumed means that playback of the track was resumed.
+	Resumed = "resumed"
+	// Stopped means that nothing is playing any more.
+	Stopped = "stopped"
+)
+
+// Event is a change in what a user is listening to.
+type Event struct {
+	// Type is one of TrackChanged, Paused, Resumed and Stopped.
+	Type string `json:"type"`
+	// User is the Watcher's User.
+	User string `json:"user,omitempty"`
+	// Time is when the change was noticed.
+	Time time.Time `json:"time"`
+	// Track is the track that is playing, or nil for Stopped events.
+	Track *spotify.FullTrack `json:"track,omitempty"`
+	// Playing is true if the track is playing, and false if it's paused.
+	Playing bool `json:"is_playing"`
+	// Progress is the position of playback within the track, in
+	// milliseconds.
+	Progress int `json:"progress_ms"`
+}
+
+// Sink receives the events of a Watcher.
+type Sink interface {
+	Send(e Event) error
+}
+
+// SinkFunc is a function that is a Sink.
+type SinkFunc func(e Event) error
+
+// Send calls f(e).
+func (f SinkFunc) Send(e Event) error { return f(e) }
+
+// State is what a Watcher last saw playing.
+type State struct {
+	// TrackURI is the URI of the track that was playing, or the empty
+	// string if nothing was.  (Local files have no ID, but do have a URI.)
+	TrackURI spotify.URI
+	// Playing is true if the track was playing, and false if it was paused.
+	Playing bool
+}
+
+// Watcher polls what a user is listening to, and sends an event to its
+// Sink whenever that changes.
+type Watcher struct {
+	// Client is authorized for the user, with the
+	// ScopeUserReadCurrentlyPlaying scope.
+	Client spotify.SpotifyClient
+	Sink   Sink
+	// User identifies the user in the events, for sinks that receive the
+	// events of several users.
+	User string
+	// Interval is how often Run polls.  If zero, DefaultInterval is used.
+	Interval time.Duration
+	// Clock is used to timestamp events and to wait between polls.  If
+	// nil, spotify.SystemClock is used.
+	Clock spotify.Clock
+	// State is what the last poll saw.  It starts as nothing playing, so
+	// the first poll reports a track that is already playing.
+	State State
+}
+
+func (w *Watcher) clock() spotify.Clock {
+	if w.Clock == nil {
+		return spotify.SystemClock
+	}
+	return w.Clock
+}
+
+// Poll checks what the user is listening to once, and sends the event for
+// the change since the last poll, if there is one.  If the sink fails, the
+// state isn't updated, so the event is sent again by the next poll.
+func (w *Watcher) Poll() error {
+	cp, err := w.Client.PlayerCurrentlyPlaying()
+	if err != nil {
+		return err
+	}
+	var next State
+	if cp.Item != nil {
+		next = State{TrackURI: cp.Item.URI, Playing: cp.Playing}
+	}
+	e := Event{User: w.User, Time: w.clock().Now(), Track: cp.Item, Playing: next.Playing, Progress: cp.Progress}
+	switch {
+	case next == w.State:
+		return nil
+	case next.TrackURI == "":
+		e.Type, e.Track, e.Progress = Stopped, nil, 0
+	case next.TrackURI != w.State.TrackURI:
+		e.Type = TrackChanged
+	case next.Playing:
+		e.Type = Resumed
+	default:
+		e.Type = Paused
+	}
+	if err := w.Sink.Send(e); err != nil {
+		return err
+	}
+	w.State = next
+	return nil
+}
+
+// Run polls until stop is closed.  Errors are passed to onError, if it isn't
+// nil, and don't stop the watcher.
+func (w *Watcher) Run(stop <-chan struct{}, onError func(error)) {
+	interval := w.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	for {
+		if err := w.Poll(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-w.clock().After(interval):
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+var epoch = time.Date(2017, 5, 19, 9, 0, 0, 0, time.UTC)
+
+// player serves the currently playing track, which the test changes.
+type player struct {
+	mu sync.Mutex
+	cp *spotify.CurrentlyPlaying
+}
+
+func (p *player) set(id spotify.ID, playing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id == "" {
+		p.cp = nil
+		return
+	}
+	p.cp = &spotify.CurrentlyPlaying{Playing: playing, Progress: 1000, Item: &spotify.FullTrack{}}
+	p.cp.Item.ID = id
+	p.cp.Item.URI = spotify.URI("spotify:track:" + id)
+}
+
+func (p *player) serve(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	json.NewEncoder(w).Encode(p.cp)
+}
+
+func TestPoll(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	p := new(player)
+	s.HandleFunc("GET", "me/player/currently-playing", p.serve)
+	c := s.NewClient()
+	var events []string
+	w := &Watcher{
+		Client: &c,
+		User:   "jo",
+		Clock:  spotifytest.NewFakeClock(epoch),
+		Sink: SinkFunc(func(e Event) error {
+			if e.User != "jo" || !e.Time.Equal(epoch) {
+				t.Errorf("got event %+v", e)
+			}
+			events = append(events, e.Type)
+			return nil
+		}),
+	}
+
+	steps := []struct {
+		id      spotify.ID
+		playing bool
+	}{
+		{"", false},
+		{"a", true},
+		{"a", true},
+		{"a", false},
+		{"a", true},
+		{"b", true},
+		{"", false},
+	}
+	for _, step := range steps {
+		p.set(step.id, step.playing)
+		if err := w.Poll(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{TrackChanged, Paused, Resumed, TrackChanged, Stopped}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %v, want %v", events, want)
+	}
+}
+
+func TestPollSinkFails(t *testing.T) {
+	s := spotifytest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+	fail := true
+	var events int
+	w := &Watcher{Client: &c, Sink: SinkFunc(func(e Event) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		events++
+		return nil
+	})}
+	if err := w.Poll(); err == nil {
+		t.Fatal("got no error from a failing sink")
+	}
+	fail = false
+	if err := w.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if events != 1 || w.State.TrackURI != "spotify:track:"+spotifytest.TrackID {
+		t.Errorf("sent %d events, state %+v", events, w.State)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	secret := []byte("s3cret")
+	clock := spotifytest.NewFakeClock(epoch)
+	var mu sync.Mutex
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if !Verify(secret, r.Header, body, epoch, time.Minute) {
+			t.Error("the signature doesn't verify")
+		}
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil || e.Type != Paused {
+			t.Errorf("got %s (%v)", body, err)
+		}
+	}))
+	defer srv.Close()
+
+	sink := &HTTPSink{URL: srv.URL, Secret: secret, Clock: clock}
+	if err := sink.Send(Event{Type: Paused, Time: epoch}); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Errorf("made %d attempts, want 3", attempts)
+	}
+	if got, want := clock.Slept(), []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("backed off %v, want %v", got, want)
+	}
+}
+
+func TestHTTPSinkPermanentError(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+	sink := &HTTPSink{URL: srv.URL, Clock: spotifytest.NewFakeClock(epoch)}
+	if err := sink.Send(Event{Type: Stopped}); err == nil {
+		t.Error("got no error for 410 Gone")
+	}
+	if attempts != 1 {
+		t.Errorf("made %d attempts, want 1", attempts)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	body := []byte(`{"type":"stopped"}`)
+	h := http.Header{}
+	h.Set(TimestampHeader, "1495184400")
+	h.Set(SignatureHeader, "sha256="+sign(secret, "1495184400", body))
+	if !Verify(secret, h, body, epoch, time.Minute) {
+		t.Error("a valid signature doesn't verify")
+	}
+	if Verify(secret, h, []byte(`{"type":"paused"}`), epoch, time.Minute) {
+		t.Error("a signature verifies for another body")
+	}
+	if Verify(secret, h, body, epoch.Add(time.Hour), time.Minute) {
+		t.Error("an old signature verifies")
+	}
+}
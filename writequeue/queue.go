@@ -0,0 +1,322 @@
+// Package writequeue queues changes to a user's library, playlists and
+// follows, and applies them to the Web API later, retrying those that fail
+// with backoff.  On App Engine, a request that saves a track can enqueue the
+// write and return at once; a cron or task queue handler then calls Flush,
+// so a flaky network or a Spotify outage delays the write instead of
+// losing it.
+//
+// Every write has an idempotency key.  Enqueueing a write whose key is
+// already queued (or was applied recently) does nothing, so a client that
+// retries its own request, or a user who presses "save" twice, doesn't
+// cause the change to be made twice.
+package writequeue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Kinds of Write.
+const (
+	SaveTracks        = "save_tracks"
+	RemoveSavedTracks = "remove_saved_tracks"
+	SaveAlbums        = "save_albums"
+	RemoveSavedAlbums = "remove_saved_albums"
+	AddToPlaylist     = "add_to_playlist"
+	FollowArtists     = "follow_artists"
+	UnfollowArtists   = "unfollow_artists"
+	FollowUsers       = "follow_users"
+	UnfollowUsers     = "unfollow_users"
+	FollowPlaylist    = "follow_playlist"
+	UnfollowPlaylist  = "unfollow_playlist"
+)
+
+// States of a Write.
+const (
+	// Pending writes are waiting to be applied, or retried.
+	Pending = "pending"
+	// Done writes have been applied.
+	Done = "done"
+	// Failed writes were given up on, because the Web API rejected them or
+	// they failed too many times.
+	Failed = "failed"
+)
+
+// Defaults for the Queue's fields.
+const (
+	DefaultMaxAttempts = 10
+	DefaultRetention   = 24 * time.Hour
+)
+
+// Write is a change to be made for a user.
+type Write struct {
+	// Key is the idempotency key.  If it's empty, Enqueue sets it to a
+	// random key.
+	Key string `json:"key"`
+	// UserID is the user whose client applies the write.
+	UserID string `json:"user_id"`
+	// Kind is one of SaveTracks, AddToPlaylist and the other kinds.
+	Kind string `json:"kind"`
+	// IDs are the tracks, albums, artists or users the write is about.
+	IDs []spotify.ID `json:"ids,omitempty"`
+	// PlaylistOwner and PlaylistID identify the playlist, for the
+	// playlist kinds.
+	PlaylistOwner string     `json:"playlist_owner,omitempty"`
+	PlaylistID    spotify.ID `json:"playlist_id,omitempty"`
+	// Public is whether a followed playlist is shown on the user's
+	// profile, for FollowPlaylist.
+	Public bool `json:"public,omitempty"`
+
+	// State is Pending, Done or Failed.
+	State string `json:"state"`
+	// Attempts is the number of times applying the write has failed.
+	Attempts int `json:"attempts"`
+	// LastError is the error of the last failed attempt, and LastStatus
+	// its HTTP status, or 0 if there was no response.
+	LastError  string `json:"last_error,omitempty"`
+	LastStatus int    `json:"last_status,omitempty"`
+	// Created is when the write was enqueued, and Updated when its state
+	// last changed.
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// NextAttempt is the earliest time the write is tried again.
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// Result is what a call to Flush did.
+type Result struct {
+	// Applied, Retrying and Failed are the writes that were applied, that
+	// failed and will be retried, and that were given up on.
+	Applied, Retrying, Failed []*Write
+}
+
+// Queue queues writes in a Store and applies them.
+type Queue struct {
+	Store Store
+	// MaxAttempts is the number of failed attempts after which a write is
+	// given up on.  If zero, DefaultMaxAttempts is used.
+	MaxAttempts int
+	// Backoff returns how long to wait before trying a write again after
+	// its nth failed attempt.  If nil, the wait starts at a minute and
+	// doubles with each attempt, up to an hour.
+	Backoff func(n int) time.Duration
+	// Retention is how long applied and failed writes are kept, to ignore
+	// writes enqueued again with their keys.  If zero, DefaultRetention is
+	// used.
+	Retention time.Duration
+	// Clock is used to schedule retries.  If nil, spotify.SystemClock is
+	// used.
+	Clock spotify.Clock
+}
+
+func (q *Queue) clock() spotify.Clock {
+	if q.Clock == nil {
+		return spotify.SystemClock
+	}
+	return q.Clock
+}
+
+func (q *Queue) maxAttempts() int {
+	if q.MaxAttempts == 0 {
+		return DefaultMaxAttempts
+	}
+	return q.MaxAttempts
+}
+
+func (q *Queue) backoff(n int) time.Duration {
+	if q.Backoff != nil {
+		return q.Backoff(n)
+	}
+	d := time.Minute
+	for i := 1; i < n && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+func (q *Queue) retention() time.Duration {
+	if q.Retention == 0 {
+		return DefaultRetention
+	}
+	return q.Retention
+}
+
+// Enqueue adds w to the queue, to be applied by the next Flush for its
+// user, and returns its key.  If a write with the same key is already in
+// the queue, the queue is left as it is.
+func (q *Queue) Enqueue(ctx context.Context, w Write) (string, error) {
+	if w.UserID == "" {
+		return "", errors.New("writequeue: the write has no user")
+	}
+	if w.Key == "" {
+		key, err := newKey()
+		if err != nil {
+			return "", err
+		}
+		w.Key = key
+	} else {
+		old, err := q.Store.Get(ctx, w.UserID, w.Key)
+		if err != nil {
+			return "", err
+		}
+		if old != nil {
+			return w.Key, nil
+		}
+	}
+	now := q.clock().Now()
+	w.State, w.Attempts, w.LastError, w.LastStatus = Pending, 0, "", 0
+	w.Created, w.Updated, w.NextAttempt = now, now, now
+	return w.Key, q.Store.Put(ctx, &w)
+}
+
+// newKey returns a random idempotency key.
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Flush applies the user's pending writes that are due, oldest first,
+// using c, which must be authorized for the user with the scopes the
+// writes need.  Writes that fail are retried by later calls, after a
+// backoff; writes the Web API rejects (with a 4xx status other than 429
+// Too Many Requests) are given up on at once.  If the Web API limits the
+// rate of requests, the rest of the writes are left for the next call.
+// Applied and failed writes older than the queue's Retention are deleted.
+//
+// An error is returned only if the store fails; the outcome of each write
+// is in the Result.
+func (q *Queue) Flush(ctx context.Context, c spotify.SpotifyClient, userID string) (*Result, error) {
+	writes, err := q.Store.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(byCreated(writes))
+	now := q.clock().Now()
+	result := new(Result)
+	limited := false
+	for _, w := range writes {
+		if w.State != Pending {
+			if now.Sub(w.Updated) >= q.retention() {
+				if err := q.Store.Delete(ctx, userID, w.Key); err != nil {
+					return result, err
+				}
+			}
+			continue
+		}
+		if limited || w.NextAttempt.After(now) {
+			continue
+		}
+		err := apply(ctx, c, w)
+		w.Updated = now
+		switch {
+		case err == nil:
+			w.State, w.LastError, w.LastStatus = Done, "", 0
+			result.Applied = append(result.Applied, w)
+		default:
+			w.Attempts++
+			w.LastError, w.LastStatus = err.Error(), 0
+			permanent := false
+			if e, ok := err.(*spotify.Error); ok {
+				w.LastStatus = e.Status
+				permanent = !spotify.IsRetryable(err)
+			}
+			if permanent || w.Attempts >= q.maxAttempts() {
+				w.State = Failed
+				result.Failed = append(result.Failed, w)
+				break
+			}
+			w.NextAttempt = now.Add(q.backoff(w.Attempts))
+			result.Retrying = append(result.Retrying, w)
+			limited = w.LastStatus == http.StatusTooManyRequests
+		}
+		if err := q.Store.Put(ctx, w); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// apply makes the change of w.
+func apply(ctx context.Context, c spotify.SpotifyClient, w *Write) error {
+	switch w.Kind {
+	case SaveTracks:
+		return c.AddTracksToLibraryWithContext(ctx, w.IDs...)
+	case RemoveSavedTracks:
+		return c.RemoveTracksFromLibraryWithContext(ctx, w.IDs...)
+	case SaveAlbums:
+		return c.AddAlbumsToLibraryWithContext(ctx, w.IDs...)
+	case RemoveSavedAlbums:
+		return c.RemoveAlbumsFromLibraryWithContext(ctx, w.IDs...)
+	case AddToPlaylist:
+		return addToPlaylist(ctx, c, w)
+	case FollowArtists:
+		return c.FollowArtistWithContext(ctx, w.IDs...)
+	case UnfollowArtists:
+		return c.UnfollowArtistWithContext(ctx, w.IDs...)
+	case FollowUsers:
+		return c.FollowUserWithContext(ctx, w.IDs...)
+	case UnfollowUsers:
+		return c.UnfollowUserWithContext(ctx, w.IDs...)
+	case FollowPlaylist:
+		return c.FollowPlaylistWithContext(ctx, spotify.ID(w.PlaylistOwner), w.PlaylistID, w.Public)
+	case UnfollowPlaylist:
+		return c.UnfollowPlaylistWithContext(ctx, spotify.ID(w.PlaylistOwner), w.PlaylistID)
+	}
+	return errUnknownKind
+}
+
+// errUnknownKind is the error for a write of an unknown kind.  It has a 400
+// status so that the write is given up on at once.
+var errUnknownKind = &spotify.Error{Message: "writequeue: unknown kind of write", Status: http.StatusBadRequest}
+
+// addToPlaylist adds the tracks of w to its playlist.  Every other kind of
+// write can be made more than once without changing the result, but adding
+// tracks twice duplicates them.  A request that failed with a network error
+// or a server error may still have been carried out, so when retrying
+// those (but not requests turned down by rate limiting), tracks that are
+// already in the playlist are left out.
+func addToPlaylist(ctx context.Context, c spotify.SpotifyClient, w *Write) error {
+	ids := w.IDs
+	if w.Attempts > 0 && w.LastStatus != http.StatusTooManyRequests {
+		present := make(map[spotify.ID]bool)
+		err := c.EachPlaylistTrackWithContext(ctx, w.PlaylistOwner, w.PlaylistID, nil, "items(track(id)),next",
+			func(i int, t *spotify.PlaylistTrack) error {
+				present[t.Track.ID] = true
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+		ids = nil
+		for _, id := range w.IDs {
+			if !present[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+	_, err := c.AddTracksToPlaylistWithContext(ctx, w.PlaylistOwner, w.PlaylistID, ids...)
+	return err
+}
+
+type byCreated []*Write
+
+func (b byCreated) Len() int           { return len(b) }
+func (b byCreated) Less(i, j int) bool { return b[i].Created.Before(b[j].Created) }
+func (b byCreated) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
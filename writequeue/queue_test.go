@@ -0,0 +1,167 @@
+package writequeue
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+	"github.com/ljmeyers80529/spot-go-gae/spotifytest"
+)
+
+var epoch = time.Date(2017, 5, 19, 9, 0, 0, 0, time.UTC)
+
+const other spotify.ID = "1zHlj4dQ8ZAtrayhuDDmkY"
+
+// flakyClient fails some of the calls to its SpotifyClient.
+type flakyClient struct {
+	spotify.SpotifyClient
+	// errs are the errors of the next calls; nil means the call succeeds.
+	errs []error
+}
+
+func (c *flakyClient) next() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+// AddTracksToPlaylistWithContext adds the tracks even if the call fails, as
+// if the response was lost.
+func (c *flakyClient) AddTracksToPlaylistWithContext(ctx context.Context, userID string, playlistID spotify.ID, ids ...spotify.ID) (string, error) {
+	snapshot, err := c.SpotifyClient.AddTracksToPlaylistWithContext(ctx, userID, playlistID, ids...)
+	if err == nil {
+		err = c.next()
+	}
+	return snapshot, err
+}
+
+func (c *flakyClient) FollowArtistWithContext(ctx context.Context, ids ...spotify.ID) error {
+	if err := c.next(); err != nil {
+		return err
+	}
+	return c.SpotifyClient.FollowArtistWithContext(ctx, ids...)
+}
+
+func TestEnqueueAndFlush(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	ctx := context.Background()
+	clock := spotifytest.NewFakeClock(epoch)
+	q := &Queue{Store: NewMemoryStore(), Clock: clock}
+
+	for i := 0; i < 2; i++ {
+		key, err := q.Enqueue(ctx, Write{Key: "save-1", UserID: "jo", Kind: SaveTracks, IDs: []spotify.ID{spotifytest.TrackID}})
+		if err != nil || key != "save-1" {
+			t.Fatalf("got key %q (%v)", key, err)
+		}
+	}
+	clock.Advance(time.Second)
+	key, err := q.Enqueue(ctx, Write{UserID: "jo", Kind: SaveTracks, IDs: []spotify.ID{other}})
+	if err != nil || key == "" {
+		t.Fatalf("got key %q (%v)", key, err)
+	}
+	result, err := q.Flush(ctx, &c, "jo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 2 || result.Applied[0].Key != "save-1" {
+		t.Errorf("got result %+v", result)
+	}
+	if saved := f.SavedTracks(); len(saved) != 2 {
+		t.Errorf("saved %v", saved)
+	}
+
+	// Enqueueing an applied write again does nothing, until it has been
+	// deleted.
+	q.Enqueue(ctx, Write{Key: "save-1", UserID: "jo", Kind: RemoveSavedTracks, IDs: []spotify.ID{spotifytest.TrackID}})
+	if result, _ := q.Flush(ctx, &c, "jo"); len(result.Applied) != 0 {
+		t.Errorf("applied %+v again", result.Applied[0])
+	}
+	clock.Advance(DefaultRetention)
+	q.Flush(ctx, &c, "jo")
+	if writes, _ := q.Store.List(ctx, "jo"); len(writes) != 0 {
+		t.Errorf("kept %d writes after the retention period", len(writes))
+	}
+}
+
+func TestFlushRetriesAddToPlaylist(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	pl, err := c.CreatePlaylistForUser(spotifytest.UserID, "Queue", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	clock := spotifytest.NewFakeClock(epoch)
+	q := &Queue{Store: NewMemoryStore(), Clock: clock}
+	q.Enqueue(ctx, Write{
+		UserID:        spotifytest.UserID,
+		Kind:          AddToPlaylist,
+		PlaylistOwner: spotifytest.UserID,
+		PlaylistID:    pl.ID,
+		IDs:           []spotify.ID{spotifytest.TrackID, other},
+	})
+
+	// The tracks are added, but the response is lost.
+	flaky := &flakyClient{SpotifyClient: &c, errs: []error{errors.New("connection reset")}}
+	result, err := q.Flush(ctx, flaky, spotifytest.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Retrying) != 1 || result.Retrying[0].LastError != "connection reset" {
+		t.Fatalf("got result %+v", result)
+	}
+	if w := result.Retrying[0]; !w.NextAttempt.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("retrying at %v", w.NextAttempt)
+	}
+	if result, _ := q.Flush(ctx, flaky, spotifytest.UserID); len(result.Applied)+len(result.Retrying) != 0 {
+		t.Errorf("retried before the backoff: %+v", result)
+	}
+
+	clock.Advance(time.Minute)
+	result, err = q.Flush(ctx, flaky, spotifytest.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 1 {
+		t.Fatalf("got result %+v", result)
+	}
+	if ids := f.PlaylistTracks(pl.ID); len(ids) != 2 {
+		t.Errorf("got playlist %v, want the tracks added once", ids)
+	}
+}
+
+func TestFlushGivesUp(t *testing.T) {
+	f := spotifytest.NewFake()
+	defer f.Close()
+	c := f.NewClient()
+	ctx := context.Background()
+	q := &Queue{Store: NewMemoryStore(), Clock: spotifytest.NewFakeClock(epoch), MaxAttempts: 2}
+
+	q.Enqueue(ctx, Write{Key: "bad", UserID: "jo", Kind: FollowArtists, IDs: []spotify.ID{other}})
+	flaky := &flakyClient{SpotifyClient: &c, errs: []error{&spotify.Error{Message: "invalid id", Status: http.StatusBadRequest}}}
+	result, _ := q.Flush(ctx, flaky, "jo")
+	if len(result.Failed) != 1 || result.Failed[0].LastStatus != http.StatusBadRequest {
+		t.Errorf("got result %+v for a rejected write", result)
+	}
+
+	q.Enqueue(ctx, Write{Key: "flaky", UserID: "jo", Kind: FollowArtists, IDs: []spotify.ID{other}})
+	q.Backoff = func(n int) time.Duration { return 0 }
+	unavailable := &spotify.Error{Message: "unavailable", Status: http.StatusServiceUnavailable}
+	flaky.errs = []error{unavailable, unavailable}
+	for i, want := range []string{Pending, Failed} {
+		result, _ := q.Flush(ctx, flaky, "jo")
+		if w, _ := q.Store.Get(ctx, "jo", "flaky"); w.State != want {
+			t.Errorf("after attempt %d, got state %s (%+v)", i+1, w.State, result)
+		}
+	}
+}
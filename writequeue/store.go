@@ -0,0 +1,187 @@
+package writequeue
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+
+	spotify "github.com/ljmeyers80529/spot-go-gae"
+)
+
+// Store persists the writes of each user, by key.
+type Store interface {
+	// Get returns the user's write with the given key, or nil if there
+	// isn't one.
+	Get(ctx context.Context, userID, key string) (*Write, error)
+	// Put adds w, or replaces the write with the same user and key.
+	Put(ctx context.Context, w *Write) error
+	// List returns all of the user's writes, in any order.
+	List(ctx context.Context, userID string) ([]*Write, error)
+	Delete(ctx context.Context, userID, key string) error
+}
+
+// DatastoreStore is a Store that keeps writes in App Engine Datastore
+// entities of the given kind, keyed by idempotency key, whose parent is an
+// entity of kind + "User" keyed by user ID.  A user's queue is read with an
+// ancestor query, so it's strongly consistent, and needs no indexes.
+type DatastoreStore string
+
+// writeEntity is how a Write is stored in Datastore.
+type writeEntity struct {
+	Kind          string   `datastore:",noindex"`
+	IDs           []string `datastore:",noindex"`
+	PlaylistOwner string   `datastore:",noindex"`
+	PlaylistID    string   `datastore:",noindex"`
+	Public        bool     `datastore:",noindex"`
+	State         string   `datastore:",noindex"`
+	Attempts      int      `datastore:",noindex"`
+	LastError     string   `datastore:",noindex"`
+	LastStatus    int      `datastore:",noindex"`
+	Created       time.Time
+	Updated       time.Time `datastore:",noindex"`
+	NextAttempt   time.Time `datastore:",noindex"`
+}
+
+func (kind DatastoreStore) parent(ctx context.Context, userID string) *datastore.Key {
+	return datastore.NewKey(ctx, string(kind)+"User", userID, 0, nil)
+}
+
+func (kind DatastoreStore) key(ctx context.Context, userID, key string) *datastore.Key {
+	return datastore.NewKey(ctx, string(kind), key, 0, kind.parent(ctx, userID))
+}
+
+func fromEntity(userID, key string, e *writeEntity) *Write {
+	w := &Write{
+		Key:           key,
+		UserID:        userID,
+		Kind:          e.Kind,
+		PlaylistOwner: e.PlaylistOwner,
+		PlaylistID:    spotify.ID(e.PlaylistID),
+		Public:        e.Public,
+		State:         e.State,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		LastStatus:    e.LastStatus,
+		Created:       e.Created,
+		Updated:       e.Updated,
+		NextAttempt:   e.NextAttempt,
+	}
+	for _, id := range e.IDs {
+		w.IDs = append(w.IDs, spotify.ID(id))
+	}
+	return w
+}
+
+// Get implements Store.
+func (kind DatastoreStore) Get(ctx context.Context, userID, key string) (*Write, error) {
+	var e writeEntity
+	err := datastore.Get(ctx, kind.key(ctx, userID, key), &e)
+	if err == datastore.ErrNoSuchEntity {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromEntity(userID, key, &e), nil
+}
+
+// Put implements Store.
+func (kind DatastoreStore) Put(ctx context.Context, w *Write) error {
+	e := &writeEntity{
+		Kind:          w.Kind,
+		PlaylistOwner: w.PlaylistOwner,
+		PlaylistID:    string(w.PlaylistID),
+		Public:        w.Public,
+		State:         w.State,
+		Attempts:      w.Attempts,
+		LastError:     w.LastError,
+		LastStatus:    w.LastStatus,
+		Created:       w.Created,
+		Updated:       w.Updated,
+		NextAttempt:   w.NextAttempt,
+	}
+	for _, id := range w.IDs {
+		e.IDs = append(e.IDs, string(id))
+	}
+	_, err := datastore.Put(ctx, kind.key(ctx, w.UserID, w.Key), e)
+	return err
+}
+
+// List implements Store.
+func (kind DatastoreStore) List(ctx context.Context, userID string) ([]*Write, error) {
+	var entities []writeEntity
+	keys, err := datastore.NewQuery(string(kind)).Ancestor(kind.parent(ctx, userID)).GetAll(ctx, &entities)
+	if err != nil {
+		return nil, err
+	}
+	writes := make([]*Write, len(keys))
+	for i, k := range keys {
+		writes[i] = fromEntity(userID, k.StringID(), &entities[i])
+	}
+	return writes, nil
+}
+
+// Delete implements Store.
+func (kind DatastoreStore) Delete(ctx context.Context, userID, key string) error {
+	err := datastore.Delete(ctx, kind.key(ctx, userID, key))
+	if err == datastore.ErrNoSuchEntity {
+		return nil
+	}
+	return err
+}
+
+// MemoryStore is a Store that keeps writes in memory.  It's useful for
+// tests, but the writes are lost when the process exits.
+type MemoryStore struct {
+	mu     sync.Mutex
+	writes map[string]map[string]Write
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{writes: make(map[string]map[string]Write)}
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(ctx context.Context, userID, key string) (*Write, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.writes[userID][key]
+	if !ok {
+		return nil, nil
+	}
+	return &w, nil
+}
+
+// Put implements Store.
+func (m *MemoryStore) Put(ctx context.Context, w *Write) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writes[w.UserID] == nil {
+		m.writes[w.UserID] = make(map[string]Write)
+	}
+	m.writes[w.UserID][w.Key] = *w
+	return nil
+}
+
+// List implements Store.
+func (m *MemoryStore) List(ctx context.Context, userID string) ([]*Write, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var writes []*Write
+	for _, w := range m.writes[userID] {
+		w := w
+		writes = append(writes, &w)
+	}
+	return writes, nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(ctx context.Context, userID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.writes[userID], key)
+	return nil
+}